@@ -13,6 +13,7 @@ import (
 	healthUsecase "github.com/tian841224/stock-bot/internal/application/usecase/health"
 	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
 	"github.com/tian841224/stock-bot/internal/application/usecase/user"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 	formatterAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/formatter"
 	healthAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/health"
 	marketAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/market"
@@ -66,10 +67,17 @@ func main() {
 	userRepo := repository.NewPostgresUserRepository(gormDB, appLogger)
 	stockSymbolRepo := repository.NewSymbolRepository(gormDB, appLogger)
 	tradeDateRepo := repository.NewPostgresTradeDateRepository(gormDB, appLogger)
+	dailyBarRepo := repository.NewDailyBarRepository(gormDB, appLogger)
 	subscriptionRepo := repository.NewSubscriptionRepository(gormDB, appLogger)
 	subscriptionSymbolRepo := repository.NewSubscriptionSymbolRepository(gormDB, appLogger)
 	featureReader, _ := repository.NewFeatureRepository(gormDB, appLogger)
 	syncMetadataRepo := repository.NewSyncMetadataRepository(gormDB, appLogger)
+	priceAlertRepo := repository.NewPriceAlertRepository(gormDB, appLogger)
+	watchlistRepo := repository.NewWatchlistRepository(gormDB, appLogger)
+	watchlistItemRepo := repository.NewWatchlistItemRepository(gormDB, appLogger)
+	portfolioTradeRepo := repository.NewPortfolioTradeRepository(gormDB, appLogger)
+	tradingSettingRepo := repository.NewTradingSettingRepository(gormDB, appLogger)
+	userPreferenceRepo := repository.NewUserPreferenceRepository(gormDB, appLogger)
 	appLogger.Info("Feature Repository 初始化成功，預設功能資料已建立")
 
 	// ============================================================
@@ -122,21 +130,33 @@ func main() {
 		userRepo,
 	)
 
+	// Watchlist Gateway
+	watchlistGateway := userSubscriptionAdapter.NewWatchlistGateway(
+		watchlistRepo,
+		watchlistItemRepo,
+	)
+
+	// Market Provider Registry
+	marketProviderRegistry, err := marketAdapter.NewMarketProviderRegistry(*cfg, twseAPI, cnyesAPI, fugleAPI, finmindAPI, appLogger)
+	if err != nil {
+		appLogger.Fatal("建立市場資料來源失敗", logger.Error(err))
+	}
+
 	// Market Data Gateway
 	marketDataGateway := marketAdapter.NewMarketDataGateway(
+		marketProviderRegistry,
+		dailyBarRepo,
 		twseAPI,
-		cnyesAPI,
-		fugleAPI,
 		finmindAPI,
 		validationGateway,
 		tradeDateRepo,
+		appLogger,
 	)
 
 	// Market Chart Gateway
 	marketChartGateway := marketAdapter.NewMarketChartGateway(
 		marketDataGateway,
 		validationGateway,
-		fugleAPI,
 	)
 
 	// Formatter Adapter
@@ -175,16 +195,48 @@ func main() {
 		validationGateway,       // ValidationPort
 	)
 
+	// User Price Alert Use Case
+	userPriceAlertUsecase := user.NewUserPriceAlertUsecase(
+		priceAlertRepo,
+		validationGateway,
+	)
+
+	// User Watchlist Use Case
+	userWatchlistUsecase := user.NewUserWatchlistUsecase(
+		watchlistGateway,
+		validationGateway,
+	)
+
+	// User Portfolio Use Case
+	userPortfolioUsecase := user.NewUserPortfolioUsecase(
+		portfolioTradeRepo,
+		tradingSettingRepo,
+		marketDataGateway,
+		validationGateway,
+		newTransactionCostModel(cfg),
+	)
+
+	// User Preference Use Case
+	userPreferenceUsecase := user.NewUserPreferenceUsecase(
+		userPreferenceRepo,
+		userSubscriptionGateway,
+		cfg.SCHEDULER_TIMEZONE,
+	)
+
 	// Bot Command Use Case
 	botCommandUsecase := bot.NewBotCommandUsecase(
 		formatterGateway,
 		marketDataUsecase,
 		marketChartUsecase,
 		userSubscriptionUsecase,
+		userPriceAlertUsecase,
+		userWatchlistUsecase,
+		userPortfolioUsecase,
+		userPreferenceUsecase,
 	)
 
 	// Health Check Use Case
-	healthChecker := healthAdapter.NewHealthChecker(gormDB, finmindAPI, fugleAPI, syncMetadataRepo)
+	healthChecker := healthAdapter.NewHealthChecker(gormDB, finmindAPI, fugleAPI, syncMetadataRepo, marketProviderRegistry)
 	healthUsecaseInstance := healthUsecase.NewHealthCheckUsecase(healthChecker, "stock-bot", "1.0.0", appLogger)
 
 	// Bot Platform Use Cases
@@ -197,11 +249,24 @@ func main() {
 		appLogger,
 	)
 
+	tgInlineQueryUsecase := bot.NewTelegramInlineQueryUsecase(
+		stockSymbolRepo,
+		marketDataGateway,
+		marketChartGateway,
+		formatterGateway,
+		tgClient,
+		imgbbClient,
+		appLogger,
+	)
+
 	lineCommandUsecase := bot.NewLineBotCommandUsecase(
 		botCommandUsecase,
 		lineClient,
 		imgbbClient,
 	)
+
+	// 股票名稱解析（/k 台積電、/d tsmc）
+	symbolResolver := stock.NewSymbolResolver(stockSymbolRepo, appLogger)
 	appLogger.Info("Use Case 層初始化成功")
 
 	// ============================================================
@@ -210,6 +275,8 @@ func main() {
 	appLogger.Info("初始化 Message Processor 層...")
 	tgProcessor := bot.NewTelegramMessageProcessor(
 		tgCommandUsecase,
+		tgInlineQueryUsecase,
+		symbolResolver,
 		userRepo,
 		tgClient,
 		appLogger,
@@ -217,6 +284,7 @@ func main() {
 
 	lineProcessor := bot.NewLineMessageProcessor(
 		lineCommandUsecase,
+		symbolResolver,
 		userRepo,
 		lineClient,
 		appLogger,
@@ -262,6 +330,18 @@ func main() {
 }
 
 // setupRouter 設定 HTTP 路由
+// newTransactionCostModel 建立台股交易成本模型，並套用環境變數中的券商預設值
+func newTransactionCostModel(cfg *config.Config) *entity.TransactionCostModel {
+	costModel := entity.NewTaiwanTransactionCostModel()
+	if cfg.TRADING_FEE_DISCOUNT > 0 {
+		costModel.FeeDiscount = cfg.TRADING_FEE_DISCOUNT
+	}
+	if cfg.TRADING_MIN_FEE > 0 {
+		costModel.MinFee = cfg.TRADING_MIN_FEE
+	}
+	return costModel
+}
+
 func setupRouter(
 	cfg *config.Config,
 	tgProcessor *bot.TelegramMessageProcessor,
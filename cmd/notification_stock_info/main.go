@@ -9,14 +9,18 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+	"github.com/tian841224/stock-bot/internal/application/port"
 	healthUsecase "github.com/tian841224/stock-bot/internal/application/usecase/health"
 	notificationUseCase "github.com/tian841224/stock-bot/internal/application/usecase/notification"
 	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
 	formatterAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/formatter"
 	healthAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/health"
 	marketAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/market"
+	notifierAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/notifier"
 	presenterAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/presenter"
 	"github.com/tian841224/stock-bot/internal/infrastructure/config"
+	linebotInfra "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/line"
 	tgbotInfra "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/telegram"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/cnyes"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade"
@@ -28,6 +32,9 @@ import (
 	healthHandler "github.com/tian841224/stock-bot/internal/interfaces/health"
 )
 
+// notificationDispatchSpec 送出通知佇列及重試失敗通知的頻率
+const notificationDispatchSpec = "@every 10s"
+
 func main() {
 	// ============================================================
 	// 基礎設施初始化
@@ -60,6 +67,11 @@ func main() {
 		appLogger.Fatal("建立 Telegram Bot 客戶端失敗", logger.Error(err))
 	}
 
+	lineClient, err := linebotInfra.NewBot(*cfg, appLogger)
+	if err != nil {
+		appLogger.Fatal("建立 LINE Bot 客戶端失敗", logger.Error(err))
+	}
+
 	fugleAPI := fugle.NewFugleAPI(*cfg)
 	twseAPI := twse.NewTwseAPI()
 	cnyesAPI := cnyes.NewCnyesAPI()
@@ -68,35 +80,41 @@ func main() {
 	// ============================================================
 	// Repository
 	// ============================================================
+	userRepo := repository.NewPostgresUserRepository(gormDB, appLogger)
 	stockSymbolRepo := repository.NewSymbolRepository(gormDB, appLogger)
 	tradeDateRepo := repository.NewPostgresTradeDateRepository(gormDB, appLogger)
+	dailyBarRepo := repository.NewDailyBarRepository(gormDB, appLogger)
+	subscriptionRepo := repository.NewSubscriptionRepository(gormDB, appLogger)
 	subscriptionSymbolRepo := repository.NewSubscriptionSymbolRepository(gormDB, appLogger)
 	syncMetadataRepo := repository.NewSyncMetadataRepository(gormDB, appLogger)
-
-	// ============================================================
-	// Health Check
-	// ============================================================
-	healthChecker := healthAdapter.NewHealthChecker(gormDB, finmindAPI, fugleAPI, syncMetadataRepo)
-	healthUsecaseInstance := healthUsecase.NewHealthCheckUsecase(healthChecker, "stock-scheduler", "1.0.0", appLogger)
+	priceAlertRepo := repository.NewPriceAlertRepository(gormDB, appLogger)
+	notificationEventRepo := repository.NewNotificationEventRepository(gormDB, appLogger)
+	notificationDeliveryRepo := repository.NewNotificationDeliveryRepository(gormDB, appLogger)
+	userPreferenceRepo := repository.NewUserPreferenceRepository(gormDB, appLogger)
 
 	// ============================================================
 	// Adapter / Gateway
 	// ============================================================
 	validationGateway := presenterAdapter.NewValidationGateway(nil, stockSymbolRepo)
 
+	marketProviderRegistry, err := marketAdapter.NewMarketProviderRegistry(*cfg, twseAPI, cnyesAPI, fugleAPI, finmindAPI, appLogger)
+	if err != nil {
+		appLogger.Fatal("建立市場資料來源失敗", logger.Error(err))
+	}
+
 	marketDataGateway := marketAdapter.NewMarketDataGateway(
+		marketProviderRegistry,
+		dailyBarRepo,
 		twseAPI,
-		cnyesAPI,
-		fugleAPI,
 		finmindAPI,
 		validationGateway,
 		tradeDateRepo,
+		appLogger,
 	)
 
 	marketChartGateway := marketAdapter.NewMarketChartGateway(
 		marketDataGateway,
 		validationGateway,
-		fugleAPI,
 	)
 
 	telegramFormatter := formatterAdapter.NewTelegramFormatter()
@@ -108,6 +126,12 @@ func main() {
 		lineFormatter,
 	)
 
+	// ============================================================
+	// Health Check
+	// ============================================================
+	healthChecker := healthAdapter.NewHealthChecker(gormDB, finmindAPI, fugleAPI, syncMetadataRepo, marketProviderRegistry)
+	healthUsecaseInstance := healthUsecase.NewHealthCheckUsecase(healthChecker, "stock-scheduler", "1.0.0", appLogger)
+
 	// ============================================================
 	// Use Case
 	// ============================================================
@@ -118,15 +142,70 @@ func main() {
 		appLogger,
 	)
 
+	notificationOutbox := notificationUseCase.NewNotificationOutbox(notificationEventRepo, appLogger)
+
+	notificationDispatcher := notificationUseCase.NewNotificationDispatcher(
+		notificationDeliveryRepo,
+		userRepo,
+		userPreferenceRepo,
+		[]port.NotifierPort{
+			notifierAdapter.NewTelegramNotifier(tgClient),
+			notifierAdapter.NewLineNotifier(lineClient),
+		},
+		cfg.NOTIFICATION_MAX_RETRIES,
+		appLogger,
+	)
+
 	sendNotificationUsecase := notificationUseCase.NewSendNotificationUsecase(
 		subscriptionSymbolRepo,
 		marketDataUsecase,
 		formatterGateway,
-		tgClient,
+		notificationOutbox,
+		appLogger,
+	)
+
+	priceAlertNotificationUsecase := notificationUseCase.NewPriceAlertNotificationUsecase(
+		priceAlertRepo,
+		userPreferenceRepo,
+		marketDataUsecase,
+		formatterGateway,
+		notificationOutbox,
 		appLogger,
 	)
 
-	scheduleHandlerUsecase := notificationUseCase.NewScheduleHandlerUsecase(sendNotificationUsecase, appLogger)
+	exDividendNotificationUsecase := notificationUseCase.NewExDividendNotificationUsecase(
+		subscriptionSymbolRepo,
+		marketDataGateway,
+		tradeDateRepo,
+		formatterGateway,
+		notificationOutbox,
+		cfg.EX_DIVIDEND_REMINDER_DAYS,
+		appLogger,
+	)
+
+	dailyDigestUsecase := notificationUseCase.NewDailyDigestUsecase(
+		subscriptionRepo,
+		subscriptionSymbolRepo,
+		marketDataUsecase,
+		formatterGateway,
+		notificationOutbox,
+		appLogger,
+	)
+
+	scheduleHandlerUsecase, err := notificationUseCase.NewScheduleHandlerUsecase(
+		subscriptionRepo,
+		tradeDateRepo,
+		sendNotificationUsecase,
+		priceAlertNotificationUsecase,
+		exDividendNotificationUsecase,
+		dailyDigestUsecase,
+		cfg.SCHEDULER_STOCK_SPEC,
+		cfg.SCHEDULER_MORNING_TIME,
+		appLogger,
+	)
+	if err != nil {
+		appLogger.Fatal("建立推播排程失敗", logger.Error(err))
+	}
 	appLogger.Info("所有服務初始化完成")
 
 	// ============================================================
@@ -151,7 +230,7 @@ func main() {
 	}()
 
 	// 啟動排程通知任務
-	go runScheduledNotifications(ctx, scheduleHandlerUsecase, appLogger)
+	go runScheduledNotifications(ctx, scheduleHandlerUsecase, notificationDispatcher, cfg.SCHEDULER_TIMEZONE, appLogger)
 
 	<-quit
 	appLogger.Info("收到關閉信號，正在優雅關閉...")
@@ -159,46 +238,50 @@ func main() {
 	appLogger.Info("=== 通知服務已關閉 ===")
 }
 
-func runScheduledNotifications(ctx context.Context, scheduler notificationUseCase.ScheduleHandlerUsecase, log logger.Logger) {
-	log.Info("排程通知服務已啟動")
-
-	// 啟動時立刻執行一次
-	log.Info("正在執行啟動時的通知任務...")
-	if err := scheduler.RunScheduledTasks(ctx); err != nil {
-		log.Error("啟動時通知任務執行失敗", logger.Error(err))
-	} else {
-		log.Info("啟動時通知任務執行完成")
+// runScheduledNotifications 每分鐘檢查一次各訂閱的推播排程，cron 表達式以 SCHEDULER_TIMEZONE 時區解讀；
+// 排程任務只將推播寫入通知佇列，由 dispatcher 定期送出
+func runScheduledNotifications(
+	ctx context.Context,
+	scheduler notificationUseCase.ScheduleHandlerUsecase,
+	dispatcher notificationUseCase.NotificationDispatcher,
+	timezone string,
+	log logger.Logger,
+) {
+	if timezone == "" {
+		timezone = "Asia/Taipei"
 	}
-
-	// 設定每天下午三點 (台北時間) 執行
-	loc, err := time.LoadLocation("Asia/Taipei")
+	loc, err := time.LoadLocation(timezone)
 	if err != nil {
-		log.Error("無法載入 Asia/Taipei 時區，將使用 Local 時區", logger.Error(err))
+		log.Error("無法載入排程時區，將使用 Local 時區", logger.String("timezone", timezone), logger.Error(err))
 		loc = time.Local
 	}
 
-	for {
-		now := time.Now().In(loc)
-		nextRun := time.Date(now.Year(), now.Month(), now.Day(), 15, 0, 0, 0, loc)
-
-		if now.After(nextRun) {
-			nextRun = nextRun.Add(24 * time.Hour)
+	// 上一輪尚未完成時略過本輪，避免同一筆投遞紀錄被重複送出；略過的分鐘由下一輪 RunDueTasks 補上
+	runner := cron.New(cron.WithLocation(loc), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	if _, err := runner.AddFunc("* * * * *", func() {
+		if err := scheduler.RunDueTasks(ctx, time.Now().In(loc)); err != nil {
+			log.Error("排程通知任務執行失敗", logger.Error(err))
 		}
-
-		duration := nextRun.Sub(now)
-		log.Info("下次排程任務將在 " + duration.String() + " 後執行 (" + nextRun.Format("2006-01-02 15:04:05") + ")")
-
-		select {
-		case <-ctx.Done():
-			log.Info("排程通知服務已停止")
-			return
-		case <-time.After(duration):
-			log.Info("執行下午三點的排程通知任務...")
-			if err := scheduler.RunScheduledTasks(ctx); err != nil {
-				log.Error("排程通知任務執行失敗", logger.Error(err))
-			} else {
-				log.Info("排程通知任務執行完成")
-			}
+	}); err != nil {
+		log.Error("建立排程失敗", logger.Error(err))
+		return
+	}
+	if _, err := runner.AddFunc(notificationDispatchSpec, func() {
+		if err := dispatcher.DispatchQueued(ctx); err != nil {
+			log.Error("送出通知佇列失敗", logger.Error(err))
 		}
+		if err := dispatcher.RetryFailed(ctx); err != nil {
+			log.Error("重試失敗通知失敗", logger.Error(err))
+		}
+	}); err != nil {
+		log.Error("建立通知佇列排程失敗", logger.Error(err))
+		return
 	}
+
+	runner.Start()
+	log.Info("排程通知服務已啟動", logger.String("timezone", loc.String()))
+
+	<-ctx.Done()
+	<-runner.Stop().Done()
+	log.Info("排程通知服務已停止")
 }
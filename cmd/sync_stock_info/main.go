@@ -9,19 +9,26 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
 	healthUsecase "github.com/tian841224/stock-bot/internal/application/usecase/health"
 	"github.com/tian841224/stock-bot/internal/application/usecase/stock_sync"
 	healthAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/health"
+	marketAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/market"
 	stock "github.com/tian841224/stock-bot/internal/infrastructure/adapter/stock"
 	"github.com/tian841224/stock-bot/internal/infrastructure/config"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/cnyes"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/twse"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 	database "github.com/tian841224/stock-bot/internal/infrastructure/persistence"
 	repository "github.com/tian841224/stock-bot/internal/infrastructure/persistence/postgres"
 	healthHandler "github.com/tian841224/stock-bot/internal/interfaces/health"
 )
 
+// dailyBarSyncSpec 日K同步排程：早上更新美股前一交易日、傍晚更新台股當日收盤資料
+const dailyBarSyncSpec = "0 8,18 * * *"
+
 func main() {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -49,12 +56,21 @@ func main() {
 	stockSymbolRepo := repository.NewSymbolRepository(gormDB, appLogger)
 	syncMetadataRepo := repository.NewSyncMetadataRepository(gormDB, appLogger)
 	tradeDateRepo := repository.NewPostgresTradeDateRepository(gormDB, appLogger)
+	dailyBarRepo := repository.NewDailyBarRepository(gormDB, appLogger)
 	finmindAPI := finmindtrade.NewFinmindTradeAPI(*cfg)
 	fugleAPI := fugle.NewFugleAPI(*cfg)
+	twseAPI := twse.NewTwseAPI()
+	cnyesAPI := cnyes.NewCnyesAPI()
 	stockInfoProvider := stock.NewFinmindStockInfoAdapter(finmindAPI)
 	stockSyncUsecase := stock_sync.NewStockSyncUsecase(stockSymbolRepo, stockInfoProvider, syncMetadataRepo, tradeDateRepo, appLogger)
 
-	healthChecker := healthAdapter.NewHealthChecker(gormDB, finmindAPI, fugleAPI, syncMetadataRepo)
+	marketProviderRegistry, err := marketAdapter.NewMarketProviderRegistry(*cfg, twseAPI, cnyesAPI, fugleAPI, finmindAPI, appLogger)
+	if err != nil {
+		appLogger.Fatal("建立市場資料來源失敗", logger.Error(err))
+	}
+	dailyBarSyncUsecase := stock_sync.NewDailyBarSyncUsecase(stockSymbolRepo, dailyBarRepo, marketProviderRegistry, tradeDateRepo, appLogger)
+
+	healthChecker := healthAdapter.NewHealthChecker(gormDB, finmindAPI, fugleAPI, syncMetadataRepo, marketProviderRegistry)
 	healthUsecaseInstance := healthUsecase.NewHealthCheckUsecase(healthChecker, "stock-sync", "1.0.0", appLogger)
 
 	appLogger.Info("服務初始化成功")
@@ -68,7 +84,10 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	// 啟動背景同步服務
-	go runBackgroundSync(ctx, stockSyncUsecase, appLogger)
+	go runBackgroundSync(ctx, stockSyncUsecase, dailyBarSyncUsecase, appLogger)
+
+	// 啟動日K同步排程
+	go runDailyBarSync(ctx, dailyBarSyncUsecase, cfg.SCHEDULER_TIMEZONE, appLogger)
 
 	// 啟動健康檢查 HTTP 服務器
 	go func() {
@@ -92,7 +111,7 @@ func main() {
 	appLogger.Info("=== 程式已關閉 ===")
 }
 
-func runBackgroundSync(ctx context.Context, stockSyncUsecase stock_sync.StockSyncUsecase, appLogger logger.Logger) {
+func runBackgroundSync(ctx context.Context, stockSyncUsecase stock_sync.StockSyncUsecase, dailyBarSyncUsecase stock_sync.DailyBarSyncUsecase, appLogger logger.Logger) {
 	defer func() {
 		appLogger.Info("背景同步任務已完全停止")
 	}()
@@ -118,6 +137,11 @@ func runBackgroundSync(ctx context.Context, stockSyncUsecase stock_sync.StockSyn
 	}
 	appLogger.Info("台股交易日同步完成")
 
+	// 日K同步需要股票清單及交易日，於初始同步後回補
+	if err := dailyBarSyncUsecase.SyncDailyBars(ctx); err != nil {
+		appLogger.Error("日K同步失敗", logger.Error(err))
+	}
+
 	ticker := time.NewTicker(24 * time.Hour)
 	defer ticker.Stop()
 
@@ -144,3 +168,32 @@ func runBackgroundSync(ctx context.Context, stockSyncUsecase stock_sync.StockSyn
 		}
 	}
 }
+
+// runDailyBarSync 依 dailyBarSyncSpec 增量更新訂閱及觀察清單股票的日K，cron 表達式以 SCHEDULER_TIMEZONE 時區解讀
+func runDailyBarSync(ctx context.Context, dailyBarSyncUsecase stock_sync.DailyBarSyncUsecase, timezone string, log logger.Logger) {
+	if timezone == "" {
+		timezone = "Asia/Taipei"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Error("無法載入排程時區，將使用 Local 時區", logger.String("timezone", timezone), logger.Error(err))
+		loc = time.Local
+	}
+
+	runner := cron.New(cron.WithLocation(loc), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	if _, err := runner.AddFunc(dailyBarSyncSpec, func() {
+		if err := dailyBarSyncUsecase.SyncDailyBars(ctx); err != nil {
+			log.Error("日K同步失敗", logger.Error(err))
+		}
+	}); err != nil {
+		log.Error("建立日K同步排程失敗", logger.Error(err))
+		return
+	}
+
+	runner.Start()
+	log.Info("日K同步排程已啟動", logger.String("timezone", loc.String()))
+
+	<-ctx.Done()
+	<-runner.Stop().Done()
+	log.Info("日K同步排程已停止")
+}
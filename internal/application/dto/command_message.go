@@ -0,0 +1,13 @@
+package dto
+
+// CommandMessage 指令回覆訊息與快速指令按鈕
+type CommandMessage struct {
+	Text      string
+	Shortcuts []CommandShortcut
+}
+
+// CommandShortcut 快速指令按鈕，點擊後執行 Command
+type CommandShortcut struct {
+	Label   string
+	Command string
+}
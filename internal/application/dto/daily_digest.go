@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot"
+)
+
+// DailyDigest 每日摘要，合併大盤資訊、成交量排行及使用者訂閱股票的收盤與新聞
+type DailyDigest struct {
+	// 摘要日期
+	Date time.Time
+	// 大盤資訊，取得失敗時為空
+	MarketInfo []DailyMarketInfo
+	// 成交量排行，取得失敗時為空
+	TopVolume []TopVolume
+	// 訂閱股票
+	Stocks []*DailyDigestStock
+}
+
+// DailyDigestStock 每日摘要中的單一股票
+type DailyDigestStock struct {
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 收盤資訊，取得失敗時為 nil
+	Price *StockPrice
+	// 新聞標題
+	News []StockNews
+}
+
+// LineDailyDigestMessage LINE 每日摘要訊息，以 Flex carousel 呈現
+type LineDailyDigestMessage struct {
+	// 替代文字
+	Text          string
+	FlexContainer linebot.FlexContainer
+}
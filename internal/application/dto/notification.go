@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// Notification 要推播給使用者的通知
+type Notification struct {
+	// 觸發通知的訂閱功能，價格提醒為 SubscriptionTypeDefault
+	Feature valueobject.SubscriptionType
+	// 訂閱編號
+	SubscriptionID *uint
+	// 股票編號
+	SymbolID *uint
+	// 通知內容所屬的交易日，用於去重；零值代表以推播當日計算
+	TradeDate time.Time
+	// 推播內容
+	Payload NotificationPayload
+}
+
+// NotificationPayload 通知事件儲存的推播內容
+type NotificationPayload struct {
+	// 訊息文字
+	Text string `json:"text"`
+	// Telegram 按鈕
+	Keyboard *tgbotapi.InlineKeyboardMarkup `json:"keyboard,omitempty"`
+	// LINE Flex Message 內容，Text 作為替代文字
+	LineFlex json.RawMessage `json:"line_flex,omitempty"`
+}
+
+// NotificationDeliveryResponse 投遞紀錄儲存的送出結果
+type NotificationDeliveryResponse struct {
+	// 錯誤訊息
+	Error string `json:"error,omitempty"`
+	// 是否為不再重試的永久性錯誤
+	Permanent bool `json:"permanent,omitempty"`
+	// 依使用者偏好設定略過的原因
+	SkippedReason string `json:"skipped_reason,omitempty"`
+}
@@ -0,0 +1,135 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// Portfolio 投資組合總覽
+type Portfolio struct {
+	// 持股明細
+	Holdings []*PortfolioHolding
+	// 總成本
+	TotalCost float64
+	// 總市值
+	MarketValue float64
+	// 未實現損益
+	UnrealizedPnL float64
+	// 未實現報酬率
+	UnrealizedPnLRate float64
+	// 已實現損益
+	RealizedPnL float64
+	// 以現價全部賣出的預估手續費及證交稅
+	EstimatedSellCost float64
+	// 累計手續費
+	TotalFees float64
+	// 累計證交稅
+	TotalTax float64
+	// 累計現金股利
+	CashDividends float64
+	// 報價日期
+	PriceDate time.Time
+}
+
+// PortfolioHolding 單一股票持股
+type PortfolioHolding struct {
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 持有股數
+	Quantity int64
+	// 平均成本
+	AverageCost float64
+	// 總成本
+	TotalCost float64
+	// 最新收盤價
+	ClosePrice float64
+	// 市值
+	MarketValue float64
+	// 以現價全部賣出的預估手續費及證交稅
+	EstimatedSellCost float64
+	// 未實現損益
+	UnrealizedPnL float64
+	// 未實現報酬率
+	UnrealizedPnLRate float64
+	// 市值占比
+	Weight float64
+	// 報價日期
+	PriceDate time.Time
+	// 是否取得報價，未取得時以成本估算市值
+	HasQuote bool
+}
+
+// PortfolioTrade 投資組合交易紀錄
+type PortfolioTrade struct {
+	// 紀錄編號
+	ID uint
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 交易方向
+	Side valueobject.TradeSide
+	// 成交股數
+	Quantity int64
+	// 成交單價
+	Price float64
+	// 交易日期
+	TradeDate time.Time
+}
+
+// TradingSetting 使用者適用的交易成本設定
+type TradingSetting struct {
+	// 手續費率
+	FeeRate float64
+	// 手續費折扣
+	FeeDiscount float64
+	// 整股最低手續費
+	MinFee float64
+	// 零股最低手續費
+	OddLotMinFee float64
+	// 股票證交稅率
+	StockTaxRate float64
+	// ETF 證交稅率
+	ETFTaxRate float64
+	// 現股當沖證交稅率
+	DayTradeTaxRate float64
+}
+
+// DividendIncome 投資組合股利收入
+type DividendIncome struct {
+	// 統計年度
+	Year int
+	// 各持股股利
+	Holdings []*DividendIncomeHolding
+	// 今年以來現金股利
+	YearToDateIncome float64
+	// 預估年度現金股利
+	ProjectedAnnualIncome float64
+	// 持股總成本
+	TotalCost float64
+	// 預估成本殖利率
+	YieldOnCost float64
+}
+
+// DividendIncomeHolding 單一持股的股利收入
+type DividendIncomeHolding struct {
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 目前持有股數
+	Quantity int64
+	// 今年以來現金股利
+	YearToDateIncome float64
+	// 今年以來配股股數
+	YearToDateStockShares int64
+	// 近一年每股現金股利
+	TrailingCashDividend float64
+	// 依近一年股利預估的年度現金股利
+	ProjectedAnnualIncome float64
+	// 預估成本殖利率
+	YieldOnCost float64
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// PriceAlert 價格提醒
+type PriceAlert struct {
+	// 提醒編號
+	ID uint
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 提醒類型
+	AlertType valueobject.AlertType
+	// 比較運算子
+	Operator valueobject.AlertOperator
+	// 目標價
+	TargetPrice float64
+	// 漲跌幅/跳空百分比門檻
+	ThresholdPercent float64
+	// 是否已處於觸發區間
+	Triggered bool
+	// 最近觸發時間
+	TriggeredAt *time.Time
+}
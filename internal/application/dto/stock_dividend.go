@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// StockDividend 股利發放資料
+type StockDividend struct {
+	// 股票代號
+	Symbol string
+	// 股利所屬年度（季度）
+	Year string
+	// 每股現金股利（元）
+	CashDividend float64
+	// 每股股票股利（元），以面額 10 元換算配股
+	StockDividend float64
+	// 除息交易日，未除息時為零值
+	CashExDividendDate time.Time
+	// 現金股利發放日
+	CashPaymentDate time.Time
+	// 除權交易日，未除權時為零值
+	StockExDividendDate time.Time
+	// 公告日期
+	AnnouncementDate time.Time
+}
+
+// DividendCalendar 個股除權息行事曆
+type DividendCalendar struct {
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 尚未除權息（含日期未定）的股利，依除權息日由近到遠排序
+	Upcoming []StockDividend
+	// 已除權息的歷史股利，依除權息日由近到遠排序
+	History []StockDividend
+}
+
+// ExDividendReminder 除權息前提醒
+type ExDividendReminder struct {
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 除權息日
+	ExDividendDate time.Time
+	// 距離除權息日的交易日數
+	TradingDaysLeft int
+	// 股利資料
+	Dividend StockDividend
+}
@@ -8,8 +8,12 @@ type StockPrice struct {
 	Symbol string
 	// 股票名稱
 	Name string
+	// 幣別（TWD、USD）
+	Currency string
 	// 日期
 	Date time.Time
+	// 前一交易日
+	PrevTradeDate time.Time
 	// 前一日收盤價
 	PrevClosePrice float64
 	// 開盤價
@@ -0,0 +1,26 @@
+package dto
+
+// SymbolResolution 將使用者輸入的代號或公司名稱解析為股票代號的結果
+type SymbolResolution struct {
+	// 解析出的股票代號，無法唯一決定或查無符合時為空
+	Symbol string
+	// 無法唯一決定時的候選股票，依符合程度排序
+	Candidates []SymbolCandidate
+}
+
+// SymbolCandidate 候選股票
+type SymbolCandidate struct {
+	Symbol string
+	Name   string
+	Market string
+}
+
+// IsResolved 是否已解析出唯一的股票代號
+func (r *SymbolResolution) IsResolved() bool {
+	return r.Symbol != ""
+}
+
+// IsAmbiguous 是否有多檔候選股票需要使用者選擇
+func (r *SymbolResolution) IsAmbiguous() bool {
+	return r.Symbol == "" && len(r.Candidates) > 0
+}
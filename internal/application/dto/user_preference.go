@@ -0,0 +1,46 @@
+package dto
+
+import "github.com/tian841224/stock-bot/internal/domain/valueobject"
+
+// UserPreference 使用者推播偏好設定
+type UserPreference struct {
+	// 勿擾開始時間 (HH:MM)，未啟用時為空
+	QuietHoursStart string
+	// 勿擾結束時間 (HH:MM)
+	QuietHoursEnd string
+	// 時區
+	Timezone string
+	// 偏好語言
+	Language valueobject.Language
+	// 是否啟用每日摘要
+	DigestEnabled bool
+	// 每日推播上限，0 代表不限制
+	MaxDailyPushes int
+}
+
+// UserPreferenceSetting 偏好設定項目
+type UserPreferenceSetting string
+
+const (
+	UserPreferenceSettingQuietHours     UserPreferenceSetting = "quiet"
+	UserPreferenceSettingTimezone       UserPreferenceSetting = "tz"
+	UserPreferenceSettingLanguage       UserPreferenceSetting = "lang"
+	UserPreferenceSettingDigest         UserPreferenceSetting = "digest"
+	UserPreferenceSettingMaxDailyPushes UserPreferenceSetting = "max"
+)
+
+// UserPreferenceChange 修改單一偏好設定項目
+type UserPreferenceChange struct {
+	Setting UserPreferenceSetting
+	// 勿擾開始與結束時間，兩者皆為空代表關閉勿擾時段
+	QuietHoursStart string
+	QuietHoursEnd   string
+	// 時區
+	Timezone string
+	// 偏好語言
+	Language string
+	// 是否開啟
+	Enabled bool
+	// 每日推播上限
+	MaxDailyPushes int
+}
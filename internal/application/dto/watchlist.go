@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// WatchlistQuote 觀察清單報價
+type WatchlistQuote struct {
+	// 股票代號
+	Symbol string
+	// 股票名稱
+	Name string
+	// 日期
+	Date time.Time
+	// 收盤價
+	ClosePrice float64
+	// 漲跌金額
+	ChangeAmount float64
+	// 漲跌幅
+	ChangeRate float64
+	// 漲跌方向
+	UpDownSign string
+	// 交易量
+	Volume int64
+	// 是否取得報價
+	HasQuote bool
+}
+
+// Watchlist 具名觀察清單及其報價
+type Watchlist struct {
+	// 清單名稱
+	Name string
+	// 清單內股票報價（依使用者排序）
+	Quotes []*WatchlistQuote
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type BotCommandPort interface {
-	GetUseGuideMessage() string
+	GetUseGuideMessage(userType valueobject.UserType) string
 	GetDailyMarketInfo(ctx context.Context, userType valueobject.UserType, count int) (string, error)
 	GetStockPerformance(ctx context.Context, userType valueobject.UserType, symbol string) (string, error)
 	GetStockPerformanceChart(ctx context.Context, symbol string) (*dto.ChartAsset, error)
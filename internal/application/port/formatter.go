@@ -32,6 +32,45 @@ type FormatterPort interface {
 	// FormatLineNewsMessage 格式化 Line 股票新聞訊息
 	FormatLineNewsMessage(news []dto.StockNews, stockName, symbol string) *dto.LineStockNewsMessage
 
+	// FormatTelegramDailyDigest 格式化 Telegram 每日摘要訊息
+	FormatTelegramDailyDigest(digest *dto.DailyDigest) string
+
+	// FormatLineDailyDigest 格式化 LINE 每日摘要訊息（Flex carousel）
+	FormatLineDailyDigest(digest *dto.DailyDigest) *dto.LineDailyDigestMessage
+
 	// FormatSubscribed 格式化訂閱股票和項目
-	FormatSubscribed(stocks []*dto.UserSubscriptionStock, items []*dto.UserSubscriptionItem) string
+	FormatSubscribed(stocks []*dto.UserSubscriptionStock, items []*dto.UserSubscriptionItem, userType valueobject.UserType) string
+
+	// FormatPriceAlertList 格式化價格提醒列表
+	FormatPriceAlertList(alerts []*dto.PriceAlert, userType valueobject.UserType) string
+
+	// FormatPriceAlertTriggered 格式化價格提醒觸發訊息，依使用者偏好語言顯示
+	FormatPriceAlertTriggered(alert *dto.PriceAlert, price *dto.StockPrice, userType valueobject.UserType, language valueobject.Language) string
+
+	// FormatWatchlist 格式化觀察清單報價表，每個清單一張表
+	FormatWatchlist(watchlists []*dto.Watchlist, userType valueobject.UserType) string
+
+	// FormatPortfolio 格式化投資組合持股與損益
+	FormatPortfolio(portfolio *dto.Portfolio, userType valueobject.UserType) string
+
+	// FormatPortfolioTrades 格式化投資組合交易紀錄
+	FormatPortfolioTrades(trades []*dto.PortfolioTrade, userType valueobject.UserType) string
+
+	// FormatTradingSetting 格式化交易成本設定
+	FormatTradingSetting(setting *dto.TradingSetting, userType valueobject.UserType) string
+
+	// FormatDividendIncome 格式化投資組合股利收入
+	FormatDividendIncome(income *dto.DividendIncome, userType valueobject.UserType) string
+
+	// FormatDividendCalendar 格式化個股除權息行事曆
+	FormatDividendCalendar(calendar *dto.DividendCalendar, userType valueobject.UserType) string
+
+	// FormatExDividendReminder 格式化除權息前提醒
+	FormatExDividendReminder(reminder *dto.ExDividendReminder, userType valueobject.UserType) string
+
+	// FormatSubscriptionSchedules 格式化訂閱項目的推播排程
+	FormatSubscriptionSchedules(items []*dto.UserSubscriptionItem, userType valueobject.UserType) string
+
+	// FormatUserPreference 格式化使用者推播偏好設定，依使用者偏好語言顯示
+	FormatUserPreference(preference *dto.UserPreference, userType valueobject.UserType) string
 }
@@ -11,6 +11,12 @@ type HealthChecker interface {
 	CheckAPI(ctx context.Context, apiName string) HealthStatus
 	CheckSyncStatus(ctx context.Context) SyncHealthStatus
 	CheckResources(ctx context.Context) ResourceHealthStatus
+	CheckMarketProviders(ctx context.Context) MarketProviderHealthStatus
+}
+
+// MarketProviderMonitor 提供市場資料來源的使用紀錄，用於健康檢查
+type MarketProviderMonitor interface {
+	GetMarketProviderStatus() MarketProviderHealthStatus
 }
 
 // HealthStatus 健康狀態
@@ -41,3 +47,40 @@ type ResourceHealthStatus struct {
 	Goroutines    int
 	CPUCores      int
 }
+
+// MarketProviderHealthStatus 市場資料來源健康狀態
+type MarketProviderHealthStatus struct {
+	Status       string
+	Capabilities []MarketCapabilityStatus
+}
+
+// MarketCapabilityStatus 單一資料能力（日K、報價、營收、新聞）的來源狀態
+type MarketCapabilityStatus struct {
+	Capability string
+	Status     string
+	// 依設定排序的資料來源
+	Providers []MarketProviderStats
+	// 最近的請求由哪個資料來源提供，新的在前
+	Recent []MarketProviderRequest
+}
+
+// MarketProviderStats 資料來源的累計使用次數
+type MarketProviderStats struct {
+	Name         string
+	Served       int64
+	Failed       int64
+	LastServedAt *time.Time
+	LastFailedAt *time.Time
+	LastError    *string
+}
+
+// MarketProviderRequest 單次請求的資料來源紀錄
+type MarketProviderRequest struct {
+	Time   time.Time
+	Symbol string
+	// 提供資料的來源，所有來源都失敗時為空
+	Provider string
+	// 在 Provider 之前嘗試但失敗的來源
+	FailedProviders []string
+	Error           *string
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 )
 
 // MarketDataPort 封裝 bot usecase 取用市場/股票資料所需的介面。
@@ -26,4 +27,12 @@ type MarketDataPort interface {
 	// 取得最後交易日 by 日期範圍
 	GetLatestTradeDateByDateRange(ctx context.Context, startDate time.Time, endDate time.Time) ([]time.Time, error)
 	GetStockNews(ctx context.Context, symbol string) ([]dto.StockNews, error)
+	// 取得股票股利發放資料
+	GetStockDividends(ctx context.Context, symbol string, startDate time.Time, endDate time.Time) ([]dto.StockDividend, error)
+}
+
+// DailyBarSource 從外部資料來源取得日K，用於同步本地日K資料
+type DailyBarSource interface {
+	// GetDailyBars 取得股票在日期區間內的日K，依日期由舊到新排序
+	GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error)
 }
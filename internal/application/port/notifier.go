@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// NotifierPort 主動推播訊息給使用者的管道，每個使用者平台各有一個實作
+type NotifierPort interface {
+	// Channel 推播管道對應的使用者平台
+	Channel() valueobject.UserType
+	// Push 推送訊息給指定帳號
+	Push(ctx context.Context, accountID string, payload dto.NotificationPayload) error
+	// IsPermanentError 判斷送出失敗是否無法透過重試解決
+	IsPermanentError(err error) bool
+	// IsRecipientUnavailable 判斷送出失敗是否因使用者封鎖 bot、帳號停用或聊天室已不存在
+	IsRecipientUnavailable(err error) bool
+}
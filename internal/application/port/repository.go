@@ -23,6 +23,8 @@ type UserReader interface {
 type UserWriter interface {
 	Create(ctx context.Context, user *entity.User) error
 	Update(ctx context.Context, user *entity.User) error
+	// UpdateStatus 更新使用者啟用狀態
+	UpdateStatus(ctx context.Context, userID uint, status bool) error
 	Delete(ctx context.Context, userID uint) error
 }
 
@@ -48,6 +50,7 @@ type SubscriptionWriter interface {
 	Create(ctx context.Context, subscription *entity.Subscription) error
 	Update(ctx context.Context, subscription *entity.Subscription) error
 	UpdateStatus(ctx context.Context, id uint, status bool) error
+	UpdateScheduleCron(ctx context.Context, id uint, scheduleCron string) error
 	Delete(ctx context.Context, id uint) error
 }
 
@@ -64,6 +67,12 @@ type StockSymbolReader interface {
 	GetBySymbolID(ctx context.Context, symbolID uint) ([]*entity.StockSymbol, error)
 	GetBySubscriptionAndSymbol(ctx context.Context, subscriptionID, symbolID uint) (*entity.StockSymbol, error)
 	GetMarketStats(ctx context.Context) (map[string]int, error)
+	// GetAll 取得所有股票代號
+	GetAll(ctx context.Context) ([]*entity.StockSymbol, error)
+	// SearchSymbols 以股票代號開頭或名稱包含關鍵字搜尋股票，代號完全相符者排在最前
+	SearchSymbols(ctx context.Context, keyword string, limit int) ([]*entity.StockSymbol, error)
+	// GetTrackedSymbols 取得有使用者訂閱或加入觀察清單的股票
+	GetTrackedSymbols(ctx context.Context) ([]*entity.StockSymbol, error)
 }
 
 type StockSymbolWriter interface {
@@ -113,6 +122,24 @@ type TradeDateWriter interface {
 	BatchCreateTradeDates(ctx context.Context, tradeDates []*entity.TradeDate) error
 }
 
+// DailyBarRepository 定義日K資料存取介面
+type DailyBarRepository interface {
+	DailyBarReader
+	DailyBarWriter
+}
+
+type DailyBarReader interface {
+	// GetByDateRange 取得股票在日期區間內的日K，依日期由舊到新排序
+	GetByDateRange(ctx context.Context, symbolID uint, startDate, endDate time.Time) ([]*entity.DailyBar, error)
+	// GetLatestDate 取得股票最新一筆日K的日期，沒有資料時回傳 nil
+	GetLatestDate(ctx context.Context, symbolID uint) (*time.Time, error)
+}
+
+type DailyBarWriter interface {
+	// BatchUpsert 批次寫入日K，同一股票同一天已有資料時更新價格
+	BatchUpsert(ctx context.Context, bars []*entity.DailyBar) error
+}
+
 // FeatureRepository 定義功能資料存取介面
 type FeatureRepository interface {
 	FeatureReader
@@ -144,3 +171,123 @@ type SyncMetadataRepository interface {
 	GetByMarket(ctx context.Context, market string) (*entity.SyncMetadata, error)
 	Upsert(ctx context.Context, metadata *entity.SyncMetadata) error
 }
+
+// PriceAlertRepository 定義價格提醒資料存取介面
+type PriceAlertRepository interface {
+	PriceAlertReader
+	PriceAlertWriter
+}
+
+type PriceAlertReader interface {
+	GetByID(ctx context.Context, id uint) (*entity.PriceAlert, error)
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.PriceAlert, error)
+	GetActiveAlerts(ctx context.Context) ([]*entity.PriceAlert, error)
+}
+
+type PriceAlertWriter interface {
+	Create(ctx context.Context, alert *entity.PriceAlert) error
+	UpdateTriggerState(ctx context.Context, id uint, triggered bool, triggeredAt *time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// PortfolioTradeRepository 定義投資組合交易紀錄資料存取介面
+type PortfolioTradeRepository interface {
+	PortfolioTradeReader
+	PortfolioTradeWriter
+}
+
+type PortfolioTradeReader interface {
+	GetByID(ctx context.Context, id uint) (*entity.PortfolioTrade, error)
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.PortfolioTrade, error)
+}
+
+type PortfolioTradeWriter interface {
+	Create(ctx context.Context, trade *entity.PortfolioTrade) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// TradingSettingRepository 定義使用者券商交易設定資料存取介面
+type TradingSettingRepository interface {
+	TradingSettingReader
+	TradingSettingWriter
+}
+
+type TradingSettingReader interface {
+	GetByUserID(ctx context.Context, userID uint) (*entity.TradingSetting, error)
+}
+
+type TradingSettingWriter interface {
+	Upsert(ctx context.Context, setting *entity.TradingSetting) error
+}
+
+// UserPreferenceRepository 定義使用者推播偏好設定資料存取介面
+type UserPreferenceRepository interface {
+	UserPreferenceReader
+	UserPreferenceWriter
+}
+
+type UserPreferenceReader interface {
+	GetByUserID(ctx context.Context, userID uint) (*entity.UserPreference, error)
+	// GetByUserIDs 批次取得偏好設定，未設定的使用者不包含在結果中
+	GetByUserIDs(ctx context.Context, userIDs []uint) ([]*entity.UserPreference, error)
+}
+
+type UserPreferenceWriter interface {
+	Upsert(ctx context.Context, preference *entity.UserPreference) error
+}
+
+// NotificationEventRepository 定義通知事件資料存取介面
+type NotificationEventRepository interface {
+	NotificationEventReader
+	NotificationEventWriter
+}
+
+type NotificationEventReader interface {
+	GetByID(ctx context.Context, id uint) (*entity.NotificationEvent, error)
+	GetByUserID(ctx context.Context, userID uint) ([]*entity.NotificationEvent, error)
+	GetByFeatureID(ctx context.Context, featureID uint) ([]*entity.NotificationEvent, error)
+	GetBySubscriptionID(ctx context.Context, subscriptionID uint) ([]*entity.NotificationEvent, error)
+	GetBySymbolID(ctx context.Context, symbolID uint) ([]*entity.NotificationEvent, error)
+	List(ctx context.Context, offset, limit int) ([]*entity.NotificationEvent, error)
+	GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.NotificationEvent, error)
+	GetByUserAndFeature(ctx context.Context, userID, featureID uint) ([]*entity.NotificationEvent, error)
+	GetRecentEvents(ctx context.Context, userID uint, limit int) ([]*entity.NotificationEvent, error)
+}
+
+type NotificationEventWriter interface {
+	Create(ctx context.Context, event *entity.NotificationEvent) error
+	// CreateWithDelivery 在同一個交易中建立通知事件及其投遞紀錄，去重鍵已存在時回傳 domainerror.ErrAlreadyExists
+	CreateWithDelivery(ctx context.Context, event *entity.NotificationEvent, delivery *entity.NotificationDelivery) error
+	Update(ctx context.Context, event *entity.NotificationEvent) error
+	Delete(ctx context.Context, id uint) error
+	BatchCreate(ctx context.Context, events []*entity.NotificationEvent) error
+}
+
+// NotificationDeliveryRepository 定義通知投遞紀錄資料存取介面
+type NotificationDeliveryRepository interface {
+	NotificationDeliveryReader
+	NotificationDeliveryWriter
+}
+
+type NotificationDeliveryReader interface {
+	GetByID(ctx context.Context, id uint) (*entity.NotificationDelivery, error)
+	GetByEventID(ctx context.Context, eventID uint) ([]*entity.NotificationDelivery, error)
+	GetByChannel(ctx context.Context, channel valueobject.UserType) ([]*entity.NotificationDelivery, error)
+	GetByStatus(ctx context.Context, status valueobject.NotificationDeliveryStatus) ([]*entity.NotificationDelivery, error)
+	List(ctx context.Context, offset, limit int) ([]*entity.NotificationDelivery, error)
+	GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.NotificationDelivery, error)
+	// GetFailedDeliveries 依重試時間取得 before 之前到期、需要重試的失敗投遞紀錄，包含事件與使用者資料
+	GetFailedDeliveries(ctx context.Context, before time.Time, limit int) ([]*entity.NotificationDelivery, error)
+	// GetQueuedDeliveries 依建立順序取得待送出的投遞紀錄，略過遞延到 before 之後的紀錄，包含事件與使用者資料
+	GetQueuedDeliveries(ctx context.Context, before time.Time, limit int) ([]*entity.NotificationDelivery, error)
+	// CountSentByUserSince 計算 since 之後已送給使用者的投遞紀錄數量
+	CountSentByUserSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+}
+
+type NotificationDeliveryWriter interface {
+	Create(ctx context.Context, delivery *entity.NotificationDelivery) error
+	Update(ctx context.Context, delivery *entity.NotificationDelivery) error
+	UpdateStatus(ctx context.Context, id uint, status valueobject.NotificationDeliveryStatus) error
+	Delete(ctx context.Context, id uint) error
+	BatchCreate(ctx context.Context, deliveries []*entity.NotificationDelivery) error
+}
@@ -10,4 +10,6 @@ import (
 // UserAccountPort 提供 bot usecase 查詢或建立使用者的能力。
 type UserAccountPort interface {
 	GetOrCreate(ctx context.Context, accountID string, userType valueobject.UserType) (*entity.User, error)
+	// Deactivate 停用使用者，不再推播；使用者不存在時不做任何事
+	Deactivate(ctx context.Context, accountID string, userType valueobject.UserType) error
 }
@@ -22,4 +22,6 @@ type UserSubscriptionPort interface {
 	DeleteUserSubscriptionStock(ctx context.Context, userID uint, stockSymbol string) error
 	// 刪除使用者訂閱項目
 	DeleteUserSubscriptionItem(ctx context.Context, userID uint, item valueobject.SubscriptionType) error
+	// 更新使用者訂閱項目的推播排程，空字串代表使用預設排程
+	UpdateUserSubscriptionSchedule(ctx context.Context, userID uint, item valueobject.SubscriptionType, scheduleCron string) error
 }
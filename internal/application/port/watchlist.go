@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+// WatchlistPort 提供使用者具名觀察清單的存取能力
+type WatchlistPort interface {
+	// 依排序取得使用者所有觀察清單（包含股票）
+	GetWatchlists(ctx context.Context, userID uint) ([]*entity.Watchlist, error)
+	// 依名稱取得觀察清單（包含股票），不存在時回傳 nil
+	GetWatchlistByName(ctx context.Context, userID uint, name string) (*entity.Watchlist, error)
+	// 建立觀察清單，成功後回填 ID
+	CreateWatchlist(ctx context.Context, watchlist *entity.Watchlist) error
+	// 重新命名觀察清單
+	RenameWatchlist(ctx context.Context, watchlistID uint, name string) error
+	// 刪除觀察清單及其所有股票
+	DeleteWatchlist(ctx context.Context, watchlistID uint) error
+	// 新增股票至觀察清單
+	AddWatchlistItem(ctx context.Context, item *entity.WatchlistItem) error
+	// 從觀察清單移除股票
+	DeleteWatchlistItem(ctx context.Context, watchlistID uint, symbolID uint) error
+	// 將股票移動到另一個觀察清單
+	MoveWatchlistItem(ctx context.Context, itemID uint, toWatchlistID uint, sortOrder int) error
+	// 更新清單內股票的排序
+	UpdateWatchlistItemOrders(ctx context.Context, items []*entity.WatchlistItem) error
+}
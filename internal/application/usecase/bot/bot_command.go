@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
@@ -14,7 +17,7 @@ import (
 )
 
 type BotCommandUsecase interface {
-	GetUseGuideMessage() string
+	GetUseGuideMessage(userType valueobject.UserType) string
 	GetDailyMarketInfo(ctx context.Context, userType valueobject.UserType, count int) (string, error)
 	GetStockPerformance(ctx context.Context, userType valueobject.UserType, symbol string) (string, error)
 	GetStockPerformanceChart(ctx context.Context, symbol string) (*dto.ChartAsset, error)
@@ -27,7 +30,31 @@ type BotCommandUsecase interface {
 	UnsubscribeStock(ctx context.Context, userID uint, symbol string) (string, error)
 	SubscribedItems(ctx context.Context, userID uint, item valueobject.SubscriptionType) (string, error)
 	UnsubscribedItems(ctx context.Context, userID uint, item valueobject.SubscriptionType) (string, error)
-	GetSubscribed(ctx context.Context, userID uint) (string, error)
+	GetSubscribed(ctx context.Context, userType valueobject.UserType, userID uint) (*dto.CommandMessage, error)
+	GetSubscriptionSchedules(ctx context.Context, userType valueobject.UserType, userID uint) (string, error)
+	SetSubscriptionSchedule(ctx context.Context, userID uint, item valueobject.SubscriptionType, schedule string) (string, error)
+	AddPriceAlert(ctx context.Context, userID uint, symbol string, operator valueobject.AlertOperator, targetPrice float64) (string, error)
+	AddPercentageAlert(ctx context.Context, userID uint, symbol string, alertType valueobject.AlertType, thresholdPercent float64) (string, error)
+	GetPriceAlerts(ctx context.Context, userType valueobject.UserType, userID uint) (string, error)
+	DeletePriceAlert(ctx context.Context, userID uint, alertID uint) (string, error)
+	WatchStock(ctx context.Context, userID uint, symbol string, listName string) (string, error)
+	UnwatchStock(ctx context.Context, userID uint, symbol string, listName string) (string, error)
+	GetWatchlist(ctx context.Context, userType valueobject.UserType, userID uint, listName string) (string, error)
+	CreateWatchlist(ctx context.Context, userID uint, name string) (string, error)
+	RenameWatchlist(ctx context.Context, userID uint, oldName string, newName string) (string, error)
+	DeleteWatchlist(ctx context.Context, userID uint, name string) (string, error)
+	MoveWatchlistStock(ctx context.Context, userID uint, symbol string, fromName string, toName string) (string, error)
+	ReorderWatchlistStock(ctx context.Context, userID uint, listName string, symbol string, position int) (string, error)
+	RecordTrade(ctx context.Context, userID uint, symbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) (string, error)
+	GetPortfolio(ctx context.Context, userType valueobject.UserType, userID uint) (string, error)
+	GetPortfolioTrades(ctx context.Context, userType valueobject.UserType, userID uint) (string, error)
+	DeletePortfolioTrade(ctx context.Context, userID uint, tradeID uint) (string, error)
+	GetTradingSetting(ctx context.Context, userType valueobject.UserType, userID uint) (string, error)
+	UpdateTradingSetting(ctx context.Context, userID uint, feeDiscount float64, minFee *float64) (string, error)
+	GetDividendIncome(ctx context.Context, userType valueobject.UserType, userID uint) (string, error)
+	GetDividendCalendar(ctx context.Context, userType valueobject.UserType, symbol string) (string, error)
+	GetUserPreference(ctx context.Context, userType valueobject.UserType, userID uint) (*dto.CommandMessage, error)
+	UpdateUserPreference(ctx context.Context, userID uint, change *dto.UserPreferenceChange) (string, error)
 	GetStockNewsForLine(ctx context.Context, symbol string) (*dto.LineStockNewsMessage, error)
 	GetStockNewsForTelegram(ctx context.Context, symbol string) (*dto.TgStockNewsMessage, error)
 }
@@ -38,6 +65,10 @@ type botCommandUsecase struct {
 	marketDataUsecase       stock.MarketDataUsecase
 	marketChartUsecase      stock.MarketChartUsecase
 	userSubscriptionUsecase user.UserSubscriptionUsecase
+	userPriceAlertUsecase   user.UserPriceAlertUsecase
+	userWatchlistUsecase    user.UserWatchlistUsecase
+	userPortfolioUsecase    user.UserPortfolioUsecase
+	userPreferenceUsecase   user.UserPreferenceUsecase
 	formatterPort           port.FormatterPort
 }
 
@@ -46,17 +77,25 @@ func NewBotCommandUsecase(
 	marketDataUsecase stock.MarketDataUsecase,
 	marketChartUsecase stock.MarketChartUsecase,
 	userSubscriptionUsecase user.UserSubscriptionUsecase,
+	userPriceAlertUsecase user.UserPriceAlertUsecase,
+	userWatchlistUsecase user.UserWatchlistUsecase,
+	userPortfolioUsecase user.UserPortfolioUsecase,
+	userPreferenceUsecase user.UserPreferenceUsecase,
 ) BotCommandUsecase {
 	return &botCommandUsecase{
 		formatterPort:           formatterPort,
 		marketDataUsecase:       marketDataUsecase,
 		marketChartUsecase:      marketChartUsecase,
 		userSubscriptionUsecase: userSubscriptionUsecase,
+		userPriceAlertUsecase:   userPriceAlertUsecase,
+		userWatchlistUsecase:    userWatchlistUsecase,
+		userPortfolioUsecase:    userPortfolioUsecase,
+		userPreferenceUsecase:   userPreferenceUsecase,
 	}
 }
 
-func (u *botCommandUsecase) GetUseGuideMessage() string {
-	text := `台股機器人指令指南🤖
+// useGuide 指令說明
+const useGuide = `台股機器人指令指南🤖
 
 	📊 圖表指令
 	- /k [股票代碼] - K線圖 (含月均價、最高最低價標示、成交量)
@@ -68,6 +107,7 @@ func (u *botCommandUsecase) GetUseGuideMessage() string {
 	- /d [股票代碼] [日期] - 查詢指定日期股價 (格式: YYYY-MM-DD)
 	- /i [股票代碼] - 查詢公司資訊
 	- /n [股票代碼] - 查詢股票新聞
+	- /div [股票代碼] - 查詢歷年股利及即將到來的除權息日
 	
 	📊 市場總覽指令/
 	- /m - 查詢最新大盤資訊 (預設1筆)
@@ -80,14 +120,67 @@ func (u *botCommandUsecase) GetUseGuideMessage() string {
 	- /sub [項目] - 訂閱功能
 	- /unsub [項目] - 取消訂閱功能
 	- /list - 查詢已訂閱功能及股票
+	- /schedule - 查詢各訂閱項目的推播時間
+	- /schedule [項目] [HH:MM] - 設定每日推播時間
+	- /schedule [項目] [cron] - 以 cron 表達式設定推播排程
+	- /schedule [項目] morning - 改為次一交易日早上推播
+	- /schedule [項目] reset - 恢復預設推播時間
+
+	⏰ 價格提醒
+	- /alert [股票代碼] > [價格] - 收盤價高於指定價格時提醒
+	- /alert [股票代碼] < [價格] - 收盤價低於指定價格時提醒
+	- /alert [股票代碼] move [百分比] - 收盤漲跌幅超過指定百分比時提醒
+	- /alert [股票代碼] gap [百分比] - 開盤跳空超過指定百分比時提醒
+	- /alert - 查詢已設定的提醒
+	- /alert del [編號] - 刪除提醒
+
+	👀 觀察清單
+	- /watch [股票代碼] [清單] - 加入觀察清單 (未指定清單時加入第一個清單)
+	- /unwatch [股票代碼] [清單] - 移出觀察清單
+	- /wl - 查詢所有觀察清單收盤行情
+	- /wl [清單] - 查詢指定觀察清單
+	- /wl new [清單] - 建立觀察清單
+	- /wl rename [舊名稱] [新名稱] - 重新命名觀察清單
+	- /wl del [清單] - 刪除觀察清單
+	- /wl mv [股票代碼] [來源清單] [目標清單] - 移動股票到其他清單
+	- /wl order [清單] [股票代碼] [位置] - 調整股票在清單中的順序
+
+	💼 投資組合
+	- /buy [股票代碼] [股數] [價格] [日期] - 記錄買進 (日期可省略，預設今天)
+	- /sell [股票代碼] [股數] [價格] [日期] - 記錄賣出
+	- /pf - 查詢持股均價、市值、未實現損益及權重
+	- /pf log - 查詢交易紀錄
+	- /pf del [編號] - 刪除交易紀錄
+	- /pf fee [折扣] [最低手續費] - 查詢或設定券商手續費折扣
+	- /income - 查詢今年已領及預估年度股利
+
+	⚙️ 推播設定
+	- /settings - 查詢推播偏好設定
+	- /settings quiet [HH:MM] [HH:MM] - 設定勿擾時段，時段內的推播延後送出
+	- /settings quiet off - 關閉勿擾時段
+	- /settings tz [時區] - 設定時區 (例如 Asia/Taipei)
+	- /settings lang [zh-TW|en] - 設定偏好語言
+	- /settings digest [on|off] - 開啟或關閉每日摘要
+	- /settings max [則數] - 設定每日推播上限，0 為不限制
 	
 	💡 使用範例：
 	/k 2330 - 台積電K線圖
 	/p 0050 - 元大台灣50績效圖表
 	/r 2330 - 台積電月營收圖表
 	/d 2330 2025-01-15 - 查詢台積電指定日期股價
-	/m 3 - 查詢最新3筆大盤資訊`
-	return text
+	/m 3 - 查詢最新3筆大盤資訊
+	/schedule 2 08:30 - 每日 08:30 推播新聞
+	/settings quiet 22:00 07:00 - 晚上十點到早上七點不推播
+	/alert 2330 > 1100 - 台積電收盤價突破1100時提醒
+	/buy 2330 1000 985.5 2025-01-10 - 記錄買進台積電1000股`
+
+// GetUseGuideMessage 依平台回傳指令說明
+func (u *botCommandUsecase) GetUseGuideMessage(userType valueobject.UserType) string {
+	// Telegram 以 HTML 模式送出，需跳脫 < > 等字元
+	if userType == valueobject.UserTypeTelegram {
+		return html.EscapeString(useGuide)
+	}
+	return useGuide
 }
 
 func (u *botCommandUsecase) GetDailyMarketInfo(ctx context.Context, userType valueobject.UserType, count int) (string, error) {
@@ -267,14 +360,304 @@ func (u *botCommandUsecase) UnsubscribedItems(ctx context.Context, userID uint,
 	return result, nil
 }
 
-func (u *botCommandUsecase) GetSubscribed(ctx context.Context, userID uint) (string, error) {
+// GetSubscribed 查詢已訂閱的功能及股票，並附上每個訂閱的取消按鈕
+func (u *botCommandUsecase) GetSubscribed(ctx context.Context, userType valueobject.UserType, userID uint) (*dto.CommandMessage, error) {
 	stocks, err := u.userSubscriptionUsecase.GetUserSubscriptionStockList(ctx, userID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	items, err := u.userSubscriptionUsecase.GetUserSubscriptionItemList(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
+
+	shortcuts := make([]dto.CommandShortcut, 0, len(stocks)+len(items))
+	for _, item := range items {
+		if item.Status {
+			shortcuts = append(shortcuts, dto.CommandShortcut{
+				Label:   "❌ " + item.Item.GetName(),
+				Command: fmt.Sprintf("/unsub %d", item.Item),
+			})
+		}
+	}
+	for _, stock := range stocks {
+		if stock.Status {
+			shortcuts = append(shortcuts, dto.CommandShortcut{
+				Label:   fmt.Sprintf("❌ %s %s", stock.Symbol, stock.Name),
+				Command: "/del " + stock.Symbol,
+			})
+		}
+	}
+
+	return &dto.CommandMessage{
+		Text:      u.formatterPort.FormatSubscribed(stocks, items, userType),
+		Shortcuts: shortcuts,
+	}, nil
+}
+
+func (u *botCommandUsecase) GetSubscriptionSchedules(ctx context.Context, userType valueobject.UserType, userID uint) (string, error) {
 	items, err := u.userSubscriptionUsecase.GetUserSubscriptionItemList(ctx, userID)
 	if err != nil {
 		return "", err
 	}
-	return u.formatterPort.FormatSubscribed(stocks, items), nil
+	return u.formatterPort.FormatSubscriptionSchedules(items, userType), nil
+}
+
+func (u *botCommandUsecase) SetSubscriptionSchedule(ctx context.Context, userID uint, item valueobject.SubscriptionType, schedule string) (string, error) {
+	return u.userSubscriptionUsecase.SetUserSubscriptionSchedule(ctx, userID, item, schedule)
+}
+
+func (u *botCommandUsecase) AddPriceAlert(ctx context.Context, userID uint, symbol string, operator valueobject.AlertOperator, targetPrice float64) (string, error) {
+	return u.userPriceAlertUsecase.AddPriceAlert(ctx, userID, symbol, operator, targetPrice)
+}
+
+func (u *botCommandUsecase) AddPercentageAlert(ctx context.Context, userID uint, symbol string, alertType valueobject.AlertType, thresholdPercent float64) (string, error) {
+	return u.userPriceAlertUsecase.AddPercentageAlert(ctx, userID, symbol, alertType, thresholdPercent)
+}
+
+func (u *botCommandUsecase) GetPriceAlerts(ctx context.Context, userType valueobject.UserType, userID uint) (string, error) {
+	alerts, err := u.userPriceAlertUsecase.GetPriceAlertList(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.formatterPort.FormatPriceAlertList(alerts, userType), nil
+}
+
+func (u *botCommandUsecase) DeletePriceAlert(ctx context.Context, userID uint, alertID uint) (string, error) {
+	return u.userPriceAlertUsecase.DeletePriceAlert(ctx, userID, alertID)
+}
+
+func (u *botCommandUsecase) WatchStock(ctx context.Context, userID uint, symbol string, listName string) (string, error) {
+	return u.userWatchlistUsecase.AddWatchlistStock(ctx, userID, listName, symbol)
+}
+
+func (u *botCommandUsecase) UnwatchStock(ctx context.Context, userID uint, symbol string, listName string) (string, error) {
+	return u.userWatchlistUsecase.DeleteWatchlistStock(ctx, userID, listName, symbol)
+}
+
+func (u *botCommandUsecase) GetWatchlist(ctx context.Context, userType valueobject.UserType, userID uint, listName string) (string, error) {
+	watchlists, err := u.userWatchlistUsecase.GetWatchlists(ctx, userID, listName)
+	if err != nil {
+		return "", err
+	}
+
+	// 同一檔股票可能出現在多個清單，只查詢一次
+	prices := make(map[string]*dto.StockPrice)
+	result := make([]*dto.Watchlist, 0, len(watchlists))
+	for _, watchlist := range watchlists {
+		view := &dto.Watchlist{
+			Name:   watchlist.Name,
+			Quotes: make([]*dto.WatchlistQuote, 0, len(watchlist.Items)),
+		}
+
+		for _, item := range watchlist.Items {
+			if item.StockSymbol == nil {
+				continue
+			}
+
+			symbol := item.StockSymbol.Symbol
+			price, ok := prices[symbol]
+			if !ok {
+				// 單檔取價失敗時仍顯示該股票，避免整張表無法產生
+				price, err = u.marketDataUsecase.GetStockPrice(ctx, symbol, nil)
+				if err != nil {
+					price = nil
+				}
+				prices[symbol] = price
+			}
+
+			quote := &dto.WatchlistQuote{
+				Symbol: symbol,
+				Name:   item.StockSymbol.Name,
+			}
+			if price != nil {
+				quote.Date = price.Date
+				quote.ClosePrice = price.ClosePrice
+				quote.ChangeAmount = price.ChangeAmount
+				quote.ChangeRate = price.ChangeRate
+				quote.UpDownSign = price.UpDownSign
+				quote.Volume = price.Volume
+				quote.HasQuote = true
+			}
+			view.Quotes = append(view.Quotes, quote)
+		}
+		result = append(result, view)
+	}
+
+	return u.formatterPort.FormatWatchlist(result, userType), nil
+}
+
+func (u *botCommandUsecase) CreateWatchlist(ctx context.Context, userID uint, name string) (string, error) {
+	return u.userWatchlistUsecase.CreateWatchlist(ctx, userID, name)
+}
+
+func (u *botCommandUsecase) RenameWatchlist(ctx context.Context, userID uint, oldName string, newName string) (string, error) {
+	return u.userWatchlistUsecase.RenameWatchlist(ctx, userID, oldName, newName)
+}
+
+func (u *botCommandUsecase) DeleteWatchlist(ctx context.Context, userID uint, name string) (string, error) {
+	return u.userWatchlistUsecase.DeleteWatchlist(ctx, userID, name)
+}
+
+func (u *botCommandUsecase) MoveWatchlistStock(ctx context.Context, userID uint, symbol string, fromName string, toName string) (string, error) {
+	return u.userWatchlistUsecase.MoveWatchlistStock(ctx, userID, symbol, fromName, toName)
+}
+
+func (u *botCommandUsecase) ReorderWatchlistStock(ctx context.Context, userID uint, listName string, symbol string, position int) (string, error) {
+	return u.userWatchlistUsecase.ReorderWatchlistStock(ctx, userID, listName, symbol, position)
+}
+
+func (u *botCommandUsecase) RecordTrade(ctx context.Context, userID uint, symbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) (string, error) {
+	return u.userPortfolioUsecase.AddTrade(ctx, userID, symbol, side, quantity, price, tradeDate)
+}
+
+func (u *botCommandUsecase) GetPortfolio(ctx context.Context, userType valueobject.UserType, userID uint) (string, error) {
+	portfolio, err := u.userPortfolioUsecase.GetPortfolio(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.formatterPort.FormatPortfolio(portfolio, userType), nil
+}
+
+func (u *botCommandUsecase) GetPortfolioTrades(ctx context.Context, userType valueobject.UserType, userID uint) (string, error) {
+	trades, err := u.userPortfolioUsecase.GetTradeList(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.formatterPort.FormatPortfolioTrades(trades, userType), nil
+}
+
+func (u *botCommandUsecase) DeletePortfolioTrade(ctx context.Context, userID uint, tradeID uint) (string, error) {
+	return u.userPortfolioUsecase.DeleteTrade(ctx, userID, tradeID)
+}
+
+func (u *botCommandUsecase) GetTradingSetting(ctx context.Context, userType valueobject.UserType, userID uint) (string, error) {
+	setting, err := u.userPortfolioUsecase.GetTradingSetting(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.formatterPort.FormatTradingSetting(setting, userType), nil
+}
+
+func (u *botCommandUsecase) UpdateTradingSetting(ctx context.Context, userID uint, feeDiscount float64, minFee *float64) (string, error) {
+	return u.userPortfolioUsecase.UpdateTradingSetting(ctx, userID, feeDiscount, minFee)
+}
+
+func (u *botCommandUsecase) GetDividendIncome(ctx context.Context, userType valueobject.UserType, userID uint) (string, error) {
+	income, err := u.userPortfolioUsecase.GetDividendIncome(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.formatterPort.FormatDividendIncome(income, userType), nil
+}
+
+func (u *botCommandUsecase) GetDividendCalendar(ctx context.Context, userType valueobject.UserType, symbol string) (string, error) {
+	calendar, err := u.marketDataUsecase.GetDividendCalendar(ctx, symbol)
+	if err != nil {
+		return "", err
+	}
+	return u.formatterPort.FormatDividendCalendar(calendar, userType), nil
+}
+
+func (u *botCommandUsecase) GetUserPreference(ctx context.Context, userType valueobject.UserType, userID uint) (*dto.CommandMessage, error) {
+	preference, err := u.userPreferenceUsecase.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CommandMessage{
+		Text:      u.formatterPort.FormatUserPreference(preference, userType),
+		Shortcuts: userPreferenceShortcuts(preference),
+	}, nil
+}
+
+func (u *botCommandUsecase) UpdateUserPreference(ctx context.Context, userID uint, change *dto.UserPreferenceChange) (string, error) {
+	switch change.Setting {
+	case dto.UserPreferenceSettingQuietHours:
+		if change.QuietHoursStart == "" && change.QuietHoursEnd == "" {
+			return u.userPreferenceUsecase.DisableQuietHours(ctx, userID)
+		}
+		return u.userPreferenceUsecase.SetQuietHours(ctx, userID, change.QuietHoursStart, change.QuietHoursEnd)
+	case dto.UserPreferenceSettingTimezone:
+		return u.userPreferenceUsecase.SetTimezone(ctx, userID, change.Timezone)
+	case dto.UserPreferenceSettingLanguage:
+		return u.userPreferenceUsecase.SetLanguage(ctx, userID, change.Language)
+	case dto.UserPreferenceSettingDigest:
+		return u.userPreferenceUsecase.SetDigest(ctx, userID, change.Enabled)
+	case dto.UserPreferenceSettingMaxDailyPushes:
+		return u.userPreferenceUsecase.SetMaxDailyPushes(ctx, userID, change.MaxDailyPushes)
+	default:
+		return "", errors.New(userPreferenceUsage)
+	}
+}
+
+// userPreferenceUsage 偏好設定指令說明
+const userPreferenceUsage = "使用方式：\n/settings - 查詢推播偏好設定\n/settings quiet HH:MM HH:MM - 設定勿擾時段\n/settings quiet off - 關閉勿擾時段\n/settings tz 時區 - 設定時區，例如 Asia/Taipei\n/settings lang zh-TW|en - 設定偏好語言\n/settings digest on|off - 開啟或關閉每日摘要\n/settings max 則數 - 設定每日推播上限，0 為不限制"
+
+// userPreferenceShortcuts 依目前設定產生常用設定的切換按鈕
+func userPreferenceShortcuts(preference *dto.UserPreference) []dto.CommandShortcut {
+	shortcuts := make([]dto.CommandShortcut, 0, 4)
+	if preference.DigestEnabled {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "關閉每日摘要", Command: "/settings digest off"})
+	} else {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "開啟每日摘要", Command: "/settings digest on"})
+	}
+	if preference.QuietHoursStart != "" {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "關閉勿擾時段", Command: "/settings quiet off"})
+	} else {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "勿擾 22:00～07:00", Command: "/settings quiet 22:00 07:00"})
+	}
+	if preference.MaxDailyPushes > 0 {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "取消每日上限", Command: "/settings max 0"})
+	} else {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "每日最多 5 則", Command: "/settings max 5"})
+	}
+	if preference.Language == valueobject.LanguageEnglish {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "繁體中文", Command: "/settings lang zh-TW"})
+	} else {
+		shortcuts = append(shortcuts, dto.CommandShortcut{Label: "English", Command: "/settings lang en"})
+	}
+	return shortcuts
+}
+
+// parseUserPreferenceChange 解析 /settings 之後的參數，參數錯誤時回傳含使用方式的錯誤
+func parseUserPreferenceChange(args []string) (*dto.UserPreferenceChange, error) {
+	if len(args) < 2 {
+		return nil, errors.New(userPreferenceUsage)
+	}
+
+	change := &dto.UserPreferenceChange{Setting: dto.UserPreferenceSetting(strings.ToLower(args[0]))}
+	value := strings.ToLower(args[1])
+	switch change.Setting {
+	case dto.UserPreferenceSettingQuietHours:
+		if len(args) == 2 && value == "off" {
+			return change, nil
+		}
+		if len(args) != 3 {
+			return nil, errors.New(userPreferenceUsage)
+		}
+		change.QuietHoursStart = args[1]
+		change.QuietHoursEnd = args[2]
+	case dto.UserPreferenceSettingTimezone:
+		change.Timezone = args[1]
+	case dto.UserPreferenceSettingLanguage:
+		change.Language = args[1]
+	case dto.UserPreferenceSettingDigest:
+		switch value {
+		case "on":
+			change.Enabled = true
+		case "off":
+			change.Enabled = false
+		default:
+			return nil, errors.New("請輸入 on 或 off\n\n" + userPreferenceUsage)
+		}
+	case dto.UserPreferenceSettingMaxDailyPushes:
+		maxDailyPushes, err := strconv.Atoi(args[1])
+		if err != nil {
+			return nil, errors.New("請輸入有效的每日推播上限\n\n" + userPreferenceUsage)
+		}
+		change.MaxDailyPushes = maxDailyPushes
+	default:
+		return nil, errors.New(userPreferenceUsage)
+	}
+	return change, nil
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/line/line-bot-sdk-go/v8/linebot"
+	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
 	linebotInfra "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/line"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/imgbb"
@@ -20,7 +22,36 @@ type LineCommandUsecase interface {
 	GetStockRevenueChart(ctx context.Context, symbol string, replyToken string) error
 	GetHistoricalCandlesChart(ctx context.Context, symbol string, replyToken string) error
 	GetStockCompanyInfo(ctx context.Context, symbol string, replyToken string) error
+	GetDividendCalendar(ctx context.Context, symbol string, replyToken string) error
 	GetStockNews(ctx context.Context, symbol string, replyToken string) error
+	SubscribeStock(ctx context.Context, replyToken string, userID uint, symbol string) error
+	UnsubscribeStock(ctx context.Context, replyToken string, userID uint, symbol string) error
+	SubscribedItems(ctx context.Context, replyToken string, userID uint, item valueobject.SubscriptionType) error
+	UnsubscribedItems(ctx context.Context, replyToken string, userID uint, item valueobject.SubscriptionType) error
+	GetSubscribed(ctx context.Context, replyToken string, userID uint) error
+	GetSubscriptionSchedules(ctx context.Context, replyToken string, userID uint) error
+	SetSubscriptionSchedule(ctx context.Context, replyToken string, userID uint, item valueobject.SubscriptionType, schedule string) error
+	AddPriceAlert(ctx context.Context, replyToken string, userID uint, symbol string, operator valueobject.AlertOperator, targetPrice float64) error
+	AddPercentageAlert(ctx context.Context, replyToken string, userID uint, symbol string, alertType valueobject.AlertType, thresholdPercent float64) error
+	GetPriceAlerts(ctx context.Context, replyToken string, userID uint) error
+	DeletePriceAlert(ctx context.Context, replyToken string, userID uint, alertID uint) error
+	WatchStock(ctx context.Context, replyToken string, userID uint, symbol string, listName string) error
+	UnwatchStock(ctx context.Context, replyToken string, userID uint, symbol string, listName string) error
+	GetWatchlist(ctx context.Context, replyToken string, userID uint, listName string) error
+	CreateWatchlist(ctx context.Context, replyToken string, userID uint, name string) error
+	RenameWatchlist(ctx context.Context, replyToken string, userID uint, oldName string, newName string) error
+	DeleteWatchlist(ctx context.Context, replyToken string, userID uint, name string) error
+	MoveWatchlistStock(ctx context.Context, replyToken string, userID uint, symbol string, fromName string, toName string) error
+	ReorderWatchlistStock(ctx context.Context, replyToken string, userID uint, listName string, symbol string, position int) error
+	RecordTrade(ctx context.Context, replyToken string, userID uint, symbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) error
+	GetPortfolio(ctx context.Context, replyToken string, userID uint) error
+	GetPortfolioTrades(ctx context.Context, replyToken string, userID uint) error
+	DeletePortfolioTrade(ctx context.Context, replyToken string, userID uint, tradeID uint) error
+	GetTradingSetting(ctx context.Context, replyToken string, userID uint) error
+	UpdateTradingSetting(ctx context.Context, replyToken string, userID uint, feeDiscount float64, minFee *float64) error
+	GetDividendIncome(ctx context.Context, replyToken string, userID uint) error
+	GetUserPreference(ctx context.Context, replyToken string, userID uint) error
+	UpdateUserPreference(ctx context.Context, replyToken string, userID uint, change *dto.UserPreferenceChange) error
 }
 
 var _ LineCommandUsecase = (*lineCommandUsecase)(nil)
@@ -46,7 +77,7 @@ func NewLineBotCommandUsecase(
 }
 
 func (u *lineCommandUsecase) GetUseGuideMessage(replyToken string) error {
-	message := u.botCommandUsecase.GetUseGuideMessage()
+	message := u.botCommandUsecase.GetUseGuideMessage(UserTypeLine)
 	return u.client.ReplyMessage(replyToken, message)
 }
 
@@ -129,6 +160,14 @@ func (u *lineCommandUsecase) GetStockCompanyInfo(ctx context.Context, symbol str
 	return u.client.ReplyMessage(replyToken, message)
 }
 
+func (u *lineCommandUsecase) GetDividendCalendar(ctx context.Context, symbol string, replyToken string) error {
+	message, err := u.botCommandUsecase.GetDividendCalendar(ctx, UserTypeLine, symbol)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, message)
+}
+
 func (u *lineCommandUsecase) GetStockNews(ctx context.Context, symbol string, replyToken string) error {
 	newsMessage, err := u.botCommandUsecase.GetStockNewsForLine(ctx, symbol)
 	if err != nil {
@@ -148,3 +187,243 @@ func (u *lineCommandUsecase) GetStockNews(ctx context.Context, symbol string, re
 	// 最終降級：純文字
 	return u.client.ReplyMessage(replyToken, newsMessage.Text)
 }
+
+func (u *lineCommandUsecase) SubscribeStock(ctx context.Context, replyToken string, userID uint, symbol string) error {
+	result, err := u.botCommandUsecase.SubscribeStock(ctx, userID, symbol)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) UnsubscribeStock(ctx context.Context, replyToken string, userID uint, symbol string) error {
+	result, err := u.botCommandUsecase.UnsubscribeStock(ctx, userID, symbol)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) SubscribedItems(ctx context.Context, replyToken string, userID uint, item valueobject.SubscriptionType) error {
+	result, err := u.botCommandUsecase.SubscribedItems(ctx, userID, item)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) UnsubscribedItems(ctx context.Context, replyToken string, userID uint, item valueobject.SubscriptionType) error {
+	result, err := u.botCommandUsecase.UnsubscribedItems(ctx, userID, item)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetSubscribed(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetSubscribed(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result.Text)
+}
+
+func (u *lineCommandUsecase) GetSubscriptionSchedules(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetSubscriptionSchedules(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) SetSubscriptionSchedule(ctx context.Context, replyToken string, userID uint, item valueobject.SubscriptionType, schedule string) error {
+	result, err := u.botCommandUsecase.SetSubscriptionSchedule(ctx, userID, item, schedule)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) AddPriceAlert(ctx context.Context, replyToken string, userID uint, symbol string, operator valueobject.AlertOperator, targetPrice float64) error {
+	result, err := u.botCommandUsecase.AddPriceAlert(ctx, userID, symbol, operator, targetPrice)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) AddPercentageAlert(ctx context.Context, replyToken string, userID uint, symbol string, alertType valueobject.AlertType, thresholdPercent float64) error {
+	result, err := u.botCommandUsecase.AddPercentageAlert(ctx, userID, symbol, alertType, thresholdPercent)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetPriceAlerts(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetPriceAlerts(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) DeletePriceAlert(ctx context.Context, replyToken string, userID uint, alertID uint) error {
+	result, err := u.botCommandUsecase.DeletePriceAlert(ctx, userID, alertID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) WatchStock(ctx context.Context, replyToken string, userID uint, symbol string, listName string) error {
+	result, err := u.botCommandUsecase.WatchStock(ctx, userID, symbol, listName)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) UnwatchStock(ctx context.Context, replyToken string, userID uint, symbol string, listName string) error {
+	result, err := u.botCommandUsecase.UnwatchStock(ctx, userID, symbol, listName)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetWatchlist(ctx context.Context, replyToken string, userID uint, listName string) error {
+	result, err := u.botCommandUsecase.GetWatchlist(ctx, UserTypeLine, userID, listName)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) CreateWatchlist(ctx context.Context, replyToken string, userID uint, name string) error {
+	result, err := u.botCommandUsecase.CreateWatchlist(ctx, userID, name)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) RenameWatchlist(ctx context.Context, replyToken string, userID uint, oldName string, newName string) error {
+	result, err := u.botCommandUsecase.RenameWatchlist(ctx, userID, oldName, newName)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) DeleteWatchlist(ctx context.Context, replyToken string, userID uint, name string) error {
+	result, err := u.botCommandUsecase.DeleteWatchlist(ctx, userID, name)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) MoveWatchlistStock(ctx context.Context, replyToken string, userID uint, symbol string, fromName string, toName string) error {
+	result, err := u.botCommandUsecase.MoveWatchlistStock(ctx, userID, symbol, fromName, toName)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) ReorderWatchlistStock(ctx context.Context, replyToken string, userID uint, listName string, symbol string, position int) error {
+	result, err := u.botCommandUsecase.ReorderWatchlistStock(ctx, userID, listName, symbol, position)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) RecordTrade(ctx context.Context, replyToken string, userID uint, symbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) error {
+	result, err := u.botCommandUsecase.RecordTrade(ctx, userID, symbol, side, quantity, price, tradeDate)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetPortfolio(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetPortfolio(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetPortfolioTrades(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetPortfolioTrades(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) DeletePortfolioTrade(ctx context.Context, replyToken string, userID uint, tradeID uint) error {
+	result, err := u.botCommandUsecase.DeletePortfolioTrade(ctx, userID, tradeID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetTradingSetting(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetTradingSetting(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) UpdateTradingSetting(ctx context.Context, replyToken string, userID uint, feeDiscount float64, minFee *float64) error {
+	result, err := u.botCommandUsecase.UpdateTradingSetting(ctx, userID, feeDiscount, minFee)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetDividendIncome(ctx context.Context, replyToken string, userID uint) error {
+	result, err := u.botCommandUsecase.GetDividendIncome(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.client.ReplyMessage(replyToken, result)
+}
+
+func (u *lineCommandUsecase) GetUserPreference(ctx context.Context, replyToken string, userID uint) error {
+	return u.replyUserPreference(ctx, replyToken, userID, "")
+}
+
+// UpdateUserPreference 修改偏好設定後，連同最新設定與快速回覆按鈕一併回覆
+func (u *lineCommandUsecase) UpdateUserPreference(ctx context.Context, replyToken string, userID uint, change *dto.UserPreferenceChange) error {
+	result, err := u.botCommandUsecase.UpdateUserPreference(ctx, userID, change)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+	return u.replyUserPreference(ctx, replyToken, userID, result)
+}
+
+// replyUserPreference 回覆偏好設定及快速回覆按鈕，點擊按鈕即送出對應的指令
+func (u *lineCommandUsecase) replyUserPreference(ctx context.Context, replyToken string, userID uint, header string) error {
+	preference, err := u.botCommandUsecase.GetUserPreference(ctx, UserTypeLine, userID)
+	if err != nil {
+		return u.client.ReplyMessage(replyToken, err.Error())
+	}
+
+	actions := make([]*linebot.MessageAction, 0, len(preference.Shortcuts))
+	for _, shortcut := range preference.Shortcuts {
+		actions = append(actions, linebot.NewMessageAction(shortcut.Label, shortcut.Command))
+	}
+
+	text := preference.Text
+	if header != "" {
+		text = header + "\n\n" + text
+	}
+	return u.client.ReplyMessageWithQuickReply(replyToken, text, actions)
+}
@@ -2,20 +2,27 @@ package bot
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot"
+	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
 	linebotInfra "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/line"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 )
 
+// subscriptionItemUsage 訂閱項目說明
+const subscriptionItemUsage = "使用方式：\n/sub 項目 - 訂閱功能\n/unsub 項目 - 取消訂閱功能\n\n項目：\n1 - 當日個股資訊\n2 - 訂閱股票新聞\n3 - 當日市場成交行情\n4 - 當日交易量前20名\n5 - 除權息提醒\n6 - 每日摘要（合併當日推播為一則訊息）"
+
 // LineMessageProcessor 處理 LINE 訊息的路由和編排
 type LineMessageProcessor struct {
 	lineCommandUsecase LineCommandUsecase
+	symbolResolver     stock.SymbolResolver
 	userAccountPort    port.UserAccountPort
 	lineBotClient      *linebotInfra.LineBotClient
 	logger             logger.Logger
@@ -23,12 +30,14 @@ type LineMessageProcessor struct {
 
 func NewLineMessageProcessor(
 	lineCommandUsecase LineCommandUsecase,
+	symbolResolver stock.SymbolResolver,
 	userAccountPort port.UserAccountPort,
 	lineBotClient *linebotInfra.LineBotClient,
 	log logger.Logger,
 ) *LineMessageProcessor {
 	return &LineMessageProcessor{
 		lineCommandUsecase: lineCommandUsecase,
+		symbolResolver:     symbolResolver,
 		userAccountPort:    userAccountPort,
 		lineBotClient:      lineBotClient,
 		logger:             log,
@@ -50,7 +59,8 @@ func (p *LineMessageProcessor) ProcessTextMessage(ctx context.Context, event *li
 		logger.String("message", messageText))
 
 	// 確保使用者存在
-	if err := p.ensureUser(ctx, userID); err != nil {
+	accountID, err := p.ensureUser(ctx, userID)
+	if err != nil {
 		p.logger.Error("確保使用者存在失敗", logger.Error(err))
 	}
 
@@ -60,18 +70,52 @@ func (p *LineMessageProcessor) ProcessTextMessage(ctx context.Context, event *li
 		return p.lineBotClient.ReplyMessage(replyToken, "你說了: "+messageText)
 	}
 
+	// 股票名稱解析為代號；符合多檔股票時請使用者從快速回覆選擇
+	args := strings.Fields(messageText)[1:]
+	args, candidates := resolveSymbolArg(ctx, p.symbolResolver, p.logger, command, args)
+	if len(candidates) > 0 {
+		return p.replySymbolCandidates(replyToken, command, args, candidates)
+	}
+	command, arg1, arg2 = p.parseMessageArgs(strings.Join(append([]string{command}, args...), " "))
+
 	// 路由到對應的命令處理器
-	return p.routeCommand(ctx, command, arg1, arg2, replyToken)
+	return p.routeCommand(ctx, command, arg1, arg2, args, replyToken, accountID)
 }
 
-// ensureUser 確保使用者存在，不存在則建立
-func (p *LineMessageProcessor) ensureUser(ctx context.Context, userID string) error {
-	_, err := p.userAccountPort.GetOrCreate(ctx, userID, valueobject.UserTypeLine)
+// ProcessFollow 使用者加入或解除封鎖官方帳號時，建立或恢復使用者帳號
+func (p *LineMessageProcessor) ProcessFollow(ctx context.Context, event *linebot.Event) error {
+	_, err := p.ensureUser(ctx, event.Source.UserID)
 	return err
 }
 
+// ProcessUnfollow 使用者封鎖官方帳號時停用帳號，不再推播；LINE 的 Push API 錯誤回應無法辨識封鎖，只能由此事件得知
+func (p *LineMessageProcessor) ProcessUnfollow(ctx context.Context, event *linebot.Event) error {
+	p.logger.Info("LINE 使用者封鎖官方帳號", logger.String("user_id", event.Source.UserID))
+	return p.userAccountPort.Deactivate(ctx, event.Source.UserID, valueobject.UserTypeLine)
+}
+
+// replySymbolCandidates 列出符合的股票，點擊快速回覆即以該股票代號執行原本的指令
+func (p *LineMessageProcessor) replySymbolCandidates(replyToken, command string, args []string, candidates []dto.SymbolCandidate) error {
+	shortcuts := symbolCandidateShortcuts(command, args, candidates)
+	actions := make([]*linebot.MessageAction, 0, len(shortcuts))
+	for _, shortcut := range shortcuts {
+		actions = append(actions, linebot.NewMessageAction(shortcut.Label, shortcut.Command))
+	}
+	text := fmt.Sprintf("找到多檔符合「%s」的股票，請選擇：", args[symbolArgIndex(command, args)])
+	return p.lineBotClient.ReplyMessageWithQuickReply(replyToken, text, actions)
+}
+
+// ensureUser 確保使用者存在，不存在則建立，回傳使用者 ID
+func (p *LineMessageProcessor) ensureUser(ctx context.Context, userID string) (uint, error) {
+	user, err := p.userAccountPort.GetOrCreate(ctx, userID, valueobject.UserTypeLine)
+	if err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
 // routeCommand 路由命令到對應的處理器
-func (p *LineMessageProcessor) routeCommand(ctx context.Context, command, arg1, arg2, replyToken string) error {
+func (p *LineMessageProcessor) routeCommand(ctx context.Context, command, arg1, arg2 string, args []string, replyToken string, userID uint) error {
 	switch command {
 	case "/start":
 		return p.lineCommandUsecase.GetUseGuideMessage(replyToken)
@@ -91,6 +135,47 @@ func (p *LineMessageProcessor) routeCommand(ctx context.Context, command, arg1,
 		return p.handleDailyMarket(ctx, replyToken, arg1)
 	case "/n":
 		return p.lineCommandUsecase.GetStockNews(ctx, arg1, replyToken)
+	case "/add":
+		return p.handleSubscribeStock(ctx, replyToken, userID, arg1)
+	case "/del":
+		return p.handleUnsubscribeStock(ctx, replyToken, userID, arg1)
+	case "/sub":
+		return p.handleSubscribedItems(ctx, replyToken, userID, arg1)
+	case "/unsub":
+		return p.handleUnsubscribedItems(ctx, replyToken, userID, arg1)
+	case "/list":
+		if userID == 0 {
+			return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+		}
+		return p.lineCommandUsecase.GetSubscribed(ctx, replyToken, userID)
+	case "/schedule":
+		return p.handleSubscriptionSchedule(ctx, replyToken, userID, args)
+	case "/alert":
+		return p.handlePriceAlert(ctx, replyToken, userID, args)
+	case "/watch":
+		return p.handleWatchStock(ctx, replyToken, userID, arg1, arg2)
+	case "/unwatch":
+		return p.handleUnwatchStock(ctx, replyToken, userID, arg1, arg2)
+	case "/wl":
+		return p.handleWatchlist(ctx, replyToken, userID, args)
+	case "/buy":
+		return p.handleTrade(ctx, replyToken, userID, valueobject.TradeSideBuy, args)
+	case "/sell":
+		return p.handleTrade(ctx, replyToken, userID, valueobject.TradeSideSell, args)
+	case "/pf":
+		return p.handlePortfolio(ctx, replyToken, userID, args)
+	case "/div":
+		if arg1 == "" {
+			return p.sendError(replyToken, "請輸入股票代號\n\n使用方式：\n/div 股票代號 - 查詢歷年股利及即將到來的除權息日")
+		}
+		return p.lineCommandUsecase.GetDividendCalendar(ctx, arg1, replyToken)
+	case "/income":
+		if userID == 0 {
+			return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+		}
+		return p.lineCommandUsecase.GetDividendIncome(ctx, replyToken, userID)
+	case "/settings":
+		return p.handleUserPreference(ctx, replyToken, userID, args)
 		// default:
 		// 	return p.handleUnknownCommand(replyToken)
 	}
@@ -158,6 +243,251 @@ func (p *LineMessageProcessor) handleDailyMarket(ctx context.Context, replyToken
 	return p.lineCommandUsecase.GetDailyMarketInfo(ctx, replyToken, count)
 }
 
+func (p *LineMessageProcessor) handleSubscribeStock(ctx context.Context, replyToken string, userID uint, symbol string) error {
+	if symbol == "" {
+		return p.sendError(replyToken, "請輸入股票代號\n\n使用方式：\n/add 股票代號 - 訂閱股票")
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	return p.lineCommandUsecase.SubscribeStock(ctx, replyToken, userID, symbol)
+}
+
+func (p *LineMessageProcessor) handleUnsubscribeStock(ctx context.Context, replyToken string, userID uint, symbol string) error {
+	if symbol == "" {
+		return p.sendError(replyToken, "請輸入股票代號\n\n使用方式：\n/del 股票代號 - 取消訂閱股票")
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	return p.lineCommandUsecase.UnsubscribeStock(ctx, replyToken, userID, symbol)
+}
+
+func (p *LineMessageProcessor) handleSubscribedItems(ctx context.Context, replyToken string, userID uint, args string) error {
+	item, ok := p.parseSubscriptionType(args)
+	if !ok {
+		return p.sendError(replyToken, "請輸入有效的訂閱類型\n\n"+subscriptionItemUsage)
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	return p.lineCommandUsecase.SubscribedItems(ctx, replyToken, userID, item)
+}
+
+func (p *LineMessageProcessor) handleUnsubscribedItems(ctx context.Context, replyToken string, userID uint, args string) error {
+	item, ok := p.parseSubscriptionType(args)
+	if !ok {
+		return p.sendError(replyToken, "請輸入有效的訂閱類型\n\n"+subscriptionItemUsage)
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	return p.lineCommandUsecase.UnsubscribedItems(ctx, replyToken, userID, item)
+}
+
+func (p *LineMessageProcessor) handleSubscriptionSchedule(ctx context.Context, replyToken string, userID uint, args []string) error {
+	usage := "使用方式：\n/schedule - 查詢推播排程\n/schedule 項目 HH:MM - 設定每日推播時間\n/schedule 項目 cron - 以 cron 表達式設定排程\n/schedule 項目 morning - 次一交易日早上推播\n/schedule 項目 reset - 恢復預設推播時間\n例如：/schedule 3 14:00 或 /schedule 2 30 8 * * 1-5"
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	if len(args) == 0 {
+		return p.lineCommandUsecase.GetSubscriptionSchedules(ctx, replyToken, userID)
+	}
+	if len(args) < 2 {
+		return p.sendError(replyToken, usage)
+	}
+
+	item, ok := p.parseSubscriptionType(args[0])
+	if !ok {
+		return p.sendError(replyToken, "請輸入有效的訂閱類型\n\n"+usage)
+	}
+	return p.lineCommandUsecase.SetSubscriptionSchedule(ctx, replyToken, userID, item, strings.Join(args[1:], " "))
+}
+
+func (p *LineMessageProcessor) handlePriceAlert(ctx context.Context, replyToken string, userID uint, args []string) error {
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+
+	alert, err := parsePriceAlertArgs(args)
+	if err != nil {
+		return p.sendError(replyToken, err.Error())
+	}
+
+	switch {
+	case alert.List:
+		return p.lineCommandUsecase.GetPriceAlerts(ctx, replyToken, userID)
+	case alert.DeleteID > 0:
+		return p.lineCommandUsecase.DeletePriceAlert(ctx, replyToken, userID, alert.DeleteID)
+	case alert.AlertType.IsPercentage():
+		return p.lineCommandUsecase.AddPercentageAlert(ctx, replyToken, userID, alert.Symbol, alert.AlertType, alert.Value)
+	default:
+		return p.lineCommandUsecase.AddPriceAlert(ctx, replyToken, userID, alert.Symbol, alert.Operator, alert.Value)
+	}
+}
+
+func (p *LineMessageProcessor) handleWatchStock(ctx context.Context, replyToken string, userID uint, symbol, listName string) error {
+	if symbol == "" {
+		return p.sendError(replyToken, "請輸入股票代號\n\n使用方式：\n/watch 股票代號 - 加入觀察清單\n/watch 股票代號 清單名稱 - 加入指定觀察清單")
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	return p.lineCommandUsecase.WatchStock(ctx, replyToken, userID, symbol, listName)
+}
+
+func (p *LineMessageProcessor) handleUnwatchStock(ctx context.Context, replyToken string, userID uint, symbol, listName string) error {
+	if symbol == "" {
+		return p.sendError(replyToken, "請輸入股票代號\n\n使用方式：\n/unwatch 股票代號 - 移出觀察清單\n/unwatch 股票代號 清單名稱 - 移出指定觀察清單")
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	return p.lineCommandUsecase.UnwatchStock(ctx, replyToken, userID, symbol, listName)
+}
+
+func (p *LineMessageProcessor) handleWatchlist(ctx context.Context, replyToken string, userID uint, args []string) error {
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	if len(args) == 0 {
+		return p.lineCommandUsecase.GetWatchlist(ctx, replyToken, userID, "")
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "new":
+		if len(args) != 2 {
+			return p.sendError(replyToken, "使用方式：\n/wl new 清單名稱 - 建立觀察清單")
+		}
+		return p.lineCommandUsecase.CreateWatchlist(ctx, replyToken, userID, args[1])
+	case "rename":
+		if len(args) != 3 {
+			return p.sendError(replyToken, "使用方式：\n/wl rename 舊名稱 新名稱 - 重新命名觀察清單")
+		}
+		return p.lineCommandUsecase.RenameWatchlist(ctx, replyToken, userID, args[1], args[2])
+	case "del":
+		if len(args) != 2 {
+			return p.sendError(replyToken, "使用方式：\n/wl del 清單名稱 - 刪除觀察清單")
+		}
+		return p.lineCommandUsecase.DeleteWatchlist(ctx, replyToken, userID, args[1])
+	case "mv":
+		if len(args) != 4 {
+			return p.sendError(replyToken, "使用方式：\n/wl mv 股票代號 來源清單 目標清單 - 移動股票到其他清單")
+		}
+		return p.lineCommandUsecase.MoveWatchlistStock(ctx, replyToken, userID, args[1], args[2], args[3])
+	case "order":
+		if len(args) != 4 {
+			return p.sendError(replyToken, "使用方式：\n/wl order 清單名稱 股票代號 位置 - 調整股票順序\n例如：/wl order 半導體 2330 1")
+		}
+		position, err := strconv.Atoi(args[3])
+		if err != nil || position <= 0 {
+			return p.sendError(replyToken, "請輸入有效的位置，且大於0\n\n使用方式：\n/wl order 清單名稱 股票代號 位置 - 調整股票順序")
+		}
+		return p.lineCommandUsecase.ReorderWatchlistStock(ctx, replyToken, userID, args[1], args[2], position)
+	default:
+		return p.lineCommandUsecase.GetWatchlist(ctx, replyToken, userID, args[0])
+	}
+}
+
+func (p *LineMessageProcessor) handleTrade(ctx context.Context, replyToken string, userID uint, side valueobject.TradeSide, args []string) error {
+	usage := fmt.Sprintf("使用方式：\n%[1]s 股票代號 股數 價格 [日期] - 記錄%[2]s\n例如：%[1]s 2330 1000 985.5 2025-01-10", "/"+string(side), side.GetName())
+	if len(args) < 3 || len(args) > 4 {
+		return p.sendError(replyToken, usage)
+	}
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+
+	quantity, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || quantity <= 0 {
+		return p.sendError(replyToken, "請輸入有效的股數，且大於0\n\n"+usage)
+	}
+
+	price, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || price <= 0 {
+		return p.sendError(replyToken, "請輸入有效的價格，且大於0\n\n"+usage)
+	}
+
+	now := time.Now()
+	tradeDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if len(args) == 4 {
+		tradeDate, err = p.parseDate(args[3])
+		if err != nil {
+			return p.sendError(replyToken, "日期格式錯誤，請使用 YYYY-MM-DD 格式\n例如：2025-01-10")
+		}
+	}
+
+	return p.lineCommandUsecase.RecordTrade(ctx, replyToken, userID, args[0], side, quantity, price, tradeDate)
+}
+
+func (p *LineMessageProcessor) handlePortfolio(ctx context.Context, replyToken string, userID uint, args []string) error {
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	if len(args) == 0 {
+		return p.lineCommandUsecase.GetPortfolio(ctx, replyToken, userID)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "log":
+		return p.lineCommandUsecase.GetPortfolioTrades(ctx, replyToken, userID)
+	case "del":
+		if len(args) != 2 {
+			return p.sendError(replyToken, "使用方式：\n/pf del 編號 - 刪除交易紀錄\n\n可使用 /pf log 查詢編號")
+		}
+		tradeID, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil || tradeID == 0 {
+			return p.sendError(replyToken, "請輸入有效的交易編號")
+		}
+		return p.lineCommandUsecase.DeletePortfolioTrade(ctx, replyToken, userID, uint(tradeID))
+	case "fee":
+		return p.handleTradingSetting(ctx, replyToken, userID, args[1:])
+	default:
+		return p.sendError(replyToken, "使用方式：\n/pf - 查詢投資組合\n/pf log - 查詢交易紀錄\n/pf del 編號 - 刪除交易紀錄\n/pf fee - 查詢或設定交易成本")
+	}
+}
+
+func (p *LineMessageProcessor) handleTradingSetting(ctx context.Context, replyToken string, userID uint, args []string) error {
+	usage := "使用方式：\n/pf fee - 查詢交易成本設定\n/pf fee 折扣 [最低手續費] - 設定券商手續費折扣\n例如：/pf fee 0.28 1 或 /pf fee 2.8 1"
+	if len(args) == 0 {
+		return p.lineCommandUsecase.GetTradingSetting(ctx, replyToken, userID)
+	}
+	if len(args) > 2 {
+		return p.sendError(replyToken, usage)
+	}
+
+	feeDiscount, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || feeDiscount <= 0 {
+		return p.sendError(replyToken, "請輸入有效的手續費折扣\n\n"+usage)
+	}
+
+	var minFee *float64
+	if len(args) == 2 {
+		value, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || value < 0 {
+			return p.sendError(replyToken, "請輸入有效的最低手續費\n\n"+usage)
+		}
+		minFee = &value
+	}
+
+	return p.lineCommandUsecase.UpdateTradingSetting(ctx, replyToken, userID, feeDiscount, minFee)
+}
+
+func (p *LineMessageProcessor) handleUserPreference(ctx context.Context, replyToken string, userID uint, args []string) error {
+	if userID == 0 {
+		return p.sendError(replyToken, "取得使用者資料失敗，請稍後再試")
+	}
+	if len(args) == 0 {
+		return p.lineCommandUsecase.GetUserPreference(ctx, replyToken, userID)
+	}
+
+	change, err := parseUserPreferenceChange(args)
+	if err != nil {
+		return p.sendError(replyToken, err.Error())
+	}
+	return p.lineCommandUsecase.UpdateUserPreference(ctx, replyToken, userID, change)
+}
+
 // func (p *LineMessageProcessor) handleUnknownCommand(replyToken string) error {
 // 	return p.sendError(replyToken, "指令不存在，輸入 /start 查看說明")
 // }
@@ -185,6 +515,19 @@ func (p *LineMessageProcessor) parseMessageArgs(messageText string) (command, ar
 	return command, arg1, arg2
 }
 
+// parseSubscriptionType 解析訂閱項目編號
+func (p *LineMessageProcessor) parseSubscriptionType(value string) (valueobject.SubscriptionType, bool) {
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return valueobject.SubscriptionTypeDefault, false
+	}
+	item, err := valueobject.NewSubscriptionType(intValue)
+	if err != nil || item == valueobject.SubscriptionTypeDefault {
+		return valueobject.SubscriptionTypeDefault, false
+	}
+	return item, true
+}
+
 func (p *LineMessageProcessor) parseDate(value string) (time.Time, error) {
 	return time.ParseInLocation("2006-01-02", value, time.Local)
 }
@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"testing"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// mockLineCommandUsecase 記錄提醒相關方法的呼叫，其餘方法未實作
+type mockLineCommandUsecase struct {
+	LineCommandUsecase
+	calls []string
+}
+
+func (m *mockLineCommandUsecase) AddPriceAlert(ctx context.Context, replyToken string, userID uint, symbol string, operator valueobject.AlertOperator, targetPrice float64) error {
+	m.calls = append(m.calls, fmt.Sprintf("AddPriceAlert %s %d %s %s %g", replyToken, userID, symbol, operator, targetPrice))
+	return nil
+}
+
+func (m *mockLineCommandUsecase) AddPercentageAlert(ctx context.Context, replyToken string, userID uint, symbol string, alertType valueobject.AlertType, thresholdPercent float64) error {
+	m.calls = append(m.calls, fmt.Sprintf("AddPercentageAlert %s %d %s %s %g", replyToken, userID, symbol, alertType.GetName(), thresholdPercent))
+	return nil
+}
+
+func (m *mockLineCommandUsecase) GetPriceAlerts(ctx context.Context, replyToken string, userID uint) error {
+	m.calls = append(m.calls, fmt.Sprintf("GetPriceAlerts %s %d", replyToken, userID))
+	return nil
+}
+
+func (m *mockLineCommandUsecase) DeletePriceAlert(ctx context.Context, replyToken string, userID uint, alertID uint) error {
+	m.calls = append(m.calls, fmt.Sprintf("DeletePriceAlert %s %d %d", replyToken, userID, alertID))
+	return nil
+}
+
+func TestLineMessageProcessor_RoutePriceAlert(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCall string
+	}{
+		{name: "查詢提醒", args: nil, wantCall: "GetPriceAlerts token 7"},
+		{name: "新增價格提醒", args: []string{"2330", "<", "950.5"}, wantCall: "AddPriceAlert token 7 2330 < 950.5"},
+		{name: "新增漲跌幅提醒", args: []string{"2330", "move", "5"}, wantCall: "AddPercentageAlert token 7 2330 漲跌幅提醒 5"},
+		{name: "新增跳空提醒", args: []string{"0050", "gap", "2.5%"}, wantCall: "AddPercentageAlert token 7 0050 跳空提醒 2.5"},
+		{name: "刪除提醒", args: []string{"del", "3"}, wantCall: "DeletePriceAlert token 7 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &mockLineCommandUsecase{}
+			processor := &LineMessageProcessor{lineCommandUsecase: usecase}
+
+			var arg1, arg2 string
+			if len(tt.args) > 0 {
+				arg1 = tt.args[0]
+			}
+			if len(tt.args) > 1 {
+				arg2 = tt.args[1]
+			}
+
+			if err := processor.routeCommand(context.Background(), "/alert", arg1, arg2, tt.args, "token", 7); err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if len(usecase.calls) != 1 || usecase.calls[0] != tt.wantCall {
+				t.Errorf("期望呼叫 %q，實際 %v", tt.wantCall, usecase.calls)
+			}
+		})
+	}
+}
+
+func TestGetUseGuideMessage_SameCommandsOnEveryPlatform(t *testing.T) {
+	u := &botCommandUsecase{}
+	line := u.GetUseGuideMessage(valueobject.UserTypeLine)
+	telegram := html.UnescapeString(u.GetUseGuideMessage(valueobject.UserTypeTelegram))
+
+	if line != telegram {
+		t.Error("LINE 與 Telegram 的指令說明應相同")
+	}
+	if !strings.Contains(line, "/alert [股票代碼] > [價格]") {
+		t.Error("LINE 指令說明應包含價格提醒")
+	}
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(msg string, fields ...logger.Field)  {}
+func (m *mockLogger) Error(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Warn(msg string, fields ...logger.Field)  {}
+func (m *mockLogger) Debug(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Panic(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Fatal(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Sync() error                              { return nil }
+
+// mockUserAccountPort 記錄建立及停用的帳號
+type mockUserAccountPort struct {
+	ensured     []string
+	deactivated []string
+}
+
+func (m *mockUserAccountPort) GetOrCreate(ctx context.Context, accountID string, userType valueobject.UserType) (*entity.User, error) {
+	m.ensured = append(m.ensured, accountID)
+	return &entity.User{ID: 1, AccountID: accountID, UserType: userType, Status: true}, nil
+}
+
+func (m *mockUserAccountPort) Deactivate(ctx context.Context, accountID string, userType valueobject.UserType) error {
+	if userType != valueobject.UserTypeLine {
+		return fmt.Errorf("使用者類型期望 LINE，實際 %v", userType)
+	}
+	m.deactivated = append(m.deactivated, accountID)
+	return nil
+}
+
+func TestLineMessageProcessor_FollowEvents(t *testing.T) {
+	accounts := &mockUserAccountPort{}
+	processor := &LineMessageProcessor{userAccountPort: accounts, logger: &mockLogger{}}
+	event := &linebot.Event{Source: &linebot.EventSource{Type: linebot.EventSourceTypeUser, UserID: "U123"}}
+
+	if err := processor.ProcessUnfollow(context.Background(), event); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+	if fmt.Sprint(accounts.deactivated) != "[U123]" {
+		t.Errorf("封鎖時期望停用 U123，實際 %v", accounts.deactivated)
+	}
+
+	if err := processor.ProcessFollow(context.Background(), event); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+	if fmt.Sprint(accounts.ensured) != "[U123]" {
+		t.Errorf("解除封鎖時期望恢復 U123，實際 %v", accounts.ensured)
+	}
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// priceAlertUsage /alert 指令說明
+const priceAlertUsage = "使用方式：\n/alert 股票代號 > 價格 - 收盤價高於指定價格時提醒\n/alert 股票代號 < 價格 - 收盤價低於指定價格時提醒\n/alert 股票代號 move 百分比 - 收盤漲跌幅超過指定百分比時提醒\n/alert 股票代號 gap 百分比 - 開盤跳空超過指定百分比時提醒\n/alert - 查詢已設定的提醒\n/alert del 編號 - 刪除提醒"
+
+// priceAlertCommand /alert 指令解析後的操作，List 與 DeleteID 皆未設定時為新增提醒
+type priceAlertCommand struct {
+	List     bool
+	DeleteID uint
+	Symbol   string
+	// AlertType 為百分比類型時 Value 為門檻百分比，否則為目標價
+	AlertType valueobject.AlertType
+	Operator  valueobject.AlertOperator
+	Value     float64
+}
+
+// parsePriceAlertArgs 解析 /alert 指令參數，Telegram 與 LINE 共用；錯誤訊息已附上指令說明
+func parsePriceAlertArgs(args []string) (*priceAlertCommand, error) {
+	if len(args) == 0 {
+		return &priceAlertCommand{List: true}, nil
+	}
+
+	if args[0] == "del" {
+		if len(args) < 2 {
+			return nil, errors.New("請輸入提醒編號\n\n" + priceAlertUsage)
+		}
+		alertID, err := strconv.ParseUint(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil || alertID == 0 {
+			return nil, errors.New("請輸入有效的提醒編號\n\n" + priceAlertUsage)
+		}
+		return &priceAlertCommand{DeleteID: uint(alertID)}, nil
+	}
+
+	if len(args) < 3 {
+		return nil, errors.New("請輸入完整的提醒條件\n\n" + priceAlertUsage)
+	}
+
+	if alertType, ok := valueobject.ParseAlertType(strings.ToLower(args[1])); ok {
+		thresholdPercent, err := strconv.ParseFloat(strings.TrimSuffix(args[2], "%"), 64)
+		if err != nil || thresholdPercent <= 0 {
+			return nil, errors.New("請輸入有效的百分比，且大於0\n\n" + priceAlertUsage)
+		}
+		return &priceAlertCommand{Symbol: args[0], AlertType: alertType, Value: thresholdPercent}, nil
+	}
+
+	operator, err := valueobject.NewAlertOperator(args[1])
+	if err != nil {
+		return nil, errors.New("比較條件僅支援 >、<、move 或 gap\n\n" + priceAlertUsage)
+	}
+
+	targetPrice, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || targetPrice <= 0 {
+		return nil, errors.New("請輸入有效的價格，且大於0\n\n" + priceAlertUsage)
+	}
+
+	return &priceAlertCommand{Symbol: args[0], AlertType: valueobject.AlertTypePrice, Operator: operator, Value: targetPrice}, nil
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+func TestParsePriceAlertArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    priceAlertCommand
+		wantErr string
+	}{
+		{name: "查詢提醒", args: nil, want: priceAlertCommand{List: true}},
+		{name: "刪除提醒", args: []string{"del", "#12"}, want: priceAlertCommand{DeleteID: 12}},
+		{name: "刪除提醒缺少編號", args: []string{"del"}, wantErr: "請輸入提醒編號"},
+		{name: "刪除提醒編號無效", args: []string{"del", "0"}, wantErr: "請輸入有效的提醒編號"},
+		{
+			name: "價格提醒",
+			args: []string{"2330", ">", "1100"},
+			want: priceAlertCommand{Symbol: "2330", AlertType: valueobject.AlertTypePrice, Operator: valueobject.AlertOperatorAbove, Value: 1100},
+		},
+		{
+			name: "漲跌幅提醒",
+			args: []string{"2330", "MOVE", "5%"},
+			want: priceAlertCommand{Symbol: "2330", AlertType: valueobject.AlertTypeMove, Value: 5},
+		},
+		{
+			name: "跳空提醒",
+			args: []string{"AAPL", "gap", "3"},
+			want: priceAlertCommand{Symbol: "AAPL", AlertType: valueobject.AlertTypeGap, Value: 3},
+		},
+		{name: "條件不完整", args: []string{"2330", ">"}, wantErr: "請輸入完整的提醒條件"},
+		{name: "不支援的比較條件", args: []string{"2330", "=", "1100"}, wantErr: "比較條件僅支援"},
+		{name: "價格無效", args: []string{"2330", "<", "-1"}, wantErr: "請輸入有效的價格"},
+		{name: "百分比無效", args: []string{"2330", "move", "abc"}, wantErr: "請輸入有效的百分比"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePriceAlertArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("錯誤期望以 %q 開頭，實際 %v", tt.wantErr, err)
+				}
+				if !strings.Contains(err.Error(), priceAlertUsage) {
+					t.Errorf("錯誤訊息應附上指令說明，實際 %q", err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("期望 %+v，實際 %+v", tt.want, *got)
+			}
+		})
+	}
+}
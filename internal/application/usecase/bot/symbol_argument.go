@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// maxSymbolCandidateLabelLength 候選股票按鈕文字的長度上限（LINE 快速回覆限制 20 字）
+const maxSymbolCandidateLabelLength = 20
+
+// symbolArgCommands 第一個參數為股票代號的指令
+var symbolArgCommands = map[string]bool{
+	"/k":       true,
+	"/p":       true,
+	"/d":       true,
+	"/i":       true,
+	"/r":       true,
+	"/n":       true,
+	"/div":     true,
+	"/add":     true,
+	"/del":     true,
+	"/watch":   true,
+	"/unwatch": true,
+	"/buy":     true,
+	"/sell":    true,
+}
+
+// symbolArgIndex 回傳指令中股票代號參數的位置，指令不帶股票代號時回傳 -1
+func symbolArgIndex(command string, args []string) int {
+	if len(args) == 0 {
+		return -1
+	}
+	if symbolArgCommands[command] {
+		return 0
+	}
+	// /alert del 編號 的參數不是股票代號
+	if command == "/alert" && args[0] != "del" {
+		return 0
+	}
+	return -1
+}
+
+// resolveSymbolArg 將指令中的股票名稱解析為股票代號並回傳取代後的參數；
+// 符合多檔股票時回傳候選清單，查無符合或解析失敗時保留原始輸入，交由原本的流程處理
+func resolveSymbolArg(ctx context.Context, resolver stock.SymbolResolver, log logger.Logger, command string, args []string) ([]string, []dto.SymbolCandidate) {
+	index := symbolArgIndex(command, args)
+	if index < 0 {
+		return args, nil
+	}
+
+	resolution, err := resolver.Resolve(ctx, args[index])
+	if err != nil {
+		log.Warn("解析股票名稱失敗", logger.String("input", args[index]), logger.Error(err))
+		return args, nil
+	}
+	if resolution.IsAmbiguous() {
+		return args, resolution.Candidates
+	}
+	if !resolution.IsResolved() || resolution.Symbol == args[index] {
+		return args, nil
+	}
+
+	resolved := make([]string, len(args))
+	copy(resolved, args)
+	resolved[index] = resolution.Symbol
+	return resolved, nil
+}
+
+// symbolCandidateShortcuts 以各候選股票代號取代原本的股票名稱，組成可直接執行的指令
+func symbolCandidateShortcuts(command string, args []string, candidates []dto.SymbolCandidate) []dto.CommandShortcut {
+	index := symbolArgIndex(command, args)
+	shortcuts := make([]dto.CommandShortcut, 0, len(candidates))
+	for _, candidate := range candidates {
+		fields := make([]string, 0, len(args)+1)
+		fields = append(fields, command)
+		fields = append(fields, args...)
+		fields[index+1] = candidate.Symbol
+
+		label := []rune(candidate.Symbol + " " + candidate.Name)
+		if len(label) > maxSymbolCandidateLabelLength {
+			label = label[:maxSymbolCandidateLabelLength]
+		}
+		shortcuts = append(shortcuts, dto.CommandShortcut{
+			Label:   string(label),
+			Command: strings.Join(fields, " "),
+		})
+	}
+	return shortcuts
+}
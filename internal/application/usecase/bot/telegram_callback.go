@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+
+	tgbot "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// callbackPermission 按鈕指令可由誰點擊
+type callbackPermission int
+
+const (
+	// callbackAnyone 只查詢資料，群組中任何成員皆可點擊
+	callbackAnyone callbackPermission = iota + 1
+	// callbackOwnerOnly 會修改聊天室帳號的訂閱、清單或設定，只接受帳號本人在私訊中點擊
+	callbackOwnerOnly
+)
+
+// telegramCallbackCommands 可由按鈕觸發的指令。按鈕的 callback data 即為完整指令（例如 "/k 2330"），
+// 點擊後與使用者輸入的指令走相同的路由；Telegram 限制 callback data 最多 64 bytes
+var telegramCallbackCommands = map[string]callbackPermission{
+	"/k":        callbackAnyone,
+	"/p":        callbackAnyone,
+	"/d":        callbackAnyone,
+	"/i":        callbackAnyone,
+	"/r":        callbackAnyone,
+	"/n":        callbackAnyone,
+	"/div":      callbackAnyone,
+	"/add":      callbackOwnerOnly,
+	"/del":      callbackOwnerOnly,
+	"/watch":    callbackOwnerOnly,
+	"/unwatch":  callbackOwnerOnly,
+	"/alert":    callbackOwnerOnly,
+	"/buy":      callbackOwnerOnly,
+	"/sell":     callbackOwnerOnly,
+	"/unsub":    callbackOwnerOnly,
+	"/settings": callbackOwnerOnly,
+}
+
+// maxCallbackDataLength Telegram callback data 長度上限
+const maxCallbackDataLength = 64
+
+// parseCallbackCommand 檢查 callback data 是否為允許由按鈕觸發的指令，並回傳可點擊的對象
+func parseCallbackCommand(data string) (string, callbackPermission, bool) {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return "", 0, false
+	}
+	permission, ok := telegramCallbackCommands[fields[0]]
+	if !ok {
+		return "", 0, false
+	}
+	return strings.Join(fields, " "), permission, true
+}
+
+// isCallbackFromOwner 點擊者是否為按鈕所在聊天室的帳號本人；
+// 帳號以聊天室 ID 識別，只有私訊的聊天室 ID 與點擊者的使用者 ID 相同
+func isCallbackFromOwner(query *tgbot.CallbackQuery) bool {
+	return query.From != nil && query.Message != nil && query.Message.Chat != nil &&
+		query.Message.Chat.ID == query.From.ID
+}
+
+// stockPriceShortcuts 查詢收盤資訊後可接著執行的指令
+func stockPriceShortcuts(symbol string) []dto.CommandShortcut {
+	return []dto.CommandShortcut{
+		{Label: "K線", Command: "/k " + symbol},
+		{Label: "績效", Command: "/p " + symbol},
+		{Label: "營收", Command: "/r " + symbol},
+		{Label: "新聞", Command: "/n " + symbol},
+		{Label: "加入訂閱", Command: "/add " + symbol},
+	}
+}
+
+// newShortcutKeyboard 將快速指令轉為 inline keyboard，每列 perRow 個按鈕；
+// 超過 callback data 長度上限的指令不建立按鈕，沒有按鈕時回傳 nil
+func newShortcutKeyboard(shortcuts []dto.CommandShortcut, perRow int) *tgbot.InlineKeyboardMarkup {
+	if perRow <= 0 {
+		perRow = 1
+	}
+
+	rows := make([][]tgbot.InlineKeyboardButton, 0, (len(shortcuts)+perRow-1)/perRow)
+	row := make([]tgbot.InlineKeyboardButton, 0, perRow)
+	for _, shortcut := range shortcuts {
+		if len(shortcut.Command) > maxCallbackDataLength {
+			continue
+		}
+		row = append(row, tgbot.NewInlineKeyboardButtonData(shortcut.Label, shortcut.Command))
+		if len(row) == perRow {
+			rows = append(rows, row)
+			row = make([]tgbot.InlineKeyboardButton, 0, perRow)
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	keyboard := tgbot.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"testing"
+
+	tgbot "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestParseCallbackCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		wantCommand    string
+		wantPermission callbackPermission
+		wantOK         bool
+	}{
+		{name: "查詢指令", data: "/k  2330", wantCommand: "/k 2330", wantPermission: callbackAnyone, wantOK: true},
+		{name: "修改帳號資料的指令", data: "/settings digest off", wantCommand: "/settings digest off", wantPermission: callbackOwnerOnly, wantOK: true},
+		{name: "不允許由按鈕觸發的指令", data: "/schedule 1 reset"},
+		{name: "空白內容", data: "  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, permission, ok := parseCallbackCommand(tt.data)
+			if ok != tt.wantOK || command != tt.wantCommand || permission != tt.wantPermission {
+				t.Errorf("期望 (%q, %v, %v)，實際 (%q, %v, %v)", tt.wantCommand, tt.wantPermission, tt.wantOK, command, permission, ok)
+			}
+		})
+	}
+}
+
+func TestIsCallbackFromOwner(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *tgbot.CallbackQuery
+		want  bool
+	}{
+		{
+			name:  "私訊中由本人點擊",
+			query: &tgbot.CallbackQuery{From: &tgbot.User{ID: 100}, Message: &tgbot.Message{Chat: &tgbot.Chat{ID: 100, Type: "private"}}},
+			want:  true,
+		},
+		{
+			name:  "群組中由成員點擊",
+			query: &tgbot.CallbackQuery{From: &tgbot.User{ID: 100}, Message: &tgbot.Message{Chat: &tgbot.Chat{ID: -200, Type: "group"}}},
+			want:  false,
+		},
+		{
+			name:  "缺少點擊者",
+			query: &tgbot.CallbackQuery{Message: &tgbot.Message{Chat: &tgbot.Chat{ID: 100}}},
+			want:  false,
+		},
+		{
+			name:  "缺少原始訊息",
+			query: &tgbot.CallbackQuery{From: &tgbot.User{ID: 100}},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCallbackFromOwner(tt.query); got != tt.want {
+				t.Errorf("期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package bot
 
 import (
 	"context"
+	"html"
 	"strconv"
 	"time"
 
+	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
 	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
@@ -13,7 +15,7 @@ import (
 )
 
 type TelegramCommandUsecase interface {
-	GetUseGuideMessage(chatID int64) error
+	GetUseGuideMessage(ctx context.Context, chatID int64) error
 	GetDailyMarketInfo(ctx context.Context, chatID int64, count int) error
 	GetStockPerformance(ctx context.Context, symbol string, chatID int64) error
 	GetStockPerformanceChart(ctx context.Context, symbol string, chatID int64) error
@@ -22,12 +24,36 @@ type TelegramCommandUsecase interface {
 	GetStockRevenueChart(ctx context.Context, symbol string, chatID int64) error
 	GetHistoricalCandlesChart(ctx context.Context, symbol string, chatID int64) error
 	GetStockCompanyInfo(ctx context.Context, symbol string, chatID int64) error
+	GetDividendCalendar(ctx context.Context, symbol string, chatID int64) error
 	GetStockNews(ctx context.Context, symbol string, chatID int64) error
 	SubscribeStock(ctx context.Context, chatID int64, symbol string) error
 	UnsubscribeStock(ctx context.Context, chatID int64, symbol string) error
 	SubscribedItems(ctx context.Context, chatID int64, item valueobject.SubscriptionType) error
 	UnsubscribedItems(ctx context.Context, chatID int64, item valueobject.SubscriptionType) error
 	GetSubscribed(ctx context.Context, chatID int64) error
+	GetSubscriptionSchedules(ctx context.Context, chatID int64) error
+	SetSubscriptionSchedule(ctx context.Context, chatID int64, item valueobject.SubscriptionType, schedule string) error
+	AddPriceAlert(ctx context.Context, chatID int64, symbol string, operator valueobject.AlertOperator, targetPrice float64) error
+	AddPercentageAlert(ctx context.Context, chatID int64, symbol string, alertType valueobject.AlertType, thresholdPercent float64) error
+	GetPriceAlerts(ctx context.Context, chatID int64) error
+	DeletePriceAlert(ctx context.Context, chatID int64, alertID uint) error
+	WatchStock(ctx context.Context, chatID int64, symbol string, listName string) error
+	UnwatchStock(ctx context.Context, chatID int64, symbol string, listName string) error
+	GetWatchlist(ctx context.Context, chatID int64, listName string) error
+	CreateWatchlist(ctx context.Context, chatID int64, name string) error
+	RenameWatchlist(ctx context.Context, chatID int64, oldName string, newName string) error
+	DeleteWatchlist(ctx context.Context, chatID int64, name string) error
+	MoveWatchlistStock(ctx context.Context, chatID int64, symbol string, fromName string, toName string) error
+	ReorderWatchlistStock(ctx context.Context, chatID int64, listName string, symbol string, position int) error
+	RecordTrade(ctx context.Context, chatID int64, symbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) error
+	GetPortfolio(ctx context.Context, chatID int64) error
+	GetPortfolioTrades(ctx context.Context, chatID int64) error
+	DeletePortfolioTrade(ctx context.Context, chatID int64, tradeID uint) error
+	GetTradingSetting(ctx context.Context, chatID int64) error
+	UpdateTradingSetting(ctx context.Context, chatID int64, feeDiscount float64, minFee *float64) error
+	GetDividendIncome(ctx context.Context, chatID int64) error
+	GetUserPreference(ctx context.Context, chatID int64) error
+	UpdateUserPreference(ctx context.Context, chatID int64, change *dto.UserPreferenceChange) error
 }
 
 var _ TelegramCommandUsecase = (*telegramCommandUsecase)(nil)
@@ -54,171 +80,453 @@ func NewTgBotCommandUsecase(
 	return &telegramCommandUsecase{formatterPort: formatterPort, botCommandUsecase: botCommandUsecase, marketDataUsecase: marketDataUsecase, userAccountPort: userAccountPort, client: client, logger: log}
 }
 
-func (u *telegramCommandUsecase) GetUseGuideMessage(chatID int64) error {
-	message := u.botCommandUsecase.GetUseGuideMessage()
-	return u.client.SendMessage(chatID, message)
+func (u *telegramCommandUsecase) GetUseGuideMessage(ctx context.Context, chatID int64) error {
+	message := u.botCommandUsecase.GetUseGuideMessage(UserTypeTelegram)
+	return u.client.SendMessage(ctx, chatID, message)
 }
 
 func (u *telegramCommandUsecase) GetDailyMarketInfo(ctx context.Context, chatID int64, count int) error {
 	message, err := u.botCommandUsecase.GetDailyMarketInfo(ctx, UserTypeTelegram, count)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, message)
+	return u.client.SendMessage(ctx, chatID, message)
 }
 
 func (u *telegramCommandUsecase) GetStockPerformance(ctx context.Context, symbol string, chatID int64) error {
 	message, err := u.botCommandUsecase.GetStockPerformance(ctx, UserTypeTelegram, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, message)
+	return u.client.SendMessage(ctx, chatID, message)
 }
 
 func (u *telegramCommandUsecase) GetStockPerformanceChart(ctx context.Context, symbol string, chatID int64) error {
 	chart, err := u.botCommandUsecase.GetStockPerformanceChart(ctx, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	if chart == nil {
-		return u.sendError(chatID, "圖表資料為空")
+		return u.sendError(ctx, chatID, "圖表資料為空")
 	}
 
-	return u.client.SendPhoto(chatID, chart.Data, chart.FileName)
+	return u.client.SendPhoto(ctx, chatID, chart.Data, chart.FileName)
 }
 
 func (u *telegramCommandUsecase) GetTopVolumeStock(ctx context.Context, chatID int64) error {
 	message, err := u.botCommandUsecase.GetTopVolumeStock(ctx, UserTypeTelegram)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, message)
+	return u.client.SendMessage(ctx, chatID, message)
 }
 
 func (u *telegramCommandUsecase) GetStockPrice(ctx context.Context, symbol string, date *time.Time, chatID int64) error {
 	message, err := u.botCommandUsecase.GetStockPrice(ctx, UserTypeTelegram, symbol, date)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, message)
+	return u.client.SendMessageWithKeyboard(ctx, chatID, message, newShortcutKeyboard(stockPriceShortcuts(symbol), 5))
 }
 
 func (u *telegramCommandUsecase) GetStockRevenueChart(ctx context.Context, symbol string, chatID int64) error {
 	chart, err := u.botCommandUsecase.GetStockRevenueChart(ctx, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	if chart == nil {
-		return u.sendError(chatID, "圖表資料為空")
+		return u.sendError(ctx, chatID, "圖表資料為空")
 	}
 
-	return u.client.SendPhoto(chatID, chart.Data, chart.FileName)
+	return u.client.SendPhoto(ctx, chatID, chart.Data, chart.FileName)
 }
 
 func (u *telegramCommandUsecase) GetHistoricalCandlesChart(ctx context.Context, symbol string, chatID int64) error {
 	chart, err := u.botCommandUsecase.GetHistoricalCandlesChart(ctx, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	if chart == nil {
-		return u.sendError(chatID, "圖表資料為空")
+		return u.sendError(ctx, chatID, "圖表資料為空")
 	}
 
-	return u.client.SendPhoto(chatID, chart.Data, chart.FileName)
+	return u.client.SendPhoto(ctx, chatID, chart.Data, chart.FileName)
 }
 
 func (u *telegramCommandUsecase) GetStockCompanyInfo(ctx context.Context, symbol string, chatID int64) error {
 	message, err := u.botCommandUsecase.GetStockCompanyInfo(ctx, UserTypeTelegram, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, message)
+	return u.client.SendMessage(ctx, chatID, message)
+}
+
+func (u *telegramCommandUsecase) GetDividendCalendar(ctx context.Context, symbol string, chatID int64) error {
+	message, err := u.botCommandUsecase.GetDividendCalendar(ctx, UserTypeTelegram, symbol)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, message)
 }
 
 func (u *telegramCommandUsecase) GetStockNews(ctx context.Context, symbol string, chatID int64) error {
 	newsMessage, err := u.botCommandUsecase.GetStockNewsForTelegram(ctx, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	if newsMessage.InlineKeyboardMarkup == nil {
-		return u.client.SendMessage(chatID, newsMessage.Text)
+		return u.client.SendMessage(ctx, chatID, newsMessage.Text)
 	}
 
-	return u.client.SendMessageWithKeyboard(chatID, newsMessage.Text, newsMessage.InlineKeyboardMarkup)
+	return u.client.SendMessageWithKeyboard(ctx, chatID, newsMessage.Text, newsMessage.InlineKeyboardMarkup)
 }
 
 func (u *telegramCommandUsecase) SubscribeStock(ctx context.Context, chatID int64, symbol string) error {
 	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	result, err := u.botCommandUsecase.SubscribeStock(ctx, userID, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, result)
+	return u.client.SendMessage(ctx, chatID, result)
 }
 
 func (u *telegramCommandUsecase) UnsubscribeStock(ctx context.Context, chatID int64, symbol string) error {
 	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	result, err := u.botCommandUsecase.UnsubscribeStock(ctx, userID, symbol)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, result)
+	return u.client.SendMessage(ctx, chatID, result)
 }
 
 func (u *telegramCommandUsecase) SubscribedItems(ctx context.Context, chatID int64, item valueobject.SubscriptionType) error {
 	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	result, err := u.botCommandUsecase.SubscribedItems(ctx, userID, item)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, result)
+	return u.client.SendMessage(ctx, chatID, result)
 }
 
 func (u *telegramCommandUsecase) UnsubscribedItems(ctx context.Context, chatID int64, item valueobject.SubscriptionType) error {
 	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
 	result, err := u.botCommandUsecase.UnsubscribedItems(ctx, userID, item)
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
-	return u.client.SendMessage(chatID, result)
+	return u.client.SendMessage(ctx, chatID, result)
 }
 func (u *telegramCommandUsecase) GetSubscribed(ctx context.Context, chatID int64) error {
 	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetSubscribed(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	// 每個訂閱一列取消按鈕
+	return u.client.SendMessageWithKeyboard(ctx, chatID, result.Text, newShortcutKeyboard(result.Shortcuts, 1))
+}
+
+func (u *telegramCommandUsecase) GetSubscriptionSchedules(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetSubscriptionSchedules(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) SetSubscriptionSchedule(ctx context.Context, chatID int64, item valueobject.SubscriptionType, schedule string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.SetSubscriptionSchedule(ctx, userID, item, schedule)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) AddPriceAlert(ctx context.Context, chatID int64, symbol string, operator valueobject.AlertOperator, targetPrice float64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.AddPriceAlert(ctx, userID, symbol, operator, targetPrice)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) AddPercentageAlert(ctx context.Context, chatID int64, symbol string, alertType valueobject.AlertType, thresholdPercent float64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.AddPercentageAlert(ctx, userID, symbol, alertType, thresholdPercent)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) GetPriceAlerts(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetPriceAlerts(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) DeletePriceAlert(ctx context.Context, chatID int64, alertID uint) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.DeletePriceAlert(ctx, userID, alertID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) WatchStock(ctx context.Context, chatID int64, symbol string, listName string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.WatchStock(ctx, userID, symbol, listName)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) UnwatchStock(ctx context.Context, chatID int64, symbol string, listName string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.UnwatchStock(ctx, userID, symbol, listName)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) GetWatchlist(ctx context.Context, chatID int64, listName string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetWatchlist(ctx, UserTypeTelegram, userID, listName)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) CreateWatchlist(ctx context.Context, chatID int64, name string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.CreateWatchlist(ctx, userID, name)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
 	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
 
-	result, err := u.botCommandUsecase.GetSubscribed(ctx, userID)
+func (u *telegramCommandUsecase) RenameWatchlist(ctx context.Context, chatID int64, oldName string, newName string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
 	if err != nil {
-		return u.sendError(chatID, err.Error())
+		return u.sendError(ctx, chatID, err.Error())
 	}
 
-	return u.client.SendMessage(chatID, result)
+	result, err := u.botCommandUsecase.RenameWatchlist(ctx, userID, oldName, newName)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
 }
 
-func (u *telegramCommandUsecase) sendError(chatID int64, message string) error {
+func (u *telegramCommandUsecase) DeleteWatchlist(ctx context.Context, chatID int64, name string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.DeleteWatchlist(ctx, userID, name)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) MoveWatchlistStock(ctx context.Context, chatID int64, symbol string, fromName string, toName string) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.MoveWatchlistStock(ctx, userID, symbol, fromName, toName)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) ReorderWatchlistStock(ctx context.Context, chatID int64, listName string, symbol string, position int) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.ReorderWatchlistStock(ctx, userID, listName, symbol, position)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) RecordTrade(ctx context.Context, chatID int64, symbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.RecordTrade(ctx, userID, symbol, side, quantity, price, tradeDate)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) GetPortfolio(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetPortfolio(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) GetPortfolioTrades(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetPortfolioTrades(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) DeletePortfolioTrade(ctx context.Context, chatID int64, tradeID uint) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.DeletePortfolioTrade(ctx, userID, tradeID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) GetTradingSetting(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetTradingSetting(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) UpdateTradingSetting(ctx context.Context, chatID int64, feeDiscount float64, minFee *float64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.UpdateTradingSetting(ctx, userID, feeDiscount, minFee)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) GetDividendIncome(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.GetDividendIncome(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.client.SendMessage(ctx, chatID, result)
+}
+
+func (u *telegramCommandUsecase) sendError(ctx context.Context, chatID int64, message string) error {
 	u.logger.Warn("發送訊息失敗", logger.Int64("chat_id", chatID), logger.String("message", message))
-	return u.client.SendMessage(chatID, message)
+	return u.client.SendMessage(ctx, chatID, html.EscapeString(message))
 }
 
 // ensureUser 確保使用者存在，不存在則建立
@@ -229,3 +537,39 @@ func (p *telegramCommandUsecase) getUser(ctx context.Context, accountID string)
 	}
 	return user.ID, nil
 }
+
+func (u *telegramCommandUsecase) GetUserPreference(ctx context.Context, chatID int64) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.sendUserPreference(ctx, chatID, userID, "")
+}
+
+// UpdateUserPreference 修改偏好設定後，連同最新設定與按鈕一併回覆
+func (u *telegramCommandUsecase) UpdateUserPreference(ctx context.Context, chatID int64, change *dto.UserPreferenceChange) error {
+	userID, err := u.getUser(ctx, strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	result, err := u.botCommandUsecase.UpdateUserPreference(ctx, userID, change)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+	return u.sendUserPreference(ctx, chatID, userID, result)
+}
+
+// sendUserPreference 送出偏好設定及快速設定按鈕
+func (u *telegramCommandUsecase) sendUserPreference(ctx context.Context, chatID int64, userID uint, header string) error {
+	preference, err := u.botCommandUsecase.GetUserPreference(ctx, UserTypeTelegram, userID)
+	if err != nil {
+		return u.sendError(ctx, chatID, err.Error())
+	}
+
+	text := preference.Text
+	if header != "" {
+		text = html.EscapeString(header) + "\n\n" + text
+	}
+	return u.client.SendMessageWithKeyboard(ctx, chatID, text, newShortcutKeyboard(preference.Shortcuts, 2))
+}
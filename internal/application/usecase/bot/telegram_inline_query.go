@@ -0,0 +1,259 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	tgbotapi "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/telegram"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/imgbb"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+
+	tgbot "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// inlineQueryResultLimit inline 模式最多回傳的股票數量
+	inlineQueryResultLimit = 5
+	// inlineQueryMinLength 開始搜尋的最少字數，避免輸入第一個字就查詢
+	inlineQueryMinLength = 2
+	// inlineQueryCacheTime Telegram 端快取 inline 結果的秒數
+	inlineQueryCacheTime = 60
+	// inlineQuoteTTL 報價與搜尋結果的快取時間，連續輸入時不會每個字都呼叫 API
+	inlineQuoteTTL = time.Minute
+	// inlineChartTTL K線圖網址的快取時間
+	inlineChartTTL = time.Hour
+	// inlineChartTimeout 背景產生K線圖的逾時時間
+	inlineChartTimeout = 30 * time.Second
+	// inlineChartExpiration 上傳到 ImgBB 的圖片保留秒數
+	inlineChartExpiration = 24 * 60 * 60
+	// inlineQuoteLookbackDays 取得最新報價時往前查詢的天數，涵蓋連假
+	inlineQuoteLookbackDays = 14
+	// inlineCacheMaxEntries 各快取的項目上限
+	inlineCacheMaxEntries = 1024
+)
+
+// TelegramInlineQueryUsecase 處理 Telegram inline 模式（在任何聊天室輸入 @bot 股票代號）
+type TelegramInlineQueryUsecase interface {
+	AnswerInlineQuery(ctx context.Context, inlineQueryID string, query string) error
+}
+
+type telegramInlineQueryUsecase struct {
+	stockSymbolRepo port.StockSymbolReader
+	marketDataPort  port.MarketDataPort
+	marketChartPort port.MarketChartPort
+	formatterPort   port.FormatterPort
+	client          *tgbotapi.TgBotClient
+	imgbbClient     *imgbb.ImgBBClient
+	searchCache     *ttlCache[[]*entity.StockSymbol]
+	quoteCache      *ttlCache[*dto.StockPrice]
+	chartCache      *ttlCache[string]
+	// chartPending 背景產生中的K線圖，避免同一檔股票重複產生
+	chartPending sync.Map
+	logger       logger.Logger
+}
+
+var _ TelegramInlineQueryUsecase = (*telegramInlineQueryUsecase)(nil)
+
+// NewTelegramInlineQueryUsecase 建立 inline 模式處理；imgbbClient 為 nil 時結果不附K線圖
+func NewTelegramInlineQueryUsecase(
+	stockSymbolRepo port.StockSymbolReader,
+	marketDataPort port.MarketDataPort,
+	marketChartPort port.MarketChartPort,
+	formatterPort port.FormatterPort,
+	client *tgbotapi.TgBotClient,
+	imgbbClient *imgbb.ImgBBClient,
+	log logger.Logger,
+) TelegramInlineQueryUsecase {
+	return &telegramInlineQueryUsecase{
+		stockSymbolRepo: stockSymbolRepo,
+		marketDataPort:  marketDataPort,
+		marketChartPort: marketChartPort,
+		formatterPort:   formatterPort,
+		client:          client,
+		imgbbClient:     imgbbClient,
+		searchCache:     newTTLCache[[]*entity.StockSymbol](inlineQuoteTTL, inlineCacheMaxEntries),
+		quoteCache:      newTTLCache[*dto.StockPrice](inlineQuoteTTL, inlineCacheMaxEntries),
+		chartCache:      newTTLCache[string](inlineChartTTL, inlineCacheMaxEntries),
+		logger:          log,
+	}
+}
+
+// AnswerInlineQuery 搜尋符合的股票並回傳結果；只有代號或名稱完全相符的股票會查詢報價，
+// 其餘結果僅列出股票，點擊後以完整代號重新查詢，避免每次輸入都呼叫報價 API
+func (u *telegramInlineQueryUsecase) AnswerInlineQuery(ctx context.Context, inlineQueryID string, query string) error {
+	keyword := strings.TrimSpace(query)
+	if utf8.RuneCountInString(keyword) < inlineQueryMinLength {
+		return u.client.AnswerInlineQuery(inlineQueryID, []interface{}{}, inlineQueryCacheTime)
+	}
+
+	symbols, err := u.searchSymbols(ctx, keyword)
+	if err != nil {
+		u.logger.Error("inline query 搜尋股票失敗", logger.String("query", keyword), logger.Error(err))
+		return u.client.AnswerInlineQuery(inlineQueryID, []interface{}{}, 0)
+	}
+
+	cacheTime := inlineQueryCacheTime
+	results := make([]interface{}, 0, len(symbols))
+	for _, symbol := range symbols {
+		if !isExactSymbolMatch(symbol, keyword) {
+			results = append(results, newSuggestionResult(symbol))
+			continue
+		}
+
+		quote := u.getQuote(ctx, symbol.Symbol)
+		if quote == nil {
+			continue
+		}
+		chartURL, ready := u.cachedChartURL(symbol.Symbol)
+		if !ready {
+			// K線圖仍在背景產生，不讓 Telegram 快取缺少圖表的結果
+			cacheTime = 0
+		}
+		results = append(results, u.newQuoteResult(symbol, quote, chartURL))
+	}
+
+	return u.client.AnswerInlineQuery(inlineQueryID, results, cacheTime)
+}
+
+// isExactSymbolMatch 代號（不分大小寫）或名稱與關鍵字完全相符
+func isExactSymbolMatch(symbol *entity.StockSymbol, keyword string) bool {
+	return strings.EqualFold(symbol.Symbol, keyword) || symbol.Name == keyword
+}
+
+func (u *telegramInlineQueryUsecase) searchSymbols(ctx context.Context, keyword string) ([]*entity.StockSymbol, error) {
+	cacheKey := strings.ToUpper(keyword)
+	if symbols, ok := u.searchCache.Get(cacheKey); ok {
+		return symbols, nil
+	}
+
+	symbols, err := u.stockSymbolRepo.SearchSymbols(ctx, keyword, inlineQueryResultLimit)
+	if err != nil {
+		return nil, err
+	}
+	u.searchCache.Set(cacheKey, symbols)
+	return symbols, nil
+}
+
+// getQuote 取得最近一個交易日的報價，漲跌以前一個交易日收盤價計算；
+// 取得失敗時回傳 nil，失敗結果同樣快取，避免連續輸入時重複呼叫 API
+func (u *telegramInlineQueryUsecase) getQuote(ctx context.Context, symbol string) *dto.StockPrice {
+	if quote, ok := u.quoteCache.Get(symbol); ok {
+		return quote
+	}
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -inlineQuoteLookbackDays)
+	prices, err := u.marketDataPort.GetStockPrice(ctx, symbol, &startDate, &endDate)
+	if err != nil || prices == nil || len(*prices) == 0 {
+		u.logger.Warn("inline query 取得報價失敗", logger.String("symbol", symbol), logger.Error(err))
+		u.quoteCache.Set(symbol, nil)
+		return nil
+	}
+
+	latest := (*prices)[len(*prices)-1]
+	if len(*prices) > 1 {
+		prev := (*prices)[len(*prices)-2]
+		latest.PrevTradeDate = prev.Date
+		latest.PrevClosePrice = prev.ClosePrice
+		latest.ChangeAmount = latest.ClosePrice - prev.ClosePrice
+		if prev.ClosePrice != 0 {
+			latest.ChangeRate = latest.ChangeAmount / prev.ClosePrice * 100
+		}
+		switch {
+		case latest.ChangeAmount > 0:
+			latest.UpDownSign = "+"
+		case latest.ChangeAmount < 0:
+			latest.UpDownSign = "-"
+		}
+	}
+
+	u.quoteCache.Set(symbol, &latest)
+	return &latest
+}
+
+// cachedChartURL 取得快取的K線圖網址，ready 為 false 代表尚未產生，此時改在背景產生並回傳空字串；
+// 未設定 ImgBB 時不附圖表
+func (u *telegramInlineQueryUsecase) cachedChartURL(symbol string) (chartURL string, ready bool) {
+	if u.imgbbClient == nil {
+		return "", true
+	}
+	if chartURL, ok := u.chartCache.Get(symbol); ok {
+		return chartURL, true
+	}
+
+	if _, pending := u.chartPending.LoadOrStore(symbol, struct{}{}); !pending {
+		go func() {
+			defer u.chartPending.Delete(symbol)
+			ctx, cancel := context.WithTimeout(context.Background(), inlineChartTimeout)
+			defer cancel()
+			u.chartCache.Set(symbol, u.generateChartURL(ctx, symbol))
+		}()
+	}
+	return "", false
+}
+
+// generateChartURL 產生K線圖並上傳到 ImgBB 取得網址，失敗時回傳空字串；
+// 失敗結果同樣寫入快取，避免每次輸入都重新產生圖表
+func (u *telegramInlineQueryUsecase) generateChartURL(ctx context.Context, symbol string) string {
+	data, _, err := u.marketChartPort.GetHistoricalCandlesChart(ctx, symbol)
+	if err != nil {
+		u.logger.Warn("inline query 產生K線圖失敗", logger.String("symbol", symbol), logger.Error(err))
+		return ""
+	}
+
+	resp, err := u.imgbbClient.UploadFromFile(bytes.NewReader(data), "chart.png", &imgbb.UploadOptions{
+		Name:       "inline_" + symbol,
+		Expiration: inlineChartExpiration,
+	})
+	if err != nil {
+		u.logger.Warn("inline query 上傳K線圖失敗", logger.String("symbol", symbol), logger.Error(err))
+		return ""
+	}
+	return resp.Data.URL
+}
+
+// newQuoteResult 建立報價卡片：標題為代號、名稱及收盤價，送出的訊息與 /d 相同，附K線圖時以連結預覽顯示
+func (u *telegramInlineQueryUsecase) newQuoteResult(symbol *entity.StockSymbol, quote *dto.StockPrice, chartURL string) tgbot.InlineQueryResultArticle {
+	text := u.formatterPort.FormatStockPrice(quote, UserTypeTelegram)
+	if chartURL != "" {
+		text += fmt.Sprintf("\n<a href=\"%s\">📊 K線圖</a>", html.EscapeString(chartURL))
+	}
+
+	result := tgbot.NewInlineQueryResultArticleHTML(
+		fmt.Sprintf("%s-%s", symbol.Symbol, quote.Date.Format("20060102")),
+		fmt.Sprintf("%s %s  %.2f", symbol.Symbol, symbol.Name, quote.ClosePrice),
+		text,
+	)
+	result.Description = fmt.Sprintf("%s %s%.2f (%s%.2f%%)",
+		quote.Date.Format("2006/01/02"), quote.UpDownSign, math.Abs(quote.ChangeAmount), quote.UpDownSign, math.Abs(quote.ChangeRate))
+	result.ThumbURL = chartURL
+	return result
+}
+
+// newSuggestionResult 建立未完全相符的股票卡片，不查詢報價；按鈕以完整代號重新觸發 inline 查詢
+func newSuggestionResult(symbol *entity.StockSymbol) tgbot.InlineQueryResultArticle {
+	result := tgbot.NewInlineQueryResultArticleHTML(
+		"symbol-"+symbol.Symbol,
+		fmt.Sprintf("%s %s", symbol.Symbol, symbol.Name),
+		fmt.Sprintf("<b>%s (%s)</b>", html.EscapeString(symbol.Name), html.EscapeString(symbol.Symbol)),
+	)
+	result.Description = "輸入完整代號或名稱查看報價"
+
+	query := symbol.Symbol
+	keyboard := tgbot.NewInlineKeyboardMarkup(tgbot.NewInlineKeyboardRow(tgbot.InlineKeyboardButton{
+		Text:                         "📈 查看報價",
+		SwitchInlineQueryCurrentChat: &query,
+	}))
+	result.ReplyMarkup = &keyboard
+	return result
+}
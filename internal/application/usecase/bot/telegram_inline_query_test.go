@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+func TestIsExactSymbolMatch(t *testing.T) {
+	tsmc := &entity.StockSymbol{Symbol: "2330", Name: "台積電"}
+	apple := &entity.StockSymbol{Symbol: "AAPL", Name: "Apple"}
+
+	tests := []struct {
+		name    string
+		symbol  *entity.StockSymbol
+		keyword string
+		want    bool
+	}{
+		{name: "代號完全相符", symbol: tsmc, keyword: "2330", want: true},
+		{name: "名稱完全相符", symbol: tsmc, keyword: "台積電", want: true},
+		{name: "代號不分大小寫", symbol: apple, keyword: "aapl", want: true},
+		{name: "代號前綴不查詢報價", symbol: tsmc, keyword: "233", want: false},
+		{name: "名稱部分相符不查詢報價", symbol: tsmc, keyword: "台積", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExactSymbolMatch(tt.symbol, tt.keyword); got != tt.want {
+				t.Errorf("期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewSuggestionResult(t *testing.T) {
+	result := newSuggestionResult(&entity.StockSymbol{Symbol: "2330", Name: "台積電"})
+
+	if result.ReplyMarkup == nil || len(result.ReplyMarkup.InlineKeyboard) != 1 {
+		t.Fatalf("應附上查看報價按鈕，實際 %+v", result.ReplyMarkup)
+	}
+	button := result.ReplyMarkup.InlineKeyboard[0][0]
+	if button.SwitchInlineQueryCurrentChat == nil || *button.SwitchInlineQueryCurrentChat != "2330" {
+		t.Errorf("按鈕應以完整代號重新查詢，實際 %+v", button)
+	}
+}
@@ -2,11 +2,15 @@ package bot
 
 import (
 	"context"
+	"fmt"
+	"html"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
 	tgbotapi "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/telegram"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
@@ -16,28 +20,43 @@ import (
 
 // TelegramMessageProcessor 處理 Telegram 訊息的路由和編排
 type TelegramMessageProcessor struct {
-	tgCommandUsecase TelegramCommandUsecase
-	userAccountPort  port.UserAccountPort
-	tgClient         *tgbotapi.TgBotClient
-	logger           logger.Logger
+	tgCommandUsecase     TelegramCommandUsecase
+	tgInlineQueryUsecase TelegramInlineQueryUsecase
+	symbolResolver       stock.SymbolResolver
+	userAccountPort      port.UserAccountPort
+	tgClient             *tgbotapi.TgBotClient
+	logger               logger.Logger
 }
 
 func NewTelegramMessageProcessor(
 	tgCommandUsecase TelegramCommandUsecase,
+	tgInlineQueryUsecase TelegramInlineQueryUsecase,
+	symbolResolver stock.SymbolResolver,
 	userAccountPort port.UserAccountPort,
 	tgClient *tgbotapi.TgBotClient,
 	log logger.Logger,
 ) *TelegramMessageProcessor {
 	return &TelegramMessageProcessor{
-		tgCommandUsecase: tgCommandUsecase,
-		userAccountPort:  userAccountPort,
-		tgClient:         tgClient,
-		logger:           log,
+		tgCommandUsecase:     tgCommandUsecase,
+		tgInlineQueryUsecase: tgInlineQueryUsecase,
+		symbolResolver:       symbolResolver,
+		userAccountPort:      userAccountPort,
+		tgClient:             tgClient,
+		logger:               log,
 	}
 }
 
 // ProcessUpdate 處理 Telegram update，包含命令路由
 func (p *TelegramMessageProcessor) ProcessUpdate(ctx context.Context, update *tgbot.Update) error {
+	if update.InlineQuery != nil {
+		p.logger.Info("收到 Telegram inline query",
+			logger.Int64("user_id", update.InlineQuery.From.ID),
+			logger.String("query", update.InlineQuery.Query))
+		return p.tgInlineQueryUsecase.AnswerInlineQuery(ctx, update.InlineQuery.ID, update.InlineQuery.Query)
+	}
+	if update.CallbackQuery != nil {
+		return p.processCallbackQuery(ctx, update.CallbackQuery)
+	}
 	if update.Message == nil || update.Message.Text == "" {
 		return nil
 	}
@@ -49,14 +68,56 @@ func (p *TelegramMessageProcessor) ProcessUpdate(ctx context.Context, update *tg
 		logger.Int64("chat_id", chatID),
 		logger.String("message", messageText))
 
+	return p.processCommand(ctx, chatID, messageText)
+}
+
+// processCallbackQuery 路由按鈕點擊：callback data 為允許由按鈕觸發的指令時，
+// 以按鈕所在的聊天室執行該指令；會修改帳號資料的按鈕只接受帳號本人點擊，
+// 避免群組中其他成員透過按鈕變更群組的訂閱或設定
+func (p *TelegramMessageProcessor) processCallbackQuery(ctx context.Context, query *tgbot.CallbackQuery) error {
+	command, permission, ok := parseCallbackCommand(query.Data)
+	if !ok || query.Message == nil || query.Message.Chat == nil {
+		p.logger.Warn("不支援的按鈕", logger.String("data", query.Data))
+		return p.tgClient.AnswerCallbackQuery(query.ID, "此按鈕已失效")
+	}
+	if permission == callbackOwnerOnly && !isCallbackFromOwner(query) {
+		p.logger.Warn("非帳號本人點擊按鈕",
+			logger.Int64("chat_id", query.Message.Chat.ID),
+			logger.String("data", query.Data))
+		return p.tgClient.AnswerCallbackQuery(query.ID, "此按鈕只能在與機器人的私訊中使用")
+	}
+
+	// 先結束按鈕的載入狀態，圖表等指令可能需要較久時間
+	if err := p.tgClient.AnswerCallbackQuery(query.ID, ""); err != nil {
+		p.logger.Warn("回應按鈕點擊失敗", logger.Error(err))
+	}
+
+	chatID := query.Message.Chat.ID
+	p.logger.Info("收到 Telegram 按鈕點擊",
+		logger.Int64("chat_id", chatID),
+		logger.String("data", query.Data))
+
+	return p.processCommand(ctx, chatID, command)
+}
+
+// processCommand 解析並執行指令，失敗時回覆錯誤訊息
+func (p *TelegramMessageProcessor) processCommand(ctx context.Context, chatID int64, messageText string) error {
 	// 解析命令和參數
 	command, arg1, arg2 := p.parseMessageArgs(messageText)
 	if command == "" {
 		return nil
 	}
+	args := strings.Fields(messageText)[1:]
+
+	// 股票名稱解析為代號；符合多檔股票時請使用者從按鈕選擇
+	args, candidates := resolveSymbolArg(ctx, p.symbolResolver, p.logger, command, args)
+	if len(candidates) > 0 {
+		return p.sendSymbolCandidates(ctx, chatID, command, args, candidates)
+	}
+	command, arg1, arg2 = p.parseMessageArgs(strings.Join(append([]string{command}, args...), " "))
 
 	// 路由到對應的命令處理器
-	if err := p.routeCommand(ctx, command, arg1, arg2, chatID); err != nil {
+	if err := p.routeCommand(ctx, command, arg1, arg2, args, chatID); err != nil {
 		p.logger.Error("處理命令失敗",
 			logger.String("command", command),
 			logger.String("arg1", arg1),
@@ -69,16 +130,16 @@ func (p *TelegramMessageProcessor) ProcessUpdate(ctx context.Context, update *tg
 		if errorMsg == "" {
 			errorMsg = "處理請求時發生錯誤，請稍後再試"
 		}
-		return p.sendError(chatID, errorMsg)
+		return p.sendError(ctx, chatID, errorMsg)
 	}
 	return nil
 }
 
 // routeCommand 路由命令到對應的處理器
-func (p *TelegramMessageProcessor) routeCommand(ctx context.Context, command, arg1, arg2 string, chatID int64) error {
+func (p *TelegramMessageProcessor) routeCommand(ctx context.Context, command, arg1, arg2 string, args []string, chatID int64) error {
 	switch command {
 	case "/start":
-		return p.tgCommandUsecase.GetUseGuideMessage(chatID)
+		return p.tgCommandUsecase.GetUseGuideMessage(ctx, chatID)
 	case "/k":
 		return p.handleHistoricalCandles(ctx, chatID, arg1)
 	case "/p":
@@ -105,6 +166,31 @@ func (p *TelegramMessageProcessor) routeCommand(ctx context.Context, command, ar
 		return p.tgCommandUsecase.UnsubscribeStock(ctx, chatID, arg1)
 	case "/list":
 		return p.tgCommandUsecase.GetSubscribed(ctx, chatID)
+	case "/schedule":
+		return p.handleSubscriptionSchedule(ctx, chatID, args)
+	case "/alert":
+		return p.handlePriceAlert(ctx, chatID, args)
+	case "/watch":
+		return p.handleWatchStock(ctx, chatID, arg1, arg2)
+	case "/unwatch":
+		return p.handleUnwatchStock(ctx, chatID, arg1, arg2)
+	case "/wl":
+		return p.handleWatchlist(ctx, chatID, args)
+	case "/buy":
+		return p.handleTrade(ctx, chatID, valueobject.TradeSideBuy, args)
+	case "/sell":
+		return p.handleTrade(ctx, chatID, valueobject.TradeSideSell, args)
+	case "/pf":
+		return p.handlePortfolio(ctx, chatID, args)
+	case "/div":
+		if arg1 == "" {
+			return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/div 股票代號 - 查詢歷年股利及即將到來的除權息日")
+		}
+		return p.tgCommandUsecase.GetDividendCalendar(ctx, arg1, chatID)
+	case "/income":
+		return p.tgCommandUsecase.GetDividendIncome(ctx, chatID)
+	case "/settings":
+		return p.handleUserPreference(ctx, chatID, args)
 	default:
 		// return p.handleUnknownCommand(chatID)
 	}
@@ -115,28 +201,28 @@ func (p *TelegramMessageProcessor) routeCommand(ctx context.Context, command, ar
 
 func (p *TelegramMessageProcessor) handleHistoricalCandles(ctx context.Context, chatID int64, symbol string) error {
 	if symbol == "" {
-		return p.sendError(chatID, "請輸入股票代號\n\n使用方式：\n/k 股票代號 - 查詢K線圖")
+		return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/k 股票代號 - 查詢K線圖")
 	}
 	return p.tgCommandUsecase.GetHistoricalCandlesChart(ctx, symbol, chatID)
 }
 
 func (p *TelegramMessageProcessor) handlePerformanceChart(ctx context.Context, chatID int64, symbol string) error {
 	if symbol == "" {
-		return p.sendError(chatID, "請輸入股票代號\n\n使用方式：\n/p 股票代號 - 查詢績效圖表")
+		return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/p 股票代號 - 查詢績效圖表")
 	}
 	return p.tgCommandUsecase.GetStockPerformanceChart(ctx, symbol, chatID)
 }
 
 func (p *TelegramMessageProcessor) handleStockPrice(ctx context.Context, chatID int64, symbol, rawDate string) error {
 	if symbol == "" {
-		return p.sendError(chatID, "請輸入股票代號\n\n使用方式：\n/d 股票代號 - 查詢今日股價\n/d 股票代號 2025-12-09 - 查詢指定日期股價")
+		return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/d 股票代號 - 查詢今日股價\n/d 股票代號 2025-12-09 - 查詢指定日期股價")
 	}
 
 	var datePtr *time.Time
 	if rawDate != "" {
 		parsed, err := p.parseDate(rawDate)
 		if err != nil {
-			return p.sendError(chatID, "日期格式錯誤，請使用 YYYY-MM-DD 格式\n例如：2025-12-09")
+			return p.sendError(ctx, chatID, "日期格式錯誤，請使用 YYYY-MM-DD 格式\n例如：2025-12-09")
 		}
 		datePtr = &parsed
 		return p.tgCommandUsecase.GetStockPrice(ctx, symbol, datePtr, chatID)
@@ -155,7 +241,7 @@ func (p *TelegramMessageProcessor) handleStockPrice(ctx context.Context, chatID
 
 func (p *TelegramMessageProcessor) handleRevenueChart(ctx context.Context, chatID int64, symbol string) error {
 	if symbol == "" {
-		return p.sendError(chatID, "請輸入股票代號\n\n使用方式：\n/r 股票代號 - 查詢月營收圖表")
+		return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/r 股票代號 - 查詢月營收圖表")
 	}
 	return p.tgCommandUsecase.GetStockRevenueChart(ctx, symbol, chatID)
 }
@@ -165,7 +251,7 @@ func (p *TelegramMessageProcessor) handleDailyMarket(ctx context.Context, chatID
 	if countStr != "" {
 		countInt, err := strconv.Atoi(countStr)
 		if countInt <= 0 || err != nil {
-			return p.sendError(chatID, "請輸入有效的數字，且大於0\n\n使用方式：\n/m [數量] - 查詢指定筆數的大盤資訊")
+			return p.sendError(ctx, chatID, "請輸入有效的數字，且大於0\n\n使用方式：\n/m [數量] - 查詢指定筆數的大盤資訊")
 		}
 		count = countInt
 	}
@@ -175,12 +261,12 @@ func (p *TelegramMessageProcessor) handleDailyMarket(ctx context.Context, chatID
 func (p *TelegramMessageProcessor) handleSubscribedItems(ctx context.Context, chatID int64, args string) error {
 	intValue, err := strconv.Atoi(args)
 	if err != nil {
-		return p.sendError(chatID, "請輸入有效的訂閱類型")
+		return p.sendError(ctx, chatID, "請輸入有效的訂閱類型")
 	}
 
 	item, err := valueobject.NewSubscriptionType(intValue)
 	if err != nil {
-		return p.sendError(chatID, "請輸入有效的訂閱類型")
+		return p.sendError(ctx, chatID, "請輸入有效的訂閱類型")
 	}
 
 	return p.tgCommandUsecase.SubscribedItems(ctx, chatID, item)
@@ -189,28 +275,220 @@ func (p *TelegramMessageProcessor) handleSubscribedItems(ctx context.Context, ch
 func (p *TelegramMessageProcessor) handleUnsubscribedItems(ctx context.Context, chatID int64, args string) error {
 	intValue, err := strconv.Atoi(args)
 	if err != nil {
-		return p.sendError(chatID, "請輸入有效的訂閱類型")
+		return p.sendError(ctx, chatID, "請輸入有效的訂閱類型")
 	}
 
 	item, err := valueobject.NewSubscriptionType(intValue)
 	if err != nil {
-		return p.sendError(chatID, "請輸入有效的訂閱類型")
+		return p.sendError(ctx, chatID, "請輸入有效的訂閱類型")
 	}
 
 	return p.tgCommandUsecase.UnsubscribedItems(ctx, chatID, item)
 }
 
+func (p *TelegramMessageProcessor) handleSubscriptionSchedule(ctx context.Context, chatID int64, args []string) error {
+	usage := "使用方式：\n/schedule - 查詢推播排程\n/schedule 項目 HH:MM - 設定每日推播時間\n/schedule 項目 cron - 以 cron 表達式設定排程\n/schedule 項目 morning - 次一交易日早上推播\n/schedule 項目 reset - 恢復預設推播時間\n例如：/schedule 3 14:00 或 /schedule 2 30 8 * * 1-5"
+	if len(args) == 0 {
+		return p.tgCommandUsecase.GetSubscriptionSchedules(ctx, chatID)
+	}
+	if len(args) < 2 {
+		return p.sendError(ctx, chatID, usage)
+	}
+
+	intValue, err := strconv.Atoi(args[0])
+	if err != nil {
+		return p.sendError(ctx, chatID, "請輸入有效的訂閱類型\n\n"+usage)
+	}
+
+	item, err := valueobject.NewSubscriptionType(intValue)
+	if err != nil {
+		return p.sendError(ctx, chatID, "請輸入有效的訂閱類型\n\n"+usage)
+	}
+
+	return p.tgCommandUsecase.SetSubscriptionSchedule(ctx, chatID, item, strings.Join(args[1:], " "))
+}
+
+func (p *TelegramMessageProcessor) handleWatchStock(ctx context.Context, chatID int64, symbol, listName string) error {
+	if symbol == "" {
+		return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/watch 股票代號 - 加入觀察清單\n/watch 股票代號 清單名稱 - 加入指定觀察清單")
+	}
+	return p.tgCommandUsecase.WatchStock(ctx, chatID, symbol, listName)
+}
+
+func (p *TelegramMessageProcessor) handleUnwatchStock(ctx context.Context, chatID int64, symbol, listName string) error {
+	if symbol == "" {
+		return p.sendError(ctx, chatID, "請輸入股票代號\n\n使用方式：\n/unwatch 股票代號 - 移出觀察清單\n/unwatch 股票代號 清單名稱 - 移出指定觀察清單")
+	}
+	return p.tgCommandUsecase.UnwatchStock(ctx, chatID, symbol, listName)
+}
+
+func (p *TelegramMessageProcessor) handleWatchlist(ctx context.Context, chatID int64, args []string) error {
+	if len(args) == 0 {
+		return p.tgCommandUsecase.GetWatchlist(ctx, chatID, "")
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "new":
+		if len(args) != 2 {
+			return p.sendError(ctx, chatID, "使用方式：\n/wl new 清單名稱 - 建立觀察清單")
+		}
+		return p.tgCommandUsecase.CreateWatchlist(ctx, chatID, args[1])
+	case "rename":
+		if len(args) != 3 {
+			return p.sendError(ctx, chatID, "使用方式：\n/wl rename 舊名稱 新名稱 - 重新命名觀察清單")
+		}
+		return p.tgCommandUsecase.RenameWatchlist(ctx, chatID, args[1], args[2])
+	case "del":
+		if len(args) != 2 {
+			return p.sendError(ctx, chatID, "使用方式：\n/wl del 清單名稱 - 刪除觀察清單")
+		}
+		return p.tgCommandUsecase.DeleteWatchlist(ctx, chatID, args[1])
+	case "mv":
+		if len(args) != 4 {
+			return p.sendError(ctx, chatID, "使用方式：\n/wl mv 股票代號 來源清單 目標清單 - 移動股票到其他清單")
+		}
+		return p.tgCommandUsecase.MoveWatchlistStock(ctx, chatID, args[1], args[2], args[3])
+	case "order":
+		if len(args) != 4 {
+			return p.sendError(ctx, chatID, "使用方式：\n/wl order 清單名稱 股票代號 位置 - 調整股票順序\n例如：/wl order 半導體 2330 1")
+		}
+		position, err := strconv.Atoi(args[3])
+		if err != nil || position <= 0 {
+			return p.sendError(ctx, chatID, "請輸入有效的位置，且大於0\n\n使用方式：\n/wl order 清單名稱 股票代號 位置 - 調整股票順序")
+		}
+		return p.tgCommandUsecase.ReorderWatchlistStock(ctx, chatID, args[1], args[2], position)
+	default:
+		return p.tgCommandUsecase.GetWatchlist(ctx, chatID, args[0])
+	}
+}
+
+func (p *TelegramMessageProcessor) handleTrade(ctx context.Context, chatID int64, side valueobject.TradeSide, args []string) error {
+	usage := fmt.Sprintf("使用方式：\n%[1]s 股票代號 股數 價格 [日期] - 記錄%[2]s\n例如：%[1]s 2330 1000 985.5 2025-01-10", "/"+string(side), side.GetName())
+	if len(args) < 3 || len(args) > 4 {
+		return p.sendError(ctx, chatID, usage)
+	}
+
+	quantity, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || quantity <= 0 {
+		return p.sendError(ctx, chatID, "請輸入有效的股數，且大於0\n\n"+usage)
+	}
+
+	price, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || price <= 0 {
+		return p.sendError(ctx, chatID, "請輸入有效的價格，且大於0\n\n"+usage)
+	}
+
+	now := time.Now()
+	tradeDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if len(args) == 4 {
+		tradeDate, err = p.parseDate(args[3])
+		if err != nil {
+			return p.sendError(ctx, chatID, "日期格式錯誤，請使用 YYYY-MM-DD 格式\n例如：2025-01-10")
+		}
+	}
+
+	return p.tgCommandUsecase.RecordTrade(ctx, chatID, args[0], side, quantity, price, tradeDate)
+}
+
+func (p *TelegramMessageProcessor) handlePortfolio(ctx context.Context, chatID int64, args []string) error {
+	if len(args) == 0 {
+		return p.tgCommandUsecase.GetPortfolio(ctx, chatID)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "log":
+		return p.tgCommandUsecase.GetPortfolioTrades(ctx, chatID)
+	case "del":
+		if len(args) != 2 {
+			return p.sendError(ctx, chatID, "使用方式：\n/pf del 編號 - 刪除交易紀錄\n\n可使用 /pf log 查詢編號")
+		}
+		tradeID, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil || tradeID == 0 {
+			return p.sendError(ctx, chatID, "請輸入有效的交易編號")
+		}
+		return p.tgCommandUsecase.DeletePortfolioTrade(ctx, chatID, uint(tradeID))
+	case "fee":
+		return p.handleTradingSetting(ctx, chatID, args[1:])
+	default:
+		return p.sendError(ctx, chatID, "使用方式：\n/pf - 查詢投資組合\n/pf log - 查詢交易紀錄\n/pf del 編號 - 刪除交易紀錄\n/pf fee - 查詢或設定交易成本")
+	}
+}
+
+func (p *TelegramMessageProcessor) handleTradingSetting(ctx context.Context, chatID int64, args []string) error {
+	usage := "使用方式：\n/pf fee - 查詢交易成本設定\n/pf fee 折扣 [最低手續費] - 設定券商手續費折扣\n例如：/pf fee 0.28 1 或 /pf fee 2.8 1"
+	if len(args) == 0 {
+		return p.tgCommandUsecase.GetTradingSetting(ctx, chatID)
+	}
+	if len(args) > 2 {
+		return p.sendError(ctx, chatID, usage)
+	}
+
+	feeDiscount, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || feeDiscount <= 0 {
+		return p.sendError(ctx, chatID, "請輸入有效的手續費折扣\n\n"+usage)
+	}
+
+	var minFee *float64
+	if len(args) == 2 {
+		value, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || value < 0 {
+			return p.sendError(ctx, chatID, "請輸入有效的最低手續費\n\n"+usage)
+		}
+		minFee = &value
+	}
+
+	return p.tgCommandUsecase.UpdateTradingSetting(ctx, chatID, feeDiscount, minFee)
+}
+
+func (p *TelegramMessageProcessor) handleUserPreference(ctx context.Context, chatID int64, args []string) error {
+	if len(args) == 0 {
+		return p.tgCommandUsecase.GetUserPreference(ctx, chatID)
+	}
+
+	change, err := parseUserPreferenceChange(args)
+	if err != nil {
+		return p.sendError(ctx, chatID, err.Error())
+	}
+	return p.tgCommandUsecase.UpdateUserPreference(ctx, chatID, change)
+}
+
+func (p *TelegramMessageProcessor) handlePriceAlert(ctx context.Context, chatID int64, args []string) error {
+	alert, err := parsePriceAlertArgs(args)
+	if err != nil {
+		return p.sendError(ctx, chatID, err.Error())
+	}
+
+	switch {
+	case alert.List:
+		return p.tgCommandUsecase.GetPriceAlerts(ctx, chatID)
+	case alert.DeleteID > 0:
+		return p.tgCommandUsecase.DeletePriceAlert(ctx, chatID, alert.DeleteID)
+	case alert.AlertType.IsPercentage():
+		return p.tgCommandUsecase.AddPercentageAlert(ctx, chatID, alert.Symbol, alert.AlertType, alert.Value)
+	default:
+		return p.tgCommandUsecase.AddPriceAlert(ctx, chatID, alert.Symbol, alert.Operator, alert.Value)
+	}
+}
+
 // func (p *TelegramMessageProcessor) handleUnknownCommand(chatID int64) error {
-// 	return p.sendError(chatID, "指令不存在，輸入 /start 查看說明")
+// 	return p.sendError(ctx, chatID, "指令不存在，輸入 /start 查看說明")
 // }
 
 // 輔助方法
 
-func (p *TelegramMessageProcessor) sendError(chatID int64, message string) error {
+// sendSymbolCandidates 列出符合的股票，點擊按鈕即以該股票代號執行原本的指令
+func (p *TelegramMessageProcessor) sendSymbolCandidates(ctx context.Context, chatID int64, command string, args []string, candidates []dto.SymbolCandidate) error {
+	input := args[symbolArgIndex(command, args)]
+	text := fmt.Sprintf("找到多檔符合「%s」的股票，請選擇：", html.EscapeString(input))
+	return p.tgClient.SendMessageWithKeyboard(ctx, chatID, text, newShortcutKeyboard(symbolCandidateShortcuts(command, args, candidates), 2))
+}
+
+func (p *TelegramMessageProcessor) sendError(ctx context.Context, chatID int64, message string) error {
 	p.logger.Warn("發送錯誤訊息",
 		logger.Int64("chat_id", chatID),
 		logger.String("message", message))
-	return p.tgClient.SendMessage(chatID, message)
+	// 訊息以 HTML 模式發送，需跳脫使用說明中的 < >
+	return p.tgClient.SendMessage(ctx, chatID, html.EscapeString(message))
 }
 
 func (p *TelegramMessageProcessor) parseMessageArgs(messageText string) (command, arg1, arg2 string) {
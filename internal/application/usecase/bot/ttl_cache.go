@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache 短時間快取，過期的項目在讀取時視為不存在；
+// 項目數達上限時先清除過期項目，仍然滿載則淘汰最早過期的項目
+type ttlCache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]ttlCacheEntry[V]
+	now        func() time.Time
+}
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration, maxEntries int) *ttlCache[V] {
+	return &ttlCache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]ttlCacheEntry[V]),
+		now:        time.Now,
+	}
+}
+
+// Get 取得尚未過期的快取值
+func (c *ttlCache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || c.now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set 寫入快取值
+func (c *ttlCache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.evictOldest()
+		}
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// evictOldest 淘汰最早過期（即最早寫入）的項目，呼叫端須持有鎖
+func (c *ttlCache[V]) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	found := false
+	for k, entry := range c.entries {
+		if !found || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest, found = k, entry.expiresAt, true
+		}
+	}
+	if found {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestTTLCache 建立以 now 指標控制時間的快取
+func newTestTTLCache(ttl time.Duration, maxEntries int, now *time.Time) *ttlCache[string] {
+	cache := newTTLCache[string](ttl, maxEntries)
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+func TestTTLCache_Expiry(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	cache := newTestTTLCache(time.Minute, 10, &now)
+	cache.Set("2330", "台積電")
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		wantOK  bool
+	}{
+		{name: "尚未過期", elapsed: 30 * time.Second, wantOK: true},
+		{name: "剛好到期仍可讀取", elapsed: time.Minute, wantOK: true},
+		{name: "過期後視為不存在", elapsed: time.Minute + time.Second, wantOK: false},
+	}
+
+	start := now
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = start.Add(tt.elapsed)
+			value, ok := cache.Get("2330")
+			if ok != tt.wantOK {
+				t.Fatalf("期望 %v，實際 %v", tt.wantOK, ok)
+			}
+			if ok && value != "台積電" {
+				t.Errorf("期望 台積電，實際 %s", value)
+			}
+		})
+	}
+
+	// 重新寫入後重新計算到期時間
+	cache.Set("2330", "台積電")
+	now = now.Add(30 * time.Second)
+	if _, ok := cache.Get("2330"); !ok {
+		t.Error("重新寫入後應可讀取")
+	}
+}
+
+func TestTTLCache_Eviction(t *testing.T) {
+	t.Run("滿載時先清除過期項目", func(t *testing.T) {
+		now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+		cache := newTestTTLCache(time.Minute, 3, &now)
+		cache.Set("a", "1")
+		cache.Set("b", "2")
+		now = now.Add(2 * time.Minute)
+		cache.Set("c", "3")
+		cache.Set("d", "4")
+
+		if len(cache.entries) != 2 {
+			t.Fatalf("項目數期望 2，實際 %d", len(cache.entries))
+		}
+		for _, key := range []string{"c", "d"} {
+			if _, ok := cache.Get(key); !ok {
+				t.Errorf("%s 應保留", key)
+			}
+		}
+	})
+
+	t.Run("沒有過期項目時淘汰最早寫入的項目", func(t *testing.T) {
+		now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+		cache := newTestTTLCache(time.Minute, 3, &now)
+		for _, key := range []string{"a", "b", "c"} {
+			cache.Set(key, key)
+			now = now.Add(time.Second)
+		}
+		cache.Set("d", "d")
+
+		if len(cache.entries) != 3 {
+			t.Fatalf("項目數期望 3，實際 %d", len(cache.entries))
+		}
+		if _, ok := cache.Get("a"); ok {
+			t.Error("最早寫入的 a 應被淘汰")
+		}
+		for _, key := range []string{"b", "c", "d"} {
+			if _, ok := cache.Get(key); !ok {
+				t.Errorf("%s 應保留", key)
+			}
+		}
+	})
+
+	t.Run("更新既有項目不淘汰其他項目", func(t *testing.T) {
+		now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+		cache := newTestTTLCache(time.Minute, 2, &now)
+		cache.Set("a", "1")
+		cache.Set("b", "2")
+		cache.Set("a", "3")
+
+		if value, ok := cache.Get("a"); !ok || value != "3" {
+			t.Errorf("a 期望更新為 3，實際 %q (%v)", value, ok)
+		}
+		if _, ok := cache.Get("b"); !ok {
+			t.Error("b 應保留")
+		}
+	})
+}
@@ -89,6 +89,42 @@ func (h *healthCheckUsecase) GetHealthStatus(ctx context.Context) (*HealthCheckR
 		"cpu_cores":       resourceStatus.CPUCores,
 	}
 
+	marketProviderStatus := h.checker.CheckMarketProviders(ctx)
+	capabilities := make(map[string]any, len(marketProviderStatus.Capabilities))
+	for _, capability := range marketProviderStatus.Capabilities {
+		providers := make([]map[string]any, len(capability.Providers))
+		for i, provider := range capability.Providers {
+			providers[i] = map[string]any{
+				"name":           provider.Name,
+				"served":         provider.Served,
+				"failed":         provider.Failed,
+				"last_served_at": provider.LastServedAt,
+				"last_failed_at": provider.LastFailedAt,
+				"last_error":     provider.LastError,
+			}
+		}
+		recent := make([]map[string]any, len(capability.Recent))
+		for i, request := range capability.Recent {
+			recent[i] = map[string]any{
+				"time":             request.Time,
+				"symbol":           request.Symbol,
+				"provider":         request.Provider,
+				"failed_providers": request.FailedProviders,
+				"error":            request.Error,
+			}
+		}
+		capabilities[capability.Capability] = map[string]any{
+			"status":    capability.Status,
+			"providers": providers,
+			"recent":    recent,
+		}
+	}
+	// 資料來源已有自動備援，備援狀態僅供參考，不影響整體狀態
+	checks["market_providers"] = map[string]any{
+		"status":       marketProviderStatus.Status,
+		"capabilities": capabilities,
+	}
+
 	overallStatus, overallHealthy := h.determineOverallStatus(dbStatus, finmindStatus, fugleStatus, syncStatus, resourceStatus)
 
 	response := &HealthCheckResponse{
@@ -9,10 +9,11 @@ import (
 )
 
 type mockHealthChecker struct {
-	checkDatabaseFunc   func(ctx context.Context) port.HealthStatus
-	checkAPIFunc        func(ctx context.Context, apiName string) port.HealthStatus
-	checkSyncStatusFunc func(ctx context.Context) port.SyncHealthStatus
-	checkResourcesFunc  func(ctx context.Context) port.ResourceHealthStatus
+	checkDatabaseFunc        func(ctx context.Context) port.HealthStatus
+	checkAPIFunc             func(ctx context.Context, apiName string) port.HealthStatus
+	checkSyncStatusFunc      func(ctx context.Context) port.SyncHealthStatus
+	checkResourcesFunc       func(ctx context.Context) port.ResourceHealthStatus
+	checkMarketProvidersFunc func(ctx context.Context) port.MarketProviderHealthStatus
 }
 
 func (m *mockHealthChecker) CheckDatabase(ctx context.Context) port.HealthStatus {
@@ -43,6 +44,13 @@ func (m *mockHealthChecker) CheckResources(ctx context.Context) port.ResourceHea
 	return port.ResourceHealthStatus{Status: "healthy", MemoryUsageMB: 100, Goroutines: 10, CPUCores: 4}
 }
 
+func (m *mockHealthChecker) CheckMarketProviders(ctx context.Context) port.MarketProviderHealthStatus {
+	if m.checkMarketProvidersFunc != nil {
+		return m.checkMarketProvidersFunc(ctx)
+	}
+	return port.MarketProviderHealthStatus{Status: "healthy"}
+}
+
 type mockLogger struct{}
 
 func (m *mockLogger) Info(msg string, fields ...logger.Field)  {}
@@ -145,6 +153,58 @@ func TestHealthCheckUsecase_GetHealthStatus_ResourcesDegraded(t *testing.T) {
 	}
 }
 
+func TestHealthCheckUsecase_GetHealthStatus_MarketProvidersFallback(t *testing.T) {
+	mockChecker := &mockHealthChecker{
+		checkMarketProvidersFunc: func(ctx context.Context) port.MarketProviderHealthStatus {
+			return port.MarketProviderHealthStatus{
+				Status: "degraded",
+				Capabilities: []port.MarketCapabilityStatus{
+					{
+						Capability: "daily_bars",
+						Status:     "degraded",
+						Providers: []port.MarketProviderStats{
+							{Name: "finmind", Failed: 1},
+							{Name: "fugle", Served: 1},
+						},
+						Recent: []port.MarketProviderRequest{
+							{Symbol: "2330", Provider: "fugle", FailedProviders: []string{"finmind"}},
+						},
+					},
+				},
+			}
+		},
+	}
+	usecase := NewHealthCheckUsecase(mockChecker, "test-service", "1.0.0", &mockLogger{})
+
+	response, err := usecase.GetHealthStatus(context.Background())
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// 資料來源已自動備援，不影響整體狀態
+	if response.Status != "healthy" {
+		t.Errorf("Expected status healthy, got %s", response.Status)
+	}
+
+	marketProviders, ok := response.Checks["market_providers"].(map[string]any)
+	if !ok {
+		t.Fatal("Expected market_providers check")
+	}
+	if marketProviders["status"] != "degraded" {
+		t.Errorf("Expected market_providers status degraded, got %v", marketProviders["status"])
+	}
+
+	capabilities := marketProviders["capabilities"].(map[string]any)
+	dailyBars, ok := capabilities["daily_bars"].(map[string]any)
+	if !ok {
+		t.Fatal("Expected daily_bars capability")
+	}
+	recent := dailyBars["recent"].([]map[string]any)
+	if len(recent) != 1 || recent[0]["provider"] != "fugle" {
+		t.Errorf("Expected latest request served by fugle, got %v", recent)
+	}
+}
+
 func TestHealthCheckUsecase_DetermineOverallStatus(t *testing.T) {
 	usecase := &healthCheckUsecase{}
 
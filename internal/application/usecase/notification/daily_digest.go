@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// digestNewsLimit 每日摘要中每檔股票顯示的新聞則數
+const digestNewsLimit = 3
+
+// DailyDigestUsecase 將訂閱每日摘要的使用者當日的大盤資訊、成交量排行、訂閱股票收盤與新聞合併為一則推播
+type DailyDigestUsecase interface {
+	SendDailyDigests(ctx context.Context, filter SubscriptionFilter) error
+}
+
+type dailyDigestUsecase struct {
+	subscriptionRepo       port.SubscriptionReader
+	subscriptionSymbolRepo port.SubscriptionSymbolRepository
+	marketDataUsecase      stock.MarketDataUsecase
+	formatterPort          port.FormatterPort
+	outbox                 NotificationOutbox
+	workers                int
+	logger                 logger.Logger
+}
+
+var _ DailyDigestUsecase = (*dailyDigestUsecase)(nil)
+
+func NewDailyDigestUsecase(
+	subscriptionRepo port.SubscriptionReader,
+	subscriptionSymbolRepo port.SubscriptionSymbolRepository,
+	marketDataUsecase stock.MarketDataUsecase,
+	formatterPort port.FormatterPort,
+	outbox NotificationOutbox,
+	log logger.Logger,
+) DailyDigestUsecase {
+	return &dailyDigestUsecase{
+		subscriptionRepo:       subscriptionRepo,
+		subscriptionSymbolRepo: subscriptionSymbolRepo,
+		marketDataUsecase:      marketDataUsecase,
+		formatterPort:          formatterPort,
+		outbox:                 outbox,
+		workers:                defaultNotificationWorkers,
+		logger:                 log,
+	}
+}
+
+// SendDailyDigests 推送每日摘要。大盤、成交量排行每次只查詢一次，訂閱股票的收盤與新聞每檔只查詢一次；
+// 部分資料取得失敗時仍送出其餘內容
+func (u *dailyDigestUsecase) SendDailyDigests(ctx context.Context, filter SubscriptionFilter) error {
+	subscriptions, err := u.subscriptionRepo.GetByFeatureID(ctx, uint(valueobject.SubscriptionTypeDailyDigest))
+	if err != nil {
+		return err
+	}
+	subscriptions = filterDigestSubscriptions(subscriptions, filter)
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeDailyDigest)
+	if err != nil {
+		return err
+	}
+
+	// 依使用者整理訂閱股票，同一檔股票只建立一份摘要資料
+	stocksBySymbol := make(map[string]*dto.DailyDigestStock)
+	stocks := make([]*dto.DailyDigestStock, 0)
+	userStocks := make(map[uint][]*dto.DailyDigestStock)
+	for _, subscriptionSymbol := range subscriptionSymbols {
+		if subscriptionSymbol.StockSymbol == nil {
+			continue
+		}
+		symbol := subscriptionSymbol.StockSymbol.Symbol
+		digestStock, ok := stocksBySymbol[symbol]
+		if !ok {
+			digestStock = &dto.DailyDigestStock{Symbol: symbol, Name: subscriptionSymbol.StockSymbol.Name}
+			stocksBySymbol[symbol] = digestStock
+			stocks = append(stocks, digestStock)
+		}
+		userStocks[subscriptionSymbol.UserID] = append(userStocks[subscriptionSymbol.UserID], digestStock)
+	}
+
+	var marketInfo []dto.DailyMarketInfo
+	if data, err := u.marketDataUsecase.GetDailyMarketInfo(ctx, 1); err != nil {
+		u.logger.Error("SendDailyDigests GetDailyMarketInfo Error", logger.Error(err))
+	} else if data != nil {
+		marketInfo = *data
+	}
+
+	var topVolume []dto.TopVolume
+	if data, err := u.marketDataUsecase.GetTopVolumeStock(ctx); err != nil {
+		u.logger.Error("SendDailyDigests GetTopVolumeStock Error", logger.Error(err))
+	} else if data != nil {
+		topVolume = *data
+	}
+
+	// 每個工作者只寫入自己負責的股票，不需額外加鎖
+	runWorkers(ctx, u.workers, stocks, func(ctx context.Context, digestStock *dto.DailyDigestStock) {
+		price, err := u.marketDataUsecase.GetStockPrice(ctx, digestStock.Symbol, nil)
+		if err != nil {
+			u.logger.Error("SendDailyDigests GetStockPrice Error",
+				logger.String("symbol", digestStock.Symbol),
+				logger.Error(err),
+			)
+		} else {
+			digestStock.Price = price
+		}
+
+		news, err := u.marketDataUsecase.GetStockNews(ctx, digestStock.Symbol, digestNewsLimit)
+		if err != nil {
+			u.logger.Error("SendDailyDigests GetStockNews Error",
+				logger.String("symbol", digestStock.Symbol),
+				logger.Error(err),
+			)
+		} else if news != nil {
+			digestStock.News = *news
+		}
+	})
+
+	now := time.Now()
+	runWorkers(ctx, u.workers, subscriptions, func(ctx context.Context, subscription *entity.Subscription) {
+		digest := &dto.DailyDigest{
+			Date:       now,
+			MarketInfo: marketInfo,
+			TopVolume:  topVolume,
+			Stocks:     userStocks[subscription.UserID],
+		}
+
+		payload, err := u.formatDigestPayload(digest, subscription.User.UserType)
+		if err != nil {
+			u.logger.Error("SendDailyDigests FormatDailyDigest Error",
+				logger.Any("userID", subscription.UserID),
+				logger.Error(err),
+			)
+			return
+		}
+
+		subscriptionID := subscription.ID
+		err = u.outbox.Enqueue(ctx, subscription.User, &dto.Notification{
+			Feature:        valueobject.SubscriptionTypeDailyDigest,
+			SubscriptionID: &subscriptionID,
+			Payload:        payload,
+		})
+		if err != nil {
+			u.logger.Error("SendDailyDigests Enqueue Error",
+				logger.String("accountID", subscription.User.AccountID),
+				logger.Error(err),
+			)
+		}
+	})
+	return nil
+}
+
+// formatDigestPayload 依使用者平台格式化每日摘要：Telegram 為單則 HTML 訊息，LINE 為 Flex carousel
+func (u *dailyDigestUsecase) formatDigestPayload(digest *dto.DailyDigest, userType valueobject.UserType) (dto.NotificationPayload, error) {
+	if userType != valueobject.UserTypeLine {
+		return dto.NotificationPayload{Text: u.formatterPort.FormatTelegramDailyDigest(digest)}, nil
+	}
+
+	message := u.formatterPort.FormatLineDailyDigest(digest)
+	payload := dto.NotificationPayload{Text: message.Text}
+	flex, err := json.Marshal(message.FlexContainer)
+	if err != nil {
+		return payload, err
+	}
+	payload.LineFlex = flex
+	return payload, nil
+}
+
+// filterDigestSubscriptions 篩選啟用中、本次排程到期的每日摘要訂閱，filter 為 nil 時不依排程篩選
+func filterDigestSubscriptions(subscriptions []*entity.Subscription, filter SubscriptionFilter) []*entity.Subscription {
+	filtered := make([]*entity.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if !subscription.IsActive() || subscription.User == nil {
+			continue
+		}
+		if filter != nil && !filter(subscription, nil) {
+			continue
+		}
+		filtered = append(filtered, subscription)
+	}
+	return filtered
+}
@@ -0,0 +1,194 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	formatterAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/formatter"
+)
+
+func newTestDigestUsecase(subscriptions []*entity.Subscription, subscriptionSymbols []*entity.SubscriptionSymbol, marketData *mockMarketDataUsecase, outbox *mockOutbox) *dailyDigestUsecase {
+	formatter := formatterAdapter.NewFormatterAdapter(nil, nil, formatterAdapter.NewTelegramFormatter(), formatterAdapter.NewLineFormatter())
+	return NewDailyDigestUsecase(
+		&mockSubscriptionReader{subscriptions: subscriptions},
+		&mockSubscriptionSymbolRepo{subscriptionSymbols: subscriptionSymbols},
+		marketData,
+		formatter,
+		outbox,
+		&mockLogger{},
+	).(*dailyDigestUsecase)
+}
+
+func TestDailyDigestUsecase_SendDailyDigests(t *testing.T) {
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	etf := &entity.StockSymbol{ID: 2, Symbol: "0050", Name: "元大台灣50", Market: "TWSE"}
+	telegramUser := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+	lineUser := &entity.User{ID: 2, AccountID: "U200", UserType: valueobject.UserTypeLine, Status: true}
+	noSymbolUser := &entity.User{ID: 3, AccountID: "300", UserType: valueobject.UserTypeTelegram, Status: true}
+
+	subscriptions := []*entity.Subscription{
+		{ID: 11, UserID: 1, Item: valueobject.SubscriptionTypeDailyDigest, Active: true, User: telegramUser},
+		{ID: 12, UserID: 2, Item: valueobject.SubscriptionTypeDailyDigest, Active: true, User: lineUser},
+		{ID: 13, UserID: 3, Item: valueobject.SubscriptionTypeDailyDigest, Active: true, User: noSymbolUser},
+		{ID: 14, UserID: 4, Item: valueobject.SubscriptionTypeDailyDigest, Active: false, User: &entity.User{ID: 4}},
+	}
+	subscriptionSymbols := []*entity.SubscriptionSymbol{
+		{ID: 1, UserID: 1, SymbolID: 1, StockSymbol: tsmc},
+		{ID: 2, UserID: 1, SymbolID: 2, StockSymbol: etf},
+		{ID: 3, UserID: 2, SymbolID: 1, StockSymbol: tsmc},
+	}
+	marketData := &mockMarketDataUsecase{
+		prices:    map[string]*dto.StockPrice{"2330": {Symbol: "2330", ClosePrice: 1005, ChangeAmount: 5, ChangeRate: 0.5}},
+		priceErrs: map[string]error{"0050": errors.New("timeout")},
+		news:      map[string][]dto.StockNews{"2330": {{Title: "台積電法說會", Link: "https://example.com/1"}}},
+	}
+	outbox := &mockOutbox{}
+	usecase := newTestDigestUsecase(subscriptions, subscriptionSymbols, marketData, outbox)
+
+	if err := usecase.SendDailyDigests(context.Background(), nil); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	// 同一檔股票只查詢一次收盤
+	if marketData.priceCalls["2330"] != 1 || marketData.priceCalls["0050"] != 1 {
+		t.Errorf("每檔股票期望查詢 1 次，實際 %v", marketData.priceCalls)
+	}
+
+	payloads := make(map[uint]dto.NotificationPayload)
+	for i, user := range outbox.users {
+		notification := outbox.notifications[i]
+		if notification.Feature != valueobject.SubscriptionTypeDailyDigest || notification.SubscriptionID == nil {
+			t.Errorf("推播內容期望為每日摘要並記錄訂閱，實際 %+v", notification)
+		}
+		payloads[user.ID] = notification.Payload
+	}
+	if len(payloads) != 3 {
+		t.Fatalf("期望推播給 3 位啟用中的使用者，實際 %d 位", len(payloads))
+	}
+
+	t.Run("多檔股票且其中一檔查詢失敗", func(t *testing.T) {
+		text := payloads[telegramUser.ID].Text
+		for _, want := range []string{"<b>台積電 (2330)</b> 1005.00", "台積電法說會", "<b>元大台灣50 (0050)</b> 暫無收盤資料"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("摘要應包含 %q，實際：\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("LINE 使用者收到 Flex carousel", func(t *testing.T) {
+		payload := payloads[lineUser.ID]
+		if len(payload.LineFlex) == 0 {
+			t.Fatal("LINE 摘要應包含 Flex 內容")
+		}
+		if !strings.Contains(string(payload.LineFlex), "2330") || strings.Contains(string(payload.LineFlex), "0050") {
+			t.Errorf("LINE 摘要應只包含使用者訂閱的股票，實際 %s", payload.LineFlex)
+		}
+	})
+
+	t.Run("沒有訂閱股票的使用者仍收到大盤摘要", func(t *testing.T) {
+		text := payloads[noSymbolUser.ID].Text
+		if !strings.Contains(text, "每日摘要") || strings.Contains(text, "訂閱股票") {
+			t.Errorf("摘要應只包含大盤部分，實際：\n%s", text)
+		}
+	})
+}
+
+func TestDailyDigestUsecase_FormatDigestPayload_Limits(t *testing.T) {
+	usecase := newTestDigestUsecase(nil, nil, &mockMarketDataUsecase{}, &mockOutbox{})
+
+	newDigest := func(count int) *dto.DailyDigest {
+		digest := &dto.DailyDigest{}
+		for i := 0; i < count; i++ {
+			symbol := fmt.Sprintf("%04d", 1000+i)
+			stock := &dto.DailyDigestStock{
+				Symbol: symbol,
+				Name:   "測試股票" + symbol,
+				Price:  &dto.StockPrice{Symbol: symbol, ClosePrice: 100},
+			}
+			for j := 0; j < digestNewsLimit; j++ {
+				stock.News = append(stock.News, dto.StockNews{
+					Title: strings.Repeat("新聞標題", 10),
+					Link:  fmt.Sprintf("https://example.com/news/%s/%d", symbol, j),
+				})
+			}
+			digest.Stocks = append(digest.Stocks, stock)
+		}
+		return digest
+	}
+
+	t.Run("Telegram 不超過單則訊息長度上限", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			stocks      int
+			wantOmitted bool
+		}{
+			{name: "少量股票完整顯示", stocks: 3},
+			{name: "股票過多時省略", stocks: 80, wantOmitted: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				payload, err := usecase.formatDigestPayload(newDigest(tt.stocks), valueobject.UserTypeTelegram)
+				if err != nil {
+					t.Fatalf("不應該發生錯誤: %v", err)
+				}
+				if length := utf8.RuneCountInString(payload.Text); length > 4096 {
+					t.Errorf("訊息長度不應超過 4096，實際 %d", length)
+				}
+				if omitted := strings.Contains(payload.Text, "因訊息長度限制未顯示"); omitted != tt.wantOmitted {
+					t.Errorf("省略附註期望 %v，實際 %v", tt.wantOmitted, omitted)
+				}
+				if !strings.Contains(payload.Text, "測試股票1000") {
+					t.Error("第一檔股票應顯示")
+				}
+			})
+		}
+	})
+
+	t.Run("LINE 不超過 carousel 上限", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			stocks       int
+			wantBubbles  int
+			wantOverflow string
+		}{
+			{name: "剛好放得下", stocks: 11, wantBubbles: 12},
+			{name: "超過上限時合併在最後一張", stocks: 20, wantBubbles: 12, wantOverflow: "其他 10 檔訂閱股票"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				payload, err := usecase.formatDigestPayload(newDigest(tt.stocks), valueobject.UserTypeLine)
+				if err != nil {
+					t.Fatalf("不應該發生錯誤: %v", err)
+				}
+
+				var carousel struct {
+					Contents []json.RawMessage `json:"contents"`
+				}
+				if err := json.Unmarshal(payload.LineFlex, &carousel); err != nil {
+					t.Fatalf("不應該發生錯誤: %v", err)
+				}
+				if len(carousel.Contents) != tt.wantBubbles {
+					t.Fatalf("bubble 數量期望 %d，實際 %d", tt.wantBubbles, len(carousel.Contents))
+				}
+
+				last := string(carousel.Contents[len(carousel.Contents)-1])
+				if tt.wantOverflow != "" && !strings.Contains(last, tt.wantOverflow) {
+					t.Errorf("最後一張應列出其餘股票，實際 %s", last)
+				}
+				if tt.wantOverflow == "" && strings.Contains(last, "其他") {
+					t.Errorf("未超過上限時不應有合併的 bubble，實際 %s", last)
+				}
+			})
+		}
+	})
+}
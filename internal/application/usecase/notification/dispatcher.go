@@ -0,0 +1,321 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// DefaultDispatchBatchSize 每次從佇列取出的投遞紀錄數量
+const DefaultDispatchBatchSize = 100
+
+// DefaultNotificationMaxRetries 送出失敗後預設的最多重試次數
+const DefaultNotificationMaxRetries = 5
+
+const (
+	// notificationRetryBaseDelay 第一次重試的等待時間，之後每次加倍
+	notificationRetryBaseDelay = time.Minute
+	// notificationRetryMaxDelay 重試等待時間上限
+	notificationRetryMaxDelay = 6 * time.Hour
+)
+
+// errUndeliverable 投遞紀錄本身無法送出（內容錯誤、使用者已停用等），重試也不會成功
+var errUndeliverable = errors.New("無法送出的通知")
+
+// NotificationDispatcher 依序送出佇列中的投遞紀錄並記錄送出結果，失敗的投遞依指數退避重試；
+// 送出前依使用者偏好設定，將勿擾時段內的投遞延後到時段結束，超過每日推播上限的投遞則略過
+type NotificationDispatcher interface {
+	DispatchQueued(ctx context.Context) error
+	RetryFailed(ctx context.Context) error
+}
+
+type notificationDispatcher struct {
+	deliveryRepo port.NotificationDeliveryRepository
+	userRepo     port.UserWriter
+	preferences  port.UserPreferenceReader
+	notifiers    map[valueobject.UserType]port.NotifierPort
+	batchSize    int
+	maxRetries   int
+	logger       logger.Logger
+}
+
+var _ NotificationDispatcher = (*notificationDispatcher)(nil)
+
+// NewNotificationDispatcher 建立通知佇列的送出者，依投遞紀錄的平台選擇推播管道；
+// maxRetries 為送出失敗後的最多重試次數
+func NewNotificationDispatcher(
+	deliveryRepo port.NotificationDeliveryRepository,
+	userRepo port.UserWriter,
+	preferences port.UserPreferenceReader,
+	notifiers []port.NotifierPort,
+	maxRetries int,
+	log logger.Logger,
+) NotificationDispatcher {
+	if maxRetries <= 0 {
+		maxRetries = DefaultNotificationMaxRetries
+	}
+	notifierByChannel := make(map[valueobject.UserType]port.NotifierPort, len(notifiers))
+	for _, notifier := range notifiers {
+		notifierByChannel[notifier.Channel()] = notifier
+	}
+	return &notificationDispatcher{
+		deliveryRepo: deliveryRepo,
+		userRepo:     userRepo,
+		preferences:  preferences,
+		notifiers:    notifierByChannel,
+		batchSize:    DefaultDispatchBatchSize,
+		maxRetries:   maxRetries,
+		logger:       log,
+	}
+}
+
+// DispatchQueued 送出所有已可送出的待送出投遞紀錄，直到佇列清空
+func (d *notificationDispatcher) DispatchQueued(ctx context.Context) error {
+	return d.drain(ctx, func() ([]*entity.NotificationDelivery, error) {
+		return d.deliveryRepo.GetQueuedDeliveries(ctx, time.Now(), d.batchSize)
+	})
+}
+
+// RetryFailed 重新送出已到重試時間的失敗投遞紀錄
+func (d *notificationDispatcher) RetryFailed(ctx context.Context) error {
+	return d.drain(ctx, func() ([]*entity.NotificationDelivery, error) {
+		return d.deliveryRepo.GetFailedDeliveries(ctx, time.Now(), d.batchSize)
+	})
+}
+
+// drain 反覆取出投遞紀錄並送出，直到沒有待處理的紀錄；
+// 更新投遞結果失敗時停止本輪處理，避免同一筆紀錄被重複送出
+func (d *notificationDispatcher) drain(ctx context.Context, fetch func() ([]*entity.NotificationDelivery, error)) error {
+	// 使用者今日已送出的數量，只在第一次需要時查詢，之後於本輪送出時累加
+	sentToday := make(map[uint]int64)
+	for {
+		deliveries, err := fetch()
+		if err != nil {
+			return err
+		}
+
+		preferences, err := d.loadPreferences(ctx, deliveries)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := d.dispatch(ctx, delivery, preferences, sentToday); err != nil {
+				return err
+			}
+		}
+
+		if len(deliveries) < d.batchSize {
+			return nil
+		}
+	}
+}
+
+// dispatch 送出單筆投遞紀錄並寫回結果，只有寫回失敗時回傳錯誤
+func (d *notificationDispatcher) dispatch(ctx context.Context, delivery *entity.NotificationDelivery, preferences map[uint]*entity.UserPreference, sentToday map[uint]int64) error {
+	held, err := d.applyPreference(ctx, delivery, preferences, sentToday, time.Now())
+	if err != nil {
+		return err
+	}
+	if held {
+		return d.deliveryRepo.Update(ctx, delivery)
+	}
+
+	err = d.send(ctx, delivery)
+	if err == nil {
+		delivery.MarkSent(time.Now(), deliveryResponse(nil, false))
+		if _, ok := sentToday[delivery.Event.UserID]; ok {
+			sentToday[delivery.Event.UserID]++
+		}
+		return d.deliveryRepo.Update(ctx, delivery)
+	}
+
+	permanent, recipientUnavailable := d.classifyFailure(delivery.Channel, err)
+	var nextRetryAt *time.Time
+	if !permanent {
+		nextRetryAt = d.nextRetryAt(delivery.Attempts+1, time.Now())
+	}
+	d.logger.Error("DispatchQueued Send Error",
+		logger.Any("deliveryID", delivery.ID),
+		logger.String("channel", delivery.Channel.GetName()),
+		logger.Int("attempts", delivery.Attempts+1),
+		logger.Bool("permanent", permanent),
+		logger.Error(err),
+	)
+	delivery.MarkFailed(deliveryResponse(err, permanent), nextRetryAt)
+
+	if recipientUnavailable {
+		d.deactivateUser(ctx, delivery.Event.User)
+	}
+
+	return d.deliveryRepo.Update(ctx, delivery)
+}
+
+// applyPreference 依使用者偏好設定處理投遞紀錄：勿擾時段內延後到時段結束、已達每日推播上限時略過；
+// 回傳 true 代表本次不送出
+func (d *notificationDispatcher) applyPreference(ctx context.Context, delivery *entity.NotificationDelivery, preferences map[uint]*entity.UserPreference, sentToday map[uint]int64, now time.Time) (bool, error) {
+	if delivery.Event == nil || delivery.Event.User == nil || !delivery.Event.User.IsActive() {
+		return false, nil
+	}
+	userID := delivery.Event.User.ID
+	preference, ok := preferences[userID]
+	if !ok {
+		return false, nil
+	}
+
+	if until, quiet := preference.QuietHoursEndAfter(now); quiet {
+		delivery.Defer(until)
+		d.logger.Info("使用者勿擾時段，延後推播",
+			logger.Any("deliveryID", delivery.ID),
+			logger.Any("userID", userID),
+			logger.Any("until", until),
+		)
+		return true, nil
+	}
+
+	if !preference.HasDailyLimit() {
+		return false, nil
+	}
+	sent, ok := sentToday[userID]
+	if !ok {
+		count, err := d.deliveryRepo.CountSentByUserSince(ctx, userID, preference.StartOfDay(now))
+		if err != nil {
+			return false, err
+		}
+		sent = count
+		sentToday[userID] = sent
+	}
+	if sent >= int64(preference.MaxDailyPushes) {
+		delivery.MarkSkipped(skippedResponse("已達每日推播上限"))
+		d.logger.Info("已達使用者每日推播上限，略過推播",
+			logger.Any("deliveryID", delivery.ID),
+			logger.Any("userID", userID),
+			logger.Int("maxDailyPushes", preference.MaxDailyPushes),
+		)
+		return true, nil
+	}
+	return false, nil
+}
+
+// loadPreferences 批次取得本批投遞紀錄使用者的偏好設定
+func (d *notificationDispatcher) loadPreferences(ctx context.Context, deliveries []*entity.NotificationDelivery) (map[uint]*entity.UserPreference, error) {
+	result := make(map[uint]*entity.UserPreference)
+	if d.preferences == nil || len(deliveries) == 0 {
+		return result, nil
+	}
+
+	seen := make(map[uint]bool)
+	userIDs := make([]uint, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		if delivery.Event == nil || seen[delivery.Event.UserID] {
+			continue
+		}
+		seen[delivery.Event.UserID] = true
+		userIDs = append(userIDs, delivery.Event.UserID)
+	}
+
+	preferences, err := d.preferences.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, preference := range preferences {
+		result[preference.UserID] = preference
+	}
+	return result, nil
+}
+
+func (d *notificationDispatcher) send(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	if delivery.Event == nil || delivery.Event.User == nil {
+		return fmt.Errorf("%w: 投遞紀錄缺少通知事件或使用者", errUndeliverable)
+	}
+
+	user := delivery.Event.User
+	if !user.IsActive() {
+		return fmt.Errorf("%w: 使用者已停用", errUndeliverable)
+	}
+
+	notifier, ok := d.notifiers[delivery.Channel]
+	if !ok {
+		return fmt.Errorf("%w: 不支援的推播平台: %s", errUndeliverable, delivery.Channel.GetName())
+	}
+
+	var payload dto.NotificationPayload
+	if err := json.Unmarshal([]byte(delivery.Event.Payload), &payload); err != nil {
+		return fmt.Errorf("%w: 推播內容格式錯誤: %v", errUndeliverable, err)
+	}
+
+	return notifier.Push(ctx, user.AccountID, payload)
+}
+
+// nextRetryAt 依已嘗試次數計算下次重試時間（指數退避），超過重試上限時回傳 nil
+func (d *notificationDispatcher) nextRetryAt(attempts int, now time.Time) *time.Time {
+	if attempts > d.maxRetries {
+		return nil
+	}
+
+	delay := notificationRetryMaxDelay
+	if shift := attempts - 1; shift < 16 {
+		if backoff := notificationRetryBaseDelay << shift; backoff < delay {
+			delay = backoff
+		}
+	}
+	retryAt := now.Add(delay)
+	return &retryAt
+}
+
+// deactivateUser 使用者封鎖 bot 或聊天室已不存在時停用帳號，之後不再推播
+func (d *notificationDispatcher) deactivateUser(ctx context.Context, user *entity.User) {
+	if d.userRepo == nil || user == nil || !user.IsActive() {
+		return
+	}
+	if err := d.userRepo.UpdateStatus(ctx, user.ID, false); err != nil {
+		d.logger.Error("DispatchQueued UpdateStatus Error",
+			logger.Any("userID", user.ID),
+			logger.Error(err),
+		)
+		return
+	}
+	user.Disable()
+	d.logger.Info("使用者已無法接收推播，停用帳號", logger.Any("userID", user.ID))
+}
+
+// classifyFailure 判斷送出失敗是否為永久性錯誤，以及是否因使用者封鎖 bot 等原因無法再送達
+func (d *notificationDispatcher) classifyFailure(channel valueobject.UserType, err error) (permanent bool, recipientUnavailable bool) {
+	if errors.Is(err, errUndeliverable) {
+		return true, false
+	}
+
+	notifier, ok := d.notifiers[channel]
+	if !ok {
+		return false, false
+	}
+	recipientUnavailable = notifier.IsRecipientUnavailable(err)
+	return recipientUnavailable || notifier.IsPermanentError(err), recipientUnavailable
+}
+
+// deliveryResponse 將送出結果轉為投遞紀錄的 JSON 內容
+func deliveryResponse(err error, permanent bool) string {
+	response := dto.NotificationDeliveryResponse{Permanent: permanent}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	data, _ := json.Marshal(response)
+	return string(data)
+}
+
+// skippedResponse 將略過原因轉為投遞紀錄的 JSON 內容
+func skippedResponse(reason string) string {
+	data, _ := json.Marshal(dto.NotificationDeliveryResponse{SkippedReason: reason})
+	return string(data)
+}
@@ -0,0 +1,285 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// queuedDelivery 建立屬於指定使用者的待送出 Telegram 投遞紀錄
+func queuedDelivery(user *entity.User) *entity.NotificationDelivery {
+	return &entity.NotificationDelivery{
+		ID:      1,
+		EventID: 1,
+		Channel: valueobject.UserTypeTelegram,
+		Status:  valueobject.NotificationDeliveryStatusQueued,
+		Event: &entity.NotificationEvent{
+			ID:      1,
+			UserID:  user.ID,
+			User:    user,
+			Payload: `{"text":"2330 收盤 1000"}`,
+		},
+	}
+}
+
+func TestNotificationDispatcher_NextRetryAt(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		maxRetries int
+		attempts   int
+		wantDelay  time.Duration
+		wantNil    bool
+	}{
+		{name: "第一次重試等待基本時間", maxRetries: 5, attempts: 1, wantDelay: time.Minute},
+		{name: "每次重試等待時間加倍", maxRetries: 5, attempts: 3, wantDelay: 4 * time.Minute},
+		{name: "最後一次重試", maxRetries: 5, attempts: 5, wantDelay: 16 * time.Minute},
+		{name: "超過重試上限不再重試", maxRetries: 5, attempts: 6, wantNil: true},
+		{name: "等待時間未達上限", maxRetries: 20, attempts: 9, wantDelay: 256 * time.Minute},
+		{name: "等待時間不超過上限", maxRetries: 20, attempts: 10, wantDelay: 6 * time.Hour},
+		{name: "位移量過大時使用上限", maxRetries: 100, attempts: 80, wantDelay: 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := NewNotificationDispatcher(&mockNotificationDeliveryRepository{}, nil, nil, nil, tt.maxRetries, &mockLogger{}).(*notificationDispatcher)
+
+			got := dispatcher.nextRetryAt(tt.attempts, now)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("期望不再重試，實際 %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("期望有下次重試時間，實際為 nil")
+			}
+			if delay := got.Sub(now); delay != tt.wantDelay {
+				t.Errorf("等待時間期望 %v，實際 %v", tt.wantDelay, delay)
+			}
+		})
+	}
+}
+
+func TestNotificationDispatcher_ClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name                     string
+		channel                  valueobject.UserType
+		err                      error
+		wantPermanent            bool
+		wantRecipientUnavailable bool
+	}{
+		{name: "投遞紀錄無法送出", channel: valueobject.UserTypeTelegram, err: fmt.Errorf("%w: 使用者已停用", errUndeliverable), wantPermanent: true},
+		{name: "使用者封鎖 bot", channel: valueobject.UserTypeTelegram, err: fmt.Errorf("push: %w", errRecipientBlocked), wantPermanent: true, wantRecipientUnavailable: true},
+		{name: "平台回報永久性錯誤", channel: valueobject.UserTypeTelegram, err: errBadRequest, wantPermanent: true},
+		{name: "暫時性錯誤", channel: valueobject.UserTypeTelegram, err: errors.New("timeout")},
+		{name: "未設定推播管道的平台", channel: valueobject.UserTypeLine, err: errRecipientBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &mockNotifier{channel: valueobject.UserTypeTelegram}
+			dispatcher := NewNotificationDispatcher(&mockNotificationDeliveryRepository{}, nil, nil, []port.NotifierPort{notifier}, 0, &mockLogger{}).(*notificationDispatcher)
+
+			permanent, recipientUnavailable := dispatcher.classifyFailure(tt.channel, tt.err)
+			if permanent != tt.wantPermanent {
+				t.Errorf("永久性錯誤期望 %v，實際 %v", tt.wantPermanent, permanent)
+			}
+			if recipientUnavailable != tt.wantRecipientUnavailable {
+				t.Errorf("無法送達期望 %v，實際 %v", tt.wantRecipientUnavailable, recipientUnavailable)
+			}
+		})
+	}
+}
+
+func TestNotificationDispatcher_DispatchQueued_SendFailure(t *testing.T) {
+	tests := []struct {
+		name           string
+		pushErr        error
+		wantRetry      bool
+		wantUserActive bool
+	}{
+		{name: "暫時性錯誤排定重試", pushErr: errors.New("timeout"), wantRetry: true, wantUserActive: true},
+		{name: "永久性錯誤不重試", pushErr: errBadRequest, wantUserActive: true},
+		{name: "使用者封鎖 bot 時停用帳號", pushErr: errRecipientBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entity.User{ID: 7, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+			delivery := queuedDelivery(user)
+			deliveryRepo := &mockNotificationDeliveryRepository{queued: []*entity.NotificationDelivery{delivery}}
+			userRepo := &mockUserWriter{}
+			notifier := &mockNotifier{channel: valueobject.UserTypeTelegram, pushErr: tt.pushErr}
+			dispatcher := NewNotificationDispatcher(deliveryRepo, userRepo, nil, []port.NotifierPort{notifier}, 0, &mockLogger{})
+
+			if err := dispatcher.DispatchQueued(context.Background()); err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+
+			if len(deliveryRepo.updated) != 1 {
+				t.Fatalf("寫回的投遞紀錄期望 1 筆，實際 %d 筆", len(deliveryRepo.updated))
+			}
+			if delivery.Status != valueobject.NotificationDeliveryStatusFailed || delivery.Attempts != 1 {
+				t.Errorf("投遞紀錄應為失敗且嘗試 1 次，實際狀態 %s、嘗試 %d 次", delivery.Status, delivery.Attempts)
+			}
+			if (delivery.NextRetryAt != nil) != tt.wantRetry {
+				t.Errorf("排定重試期望 %v，實際下次重試時間 %v", tt.wantRetry, delivery.NextRetryAt)
+			}
+			if user.IsActive() != tt.wantUserActive {
+				t.Errorf("使用者啟用狀態期望 %v，實際 %v", tt.wantUserActive, user.IsActive())
+			}
+			status, updated := userRepo.statusUpdates[user.ID]
+			if tt.wantUserActive && updated {
+				t.Errorf("不應寫回使用者狀態，實際 %v", status)
+			}
+			if !tt.wantUserActive && (!updated || status) {
+				t.Errorf("應寫回停用使用者，實際 %v", userRepo.statusUpdates)
+			}
+		})
+	}
+}
+
+func TestNotificationDispatcher_DispatchQueued_Sent(t *testing.T) {
+	user := &entity.User{ID: 7, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+	delivery := queuedDelivery(user)
+	deliveryRepo := &mockNotificationDeliveryRepository{queued: []*entity.NotificationDelivery{delivery}}
+	notifier := &mockNotifier{channel: valueobject.UserTypeTelegram}
+	dispatcher := NewNotificationDispatcher(deliveryRepo, &mockUserWriter{}, nil, []port.NotifierPort{notifier}, 0, &mockLogger{})
+
+	if err := dispatcher.DispatchQueued(context.Background()); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	if len(notifier.payloads) != 1 || notifier.payloads[0].Text != "2330 收盤 1000" {
+		t.Errorf("推播內容不符，實際 %+v", notifier.payloads)
+	}
+	if delivery.Status != valueobject.NotificationDeliveryStatusSent || delivery.SentAt == nil {
+		t.Errorf("投遞紀錄應為已送出，實際 %+v", delivery)
+	}
+	if len(deliveryRepo.updated) != 1 {
+		t.Errorf("寫回的投遞紀錄期望 1 筆，實際 %d 筆", len(deliveryRepo.updated))
+	}
+}
+
+func TestNotificationDispatcher_ApplyPreference(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*3600)
+	now := time.Date(2026, 10, 14, 23, 0, 0, 0, taipei)
+
+	tests := []struct {
+		name       string
+		preference *entity.UserPreference
+		userActive bool
+		sentCount  int64
+		wantHeld   bool
+		wantStatus valueobject.NotificationDeliveryStatus
+		wantUntil  *time.Time
+	}{
+		{
+			name:       "未設定偏好直接送出",
+			userActive: true,
+			wantStatus: valueobject.NotificationDeliveryStatusQueued,
+		},
+		{
+			name:       "勿擾時段內延後到時段結束",
+			preference: &entity.UserPreference{UserID: 7, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", Timezone: "Asia/Taipei"},
+			userActive: true,
+			wantHeld:   true,
+			wantStatus: valueobject.NotificationDeliveryStatusQueued,
+			wantUntil:  func() *time.Time { until := time.Date(2026, 10, 15, 7, 0, 0, 0, taipei); return &until }(),
+		},
+		{
+			name:       "勿擾時段外直接送出",
+			preference: &entity.UserPreference{UserID: 7, QuietHoursStart: "12:00", QuietHoursEnd: "14:00", Timezone: "Asia/Taipei"},
+			userActive: true,
+			wantStatus: valueobject.NotificationDeliveryStatusQueued,
+		},
+		{
+			name:       "已達每日推播上限時略過",
+			preference: &entity.UserPreference{UserID: 7, Timezone: "Asia/Taipei", MaxDailyPushes: 3},
+			userActive: true,
+			sentCount:  3,
+			wantHeld:   true,
+			wantStatus: valueobject.NotificationDeliveryStatusSkipped,
+		},
+		{
+			name:       "未達每日推播上限直接送出",
+			preference: &entity.UserPreference{UserID: 7, Timezone: "Asia/Taipei", MaxDailyPushes: 3},
+			userActive: true,
+			sentCount:  2,
+			wantStatus: valueobject.NotificationDeliveryStatusQueued,
+		},
+		{
+			name:       "停用的使用者不套用偏好",
+			preference: &entity.UserPreference{UserID: 7, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", Timezone: "Asia/Taipei"},
+			wantStatus: valueobject.NotificationDeliveryStatusQueued,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entity.User{ID: 7, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: tt.userActive}
+			delivery := queuedDelivery(user)
+			preferences := make(map[uint]*entity.UserPreference)
+			if tt.preference != nil {
+				preferences[tt.preference.UserID] = tt.preference
+			}
+			deliveryRepo := &mockNotificationDeliveryRepository{sentCount: tt.sentCount}
+			dispatcher := NewNotificationDispatcher(deliveryRepo, nil, nil, nil, 0, &mockLogger{}).(*notificationDispatcher)
+
+			held, err := dispatcher.applyPreference(context.Background(), delivery, preferences, make(map[uint]int64), now)
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if held != tt.wantHeld {
+				t.Errorf("暫不送出期望 %v，實際 %v", tt.wantHeld, held)
+			}
+			if delivery.Status != tt.wantStatus {
+				t.Errorf("投遞狀態期望 %s，實際 %s", tt.wantStatus, delivery.Status)
+			}
+			if delivery.Attempts != 0 {
+				t.Errorf("套用偏好不應計入嘗試次數，實際 %d 次", delivery.Attempts)
+			}
+			if tt.wantUntil == nil && delivery.NextRetryAt != nil {
+				t.Errorf("不應遞延，實際遞延到 %v", delivery.NextRetryAt)
+			}
+			if tt.wantUntil != nil && (delivery.NextRetryAt == nil || !delivery.NextRetryAt.Equal(*tt.wantUntil)) {
+				t.Errorf("遞延時間期望 %v，實際 %v", tt.wantUntil, delivery.NextRetryAt)
+			}
+		})
+	}
+}
+
+func TestNotificationDispatcher_DispatchQueued_DailyLimitCountsSentInRun(t *testing.T) {
+	user := &entity.User{ID: 7, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+	first := queuedDelivery(user)
+	second := queuedDelivery(user)
+	second.ID = 2
+	deliveryRepo := &mockNotificationDeliveryRepository{sentCount: 1, queued: []*entity.NotificationDelivery{first, second}}
+	preferences := &mockUserPreferenceReader{preferences: []*entity.UserPreference{
+		{UserID: 7, Timezone: "Asia/Taipei", MaxDailyPushes: 2},
+	}}
+	notifier := &mockNotifier{channel: valueobject.UserTypeTelegram}
+	dispatcher := NewNotificationDispatcher(deliveryRepo, &mockUserWriter{}, preferences, []port.NotifierPort{notifier}, 0, &mockLogger{})
+
+	if err := dispatcher.DispatchQueued(context.Background()); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	if first.Status != valueobject.NotificationDeliveryStatusSent {
+		t.Errorf("第一筆期望已送出，實際 %s", first.Status)
+	}
+	if second.Status != valueobject.NotificationDeliveryStatusSkipped {
+		t.Errorf("本輪送出後已達上限，第二筆期望略過，實際 %s", second.Status)
+	}
+	if len(notifier.payloads) != 1 {
+		t.Errorf("推播次數期望 1，實際 %d", len(notifier.payloads))
+	}
+}
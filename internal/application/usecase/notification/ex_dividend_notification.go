@@ -0,0 +1,200 @@
+package notification
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// DefaultExDividendReminderDays 預設在除權息日前幾個交易日提醒
+const DefaultExDividendReminderDays = 2
+
+// exDividendCalendarDays 查詢交易日曆的天數，需涵蓋提醒天數加上連假
+const exDividendCalendarDays = 60
+
+// ExDividendNotificationUsecase 在除權息日前 N 個交易日推送提醒
+type ExDividendNotificationUsecase interface {
+	SendExDividendReminders(ctx context.Context, filter SubscriptionFilter) error
+}
+
+type exDividendNotificationUsecase struct {
+	subscriptionSymbolRepo port.SubscriptionSymbolRepository
+	marketDataPort         port.MarketDataPort
+	tradeDateReader        port.TradeDateReader
+	formatterPort          port.FormatterPort
+	outbox                 NotificationOutbox
+	reminderDays           int
+	logger                 logger.Logger
+}
+
+var _ ExDividendNotificationUsecase = (*exDividendNotificationUsecase)(nil)
+
+func NewExDividendNotificationUsecase(
+	subscriptionSymbolRepo port.SubscriptionSymbolRepository,
+	marketDataPort port.MarketDataPort,
+	tradeDateReader port.TradeDateReader,
+	formatterPort port.FormatterPort,
+	outbox NotificationOutbox,
+	reminderDays int,
+	log logger.Logger,
+) ExDividendNotificationUsecase {
+	if reminderDays <= 0 {
+		reminderDays = DefaultExDividendReminderDays
+	}
+	return &exDividendNotificationUsecase{
+		subscriptionSymbolRepo: subscriptionSymbolRepo,
+		marketDataPort:         marketDataPort,
+		tradeDateReader:        tradeDateReader,
+		formatterPort:          formatterPort,
+		outbox:                 outbox,
+		reminderDays:           reminderDays,
+		logger:                 log,
+	}
+}
+
+// SendExDividendReminders 對訂閱除權息提醒的使用者，檢查其訂閱股票是否剛好在 N 個交易日後除權息。
+// 僅在交易日執行，且每個除權息日只會在剛好相差 N 個交易日的那一天提醒一次
+func (u *exDividendNotificationUsecase) SendExDividendReminders(ctx context.Context, filter SubscriptionFilter) error {
+	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeExDividend)
+	if err != nil {
+		return err
+	}
+	subscriptionSymbols = filterSubscriptionSymbols(subscriptionSymbols, filter)
+	if len(subscriptionSymbols) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	tradeDates, err := u.tradeDateReader.GetByDateRange(ctx, now.AddDate(0, 0, -1), now.AddDate(0, 0, exDividendCalendarDays))
+	if err != nil {
+		return err
+	}
+	calendar := make([]string, 0, len(tradeDates))
+	for _, tradeDate := range tradeDates {
+		calendar = append(calendar, tradeDate.Date.Format("2006-01-02"))
+	}
+	sort.Strings(calendar)
+
+	today := now.Format("2006-01-02")
+	if !containsDate(calendar, today) {
+		u.logger.Info("今日非交易日，略過除權息提醒")
+		return nil
+	}
+
+	// 依股票分組，同一檔股票只查詢一次股利
+	subscribersBySymbol := make(map[string][]*entity.SubscriptionSymbol)
+	for _, subscriptionSymbol := range subscriptionSymbols {
+		if subscriptionSymbol.StockSymbol == nil || subscriptionSymbol.User == nil {
+			continue
+		}
+		symbol := subscriptionSymbol.StockSymbol.Symbol
+		subscribersBySymbol[symbol] = append(subscribersBySymbol[symbol], subscriptionSymbol)
+	}
+
+	for symbol, subscribers := range subscribersBySymbol {
+		dividends, err := u.marketDataPort.GetStockDividends(ctx, symbol, now.AddDate(-1, 0, 0), now)
+		if err != nil {
+			u.logger.Error("SendExDividendReminders GetStockDividends Error",
+				logger.String("symbol", symbol),
+				logger.Error(err),
+			)
+			continue
+		}
+
+		for _, dividend := range dividends {
+			for _, exDate := range exDividendDates(dividend) {
+				daysLeft, ok := tradingDaysUntil(today, exDate.Format("2006-01-02"), calendar)
+				if !ok || daysLeft != u.reminderDays {
+					continue
+				}
+
+				for _, subscriber := range subscribers {
+					u.sendReminder(ctx, subscriber, &dto.ExDividendReminder{
+						Symbol:          symbol,
+						Name:            subscriber.StockSymbol.Name,
+						ExDividendDate:  exDate,
+						TradingDaysLeft: daysLeft,
+						Dividend:        dividend,
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (u *exDividendNotificationUsecase) sendReminder(ctx context.Context, subscriber *entity.SubscriptionSymbol, reminder *dto.ExDividendReminder) {
+	user := subscriber.User
+	data := u.formatterPort.FormatExDividendReminder(reminder, user.UserType)
+
+	notification := subscriptionNotification(subscriber, valueobject.SubscriptionTypeExDividend, dto.NotificationPayload{Text: data})
+	if err := u.outbox.Enqueue(ctx, user, notification); err != nil {
+		u.logger.Error("SendExDividendReminders Enqueue Error",
+			logger.String("accountID", user.AccountID),
+			logger.String("data", data),
+			logger.Error(err),
+		)
+	}
+}
+
+// exDividendDates 取得股利資料中已確定的除息日與除權日，同一天時只回傳一次
+func exDividendDates(dividend dto.StockDividend) []time.Time {
+	dates := make([]time.Time, 0, 2)
+	if dividend.CashDividend > 0 && !dividend.CashExDividendDate.IsZero() {
+		dates = append(dates, dividend.CashExDividendDate)
+	}
+	if dividend.StockDividend > 0 && !dividend.StockExDividendDate.IsZero() &&
+		!dividend.StockExDividendDate.Equal(dividend.CashExDividendDate) {
+		dates = append(dates, dividend.StockExDividendDate)
+	}
+	return dates
+}
+
+// tradingDaysUntil 計算 today（不含）到 target（含）之間的交易日數，日期格式為 YYYY-MM-DD。
+// calendar 為已排序的交易日；超出交易日資料範圍的日期以週一至週五估算
+func tradingDaysUntil(today, target string, calendar []string) (int, bool) {
+	if target <= today {
+		return 0, false
+	}
+
+	count := 0
+	last := today
+	for _, date := range calendar {
+		if date <= today {
+			continue
+		}
+		if date > target {
+			return count, true
+		}
+		count++
+		last = date
+	}
+
+	// 交易日資料未涵蓋目標日期時，以平日估算剩餘天數
+	day, err := time.Parse("2006-01-02", last)
+	if err != nil {
+		return 0, false
+	}
+	for {
+		day = day.AddDate(0, 0, 1)
+		if day.Format("2006-01-02") > target {
+			break
+		}
+		if isWeekday(day) {
+			count++
+		}
+	}
+	return count, true
+}
+
+// containsDate 檢查已排序的交易日中是否包含指定日期
+func containsDate(calendar []string, date string) bool {
+	index := sort.SearchStrings(calendar, date)
+	return index < len(calendar) && calendar[index] == date
+}
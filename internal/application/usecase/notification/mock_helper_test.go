@@ -0,0 +1,382 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// mockSubscriptionReader 用於測試的 SubscriptionReader mock
+type mockSubscriptionReader struct {
+	subscriptions []*entity.Subscription
+}
+
+func (m *mockSubscriptionReader) GetByID(ctx context.Context, id uint) (*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockSubscriptionReader) GetByUserID(ctx context.Context, userID uint) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockSubscriptionReader) GetByFeatureID(ctx context.Context, featureID uint) ([]*entity.Subscription, error) {
+	result := make([]*entity.Subscription, 0)
+	for _, subscription := range m.subscriptions {
+		if uint(subscription.Item) == featureID {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSubscriptionReader) GetByUserAndFeature(ctx context.Context, userID, featureID uint) (*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockSubscriptionReader) GetByStatus(ctx context.Context, status bool) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockSubscriptionReader) List(ctx context.Context, offset, limit int) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockSubscriptionReader) GetActiveSubscriptions(ctx context.Context) ([]*entity.Subscription, error) {
+	return m.subscriptions, nil
+}
+
+func (m *mockSubscriptionReader) GetBySchedule(ctx context.Context, scheduleCron string) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockSubscriptionReader) GetUserSubscriptionList(ctx context.Context, userID uint) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+// mockSendNotification 記錄各推播任務被呼叫時通過篩選的訂閱股票
+type mockSendNotification struct {
+	mu                  sync.Mutex
+	subscriptionSymbols []*entity.SubscriptionSymbol
+	stockInfo           []*entity.SubscriptionSymbol
+}
+
+func (m *mockSendNotification) record(target *[]*entity.SubscriptionSymbol, filter SubscriptionFilter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*target = append(*target, filterSubscriptionSymbols(m.subscriptionSymbols, filter)...)
+}
+
+func (m *mockSendNotification) SendStockPriceNotification(ctx context.Context, filter SubscriptionFilter) error {
+	m.record(&m.stockInfo, filter)
+	return nil
+}
+
+func (m *mockSendNotification) SendStockNewsNotification(ctx context.Context, filter SubscriptionFilter) error {
+	return nil
+}
+
+func (m *mockSendNotification) SendMarketInfoNotification(ctx context.Context, filter SubscriptionFilter) error {
+	return nil
+}
+
+func (m *mockSendNotification) SendTopVolumeNotification(ctx context.Context, filter SubscriptionFilter) error {
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(msg string, fields ...logger.Field)  {}
+func (m *mockLogger) Error(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Warn(msg string, fields ...logger.Field)  {}
+func (m *mockLogger) Debug(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Panic(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Fatal(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Sync() error                              { return nil }
+
+// mockTradeDateReader 用於測試的 TradeDateReader mock
+type mockTradeDateReader struct {
+	tradeDates []*entity.TradeDate
+}
+
+func (m *mockTradeDateReader) GetByID(ctx context.Context, id uint) (*entity.TradeDate, error) {
+	return nil, nil
+}
+
+func (m *mockTradeDateReader) GetByDate(ctx context.Context, date time.Time) (*entity.TradeDate, error) {
+	return nil, nil
+}
+
+func (m *mockTradeDateReader) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.TradeDate, error) {
+	return m.tradeDates, nil
+}
+
+// mockNotificationEventWriter 模擬去重鍵的唯一索引，相同去重鍵第二次建立時回傳 ErrAlreadyExists
+type mockNotificationEventWriter struct {
+	events     []*entity.NotificationEvent
+	deliveries []*entity.NotificationDelivery
+	createErr  error
+}
+
+func (m *mockNotificationEventWriter) Create(ctx context.Context, event *entity.NotificationEvent) error {
+	return nil
+}
+
+func (m *mockNotificationEventWriter) CreateWithDelivery(ctx context.Context, event *entity.NotificationEvent, delivery *entity.NotificationDelivery) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	for _, existing := range m.events {
+		if event.DedupeKey != "" && existing.DedupeKey == event.DedupeKey {
+			return domainerror.ErrAlreadyExists
+		}
+	}
+	m.events = append(m.events, event)
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *mockNotificationEventWriter) Update(ctx context.Context, event *entity.NotificationEvent) error {
+	return nil
+}
+
+func (m *mockNotificationEventWriter) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *mockNotificationEventWriter) BatchCreate(ctx context.Context, events []*entity.NotificationEvent) error {
+	return nil
+}
+
+// mockNotificationDeliveryRepository 第一次查詢時回傳 queued，之後回傳空佇列，並記錄寫回的投遞紀錄
+type mockNotificationDeliveryRepository struct {
+	queued    []*entity.NotificationDelivery
+	sentCount int64
+	updated   []*entity.NotificationDelivery
+}
+
+func (m *mockNotificationDeliveryRepository) GetByID(ctx context.Context, id uint) (*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) GetByEventID(ctx context.Context, eventID uint) ([]*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) GetByChannel(ctx context.Context, channel valueobject.UserType) ([]*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) GetByStatus(ctx context.Context, status valueobject.NotificationDeliveryStatus) ([]*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) List(ctx context.Context, offset, limit int) ([]*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) GetFailedDeliveries(ctx context.Context, before time.Time, limit int) ([]*entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationDeliveryRepository) GetQueuedDeliveries(ctx context.Context, before time.Time, limit int) ([]*entity.NotificationDelivery, error) {
+	queued := m.queued
+	m.queued = nil
+	return queued, nil
+}
+
+func (m *mockNotificationDeliveryRepository) CountSentByUserSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	return m.sentCount, nil
+}
+
+func (m *mockNotificationDeliveryRepository) Create(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	return nil
+}
+
+func (m *mockNotificationDeliveryRepository) Update(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	m.updated = append(m.updated, delivery)
+	return nil
+}
+
+func (m *mockNotificationDeliveryRepository) UpdateStatus(ctx context.Context, id uint, status valueobject.NotificationDeliveryStatus) error {
+	return nil
+}
+
+func (m *mockNotificationDeliveryRepository) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *mockNotificationDeliveryRepository) BatchCreate(ctx context.Context, deliveries []*entity.NotificationDelivery) error {
+	return nil
+}
+
+// mockUserWriter 記錄被更新啟用狀態的使用者
+type mockUserWriter struct {
+	statusUpdates map[uint]bool
+}
+
+func (m *mockUserWriter) Create(ctx context.Context, user *entity.User) error {
+	return nil
+}
+
+func (m *mockUserWriter) Update(ctx context.Context, user *entity.User) error {
+	return nil
+}
+
+func (m *mockUserWriter) UpdateStatus(ctx context.Context, userID uint, status bool) error {
+	if m.statusUpdates == nil {
+		m.statusUpdates = make(map[uint]bool)
+	}
+	m.statusUpdates[userID] = status
+	return nil
+}
+
+func (m *mockUserWriter) Delete(ctx context.Context, userID uint) error {
+	return nil
+}
+
+var (
+	errRecipientBlocked = errors.New("bot was blocked by the user")
+	errBadRequest       = errors.New("bad request")
+)
+
+// mockNotifier 以固定的錯誤模擬推播結果，errRecipientBlocked 視為使用者無法接收，errBadRequest 視為永久性錯誤
+type mockNotifier struct {
+	channel  valueobject.UserType
+	pushErr  error
+	payloads []dto.NotificationPayload
+}
+
+func (m *mockNotifier) Channel() valueobject.UserType {
+	return m.channel
+}
+
+func (m *mockNotifier) Push(ctx context.Context, accountID string, payload dto.NotificationPayload) error {
+	m.payloads = append(m.payloads, payload)
+	return m.pushErr
+}
+
+func (m *mockNotifier) IsPermanentError(err error) bool {
+	return errors.Is(err, errBadRequest)
+}
+
+func (m *mockNotifier) IsRecipientUnavailable(err error) bool {
+	return errors.Is(err, errRecipientBlocked)
+}
+
+// mockUserPreferenceReader 用於測試的 UserPreferenceReader mock
+type mockUserPreferenceReader struct {
+	preferences []*entity.UserPreference
+}
+
+func (m *mockUserPreferenceReader) GetByUserID(ctx context.Context, userID uint) (*entity.UserPreference, error) {
+	for _, preference := range m.preferences {
+		if preference.UserID == userID {
+			return preference, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockUserPreferenceReader) GetByUserIDs(ctx context.Context, userIDs []uint) ([]*entity.UserPreference, error) {
+	return m.preferences, nil
+}
+
+// mockSubscriptionSymbolRepo 依訂閱功能回傳固定的訂閱股票，其餘方法未實作
+type mockSubscriptionSymbolRepo struct {
+	port.SubscriptionSymbolRepository
+	subscriptionSymbols []*entity.SubscriptionSymbol
+}
+
+func (m *mockSubscriptionSymbolRepo) GetByFeature(ctx context.Context, feature valueobject.SubscriptionType) ([]*entity.SubscriptionSymbol, error) {
+	return m.subscriptionSymbols, nil
+}
+
+// mockMarketDataUsecase 依股票代號回傳固定的收盤與新聞，priceErrs 中的股票查詢收盤時回傳錯誤
+type mockMarketDataUsecase struct {
+	stock.MarketDataUsecase
+	mu         sync.Mutex
+	prices     map[string]*dto.StockPrice
+	priceErrs  map[string]error
+	news       map[string][]dto.StockNews
+	priceCalls map[string]int
+}
+
+func (m *mockMarketDataUsecase) GetDailyMarketInfo(ctx context.Context, count int) (*[]dto.DailyMarketInfo, error) {
+	return &[]dto.DailyMarketInfo{}, nil
+}
+
+func (m *mockMarketDataUsecase) GetTopVolumeStock(ctx context.Context) (*[]dto.TopVolume, error) {
+	return &[]dto.TopVolume{}, nil
+}
+
+func (m *mockMarketDataUsecase) GetStockPrice(ctx context.Context, symbol string, date *time.Time) (*dto.StockPrice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.priceCalls == nil {
+		m.priceCalls = make(map[string]int)
+	}
+	m.priceCalls[symbol]++
+	if err := m.priceErrs[symbol]; err != nil {
+		return nil, err
+	}
+	return m.prices[symbol], nil
+}
+
+func (m *mockMarketDataUsecase) GetStockNews(ctx context.Context, symbol string, limit int) (*[]dto.StockNews, error) {
+	news := m.news[symbol]
+	return &news, nil
+}
+
+// mockOutbox 記錄寫入佇列的推播
+type mockOutbox struct {
+	mu            sync.Mutex
+	users         []*entity.User
+	notifications []*dto.Notification
+}
+
+func (m *mockOutbox) Enqueue(ctx context.Context, user *entity.User, notification *dto.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users = append(m.users, user)
+	m.notifications = append(m.notifications, notification)
+	return nil
+}
+
+// mockPriceAlertRepo 回傳固定的啟用中提醒並記錄觸發狀態更新，其餘方法未實作
+type mockPriceAlertRepo struct {
+	port.PriceAlertRepository
+	alerts  []*entity.PriceAlert
+	updated []uint
+}
+
+func (m *mockPriceAlertRepo) GetActiveAlerts(ctx context.Context) ([]*entity.PriceAlert, error) {
+	return m.alerts, nil
+}
+
+func (m *mockPriceAlertRepo) UpdateTriggerState(ctx context.Context, id uint, triggered bool, triggeredAt *time.Time) error {
+	m.updated = append(m.updated, id)
+	return nil
+}
+
+// mockPriceAlertNotification 記錄被評估價格提醒的市場
+type mockPriceAlertNotification struct {
+	markets []string
+}
+
+func (m *mockPriceAlertNotification) EvaluatePriceAlerts(ctx context.Context, market string) error {
+	m.markets = append(m.markets, market)
+	return nil
+}
@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// NotificationOutbox 將推播寫入通知事件與待送出的投遞紀錄，實際送出由 NotificationDispatcher 處理
+type NotificationOutbox interface {
+	Enqueue(ctx context.Context, user *entity.User, notification *dto.Notification) error
+}
+
+type notificationOutbox struct {
+	eventRepo port.NotificationEventWriter
+	logger    logger.Logger
+}
+
+var _ NotificationOutbox = (*notificationOutbox)(nil)
+
+func NewNotificationOutbox(eventRepo port.NotificationEventWriter, log logger.Logger) NotificationOutbox {
+	return &notificationOutbox{
+		eventRepo: eventRepo,
+		logger:    log,
+	}
+}
+
+// Enqueue 建立通知事件，並依使用者平台建立一筆待送出的投遞紀錄；已停用的使用者直接略過。
+// 訂閱推播以（使用者, 功能, 股票, 交易日）去重，交易日取自通知內容所屬的交易日，未提供時以推播當日計算；
+// 服務重啟或重新部署後重跑排程不會重複推播
+func (o *notificationOutbox) Enqueue(ctx context.Context, user *entity.User, notification *dto.Notification) error {
+	if user == nil || notification == nil {
+		return fmt.Errorf("通知缺少使用者或內容")
+	}
+	// 已停用（例如封鎖 bot）的使用者不再推播
+	if !user.IsActive() {
+		o.logger.Debug("使用者已停用，略過推播", logger.Any("userID", user.ID))
+		return nil
+	}
+
+	payload, err := json.Marshal(notification.Payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	event := &entity.NotificationEvent{
+		UserID:         user.ID,
+		FeatureID:      uint(notification.Feature),
+		SubscriptionID: notification.SubscriptionID,
+		SymbolID:       notification.SymbolID,
+		Payload:        string(payload),
+		OccurredAt:     now,
+	}
+	// 價格提醒由提醒本身的觸發狀態控制通知次數，不以去重鍵限制
+	if notification.Feature != valueobject.SubscriptionTypeDefault {
+		var symbolID uint
+		if notification.SymbolID != nil {
+			symbolID = *notification.SymbolID
+		}
+		tradeDate := notification.TradeDate
+		if tradeDate.IsZero() {
+			tradeDate = now
+		}
+		event.DedupeKey = entity.NewNotificationDedupeKey(user.ID, notification.Feature, symbolID, tradeDate)
+	}
+	delivery := &entity.NotificationDelivery{
+		Channel: user.UserType,
+		Status:  valueobject.NotificationDeliveryStatusQueued,
+	}
+
+	err = o.eventRepo.CreateWithDelivery(ctx, event, delivery)
+	if errors.Is(err, domainerror.ErrAlreadyExists) {
+		o.logger.Info("今日已推播過相同通知，略過", logger.String("dedupeKey", event.DedupeKey))
+		return nil
+	}
+	if err != nil {
+		o.logger.Error("Enqueue CreateWithDelivery Error",
+			logger.Any("userID", user.ID),
+			logger.Any("feature", notification.Feature),
+			logger.Error(err),
+		)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+func stockInfoNotification(symbolID uint, tradeDate time.Time) *dto.Notification {
+	return &dto.Notification{
+		Feature:   valueobject.SubscriptionTypeStockInfo,
+		SymbolID:  &symbolID,
+		TradeDate: tradeDate,
+		Payload:   dto.NotificationPayload{Text: "2330 收盤 1000"},
+	}
+}
+
+func TestNotificationOutbox_Enqueue_Dedupe(t *testing.T) {
+	day1 := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		notifications []*dto.Notification
+		wantEvents    int
+	}{
+		{
+			name:          "同一交易日相同通知只建立一次",
+			notifications: []*dto.Notification{stockInfoNotification(1, day1), stockInfoNotification(1, day1)},
+			wantEvents:    1,
+		},
+		{
+			name:          "不同交易日各自建立",
+			notifications: []*dto.Notification{stockInfoNotification(1, day1), stockInfoNotification(1, day2)},
+			wantEvents:    2,
+		},
+		{
+			name:          "不同股票各自建立",
+			notifications: []*dto.Notification{stockInfoNotification(1, day1), stockInfoNotification(2, day1)},
+			wantEvents:    2,
+		},
+		{
+			name: "價格提醒不去重",
+			notifications: []*dto.Notification{
+				{Feature: valueobject.SubscriptionTypeDefault, Payload: dto.NotificationPayload{Text: "提醒"}},
+				{Feature: valueobject.SubscriptionTypeDefault, Payload: dto.NotificationPayload{Text: "提醒"}},
+			},
+			wantEvents: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventRepo := &mockNotificationEventWriter{}
+			outbox := NewNotificationOutbox(eventRepo, &mockLogger{})
+			user := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+
+			for _, notification := range tt.notifications {
+				if err := outbox.Enqueue(context.Background(), user, notification); err != nil {
+					t.Fatalf("去重鍵已存在時不應回傳錯誤: %v", err)
+				}
+			}
+
+			if len(eventRepo.events) != tt.wantEvents {
+				t.Errorf("通知事件期望 %d 筆，實際 %d 筆", tt.wantEvents, len(eventRepo.events))
+			}
+		})
+	}
+}
+
+func TestNotificationOutbox_Enqueue_DedupeKeyUsesTradeDate(t *testing.T) {
+	eventRepo := &mockNotificationEventWriter{}
+	outbox := NewNotificationOutbox(eventRepo, &mockLogger{})
+	user := &entity.User{ID: 7, AccountID: "100", UserType: valueobject.UserTypeLine, Status: true}
+
+	tradeDate := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	if err := outbox.Enqueue(context.Background(), user, stockInfoNotification(3, tradeDate)); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	if len(eventRepo.events) != 1 {
+		t.Fatalf("通知事件期望 1 筆，實際 %d 筆", len(eventRepo.events))
+	}
+	if got, want := eventRepo.events[0].DedupeKey, "7:1:3:2026-10-14"; got != want {
+		t.Errorf("去重鍵期望 %s，實際 %s", want, got)
+	}
+	if delivery := eventRepo.deliveries[0]; delivery.Channel != valueobject.UserTypeLine || !delivery.IsQueued() {
+		t.Errorf("投遞紀錄應為 LINE 待送出，實際 %+v", delivery)
+	}
+}
+
+func TestNotificationOutbox_Enqueue_SkipInactiveUser(t *testing.T) {
+	eventRepo := &mockNotificationEventWriter{}
+	outbox := NewNotificationOutbox(eventRepo, &mockLogger{})
+	user := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: false}
+
+	if err := outbox.Enqueue(context.Background(), user, stockInfoNotification(1, time.Now())); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+	if len(eventRepo.events) != 0 {
+		t.Errorf("停用的使用者不應建立通知事件，實際 %d 筆", len(eventRepo.events))
+	}
+}
+
+func TestNotificationOutbox_Enqueue_RepositoryError(t *testing.T) {
+	eventRepo := &mockNotificationEventWriter{createErr: errors.New("database error")}
+	outbox := NewNotificationOutbox(eventRepo, &mockLogger{})
+	user := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+
+	if err := outbox.Enqueue(context.Background(), user, stockInfoNotification(1, time.Now())); err == nil {
+		t.Error("期望回傳錯誤，實際為 nil")
+	}
+}
@@ -0,0 +1,177 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// PriceAlertNotificationUsecase 評估價格提醒並推送觸發通知
+type PriceAlertNotificationUsecase interface {
+	// EvaluatePriceAlerts 只評估 market 市場（TW 或 US）股票的提醒，由排程依各市場收盤時間分別呼叫
+	EvaluatePriceAlerts(ctx context.Context, market string) error
+}
+
+type priceAlertNotificationUsecase struct {
+	priceAlertRepo    port.PriceAlertRepository
+	preferenceReader  port.UserPreferenceReader
+	marketDataUsecase stock.MarketDataUsecase
+	formatterPort     port.FormatterPort
+	outbox            NotificationOutbox
+	logger            logger.Logger
+}
+
+var _ PriceAlertNotificationUsecase = (*priceAlertNotificationUsecase)(nil)
+
+func NewPriceAlertNotificationUsecase(
+	priceAlertRepo port.PriceAlertRepository,
+	preferenceReader port.UserPreferenceReader,
+	marketDataUsecase stock.MarketDataUsecase,
+	formatterPort port.FormatterPort,
+	outbox NotificationOutbox,
+	log logger.Logger,
+) PriceAlertNotificationUsecase {
+	return &priceAlertNotificationUsecase{
+		priceAlertRepo:    priceAlertRepo,
+		preferenceReader:  preferenceReader,
+		marketDataUsecase: marketDataUsecase,
+		formatterPort:     formatterPort,
+		outbox:            outbox,
+		logger:            log,
+	}
+}
+
+// EvaluatePriceAlerts 依最新收盤價評估 market 市場啟用中的提醒
+// 價格提醒每次穿越僅通知一次；漲跌幅/跳空提醒以前一交易日收盤價為基準，每個交易日最多通知一次
+func (u *priceAlertNotificationUsecase) EvaluatePriceAlerts(ctx context.Context, market string) error {
+	alerts, err := u.priceAlertRepo.GetActiveAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 依股票分組，同一檔股票只查詢一次股價
+	alertsBySymbol := make(map[string][]*entity.PriceAlert)
+	userIDs := make([]uint, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.StockSymbol == nil || alert.User == nil || tradingMarket(alert.StockSymbol) != market {
+			continue
+		}
+		alertsBySymbol[alert.StockSymbol.Symbol] = append(alertsBySymbol[alert.StockSymbol.Symbol], alert)
+		userIDs = append(userIDs, alert.UserID)
+	}
+	languages := u.loadLanguages(ctx, userIDs)
+
+	for symbol, symbolAlerts := range alertsBySymbol {
+		stockPrice, err := u.marketDataUsecase.GetStockPrice(ctx, symbol, nil)
+		if err != nil {
+			u.logger.Error("EvaluatePriceAlerts GetStockPrice Error",
+				logger.String("symbol", symbol),
+				logger.Error(err),
+			)
+			continue
+		}
+
+		for _, alert := range symbolAlerts {
+			wasTriggered := alert.Triggered
+			wasTriggeredAt := alert.TriggeredAt
+
+			var fired bool
+			if alert.AlertType.IsPercentage() {
+				changePercent, ok := percentageChange(alert.AlertType, stockPrice)
+				if !ok {
+					continue
+				}
+				fired = alert.EvaluatePercentage(changePercent, stockPrice.Date)
+			} else {
+				fired = alert.Evaluate(stockPrice.ClosePrice, stockPrice.Date)
+			}
+
+			if alert.Triggered != wasTriggered || alert.TriggeredAt != wasTriggeredAt {
+				if err := u.priceAlertRepo.UpdateTriggerState(ctx, alert.ID, alert.Triggered, alert.TriggeredAt); err != nil {
+					u.logger.Error("EvaluatePriceAlerts UpdateTriggerState Error",
+						logger.Any("alertID", alert.ID),
+						logger.Error(err),
+					)
+					continue
+				}
+			}
+
+			if !fired {
+				continue
+			}
+
+			u.sendTriggered(ctx, alert, stockPrice, languages[alert.UserID])
+		}
+	}
+	return nil
+}
+
+// loadLanguages 批次取得使用者偏好語言，查詢失敗或未設定時以繁體中文顯示
+func (u *priceAlertNotificationUsecase) loadLanguages(ctx context.Context, userIDs []uint) map[uint]valueobject.Language {
+	languages := make(map[uint]valueobject.Language, len(userIDs))
+	for _, userID := range userIDs {
+		languages[userID] = valueobject.LanguageTraditionalChinese
+	}
+	if len(userIDs) == 0 {
+		return languages
+	}
+
+	preferences, err := u.preferenceReader.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		u.logger.Error("EvaluatePriceAlerts GetByUserIDs Error", logger.Error(err))
+		return languages
+	}
+	for _, preference := range preferences {
+		if preference.Language.IsValid() {
+			languages[preference.UserID] = preference.Language
+		}
+	}
+	return languages
+}
+
+func (u *priceAlertNotificationUsecase) sendTriggered(ctx context.Context, alert *entity.PriceAlert, stockPrice *dto.StockPrice, language valueobject.Language) {
+	data := u.formatterPort.FormatPriceAlertTriggered(&dto.PriceAlert{
+		ID:               alert.ID,
+		Symbol:           alert.StockSymbol.Symbol,
+		Name:             alert.StockSymbol.Name,
+		AlertType:        alert.AlertType,
+		Operator:         alert.Operator,
+		TargetPrice:      alert.TargetPrice,
+		ThresholdPercent: alert.ThresholdPercent,
+		Triggered:        alert.Triggered,
+		TriggeredAt:      alert.TriggeredAt,
+	}, stockPrice, alert.User.UserType, language)
+
+	// 價格提醒不屬於訂閱功能，事件只記錄觸發的股票
+	symbolID := alert.SymbolID
+	notification := &dto.Notification{
+		Feature:  valueobject.SubscriptionTypeDefault,
+		SymbolID: &symbolID,
+		Payload:  dto.NotificationPayload{Text: data},
+	}
+	if err := u.outbox.Enqueue(ctx, alert.User, notification); err != nil {
+		u.logger.Error("EvaluatePriceAlerts Enqueue Error",
+			logger.Any("alertID", alert.ID),
+			logger.String("accountID", alert.User.AccountID),
+			logger.Error(err),
+		)
+	}
+}
+
+// percentageChange 計算相對前一交易日收盤價的變動百分比，跳空以開盤價計算
+func percentageChange(alertType valueobject.AlertType, stockPrice *dto.StockPrice) (float64, bool) {
+	if stockPrice.PrevClosePrice == 0 {
+		return 0, false
+	}
+
+	price := stockPrice.ClosePrice
+	if alertType == valueobject.AlertTypeGap {
+		price = stockPrice.OpenPrice
+	}
+	return (price - stockPrice.PrevClosePrice) / stockPrice.PrevClosePrice * 100, true
+}
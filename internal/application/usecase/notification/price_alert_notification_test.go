@@ -0,0 +1,122 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	formatterAdapter "github.com/tian841224/stock-bot/internal/infrastructure/adapter/formatter"
+)
+
+func TestPriceAlertNotificationUsecase_EvaluatePriceAlerts_Language(t *testing.T) {
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	chineseUser := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+	englishUser := &entity.User{ID: 2, AccountID: "U200", UserType: valueobject.UserTypeLine, Status: true}
+	noPreferenceUser := &entity.User{ID: 3, AccountID: "300", UserType: valueobject.UserTypeTelegram, Status: true}
+
+	newAlert := func(id uint, user *entity.User) *entity.PriceAlert {
+		return &entity.PriceAlert{
+			ID: id, UserID: user.ID, SymbolID: tsmc.ID,
+			AlertType: valueobject.AlertTypePrice, Operator: valueobject.AlertOperatorAbove, TargetPrice: 1000,
+			Active: true, StockSymbol: tsmc, User: user,
+		}
+	}
+	alertRepo := &mockPriceAlertRepo{alerts: []*entity.PriceAlert{
+		newAlert(1, chineseUser),
+		newAlert(2, englishUser),
+		newAlert(3, noPreferenceUser),
+	}}
+	preferenceReader := &mockUserPreferenceReader{preferences: []*entity.UserPreference{
+		{UserID: chineseUser.ID, Language: valueobject.LanguageTraditionalChinese},
+		{UserID: englishUser.ID, Language: valueobject.LanguageEnglish},
+	}}
+	marketData := &mockMarketDataUsecase{prices: map[string]*dto.StockPrice{
+		"2330": {Symbol: "2330", ClosePrice: 1005, Date: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)},
+	}}
+	outbox := &mockOutbox{}
+	formatter := formatterAdapter.NewFormatterAdapter(nil, nil, formatterAdapter.NewTelegramFormatter(), formatterAdapter.NewLineFormatter())
+
+	usecase := NewPriceAlertNotificationUsecase(alertRepo, preferenceReader, marketData, formatter, outbox, &mockLogger{})
+	if err := usecase.EvaluatePriceAlerts(context.Background(), marketTW); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	texts := make(map[uint]string)
+	for i, user := range outbox.users {
+		texts[user.ID] = outbox.notifications[i].Payload.Text
+	}
+	if len(texts) != 3 {
+		t.Fatalf("期望推播給 3 位使用者，實際 %d 位", len(texts))
+	}
+
+	tests := []struct {
+		name    string
+		userID  uint
+		want    string
+		notWant string
+	}{
+		{name: "偏好繁體中文", userID: chineseUser.ID, want: "收盤價：1005.00 高於 目標價 1000.00", notWant: "Price Alert"},
+		{name: "偏好英文", userID: englishUser.ID, want: "Close: 1005.00 above target 1000.00", notWant: "價格提醒"},
+		{name: "未設定偏好時以繁體中文顯示", userID: noPreferenceUser.ID, want: "價格提醒", notWant: "Price Alert"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := texts[tt.userID]
+			if !strings.Contains(text, tt.want) || strings.Contains(text, tt.notWant) {
+				t.Errorf("推播應包含 %q 且不包含 %q，實際：\n%s", tt.want, tt.notWant, text)
+			}
+		})
+	}
+}
+
+func TestPriceAlertNotificationUsecase_EvaluatePriceAlerts_Market(t *testing.T) {
+	user := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	apple := &entity.StockSymbol{ID: 2, Symbol: "AAPL", Name: "Apple", Market: "US"}
+
+	tests := []struct {
+		name        string
+		market      string
+		wantSymbols []string
+	}{
+		{name: "台股只評估台股提醒", market: marketTW, wantSymbols: []string{"2330"}},
+		{name: "美股只評估美股提醒", market: marketUS, wantSymbols: []string{"AAPL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertRepo := &mockPriceAlertRepo{alerts: []*entity.PriceAlert{
+				{ID: 1, UserID: user.ID, SymbolID: tsmc.ID, AlertType: valueobject.AlertTypePrice, Operator: valueobject.AlertOperatorAbove, TargetPrice: 1000, Active: true, StockSymbol: tsmc, User: user},
+				{ID: 2, UserID: user.ID, SymbolID: apple.ID, AlertType: valueobject.AlertTypePrice, Operator: valueobject.AlertOperatorAbove, TargetPrice: 200, Active: true, StockSymbol: apple, User: user},
+			}}
+			marketData := &mockMarketDataUsecase{prices: map[string]*dto.StockPrice{
+				"2330": {Symbol: "2330", ClosePrice: 1005},
+				"AAPL": {Symbol: "AAPL", ClosePrice: 210},
+			}}
+			outbox := &mockOutbox{}
+			formatter := formatterAdapter.NewFormatterAdapter(nil, nil, formatterAdapter.NewTelegramFormatter(), formatterAdapter.NewLineFormatter())
+
+			usecase := NewPriceAlertNotificationUsecase(alertRepo, &mockUserPreferenceReader{}, marketData, formatter, outbox, &mockLogger{})
+			if err := usecase.EvaluatePriceAlerts(context.Background(), tt.market); err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+
+			got := make([]string, 0, len(marketData.priceCalls))
+			for symbol := range marketData.priceCalls {
+				got = append(got, symbol)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantSymbols) {
+				t.Errorf("查詢股價期望 %v，實際 %v", tt.wantSymbols, got)
+			}
+			if len(outbox.notifications) != 1 {
+				t.Errorf("推播期望 1 則，實際 %d 則", len(outbox.notifications))
+			}
+		})
+	}
+}
@@ -2,56 +2,202 @@ package notification
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/multierr"
 
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+	"github.com/tian841224/stock-bot/pkg/utils"
 )
 
-// ScheduleHandlerUsecase 提供給 scheduler 呼叫的入口（佔位）。
+// DefaultScheduleSpec 未自訂排程的訂閱所使用的推播時間（每日 15:00）
+const DefaultScheduleSpec = "0 15 * * *"
+
+// DefaultMorningTime 「次一交易日早上」排程的預設推播時間
+const DefaultMorningTime = "08:30"
+
+// usPriceAlertSpec 美股價格提醒的評估時間，以紐約時間於收盤（16:00）後執行
+const usPriceAlertSpec = "30 16 * * *"
+
+// maxScheduleCatchUp 上一輪執行過久時最多補跑的時間範圍
+const maxScheduleCatchUp = time.Hour
+
+// SubscriptionFilter 決定本次排程要推播的訂閱，stock 為個股項目訂閱的股票，其餘項目為 nil；filter 為 nil 代表全部推播
+type SubscriptionFilter func(subscription *entity.Subscription, stock *entity.StockSymbol) bool
+
+// ScheduleHandlerUsecase 提供給 scheduler 呼叫的入口，每分鐘呼叫一次 RunDueTasks。
+// 上一輪執行超過一分鐘而略過的分鐘，會在下一次呼叫時補上
 type ScheduleHandlerUsecase interface {
-	RunScheduledTasks(ctx context.Context) error
+	RunDueTasks(ctx context.Context, now time.Time) error
+}
+
+// scheduledTask 排程執行的推播任務
+type scheduledTask struct {
+	Name    string
+	Feature valueobject.SubscriptionType
+	Func    func(context.Context, SubscriptionFilter) error
 }
 
 type scheduleHandlerUsecase struct {
-	notification SendNotificationUsecase
-	log          logger.Logger
+	subscriptionRepo port.SubscriptionReader
+	calendar         *tradingCalendar
+	notification     SendNotificationUsecase
+	priceAlert       PriceAlertNotificationUsecase
+	exDividend       ExDividendNotificationUsecase
+	digest           DailyDigestUsecase
+	defaultSpec      string
+	morningSpec      string
+	log              logger.Logger
+
+	mu sync.Mutex
+	// lastRun 上次檢查排程的時間
+	lastRun time.Time
 }
 
-func NewScheduleHandlerUsecase(notification SendNotificationUsecase, log logger.Logger) ScheduleHandlerUsecase {
-	return &scheduleHandlerUsecase{
-		notification: notification,
-		log:          log,
+// NewScheduleHandlerUsecase 建立排程入口；defaultSpec 為未自訂排程時的 cron 表達式（可設為 @morning），
+// morningTime 為「次一交易日早上」的推播時間（HH:MM），空字串時使用預設值
+func NewScheduleHandlerUsecase(
+	subscriptionRepo port.SubscriptionReader,
+	tradeDateReader port.TradeDateReader,
+	notification SendNotificationUsecase,
+	priceAlert PriceAlertNotificationUsecase,
+	exDividend ExDividendNotificationUsecase,
+	digest DailyDigestUsecase,
+	defaultSpec string,
+	morningTime string,
+	log logger.Logger,
+) (ScheduleHandlerUsecase, error) {
+	if morningTime == "" {
+		morningTime = DefaultMorningTime
+	}
+	morningSpec, ok := utils.DailyCronSpec(morningTime)
+	if !ok {
+		return nil, fmt.Errorf("早上推播時間必須為 HH:MM 格式: %s", morningTime)
+	}
+	if defaultSpec == "" {
+		defaultSpec = DefaultScheduleSpec
+	}
+	if defaultSpec == entity.ScheduleNextTradingDayMorning {
+		defaultSpec = morningSpec
 	}
+	if _, err := utils.ParseCronSpec(defaultSpec); err != nil {
+		return nil, fmt.Errorf("預設推播排程 %q: %w", defaultSpec, err)
+	}
+	return &scheduleHandlerUsecase{
+		subscriptionRepo: subscriptionRepo,
+		calendar:         newTradingCalendar(tradeDateReader),
+		notification:     notification,
+		priceAlert:       priceAlert,
+		exDividend:       exDividend,
+		digest:           digest,
+		defaultSpec:      defaultSpec,
+		morningSpec:      morningSpec,
+		log:              log,
+	}, nil
 }
 
-func (u *scheduleHandlerUsecase) RunScheduledTasks(ctx context.Context) error {
+// RunDueTasks 執行上次檢查之後到 now 所在分鐘為止到期的推播。每個訂閱依自己的 ScheduleCron 觸發，未設定時使用預設排程；
+// 訂閱股票所屬市場當天休市時不推播。價格提醒依收盤價評估：台股於預設排程、美股於紐約時間收盤後，各自依該市場的交易日執行
+func (u *scheduleHandlerUsecase) RunDueTasks(ctx context.Context, now time.Time) error {
 	if u.notification == nil {
 		return nil
 	}
 
-	tasks := []struct {
-		Name string
-		Func func(context.Context) error
-	}{
-		{"SendStockPriceNotification", u.notification.SendStockPriceNotification},
-		{"SendStockNewsNotification", u.notification.SendStockNewsNotification},
-		{"SendMarketInfoNotification", u.notification.SendMarketInfoNotification},
-		{"SendTopVolumeNotification", u.notification.SendTopVolumeNotification},
+	subscriptions, err := u.subscriptionRepo.GetActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 先算好每個排程是否到期，推播任務並行時只讀取
+	after := u.advanceLastRun(now)
+	dueBySpec := map[string]bool{u.defaultSpec: u.isDue(u.defaultSpec, after, now)}
+	dueFeatures := make(map[valueobject.SubscriptionType]bool)
+	for _, subscription := range subscriptions {
+		spec := u.scheduleSpec(subscription)
+		if _, ok := dueBySpec[spec]; !ok {
+			dueBySpec[spec] = u.isDue(spec, after, now)
+		}
+		if dueBySpec[spec] {
+			dueFeatures[subscription.Item] = true
+		}
+	}
+
+	// 訂閱每日摘要的使用者改由摘要合併推送，不再個別推送摘要涵蓋的項目
+	digestUsers := make(map[uint]bool)
+	if u.digest != nil {
+		for _, subscription := range subscriptions {
+			if subscription.Item == valueobject.SubscriptionTypeDailyDigest {
+				digestUsers[subscription.UserID] = true
+			}
+		}
+	}
+
+	// 只在有推播到期時才查詢交易日，同一市場只查一次；filter 由推播任務並行呼叫，需加鎖
+	var marketMu sync.Mutex
+	openMarkets := make(map[string]bool)
+	isMarketOpen := func(market string) bool {
+		marketMu.Lock()
+		defer marketMu.Unlock()
+
+		if open, ok := openMarkets[market]; ok {
+			return open
+		}
+		open, err := u.calendar.IsTradingDay(ctx, market, now)
+		if err != nil {
+			u.log.Warn("查詢交易日失敗，改以平日判斷", logger.String("market", market), logger.Error(err))
+			open = isWeekday(now)
+		}
+		if !open {
+			u.log.Info("今日非交易日，略過推播", logger.String("market", market))
+		}
+		openMarkets[market] = open
+		return open
+	}
+
+	// 每筆訂閱依訂閱股票所屬市場判斷是否為交易日，美股不受台股休市影響
+	filter := func(subscription *entity.Subscription, stock *entity.StockSymbol) bool {
+		if digestUsers[subscription.UserID] && subscription.Item.IsIncludedInDigest() {
+			return false
+		}
+		if !dueBySpec[u.scheduleSpec(subscription)] {
+			return false
+		}
+		return isMarketOpen(tradingMarket(stock))
+	}
+
+	tasks := []scheduledTask{
+		{"SendStockPriceNotification", valueobject.SubscriptionTypeStockInfo, u.notification.SendStockPriceNotification},
+		{"SendStockNewsNotification", valueobject.SubscriptionTypeStockNews, u.notification.SendStockNewsNotification},
+		{"SendMarketInfoNotification", valueobject.SubscriptionTypeDailyMarketInfo, u.notification.SendMarketInfoNotification},
+		{"SendTopVolumeNotification", valueobject.SubscriptionTypeTopVolumeItems, u.notification.SendTopVolumeNotification},
+	}
+	if u.exDividend != nil {
+		tasks = append(tasks, scheduledTask{"SendExDividendReminders", valueobject.SubscriptionTypeExDividend, u.exDividend.SendExDividendReminders})
+	}
+	if u.digest != nil {
+		tasks = append(tasks, scheduledTask{"SendDailyDigests", valueobject.SubscriptionTypeDailyDigest, u.digest.SendDailyDigests})
 	}
 
 	errChan := make(chan error, len(tasks))
 	var wg sync.WaitGroup
 
 	for _, task := range tasks {
+		if !dueFeatures[task.Feature] {
+			continue
+		}
+
 		// 紀錄顯示當前執行的服務
 		u.log.Info("正在執行排程任務...", logger.String("task", task.Name))
 
 		wg.Add(1)
-		go func(t func(context.Context) error) {
+		go func(t func(context.Context, SubscriptionFilter) error) {
 			defer wg.Done()
-			if err := t(ctx); err != nil {
+			if err := t(ctx, filter); err != nil {
 				errChan <- err
 			}
 		}(task.Func)
@@ -64,5 +210,68 @@ func (u *scheduleHandlerUsecase) RunScheduledTasks(ctx context.Context) error {
 	for err := range errChan {
 		errs = multierr.Append(errs, err)
 	}
+
+	// 價格提醒於推播完成後依最新收盤價評估，各市場只在自己的交易日、收盤之後評估該市場股票的提醒
+	if u.priceAlert != nil {
+		for _, market := range u.dueAlertMarkets(after, now, dueBySpec[u.defaultSpec], isMarketOpen) {
+			u.log.Info("正在執行排程任務...", logger.String("task", "EvaluatePriceAlerts"), logger.String("market", market))
+			if err := u.priceAlert.EvaluatePriceAlerts(ctx, market); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+	}
 	return errs
 }
+
+// dueAlertMarkets 回傳本次需要評估價格提醒的市場：台股隨預設排程，美股於紐約時間 usPriceAlertSpec 執行
+func (u *scheduleHandlerUsecase) dueAlertMarkets(after, now time.Time, defaultDue bool, isMarketOpen func(string) bool) []string {
+	markets := make([]string, 0, 2)
+	if defaultDue && isMarketOpen(marketTW) {
+		markets = append(markets, marketTW)
+	}
+	if u.isDue(usPriceAlertSpec, after, now.In(newYorkLocation())) && isMarketOpen(marketUS) {
+		markets = append(markets, marketUS)
+	}
+	return markets
+}
+
+// scheduleSpec 取得訂閱實際使用的 cron 表達式
+func (u *scheduleHandlerUsecase) scheduleSpec(subscription *entity.Subscription) string {
+	if subscription.IsNextTradingDayMorning() {
+		return u.morningSpec
+	}
+	if subscription.HasSchedule() {
+		return subscription.ScheduleCron
+	}
+	return u.defaultSpec
+}
+
+// advanceLastRun 記錄本次檢查的時間並回傳上次檢查的時間；首次執行時只檢查 now 所在的那一分鐘，
+// 間隔超過 maxScheduleCatchUp 時只補跑最近的範圍
+func (u *scheduleHandlerUsecase) advanceLastRun(now time.Time) time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	after := u.lastRun
+	switch {
+	case after.IsZero() || after.After(now):
+		after = now.Add(-time.Minute)
+	case now.Sub(after) > maxScheduleCatchUp:
+		u.log.Warn("距上次排程檢查過久，僅補跑最近的推播",
+			logger.String("last_run", after.Format(time.RFC3339)),
+			logger.String("now", now.Format(time.RFC3339)))
+		after = now.Add(-maxScheduleCatchUp)
+	}
+	u.lastRun = now
+	return after
+}
+
+// isDue 判斷排程是否在 after 之後到 now 所在的那一分鐘之間觸發，無法解析的排程視為不觸發
+func (u *scheduleHandlerUsecase) isDue(spec string, after, now time.Time) bool {
+	schedule, err := utils.ParseCronSpec(spec)
+	if err != nil {
+		u.log.Warn("無法解析推播排程", logger.String("spec", spec), logger.Error(err))
+		return false
+	}
+	return utils.IsCronDueBetween(schedule, after, now)
+}
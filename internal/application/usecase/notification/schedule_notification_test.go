@@ -0,0 +1,254 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+var taipei = time.FixedZone("CST", 8*3600)
+
+// scheduleTime 回傳 2026-10-14（週三）台北時間的指定時刻
+func scheduleTime(hour, minute, second int) time.Time {
+	return time.Date(2026, 10, 14, hour, minute, second, 0, taipei)
+}
+
+func TestScheduleHandlerUsecase_RunDueTasks_CatchUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		runs       []time.Time
+		wantPushes int
+	}{
+		{
+			name:       "準時執行",
+			runs:       []time.Time{scheduleTime(15, 0, 5)},
+			wantPushes: 1,
+		},
+		{
+			name:       "上一輪逾時略過的分鐘於下一輪補上",
+			runs:       []time.Time{scheduleTime(14, 59, 5), scheduleTime(15, 3, 10)},
+			wantPushes: 1,
+		},
+		{
+			name:       "同一分鐘重複檢查不重複推播",
+			runs:       []time.Time{scheduleTime(15, 0, 5), scheduleTime(15, 0, 40), scheduleTime(15, 1, 5)},
+			wantPushes: 1,
+		},
+		{
+			name:       "首次執行不補跑之前的分鐘",
+			runs:       []time.Time{scheduleTime(15, 3, 0)},
+			wantPushes: 0,
+		},
+		{
+			name:       "超過補跑上限的排程不補跑",
+			runs:       []time.Time{scheduleTime(14, 59, 0), scheduleTime(16, 30, 0)},
+			wantPushes: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := &entity.Subscription{ID: 1, UserID: 1, Item: valueobject.SubscriptionTypeStockInfo, Active: true, ScheduleCron: "0 15 * * *"}
+			notification := &mockSendNotification{subscriptionSymbols: []*entity.SubscriptionSymbol{
+				{ID: 1, UserID: 1, Subscription: subscription, StockSymbol: &entity.StockSymbol{Symbol: "2330", Market: "TWSE"}},
+			}}
+			usecase, err := NewScheduleHandlerUsecase(&mockSubscriptionReader{subscriptions: []*entity.Subscription{subscription}}, nil, notification, nil, nil, nil, "", "", &mockLogger{})
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+
+			for _, run := range tt.runs {
+				if err := usecase.RunDueTasks(context.Background(), run); err != nil {
+					t.Fatalf("不應該發生錯誤: %v", err)
+				}
+			}
+
+			if len(notification.stockInfo) != tt.wantPushes {
+				t.Errorf("推播次數期望 %d，實際 %d", tt.wantPushes, len(notification.stockInfo))
+			}
+		})
+	}
+}
+
+func TestNewScheduleHandlerUsecase_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultSpec string
+		morningTime string
+		wantErr     bool
+	}{
+		{name: "使用預設值", defaultSpec: "", morningTime: ""},
+		{name: "自訂排程", defaultSpec: "30 14 * * 1-5", morningTime: "09:00"},
+		{name: "預設排程為次一交易日早上", defaultSpec: entity.ScheduleNextTradingDayMorning, morningTime: "08:00"},
+		{name: "排程格式錯誤", defaultSpec: "every day", wantErr: true},
+		{name: "早上推播時間格式錯誤", morningTime: "8點", wantErr: true},
+		{name: "早上推播時間超出範圍", morningTime: "25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewScheduleHandlerUsecase(&mockSubscriptionReader{}, nil, &mockSendNotification{}, nil, nil, nil, tt.defaultSpec, tt.morningTime, &mockLogger{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("錯誤期望 %v，實際 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestScheduleHandlerUsecase_RunDueTasks_MarketPerSubscription(t *testing.T) {
+	tests := []struct {
+		name        string
+		now         time.Time
+		tradeDates  []string
+		wantSymbols []string
+	}{
+		{
+			name:        "兩地皆開市",
+			now:         time.Date(2026, 10, 14, 15, 0, 0, 0, taipei),
+			tradeDates:  []string{"2026-10-14", "2026-10-15"},
+			wantSymbols: []string{"2330", "AAPL"},
+		},
+		{
+			name:        "台股休市時仍推播美股",
+			now:         time.Date(2026, 10, 9, 15, 0, 0, 0, taipei),
+			tradeDates:  []string{"2026-10-08", "2026-10-12"},
+			wantSymbols: []string{"AAPL"},
+		},
+		{
+			name:        "美股休市時不推播美股",
+			now:         time.Date(2026, 11, 26, 15, 0, 0, 0, taipei),
+			tradeDates:  []string{"2026-11-26", "2026-11-27"},
+			wantSymbols: []string{"2330"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tradeDates := make([]*entity.TradeDate, len(tt.tradeDates))
+			for i, date := range tt.tradeDates {
+				parsed, _ := time.Parse("2006-01-02", date)
+				tradeDates[i] = &entity.TradeDate{Date: parsed, Exchange: "TW"}
+			}
+
+			subscription := &entity.Subscription{ID: 1, UserID: 1, Item: valueobject.SubscriptionTypeStockInfo, Active: true}
+			notification := &mockSendNotification{subscriptionSymbols: []*entity.SubscriptionSymbol{
+				{ID: 1, UserID: 1, Subscription: subscription, StockSymbol: &entity.StockSymbol{Symbol: "2330", Market: "TWSE"}},
+				{ID: 2, UserID: 1, Subscription: subscription, StockSymbol: &entity.StockSymbol{Symbol: "AAPL", Market: "US"}},
+			}}
+			usecase, err := NewScheduleHandlerUsecase(&mockSubscriptionReader{subscriptions: []*entity.Subscription{subscription}}, &mockTradeDateReader{tradeDates: tradeDates}, notification, nil, nil, nil, "", "", &mockLogger{})
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+
+			if err := usecase.RunDueTasks(context.Background(), tt.now); err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+
+			got := make([]string, len(notification.stockInfo))
+			for i, subscriptionSymbol := range notification.stockInfo {
+				got[i] = subscriptionSymbol.StockSymbol.Symbol
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantSymbols) {
+				t.Errorf("推播股票期望 %v，實際 %v", tt.wantSymbols, got)
+			}
+		})
+	}
+}
+
+func TestScheduleHandlerUsecase_RunDueTasks_PriceAlertMarkets(t *testing.T) {
+	newYork := newYorkLocation()
+
+	tests := []struct {
+		name        string
+		runs        []time.Time
+		tradeDates  []string
+		wantMarkets []string
+	}{
+		{
+			name:        "台股收盤後只評估台股",
+			runs:        []time.Time{time.Date(2026, 10, 14, 15, 0, 0, 0, taipei)},
+			tradeDates:  []string{"2026-10-14", "2026-10-15"},
+			wantMarkets: []string{marketTW},
+		},
+		{
+			name:        "美股於紐約時間收盤後評估",
+			runs:        []time.Time{time.Date(2026, 10, 14, 16, 30, 0, 0, newYork)},
+			tradeDates:  []string{"2026-10-14", "2026-10-15"},
+			wantMarkets: []string{marketUS},
+		},
+		{
+			name:        "台股休市時仍評估美股",
+			runs:        []time.Time{time.Date(2026, 10, 9, 15, 0, 0, 0, taipei), time.Date(2026, 10, 9, 16, 30, 0, 0, newYork)},
+			tradeDates:  []string{"2026-10-08", "2026-10-12"},
+			wantMarkets: []string{marketUS},
+		},
+		{
+			name:        "美股休市時不評估美股",
+			runs:        []time.Time{time.Date(2026, 11, 26, 15, 0, 0, 0, taipei), time.Date(2026, 11, 26, 16, 30, 0, 0, newYork)},
+			tradeDates:  []string{"2026-11-26", "2026-11-27"},
+			wantMarkets: []string{marketTW},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tradeDates := make([]*entity.TradeDate, len(tt.tradeDates))
+			for i, date := range tt.tradeDates {
+				parsed, _ := time.Parse("2006-01-02", date)
+				tradeDates[i] = &entity.TradeDate{Date: parsed, Exchange: "TW"}
+			}
+
+			priceAlert := &mockPriceAlertNotification{}
+			usecase, err := NewScheduleHandlerUsecase(&mockSubscriptionReader{}, &mockTradeDateReader{tradeDates: tradeDates}, &mockSendNotification{}, priceAlert, nil, nil, "", "", &mockLogger{})
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+
+			// 每次執行間隔超過補跑上限，各自只檢查當下的分鐘
+			for _, run := range tt.runs {
+				if err := usecase.RunDueTasks(context.Background(), run); err != nil {
+					t.Fatalf("不應該發生錯誤: %v", err)
+				}
+			}
+
+			if fmt.Sprint(priceAlert.markets) != fmt.Sprint(tt.wantMarkets) {
+				t.Errorf("評估市場期望 %v，實際 %v", tt.wantMarkets, priceAlert.markets)
+			}
+		})
+	}
+}
+
+func TestIsUSMarketHoliday(t *testing.T) {
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{date: "2026-01-01", want: true},
+		{date: "2026-01-19", want: true},
+		{date: "2026-02-16", want: true},
+		{date: "2026-04-03", want: true},
+		{date: "2026-05-25", want: true},
+		{date: "2026-06-19", want: true},
+		{date: "2026-07-03", want: true},
+		{date: "2026-09-07", want: true},
+		{date: "2026-11-26", want: true},
+		{date: "2026-12-25", want: true},
+		{date: "2027-12-24", want: true},
+		{date: "2021-12-31", want: false},
+		{date: "2026-10-12", want: false},
+		{date: "2026-11-27", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			day, _ := time.Parse("2006-01-02", tt.date)
+			if got := isUSMarketHoliday(day); got != tt.want {
+				t.Errorf("休市日期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
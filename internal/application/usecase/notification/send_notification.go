@@ -2,27 +2,29 @@ package notification
 
 import (
 	"context"
-	"strconv"
+	"encoding/json"
 
+	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
 	"github.com/tian841224/stock-bot/internal/application/usecase/stock"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
-	tgbotapi "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/telegram"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 )
 
 type SendNotificationUsecase interface {
-	SendStockPriceNotification(ctx context.Context) error
-	SendStockNewsNotification(ctx context.Context) error
-	SendMarketInfoNotification(ctx context.Context) error
-	SendTopVolumeNotification(ctx context.Context) error
+	SendStockPriceNotification(ctx context.Context, filter SubscriptionFilter) error
+	SendStockNewsNotification(ctx context.Context, filter SubscriptionFilter) error
+	SendMarketInfoNotification(ctx context.Context, filter SubscriptionFilter) error
+	SendTopVolumeNotification(ctx context.Context, filter SubscriptionFilter) error
 }
 
 type sendNotificationUsecase struct {
 	marketDataUsecase      stock.MarketDataUsecase
 	subscriptionSymbolRepo port.SubscriptionSymbolRepository
 	formatterPort          port.FormatterPort
-	client                 *tgbotapi.TgBotClient
+	outbox                 NotificationOutbox
+	workers                int
 	logger                 logger.Logger
 }
 
@@ -30,202 +32,264 @@ func NewSendNotificationUsecase(
 	subscriptionSymbolRepo port.SubscriptionSymbolRepository,
 	marketDataUsecase stock.MarketDataUsecase,
 	formatterPort port.FormatterPort,
-	client *tgbotapi.TgBotClient,
+	outbox NotificationOutbox,
 	log logger.Logger,
 ) SendNotificationUsecase {
 	return &sendNotificationUsecase{
 		subscriptionSymbolRepo: subscriptionSymbolRepo,
 		formatterPort:          formatterPort,
 		marketDataUsecase:      marketDataUsecase,
-		client:                 client,
+		outbox:                 outbox,
+		workers:                defaultNotificationWorkers,
 		logger:                 log,
 	}
 }
 
-// SendStockPriceNotification 推送股票股價
-func (u *sendNotificationUsecase) SendStockPriceNotification(ctx context.Context) error {
+// SendStockPriceNotification 推送股票股價，每檔股票只查詢一次股價，再依使用者平台格式化後推送給所有訂閱者
+func (u *sendNotificationUsecase) SendStockPriceNotification(ctx context.Context, filter SubscriptionFilter) error {
 	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeStockInfo)
 	if err != nil {
 		return err
 	}
+	subscriptionSymbols = withUser(filterSubscriptionSymbols(subscriptionSymbols, filter))
 
-	for _, subscriptionSymbol := range subscriptionSymbols {
-		stockPrice, err := u.marketDataUsecase.GetStockPrice(ctx, subscriptionSymbol.StockSymbol.Symbol, nil)
+	runWorkers(ctx, u.workers, groupBySymbol(subscriptionSymbols), func(ctx context.Context, group []*entity.SubscriptionSymbol) {
+		symbol := group[0].StockSymbol.Symbol
+		stockPrice, err := u.marketDataUsecase.GetStockPrice(ctx, symbol, nil)
 		if err != nil {
 			u.logger.Error("SendStockPriceNotification GetStockPrice Error",
-				logger.String("symbol", subscriptionSymbol.StockSymbol.Symbol),
+				logger.String("symbol", symbol),
 				logger.Error(err),
 			)
-			continue
+			return
 		}
 
-		data := u.formatterPort.FormatStockPrice(stockPrice, valueobject.UserTypeTelegram)
-		if err != nil {
-			u.logger.Error("SendStockPriceNotification FormatStockPrice Error",
-				logger.String("symbol", subscriptionSymbol.StockSymbol.Symbol),
-				logger.Error(err),
-			)
-			continue
+		payloads := make(map[valueobject.UserType]dto.NotificationPayload)
+		for _, subscriptionSymbol := range group {
+			userType := subscriptionSymbol.User.UserType
+			payload, ok := payloads[userType]
+			if !ok {
+				payload = dto.NotificationPayload{Text: u.formatterPort.FormatStockPrice(stockPrice, userType)}
+				payloads[userType] = payload
+			}
+			notification := subscriptionNotification(subscriptionSymbol, valueobject.SubscriptionTypeStockInfo, payload)
+			notification.TradeDate = stockPrice.Date
+			u.enqueue(ctx, "SendStockPriceNotification", subscriptionSymbol, notification)
 		}
-
-		accountID, err := strconv.ParseInt(subscriptionSymbol.User.AccountID, 10, 64)
-		if err != nil {
-			u.logger.Error("SendStockPriceNotification ParseInt Error",
-				logger.String("accountID", subscriptionSymbol.User.AccountID),
-				logger.Error(err),
-			)
-			continue
-		}
-
-		err = u.client.SendMessage(accountID, data)
-		if err != nil {
-			u.logger.Error("SendStockPriceNotification SendMessage Error",
-				logger.Int64("accountID", accountID),
-				logger.String("data", data),
-				logger.Error(err),
-			)
-			continue
-		}
-	}
+	})
 	return nil
 }
 
-// SendStockNewsNotification 推送股票新聞
-func (u *sendNotificationUsecase) SendStockNewsNotification(ctx context.Context) error {
+// SendStockNewsNotification 推送股票新聞，每檔股票只查詢一次新聞，再依使用者平台格式化後推送給所有訂閱者
+func (u *sendNotificationUsecase) SendStockNewsNotification(ctx context.Context, filter SubscriptionFilter) error {
 	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeStockNews)
 	if err != nil {
 		return err
 	}
+	subscriptionSymbols = withUser(filterSubscriptionSymbols(subscriptionSymbols, filter))
 
-	for _, subscriptionSymbol := range subscriptionSymbols {
-		stockNews, err := u.marketDataUsecase.GetStockNews(ctx, subscriptionSymbol.StockSymbol.Symbol, 5)
+	runWorkers(ctx, u.workers, groupBySymbol(subscriptionSymbols), func(ctx context.Context, group []*entity.SubscriptionSymbol) {
+		symbol := group[0].StockSymbol.Symbol
+		stockNews, err := u.marketDataUsecase.GetStockNews(ctx, symbol, 5)
 		if err != nil {
 			u.logger.Error("SendStockNewsNotification GetStockNews Error",
-				logger.String("symbol", subscriptionSymbol.StockSymbol.Symbol),
+				logger.String("symbol", symbol),
 				logger.Error(err),
 			)
-			continue
+			return
 		}
 
-		data := u.formatterPort.FormatTelegramNewsMessage(*stockNews, subscriptionSymbol.StockSymbol.Symbol, subscriptionSymbol.StockSymbol.Name)
-		if err != nil {
-			u.logger.Error("SendStockNewsNotification FormatTelegramNewsMessage Error",
-				logger.String("symbol", subscriptionSymbol.StockSymbol.Symbol),
-				logger.Error(err),
-			)
-			continue
+		payloads := make(map[valueobject.UserType]dto.NotificationPayload)
+		for _, subscriptionSymbol := range group {
+			userType := subscriptionSymbol.User.UserType
+			payload, ok := payloads[userType]
+			if !ok {
+				payload, err = u.formatNewsPayload(*stockNews, subscriptionSymbol)
+				if err != nil {
+					// 只略過這位訂閱者，同一檔股票的其他訂閱者仍照常推播
+					u.logger.Error("SendStockNewsNotification FormatNewsMessage Error",
+						logger.String("symbol", symbol),
+						logger.String("accountID", subscriptionSymbol.User.AccountID),
+						logger.Error(err),
+					)
+					continue
+				}
+				payloads[userType] = payload
+			}
+			u.enqueue(ctx, "SendStockNewsNotification", subscriptionSymbol, subscriptionNotification(subscriptionSymbol, valueobject.SubscriptionTypeStockNews, payload))
 		}
+	})
+	return nil
+}
 
-		accountID, err := strconv.ParseInt(subscriptionSymbol.User.AccountID, 10, 64)
-		if err != nil {
-			u.logger.Error("SendStockNewsNotification ParseInt Error",
-				logger.String("accountID", subscriptionSymbol.User.AccountID),
-				logger.Error(err),
-			)
-			continue
-		}
+// SendMarketInfoNotification 推送大盤資訊，大盤資料每次排程只查詢一次
+func (u *sendNotificationUsecase) SendMarketInfoNotification(ctx context.Context, filter SubscriptionFilter) error {
+	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeDailyMarketInfo)
+	if err != nil {
+		return err
+	}
+	subscriptionSymbols = withUser(filterSubscriptionSymbols(subscriptionSymbols, filter))
+	if len(subscriptionSymbols) == 0 {
+		return nil
+	}
 
-		err = u.client.SendMessageWithKeyboard(accountID, data.Text, data.InlineKeyboardMarkup)
-		if err != nil {
-			u.logger.Error("SendStockNewsNotification SendMessageWithKeyboard Error",
-				logger.Int64("accountID", accountID),
-				logger.String("data", data.Text),
-				logger.Error(err),
-			)
-			continue
-		}
+	marketInfo, err := u.marketDataUsecase.GetDailyMarketInfo(ctx, 1)
+	if err != nil {
+		u.logger.Error("SendMarketInfoNotification GetDailyMarketInfo Error",
+			logger.Error(err),
+		)
+		return err
 	}
+
+	payloads := renderByUserType(subscriptionSymbols, func(userType valueobject.UserType) dto.NotificationPayload {
+		return dto.NotificationPayload{Text: u.formatterPort.FormatDailyMarketInfo(marketInfo, userType)}
+	})
+	runWorkers(ctx, u.workers, subscriptionSymbols, func(ctx context.Context, subscriptionSymbol *entity.SubscriptionSymbol) {
+		u.enqueue(ctx, "SendMarketInfoNotification", subscriptionSymbol, subscriptionNotification(subscriptionSymbol, valueobject.SubscriptionTypeDailyMarketInfo, payloads[subscriptionSymbol.User.UserType]))
+	})
 	return nil
 }
 
-// SendMarketInfoNotification 推送大盤資訊
-func (u *sendNotificationUsecase) SendMarketInfoNotification(ctx context.Context) error {
-	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeDailyMarketInfo)
+// SendTopVolumeNotification 推送交易量排行，排行資料每次排程只查詢一次
+func (u *sendNotificationUsecase) SendTopVolumeNotification(ctx context.Context, filter SubscriptionFilter) error {
+	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeTopVolumeItems)
 	if err != nil {
 		return err
 	}
+	subscriptionSymbols = withUser(filterSubscriptionSymbols(subscriptionSymbols, filter))
+	if len(subscriptionSymbols) == 0 {
+		return nil
+	}
 
-	for _, subscriptionSymbol := range subscriptionSymbols {
-		stockPrice, err := u.marketDataUsecase.GetDailyMarketInfo(ctx, 1)
-		if err != nil {
-			u.logger.Error("SendMarketInfoNotification GetDailyMarketInfo Error",
-				logger.Error(err),
-			)
-			continue
-		}
+	topVolumeStocks, err := u.marketDataUsecase.GetTopVolumeStock(ctx)
+	if err != nil {
+		u.logger.Error("SendTopVolumeNotification GetTopVolumeStock Error",
+			logger.Error(err),
+		)
+		return err
+	}
 
-		data := u.formatterPort.FormatDailyMarketInfo(stockPrice, valueobject.UserTypeTelegram)
-		if err != nil {
-			u.logger.Error("SendMarketInfoNotification FormatDailyMarketInfo Error",
-				logger.Error(err),
-			)
-			continue
-		}
+	payloads := renderByUserType(subscriptionSymbols, func(userType valueobject.UserType) dto.NotificationPayload {
+		return dto.NotificationPayload{Text: u.formatterPort.FormatTopVolumeStock(topVolumeStocks, userType)}
+	})
+	runWorkers(ctx, u.workers, subscriptionSymbols, func(ctx context.Context, subscriptionSymbol *entity.SubscriptionSymbol) {
+		u.enqueue(ctx, "SendTopVolumeNotification", subscriptionSymbol, subscriptionNotification(subscriptionSymbol, valueobject.SubscriptionTypeTopVolumeItems, payloads[subscriptionSymbol.User.UserType]))
+	})
+	return nil
+}
 
-		accountID, err := strconv.ParseInt(subscriptionSymbol.User.AccountID, 10, 64)
-		if err != nil {
-			u.logger.Error("SendMarketInfoNotification ParseInt Error",
-				logger.String("accountID", subscriptionSymbol.User.AccountID),
-				logger.Error(err),
-			)
-			continue
-		}
+// enqueue 將單一訂閱者的推播寫入佇列，失敗時僅記錄錯誤
+func (u *sendNotificationUsecase) enqueue(ctx context.Context, task string, subscriptionSymbol *entity.SubscriptionSymbol, notification *dto.Notification) {
+	err := u.outbox.Enqueue(ctx, subscriptionSymbol.User, notification)
+	if err != nil {
+		u.logger.Error(task+" Enqueue Error",
+			logger.String("accountID", subscriptionSymbol.User.AccountID),
+			logger.Error(err),
+		)
+	}
+}
 
-		err = u.client.SendMessage(accountID, data)
+// formatNewsPayload 依使用者平台格式化新聞推播：Telegram 附上新聞連結按鈕，LINE 以 Flex Message 呈現
+func (u *sendNotificationUsecase) formatNewsPayload(news []dto.StockNews, subscriptionSymbol *entity.SubscriptionSymbol) (dto.NotificationPayload, error) {
+	symbol := subscriptionSymbol.StockSymbol.Symbol
+	name := subscriptionSymbol.StockSymbol.Name
+
+	if subscriptionSymbol.User.UserType != valueobject.UserTypeLine {
+		message := u.formatterPort.FormatTelegramNewsMessage(news, name, symbol)
+		return dto.NotificationPayload{Text: message.Text, Keyboard: message.InlineKeyboardMarkup}, nil
+	}
+
+	message := u.formatterPort.FormatLineNewsMessage(news, name, symbol)
+	payload := dto.NotificationPayload{Text: message.Text}
+	if message.UseFlexMessage && message.FlexContainer != nil {
+		flex, err := json.Marshal(message.FlexContainer)
 		if err != nil {
-			u.logger.Error("SendMarketInfoNotification SendMessage Error",
-				logger.Int64("accountID", accountID),
-				logger.String("data", data),
-				logger.Error(err),
-			)
-			continue
+			return payload, err
 		}
+		payload.LineFlex = flex
 	}
-	return nil
+	return payload, nil
 }
 
-// SendTopVolumeNotification 推送交易量排行
-func (u *sendNotificationUsecase) SendTopVolumeNotification(ctx context.Context) error {
-	subscriptionSymbols, err := u.subscriptionSymbolRepo.GetByFeature(ctx, valueobject.SubscriptionTypeTopVolumeItems)
-	if err != nil {
-		return err
+// filterSubscriptionSymbols 依排程及訂閱股票的市場篩選本次需要推播的訂閱股票，filter 為 nil 時不篩選
+func filterSubscriptionSymbols(subscriptionSymbols []*entity.SubscriptionSymbol, filter SubscriptionFilter) []*entity.SubscriptionSymbol {
+	if filter == nil {
+		return subscriptionSymbols
 	}
 
+	filtered := make([]*entity.SubscriptionSymbol, 0, len(subscriptionSymbols))
 	for _, subscriptionSymbol := range subscriptionSymbols {
-		topVolumeStocks, err := u.marketDataUsecase.GetTopVolumeStock(ctx)
-		if err != nil {
-			u.logger.Error("SendTopVolumeNotification GetTopVolumeStock Error",
-				logger.Error(err),
-			)
+		subscription := subscriptionSymbol.Subscription
+		if subscription == nil {
 			continue
 		}
+		var stock *entity.StockSymbol
+		if subscription.Item.IsSymbolBased() {
+			stock = subscriptionSymbol.StockSymbol
+		}
+		if filter(subscription, stock) {
+			filtered = append(filtered, subscriptionSymbol)
+		}
+	}
+	return filtered
+}
 
-		data := u.formatterPort.FormatTopVolumeStock(topVolumeStocks, valueobject.UserTypeTelegram)
-		if err != nil {
-			u.logger.Error("SendTopVolumeNotification FormatTopVolumeStock Error",
-				logger.Error(err),
-			)
-			continue
+// withUser 略過未載入使用者的訂閱，這類訂閱無法決定推播平台及收件者
+func withUser(subscriptionSymbols []*entity.SubscriptionSymbol) []*entity.SubscriptionSymbol {
+	result := make([]*entity.SubscriptionSymbol, 0, len(subscriptionSymbols))
+	for _, subscriptionSymbol := range subscriptionSymbols {
+		if subscriptionSymbol.User != nil {
+			result = append(result, subscriptionSymbol)
 		}
+	}
+	return result
+}
 
-		accountID, err := strconv.ParseInt(subscriptionSymbol.User.AccountID, 10, 64)
-		if err != nil {
-			u.logger.Error("SendTopVolumeNotification ParseInt Error",
-				logger.String("accountID", subscriptionSymbol.User.AccountID),
-				logger.Error(err),
-			)
+// groupBySymbol 依股票將訂閱分組，保留股票第一次出現的順序
+func groupBySymbol(subscriptionSymbols []*entity.SubscriptionSymbol) [][]*entity.SubscriptionSymbol {
+	index := make(map[string]int)
+	groups := make([][]*entity.SubscriptionSymbol, 0)
+	for _, subscriptionSymbol := range subscriptionSymbols {
+		if subscriptionSymbol.StockSymbol == nil {
 			continue
 		}
+		symbol := subscriptionSymbol.StockSymbol.Symbol
+		i, ok := index[symbol]
+		if !ok {
+			i = len(groups)
+			index[symbol] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], subscriptionSymbol)
+	}
+	return groups
+}
 
-		err = u.client.SendMessage(accountID, data)
-		if err != nil {
-			u.logger.Error("SendTopVolumeNotification SendMessage Error",
-				logger.Int64("accountID", accountID),
-				logger.String("data", data),
-				logger.Error(err),
-			)
-			continue
+// renderByUserType 依訂閱者的平台各格式化一次推播內容
+func renderByUserType(subscriptionSymbols []*entity.SubscriptionSymbol, render func(valueobject.UserType) dto.NotificationPayload) map[valueobject.UserType]dto.NotificationPayload {
+	payloads := make(map[valueobject.UserType]dto.NotificationPayload)
+	for _, subscriptionSymbol := range subscriptionSymbols {
+		userType := subscriptionSymbol.User.UserType
+		if _, ok := payloads[userType]; !ok {
+			payloads[userType] = render(userType)
 		}
 	}
-	return nil
+	return payloads
+}
+
+// subscriptionNotification 建立訂閱推播的通知內容，記錄觸發的訂閱與股票
+func subscriptionNotification(subscriptionSymbol *entity.SubscriptionSymbol, feature valueobject.SubscriptionType, payload dto.NotificationPayload) *dto.Notification {
+	notification := &dto.Notification{
+		Feature: feature,
+		Payload: payload,
+	}
+	if subscriptionSymbol.Subscription != nil {
+		subscriptionID := subscriptionSymbol.Subscription.ID
+		notification.SubscriptionID = &subscriptionID
+	}
+	if subscriptionSymbol.SymbolID != 0 {
+		symbolID := subscriptionSymbol.SymbolID
+		notification.SymbolID = &symbolID
+	}
+	return notification
 }
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// mockFormatter 回傳固定的推播內容，其餘方法未實作
+type mockFormatter struct {
+	port.FormatterPort
+}
+
+func (m *mockFormatter) FormatStockPrice(stockPrice *dto.StockPrice, userType valueobject.UserType) string {
+	return "price " + stockPrice.Symbol
+}
+
+func (m *mockFormatter) FormatDailyMarketInfo(marketInfo *[]dto.DailyMarketInfo, userType valueobject.UserType) string {
+	return "market"
+}
+
+func TestSendNotificationUsecase_SkipsSubscriptionsWithoutUser(t *testing.T) {
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	user := &entity.User{ID: 1, AccountID: "100", UserType: valueobject.UserTypeTelegram, Status: true}
+	subscriptionSymbols := []*entity.SubscriptionSymbol{
+		{ID: 1, UserID: 1, SymbolID: 1, User: user, StockSymbol: tsmc},
+		{ID: 2, UserID: 2, SymbolID: 1, StockSymbol: tsmc},
+	}
+
+	tests := []struct {
+		name string
+		send func(u SendNotificationUsecase) error
+	}{
+		{name: "個股收盤", send: func(u SendNotificationUsecase) error { return u.SendStockPriceNotification(context.Background(), nil) }},
+		{name: "大盤資訊", send: func(u SendNotificationUsecase) error { return u.SendMarketInfoNotification(context.Background(), nil) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &mockOutbox{}
+			usecase := NewSendNotificationUsecase(
+				&mockSubscriptionSymbolRepo{subscriptionSymbols: subscriptionSymbols},
+				&mockMarketDataUsecase{prices: map[string]*dto.StockPrice{"2330": {Symbol: "2330"}}},
+				&mockFormatter{},
+				outbox,
+				&mockLogger{},
+			)
+
+			if err := tt.send(usecase); err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if len(outbox.users) != 1 || outbox.users[0] != user {
+				t.Errorf("期望只推播給已載入的使用者，實際 %v", outbox.users)
+			}
+		})
+	}
+}
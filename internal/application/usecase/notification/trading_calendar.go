@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+// tradingCalendarLookahead 判斷交易日資料是否涵蓋查詢日期時往後查詢的天數
+const tradingCalendarLookahead = 30
+
+// 交易日資料的市場代碼
+const (
+	marketTW = "TW"
+	marketUS = "US"
+)
+
+// tradingMarket 訂閱股票所屬的交易日市場，非個股的訂閱項目（stock 為 nil）以台股判斷
+func tradingMarket(stock *entity.StockSymbol) string {
+	if stock != nil && stock.IsUSStock() {
+		return marketUS
+	}
+	return marketTW
+}
+
+// tradingCalendar 依 trade_dates 判斷各市場當天是否開市
+type tradingCalendar struct {
+	tradeDateReader port.TradeDateReader
+}
+
+func newTradingCalendar(tradeDateReader port.TradeDateReader) *tradingCalendar {
+	return &tradingCalendar{tradeDateReader: tradeDateReader}
+}
+
+// IsTradingDay 判斷 day 所在日期是否為 market 的交易日。
+// 交易日資料尚未涵蓋該日期（例如尚未同步隔年行事曆）時，以週一至週五估算；
+// 美股沒有同步交易日資料，以紐約時間的日期排除週末及 NYSE 休市日
+func (c *tradingCalendar) IsTradingDay(ctx context.Context, market string, day time.Time) (bool, error) {
+	if market == marketUS {
+		return isUSTradingDay(day), nil
+	}
+
+	date := day.Format("2006-01-02")
+	if c.tradeDateReader == nil {
+		return isWeekday(day), nil
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	tradeDates, err := c.tradeDateReader.GetByDateRange(ctx, start, start.AddDate(0, 0, tradingCalendarLookahead))
+	if err != nil {
+		return false, err
+	}
+
+	covered := false
+	for _, tradeDate := range tradeDates {
+		if tradeDate.Exchange != market {
+			continue
+		}
+		tradeDay := tradeDate.Date.Format("2006-01-02")
+		if tradeDay == date {
+			return true, nil
+		}
+		if tradeDay > date {
+			covered = true
+		}
+	}
+
+	if covered {
+		return false, nil
+	}
+	return isWeekday(day), nil
+}
+
+func isWeekday(day time.Time) bool {
+	return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+}
+
+// isUSTradingDay 以紐約時間判斷 day 是否為美股交易日
+func isUSTradingDay(day time.Time) bool {
+	day = day.In(newYorkLocation())
+	return isWeekday(day) && !isUSMarketHoliday(day)
+}
+
+// newYorkLocation 美股使用的紐約時區，系統缺少時區資料時以美東標準時間代替
+func newYorkLocation() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return time.FixedZone("EST", -5*3600)
+}
+
+// isUSMarketHoliday 判斷 day 所在日期是否為 NYSE 休市日。
+// 假日逢週六於前一個週五休市、逢週日於隔週一休市；元旦逢週六時不另外休市
+func isUSMarketHoliday(day time.Time) bool {
+	year := day.Year()
+	date := time.Date(year, day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	holidays := []time.Time{
+		nthWeekday(year, time.January, time.Monday, 3),
+		nthWeekday(year, time.February, time.Monday, 3),
+		easterSunday(year).AddDate(0, 0, -2),
+		lastWeekday(year, time.May, time.Monday),
+		observedHoliday(year, time.July, 4),
+		nthWeekday(year, time.September, time.Monday, 1),
+		nthWeekday(year, time.November, time.Thursday, 4),
+		observedHoliday(year, time.December, 25),
+	}
+	if newYear := observedHoliday(year, time.January, 1); newYear.Year() == year {
+		holidays = append(holidays, newYear)
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observedHoliday(year, time.June, 19))
+	}
+
+	for _, holiday := range holidays {
+		if holiday.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// observedHoliday 固定日期假日的實際休市日
+func observedHoliday(year int, month time.Month, day int) time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	switch date.Weekday() {
+	case time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// nthWeekday 當月第 n 個星期 weekday
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// lastWeekday 當月最後一個星期 weekday
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easterSunday 以格里曆復活節演算法計算復活節日期
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultNotificationWorkers 推播資料取得與寫入佇列時同時執行的工作者數量
+const defaultNotificationWorkers = 8
+
+// runWorkers 以固定數量的工作者並行處理 items，全部處理完成或 ctx 取消後返回；
+// 個別項目的錯誤由 fn 自行處理
+func runWorkers[T any](ctx context.Context, workers int, items []T, fn func(context.Context, T)) {
+	if len(items) == 0 {
+		return
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	itemChan := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range itemChan {
+				fn(ctx, item)
+			}
+		}()
+	}
+
+send:
+	for _, item := range items {
+		// ctx 已取消時 select 仍可能選到可送出的分支，先檢查以免繼續派送
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break send
+		case itemChan <- item:
+		}
+	}
+	close(itemChan)
+	wg.Wait()
+}
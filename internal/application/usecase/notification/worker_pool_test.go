@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunWorkers_ProcessesEveryItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		items   int
+	}{
+		{name: "項目多於工作者", workers: 4, items: 100},
+		{name: "工作者多於項目", workers: 8, items: 3},
+		{name: "工作者數量未設定", workers: 0, items: 5},
+		{name: "沒有項目", workers: 4, items: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]int, tt.items)
+			for i := range items {
+				items[i] = i
+			}
+
+			var mu sync.Mutex
+			seen := make(map[int]int)
+			runWorkers(context.Background(), tt.workers, items, func(ctx context.Context, item int) {
+				mu.Lock()
+				defer mu.Unlock()
+				seen[item]++
+			})
+
+			if len(seen) != tt.items {
+				t.Fatalf("處理項目期望 %d 個，實際 %d 個", tt.items, len(seen))
+			}
+			for item, count := range seen {
+				if count != 1 {
+					t.Errorf("項目 %d 處理次數期望 1，實際 %d", item, count)
+				}
+			}
+		})
+	}
+}
+
+func TestRunWorkers_ConcurrencyBound(t *testing.T) {
+	const workers = 3
+	items := make([]int, 30)
+
+	var active, maxActive int32
+	runWorkers(context.Background(), workers, items, func(ctx context.Context, item int) {
+		current := atomic.AddInt32(&active, 1)
+		for {
+			observed := atomic.LoadInt32(&maxActive)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxActive, observed, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&active, -1)
+	})
+
+	if maxActive > workers {
+		t.Errorf("同時執行數量不應超過 %d，實際 %d", workers, maxActive)
+	}
+	if maxActive < 2 {
+		t.Errorf("應並行處理，實際同時執行數量最多 %d", maxActive)
+	}
+}
+
+func TestRunWorkers_ContextCanceled(t *testing.T) {
+	items := make([]int, 100)
+
+	t.Run("開始前已取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var processed int32
+		runWorkers(ctx, 4, items, func(ctx context.Context, item int) {
+			atomic.AddInt32(&processed, 1)
+		})
+		if processed != 0 {
+			t.Errorf("ctx 已取消時不應處理任何項目，實際處理 %d 個", processed)
+		}
+	})
+
+	t.Run("處理中取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var processed int32
+		runWorkers(ctx, 1, items, func(ctx context.Context, item int) {
+			atomic.AddInt32(&processed, 1)
+			cancel()
+		})
+		// 取消前可能已有一個項目在派送中
+		if processed > 2 {
+			t.Errorf("取消後應停止派送，實際處理 %d 個", processed)
+		}
+	})
+}
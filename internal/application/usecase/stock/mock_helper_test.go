@@ -111,6 +111,7 @@ type mockMarketDataPort struct {
 	GetLatestTradeDateFunc            func(ctx context.Context) (time.Time, error)
 	GetLatestTradeDateByDateRangeFunc func(ctx context.Context, startDate time.Time, endDate time.Time) ([]time.Time, error)
 	GetStockNewsFunc                  func(ctx context.Context, symbol string) ([]dto.StockNews, error)
+	GetStockDividendsFunc             func(ctx context.Context, symbol string, startDate time.Time, endDate time.Time) ([]dto.StockDividend, error)
 }
 
 func (m *mockMarketDataPort) GetDailyMarketInfo(ctx context.Context, count int) (*[]dto.DailyMarketInfo, error) {
@@ -176,6 +177,13 @@ func (m *mockMarketDataPort) GetStockNews(ctx context.Context, symbol string) ([
 	return nil, nil
 }
 
+func (m *mockMarketDataPort) GetStockDividends(ctx context.Context, symbol string, startDate time.Time, endDate time.Time) ([]dto.StockDividend, error) {
+	if m != nil && m.GetStockDividendsFunc != nil {
+		return m.GetStockDividendsFunc(ctx, symbol, startDate, endDate)
+	}
+	return nil, nil
+}
+
 type mockTradeDateRepository struct {
 	GetByIDFunc               func(ctx context.Context, id uint) (*entity.TradeDate, error)
 	GetByDateFunc             func(ctx context.Context, date time.Time) (*entity.TradeDate, error)
@@ -3,6 +3,7 @@ package stock
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
@@ -10,6 +11,9 @@ import (
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 )
 
+// usStockPriceLookbackDays 查詢美股股價時往前查詢的天數，涵蓋美股連假
+const usStockPriceLookbackDays = 14
+
 type MarketDataUsecase interface {
 	GetDailyMarketInfo(ctx context.Context, count int) (*[]dto.DailyMarketInfo, error)
 	GetStockPerformance(ctx context.Context, symbol string) (*dto.StockPerformance, error)
@@ -17,8 +21,10 @@ type MarketDataUsecase interface {
 	GetStockPrice(ctx context.Context, symbol string, date *time.Time) (*dto.StockPrice, error)
 	GetLatestTradeDate(ctx context.Context) (time.Time, error)
 	GetLatestTradeDateByDateRange(ctx context.Context, startDate time.Time, endDate time.Time) ([]time.Time, error) // 修正：改為 []time.Time
+	GetPreviousTradeDate(ctx context.Context, date time.Time) (time.Time, error)
 	GetStockNews(ctx context.Context, symbol string, limit int) (*[]dto.StockNews, error)
 	GetStockCompanyInfo(ctx context.Context, symbol string) (*dto.StockCompanyInfo, error)
+	GetDividendCalendar(ctx context.Context, symbol string) (*dto.DividendCalendar, error)
 }
 
 type marketDataUsecase struct {
@@ -117,6 +123,11 @@ func (uc *marketDataUsecase) GetStockPrice(ctx context.Context, symbol string, d
 		date = &now
 	}
 
+	// 美股不適用台股交易日及收盤時間，直接取區間內最近兩個交易日
+	if stock.IsUSStock() {
+		return uc.getUSStockPrice(ctx, stock.Symbol, *date)
+	}
+
 	// 判斷時間是否為兩點前，若是則取前一天
 	if date.Hour() < 14 {
 		prevDate := date.AddDate(0, 0, -1)
@@ -145,8 +156,12 @@ func (uc *marketDataUsecase) GetStockPrice(ctx context.Context, symbol string, d
 
 	tradeDateResult := (*result)[0]
 
-	// 取得前一天收盤價
-	prevTradeDate := tradeDates[len(tradeDates)-2]
+	// 透過交易日資料取得實際的前一交易日，避免週末及假日造成誤差
+	prevTradeDate, err := uc.GetPreviousTradeDate(ctx, tradeDate)
+	if err != nil {
+		uc.logger.Error("取得前一交易日失敗", logger.Error(err))
+		return nil, fmt.Errorf("無法取得最近交易日")
+	}
 	uc.logger.Info("取得股價資訊", logger.String("symbol", symbol), logger.Time("prevTradeDate", prevTradeDate))
 	prevTradeDateResults, err := uc.market.GetStockPrice(ctx, symbol, &prevTradeDate)
 
@@ -174,6 +189,7 @@ func (uc *marketDataUsecase) GetStockPrice(ctx context.Context, symbol string, d
 	return &dto.StockPrice{
 		Symbol:         tradeDateResult.Symbol,
 		Name:           tradeDateResult.Name,
+		Currency:       tradeDateResult.Currency,
 		Date:           tradeDateResult.Date,
 		OpenPrice:      tradeDateResult.OpenPrice,
 		ClosePrice:     tradeDateResult.ClosePrice,
@@ -184,10 +200,48 @@ func (uc *marketDataUsecase) GetStockPrice(ctx context.Context, symbol string, d
 		ChangeAmount:   changeAmount,
 		ChangeRate:     changeRate,
 		UpDownSign:     upDownSign,
+		PrevTradeDate:  prevTradeDate,
 		PrevClosePrice: prevTradeDateResult.ClosePrice,
 	}, nil
 }
 
+// getUSStockPrice 取得美股在指定日期（含）以前最近一個交易日的股價，漲跌以前一個交易日收盤價計算
+func (uc *marketDataUsecase) getUSStockPrice(ctx context.Context, symbol string, date time.Time) (*dto.StockPrice, error) {
+	startDate := date.AddDate(0, 0, -usStockPriceLookbackDays)
+	uc.logger.Info("取得美股股價資訊", logger.String("symbol", symbol), logger.Time("date", date))
+	result, err := uc.market.GetStockPrice(ctx, symbol, &startDate, &date)
+	if err != nil {
+		uc.logger.Error("取得股價資訊失敗", logger.Error(err))
+		return nil, fmt.Errorf("查無資料，請確認後再試")
+	}
+	if result == nil || len(*result) < 2 {
+		return nil, fmt.Errorf("查無資料，請確認後再試")
+	}
+
+	prices := *result
+	latest := prices[len(prices)-1]
+	prev := prices[len(prices)-2]
+
+	changeAmount := latest.ClosePrice - prev.ClosePrice
+	changeRate := 0.0
+	if prev.ClosePrice != 0 {
+		changeRate = (changeAmount / prev.ClosePrice) * 100
+	}
+	upDownSign := ""
+	if changeAmount > 0 {
+		upDownSign = "+"
+	} else {
+		upDownSign = "-"
+	}
+
+	latest.PrevTradeDate = prev.Date
+	latest.PrevClosePrice = prev.ClosePrice
+	latest.ChangeAmount = changeAmount
+	latest.ChangeRate = changeRate
+	latest.UpDownSign = upDownSign
+	return &latest, nil
+}
+
 func (uc *marketDataUsecase) GetLatestTradeDate(ctx context.Context) (time.Time, error) {
 	tradeDate, err := uc.tradeDateRepo.GetByDateRange(ctx, time.Now().AddDate(0, 0, -30), time.Now())
 	if err != nil {
@@ -234,6 +288,23 @@ func (uc *marketDataUsecase) GetLatestTradeDateByDateRange(ctx context.Context,
 	return tradeDates, nil
 }
 
+// GetPreviousTradeDate 取得指定日期的前一個交易日
+func (uc *marketDataUsecase) GetPreviousTradeDate(ctx context.Context, date time.Time) (time.Time, error) {
+	tradeDates, err := uc.tradeDateRepo.GetByDateRange(ctx, date.AddDate(0, 0, -30), date)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	target := date.Format("2006-01-02")
+	for i := len(tradeDates) - 1; i >= 0; i-- {
+		if tradeDates[i].Date.Format("2006-01-02") < target {
+			return tradeDates[i].Date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("找不到交易日資料")
+}
+
 func (uc *marketDataUsecase) GetStockNews(ctx context.Context, symbol string, limit int) (*[]dto.StockNews, error) {
 	stock, err := uc.validation.ValidateSymbol(ctx, symbol)
 	if err != nil || stock == nil {
@@ -276,3 +347,70 @@ func (uc *marketDataUsecase) GetStockCompanyInfo(ctx context.Context, symbol str
 	}
 	return companyInfo, nil
 }
+
+// dividendHistoryYears 除權息行事曆查詢的年數
+const dividendHistoryYears = 5
+
+// dividendHistoryLimit 除權息行事曆最多列出的歷史筆數
+const dividendHistoryLimit = 8
+
+// GetDividendCalendar 取得個股歷年股利及即將到來的除權息日
+func (uc *marketDataUsecase) GetDividendCalendar(ctx context.Context, symbol string) (*dto.DividendCalendar, error) {
+	stock, err := uc.validation.ValidateSymbol(ctx, symbol)
+	if err != nil || stock == nil {
+		return nil, fmt.Errorf("查無此股票代號，請重新確認")
+	}
+
+	now := time.Now()
+	dividends, err := uc.market.GetStockDividends(ctx, stock.Symbol, now.AddDate(-dividendHistoryYears, 0, 0), now)
+	if err != nil {
+		uc.logger.Error("取得股利資料失敗", logger.Error(err))
+		return nil, fmt.Errorf("取得股利資料失敗，請稍後再試")
+	}
+
+	today := now.Format("2006-01-02")
+	calendar := &dto.DividendCalendar{Symbol: stock.Symbol, Name: stock.Name}
+	for _, dividend := range dividends {
+		if dividend.CashDividend <= 0 && dividend.StockDividend <= 0 {
+			continue
+		}
+
+		exDate := latestExDividendDate(dividend)
+		switch {
+		case exDate.IsZero():
+			// 已公告但尚未決定除權息日，僅列出近一年公告的資料
+			if dividend.AnnouncementDate.After(now.AddDate(-1, 0, 0)) {
+				calendar.Upcoming = append(calendar.Upcoming, dividend)
+			}
+		case exDate.Format("2006-01-02") >= today:
+			calendar.Upcoming = append(calendar.Upcoming, dividend)
+		default:
+			calendar.History = append(calendar.History, dividend)
+		}
+	}
+
+	// 即將除權息依日期由近到遠，日期未定的排最後
+	sort.SliceStable(calendar.Upcoming, func(i, j int) bool {
+		a, b := latestExDividendDate(calendar.Upcoming[i]), latestExDividendDate(calendar.Upcoming[j])
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b)
+	})
+	sort.SliceStable(calendar.History, func(i, j int) bool {
+		return latestExDividendDate(calendar.History[i]).After(latestExDividendDate(calendar.History[j]))
+	})
+	if len(calendar.History) > dividendHistoryLimit {
+		calendar.History = calendar.History[:dividendHistoryLimit]
+	}
+
+	return calendar, nil
+}
+
+// latestExDividendDate 取得除息日與除權日中較晚的日期，皆未定時回傳零值
+func latestExDividendDate(dividend dto.StockDividend) time.Time {
+	if dividend.StockExDividendDate.After(dividend.CashExDividendDate) {
+		return dividend.StockExDividendDate
+	}
+	return dividend.CashExDividendDate
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 )
 
 func TestMarketDataUsecase_GetDailyMarketInfo(t *testing.T) {
@@ -90,3 +91,192 @@ func TestMarketDataUsecase_GetDailyMarketInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestMarketDataUsecase_GetPreviousTradeDate(t *testing.T) {
+	parse := func(value string) time.Time {
+		result, _ := time.Parse("2006-01-02", value)
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		date        time.Time
+		tradeDates  []*entity.TradeDate
+		expected    string
+		expectError bool
+	}{
+		{
+			name: "週一取得上週五",
+			date: parse("2025-03-17"),
+			tradeDates: []*entity.TradeDate{
+				{Date: parse("2025-03-13")},
+				{Date: parse("2025-03-14")},
+				{Date: parse("2025-03-17")},
+			},
+			expected: "2025-03-14",
+		},
+		{
+			name: "連假後取得假期前最後交易日",
+			date: parse("2025-02-03"),
+			tradeDates: []*entity.TradeDate{
+				{Date: parse("2025-01-22")},
+				{Date: parse("2025-02-03")},
+			},
+			expected: "2025-01-22",
+		},
+		{
+			name: "查無前一交易日",
+			date: parse("2025-02-03"),
+			tradeDates: []*entity.TradeDate{
+				{Date: parse("2025-02-03")},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockTradeDateRepository{
+				GetByDateRangeFunc: func(ctx context.Context, startDate, endDate time.Time) ([]*entity.TradeDate, error) {
+					return tt.tradeDates, nil
+				},
+			}
+
+			uc := NewMarketDataUsecase(nil, nil, mockRepo, &mockLogger{})
+
+			result, err := uc.GetPreviousTradeDate(context.Background(), tt.date)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("期望錯誤但沒有發生錯誤")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("不期望錯誤但發生錯誤: %v", err)
+			}
+			if result.Format("2006-01-02") != tt.expected {
+				t.Errorf("前一交易日不符合期望，期望: %s, 實際: %s", tt.expected, result.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestMarketDataUsecase_GetDividendCalendar(t *testing.T) {
+	now := time.Now()
+	past := now.AddDate(0, -3, 0)
+	older := now.AddDate(-1, 0, 0)
+	future := now.AddDate(0, 0, 10)
+
+	mockMarket := &mockMarketDataPort{
+		GetStockDividendsFunc: func(ctx context.Context, symbol string, startDate time.Time, endDate time.Time) ([]dto.StockDividend, error) {
+			return []dto.StockDividend{
+				{Symbol: "2330", Year: "舊", CashDividend: 3, CashExDividendDate: older},
+				{Symbol: "2330", Year: "近", CashDividend: 4, CashExDividendDate: past},
+				{Symbol: "2330", Year: "未定", CashDividend: 5, AnnouncementDate: now.AddDate(0, 0, -7)},
+				{Symbol: "2330", Year: "即將", CashDividend: 4.5, CashExDividendDate: future},
+				{Symbol: "2330", Year: "無股利"},
+			}, nil
+		},
+	}
+	mockValidation := &mockValidationPort{
+		ValidateSymbolFunc: func(ctx context.Context, symbol string) (*entity.StockSymbol, error) {
+			return &entity.StockSymbol{Symbol: "2330", Name: "台積電"}, nil
+		},
+	}
+
+	uc := NewMarketDataUsecase(mockMarket, mockValidation, nil, &mockLogger{})
+
+	result, err := uc.GetDividendCalendar(context.Background(), "2330")
+	if err != nil {
+		t.Fatalf("不期望錯誤但發生錯誤: %v", err)
+	}
+
+	upcoming := make([]string, 0, len(result.Upcoming))
+	for _, dividend := range result.Upcoming {
+		upcoming = append(upcoming, dividend.Year)
+	}
+	if strings.Join(upcoming, ",") != "即將,未定" {
+		t.Errorf("即將除權息不符合期望，期望: 即將,未定, 實際: %s", strings.Join(upcoming, ","))
+	}
+
+	history := make([]string, 0, len(result.History))
+	for _, dividend := range result.History {
+		history = append(history, dividend.Year)
+	}
+	if strings.Join(history, ",") != "近,舊" {
+		t.Errorf("歷史股利不符合期望，期望: 近,舊, 實際: %s", strings.Join(history, ","))
+	}
+}
+
+func TestMarketDataUsecase_GetStockPrice_USStock(t *testing.T) {
+	prevDate := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	latestDate := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		prices      []dto.StockPrice
+		expectError bool
+	}{
+		{
+			name: "以最近兩個交易日計算漲跌",
+			prices: []dto.StockPrice{
+				{Symbol: "AAPL", Name: "Apple", Currency: "USD", Date: prevDate, ClosePrice: 200},
+				{Symbol: "AAPL", Name: "Apple", Currency: "USD", Date: latestDate, ClosePrice: 210},
+			},
+		},
+		{
+			name: "資料不足兩個交易日",
+			prices: []dto.StockPrice{
+				{Symbol: "AAPL", Name: "Apple", Currency: "USD", Date: latestDate, ClosePrice: 210},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestedDates []*time.Time
+			mockMarket := &mockMarketDataPort{
+				GetStockPriceFunc: func(ctx context.Context, symbol string, dates ...*time.Time) (*[]dto.StockPrice, error) {
+					requestedDates = dates
+					return &tt.prices, nil
+				},
+				GetLatestTradeDateByDateRangeFunc: func(ctx context.Context, startDate time.Time, endDate time.Time) ([]time.Time, error) {
+					t.Error("美股不應該查詢台股交易日")
+					return nil, nil
+				},
+			}
+			mockValidation := &mockValidationPort{
+				ValidateSymbolFunc: func(ctx context.Context, symbol string) (*entity.StockSymbol, error) {
+					return &entity.StockSymbol{Symbol: "AAPL", Name: "Apple", Market: "US"}, nil
+				},
+			}
+
+			uc := NewMarketDataUsecase(mockMarket, mockValidation, nil, &mockLogger{})
+
+			result, err := uc.GetStockPrice(context.Background(), "AAPL", &latestDate)
+			if len(requestedDates) != 2 {
+				t.Fatalf("期望以日期區間查詢，實際傳入 %d 個日期", len(requestedDates))
+			}
+			if tt.expectError {
+				if err == nil {
+					t.Error("期望錯誤但沒有發生錯誤")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("不期望錯誤但發生錯誤: %v", err)
+			}
+			if result.Currency != "USD" || result.ClosePrice != 210 || result.PrevClosePrice != 200 {
+				t.Errorf("股價不符合期望: %+v", result)
+			}
+			if result.ChangeAmount != 10 || result.ChangeRate != 5 || result.UpDownSign != "+" {
+				t.Errorf("漲跌不符合期望，漲跌 %.2f、漲跌幅 %.2f%%、方向 %s", result.ChangeAmount, result.ChangeRate, result.UpDownSign)
+			}
+			if !result.PrevTradeDate.Equal(prevDate) {
+				t.Errorf("前一交易日期望 %s，實際 %s", prevDate, result.PrevTradeDate)
+			}
+		})
+	}
+}
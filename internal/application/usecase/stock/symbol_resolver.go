@@ -0,0 +1,300 @@
+package stock
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+const (
+	// symbolIndexRefreshInterval 股票名稱索引重新載入的間隔，股票清單每日同步一次
+	symbolIndexRefreshInterval = time.Hour
+	// maxSymbolCandidates 無法唯一決定時最多列出的候選股票數量
+	maxSymbolCandidates = 10
+	// fuzzyMatchThreshold 名稱相似度門檻，只在沒有名稱包含輸入文字的股票時使用
+	fuzzyMatchThreshold = 0.6
+)
+
+// 符合程度分數，分數 >= scorePrefix 的候選股票只有一檔時視為唯一符合
+const (
+	scoreExactName   = 100
+	scorePrefix      = 80
+	scoreSymbolStart = 70
+	scoreContains    = 60
+	scoreFuzzy       = 50
+)
+
+// symbolAliases 台股常用英文名稱及縮寫，股票清單只有中文名稱，以此對應英文輸入
+var symbolAliases = map[string]string{
+	"tsmc":             "2330",
+	"foxconn":          "2317",
+	"honhai":           "2317",
+	"mediatek":         "2454",
+	"mtk":              "2454",
+	"umc":              "2303",
+	"deltaelectronics": "2308",
+	"asus":             "2357",
+	"acer":             "2353",
+	"quanta":           "2382",
+	"largan":           "3008",
+	"realtek":          "2379",
+	"novatek":          "3034",
+	"ase":              "3711",
+	"pegatron":         "4938",
+	"wistron":          "3231",
+	"compal":           "2324",
+	"inventec":         "2356",
+	"liteon":           "2301",
+	"yageo":            "2327",
+	"chunghwatelecom":  "2412",
+	"cht":              "2412",
+	"taiwanmobile":     "3045",
+	"fareastone":       "4904",
+	"formosaplastics":  "1301",
+	"nanyaplastics":    "1303",
+	"chinasteel":       "2002",
+	"unipresident":     "1216",
+	"evergreen":        "2603",
+	"yangming":         "2609",
+	"wanhai":           "2615",
+	"cathay":           "2882",
+	"fubon":            "2881",
+	"ctbc":             "2891",
+	"megaholdings":     "2886",
+}
+
+// SymbolResolver 將使用者輸入的股票代號、中文或英文名稱解析為股票代號
+type SymbolResolver interface {
+	Resolve(ctx context.Context, input string) (*dto.SymbolResolution, error)
+}
+
+type symbolResolver struct {
+	stockSymbolRepo port.StockSymbolReader
+	logger          logger.Logger
+
+	mu       sync.RWMutex
+	index    *symbolIndex
+	loadedAt time.Time
+}
+
+var _ SymbolResolver = (*symbolResolver)(nil)
+
+// NewSymbolResolver 建立股票代號解析器，名稱索引於第一次使用時載入並定期更新
+func NewSymbolResolver(stockSymbolRepo port.StockSymbolReader, log logger.Logger) SymbolResolver {
+	return &symbolResolver{stockSymbolRepo: stockSymbolRepo, logger: log}
+}
+
+// Resolve 依序以股票代號、英文別名、名稱完全相符、名稱開頭、名稱包含及名稱相似度比對；
+// 只有一檔明顯最符合時回傳該代號，否則回傳候選清單，查無符合時兩者皆為空
+func (r *symbolResolver) Resolve(ctx context.Context, input string) (*dto.SymbolResolution, error) {
+	index, err := r.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return index.resolve(input), nil
+}
+
+// getIndex 取得名稱索引，超過更新間隔時重新載入；重新載入失敗時沿用舊索引
+func (r *symbolResolver) getIndex(ctx context.Context) (*symbolIndex, error) {
+	r.mu.RLock()
+	index, loadedAt := r.index, r.loadedAt
+	r.mu.RUnlock()
+	if index != nil && time.Since(loadedAt) < symbolIndexRefreshInterval {
+		return index, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index != nil && time.Since(r.loadedAt) < symbolIndexRefreshInterval {
+		return r.index, nil
+	}
+
+	symbols, err := r.stockSymbolRepo.GetAll(ctx)
+	if err != nil {
+		if r.index != nil {
+			r.logger.Warn("重新載入股票名稱索引失敗，沿用舊索引", logger.Error(err))
+			return r.index, nil
+		}
+		return nil, err
+	}
+
+	r.index = newSymbolIndex(symbols, symbolAliases)
+	r.loadedAt = time.Now()
+	r.logger.Info("股票名稱索引已載入", logger.Int("count", len(symbols)))
+	return r.index, nil
+}
+
+// symbolIndex 股票名稱的記憶體索引
+type symbolIndex struct {
+	entries  []symbolIndexEntry
+	bySymbol map[string]*entity.StockSymbol
+	aliases  map[string]string
+}
+
+type symbolIndexEntry struct {
+	stock  *entity.StockSymbol
+	symbol string
+	name   string
+	runes  map[rune]struct{}
+}
+
+type scoredSymbol struct {
+	stock *entity.StockSymbol
+	score int
+}
+
+func newSymbolIndex(symbols []*entity.StockSymbol, aliases map[string]string) *symbolIndex {
+	index := &symbolIndex{
+		entries:  make([]symbolIndexEntry, 0, len(symbols)),
+		bySymbol: make(map[string]*entity.StockSymbol, len(symbols)),
+		aliases:  aliases,
+	}
+	for _, stock := range symbols {
+		symbol := normalizeSymbolQuery(stock.Symbol)
+		if symbol == "" {
+			continue
+		}
+		if _, exists := index.bySymbol[symbol]; exists {
+			continue
+		}
+		name := normalizeSymbolQuery(stock.Name)
+		index.bySymbol[symbol] = stock
+		index.entries = append(index.entries, symbolIndexEntry{
+			stock:  stock,
+			symbol: symbol,
+			name:   name,
+			runes:  runeSet(name),
+		})
+	}
+	return index
+}
+
+func (idx *symbolIndex) resolve(input string) *dto.SymbolResolution {
+	query := normalizeSymbolQuery(input)
+	if query == "" {
+		return &dto.SymbolResolution{}
+	}
+
+	if stock, ok := idx.bySymbol[query]; ok {
+		return &dto.SymbolResolution{Symbol: stock.Symbol}
+	}
+	if symbol, ok := idx.aliases[query]; ok {
+		if stock, ok := idx.bySymbol[symbol]; ok {
+			return &dto.SymbolResolution{Symbol: stock.Symbol}
+		}
+	}
+
+	matches := idx.match(query)
+	if len(matches) == 0 {
+		return &dto.SymbolResolution{}
+	}
+
+	// 只有一檔候選，或只有一檔名稱完全相符或開頭相符時視為唯一符合
+	if len(matches) == 1 || (matches[0].score >= scorePrefix && matches[1].score < scorePrefix) {
+		return &dto.SymbolResolution{Symbol: matches[0].stock.Symbol}
+	}
+
+	if len(matches) > maxSymbolCandidates {
+		matches = matches[:maxSymbolCandidates]
+	}
+	candidates := make([]dto.SymbolCandidate, len(matches))
+	for i, match := range matches {
+		candidates[i] = dto.SymbolCandidate{
+			Symbol: match.stock.Symbol,
+			Name:   match.stock.Name,
+			Market: match.stock.Market,
+		}
+	}
+	return &dto.SymbolResolution{Candidates: candidates}
+}
+
+// match 依符合程度排序候選股票，沒有名稱包含輸入文字的股票時才以相似度比對
+func (idx *symbolIndex) match(query string) []scoredSymbol {
+	matches := make([]scoredSymbol, 0)
+	for _, entry := range idx.entries {
+		if score := entry.score(query); score > 0 {
+			matches = append(matches, scoredSymbol{stock: entry.stock, score: score})
+		}
+	}
+
+	if len(matches) == 0 && len([]rune(query)) >= 2 {
+		queryRunes := runeSet(query)
+		for _, entry := range idx.entries {
+			if diceCoefficient(queryRunes, entry.runes) >= fuzzyMatchThreshold {
+				matches = append(matches, scoredSymbol{stock: entry.stock, score: scoreFuzzy})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if len(matches[i].stock.Symbol) != len(matches[j].stock.Symbol) {
+			return len(matches[i].stock.Symbol) < len(matches[j].stock.Symbol)
+		}
+		return matches[i].stock.Symbol < matches[j].stock.Symbol
+	})
+	return matches
+}
+
+func (e *symbolIndexEntry) score(query string) int {
+	switch {
+	case e.name == query:
+		return scoreExactName
+	case strings.HasPrefix(e.name, query):
+		return scorePrefix
+	case strings.HasPrefix(e.symbol, query):
+		return scoreSymbolStart
+	case strings.Contains(e.name, query):
+		return scoreContains
+	default:
+		return 0
+	}
+}
+
+// normalizeSymbolQuery 統一大小寫與全形字元，並移除空白及常見標點，讓「TSMC」「ｔｓｍｃ」「Hon Hai」都能比對
+func normalizeSymbolQuery(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		// 全形英數字轉半形
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		if unicode.IsSpace(r) || r == '-' || r == '.' || r == ',' || r == '*' {
+			continue
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+func runeSet(value string) map[rune]struct{} {
+	set := make(map[rune]struct{}, len(value))
+	for _, r := range value {
+		set[r] = struct{}{}
+	}
+	return set
+}
+
+// diceCoefficient 以字元集合計算相似度，介於 0 到 1
+func diceCoefficient(a, b map[rune]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for r := range a {
+		if _, ok := b[r]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
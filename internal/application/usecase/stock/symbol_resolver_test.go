@@ -0,0 +1,134 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+// mockStockSymbolReader 用於測試的 StockSymbolReader mock，只實作 GetAll
+type mockStockSymbolReader struct {
+	symbols   []*entity.StockSymbol
+	err       error
+	callCount int
+}
+
+func (m *mockStockSymbolReader) GetByID(ctx context.Context, id uint) (*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetBySymbol(ctx context.Context, symbol string) (*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetBySubscriptionID(ctx context.Context, subscriptionID uint) ([]*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetBySymbolID(ctx context.Context, symbolID uint) ([]*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetBySubscriptionAndSymbol(ctx context.Context, subscriptionID, symbolID uint) (*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetMarketStats(ctx context.Context) (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetAll(ctx context.Context) ([]*entity.StockSymbol, error) {
+	m.callCount++
+	return m.symbols, m.err
+}
+
+func (m *mockStockSymbolReader) SearchSymbols(ctx context.Context, keyword string, limit int) ([]*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolReader) GetTrackedSymbols(ctx context.Context) ([]*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func testStockSymbols() []*entity.StockSymbol {
+	return []*entity.StockSymbol{
+		{Symbol: "2330", Name: "台積電", Market: "TWSE"},
+		{Symbol: "2317", Name: "鴻海", Market: "TWSE"},
+		{Symbol: "2308", Name: "台達電", Market: "TWSE"},
+		{Symbol: "1301", Name: "台塑", Market: "TWSE"},
+		{Symbol: "6505", Name: "台塑化", Market: "TWSE"},
+		{Symbol: "1326", Name: "台化", Market: "TWSE"},
+		{Symbol: "AAPL", Name: "Apple Inc", Market: "US"},
+		{Symbol: "NVDA", Name: "NVIDIA Corporation", Market: "US"},
+	}
+}
+
+func TestSymbolResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantSymbol     string
+		wantCandidates []string
+	}{
+		{name: "股票代號完全相符", input: "2330", wantSymbol: "2330"},
+		{name: "美股代號不分大小寫", input: "aapl", wantSymbol: "AAPL"},
+		{name: "英文別名", input: "TSMC", wantSymbol: "2330"},
+		{name: "全形英文別名", input: "ｔｓｍｃ", wantSymbol: "2330"},
+		{name: "中文名稱完全相符", input: "鴻海", wantSymbol: "2317"},
+		{name: "中文名稱開頭唯一相符", input: "台積", wantSymbol: "2330"},
+		{name: "英文名稱開頭唯一相符", input: "nvidia", wantSymbol: "NVDA"},
+		{name: "名稱完全相符但有其他名稱開頭相符時列出候選", input: "台塑", wantCandidates: []string{"1301", "6505"}},
+		{name: "名稱包含多檔時列出候選", input: "電", wantCandidates: []string{"2308", "2330"}},
+		{name: "名稱相似時列出候選", input: "台基電", wantCandidates: []string{"2308", "2330"}},
+		{name: "查無符合", input: "不存在", wantSymbol: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewSymbolResolver(&mockStockSymbolReader{symbols: testStockSymbols()}, &mockLogger{})
+
+			result, err := resolver.Resolve(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if result.Symbol != tt.wantSymbol {
+				t.Errorf("Symbol 期望 %q，實際 %q", tt.wantSymbol, result.Symbol)
+			}
+
+			got := make([]string, len(result.Candidates))
+			for i, candidate := range result.Candidates {
+				got[i] = candidate.Symbol
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantCandidates) && !(len(got) == 0 && len(tt.wantCandidates) == 0) {
+				t.Errorf("候選股票期望 %v，實際 %v", tt.wantCandidates, got)
+			}
+		})
+	}
+}
+
+func TestSymbolResolver_IndexLoading(t *testing.T) {
+	t.Run("索引只載入一次", func(t *testing.T) {
+		reader := &mockStockSymbolReader{symbols: testStockSymbols()}
+		resolver := NewSymbolResolver(reader, &mockLogger{})
+
+		for _, input := range []string{"2330", "台積", "tsmc"} {
+			if _, err := resolver.Resolve(context.Background(), input); err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+		}
+		if reader.callCount != 1 {
+			t.Errorf("期望只載入 1 次索引，實際 %d 次", reader.callCount)
+		}
+	})
+
+	t.Run("載入索引失敗時回傳錯誤", func(t *testing.T) {
+		reader := &mockStockSymbolReader{err: fmt.Errorf("資料庫錯誤")}
+		resolver := NewSymbolResolver(reader, &mockLogger{})
+
+		if _, err := resolver.Resolve(context.Background(), "2330"); err == nil {
+			t.Error("期望錯誤但沒有發生錯誤")
+		}
+	})
+}
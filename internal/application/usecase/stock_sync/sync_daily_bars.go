@@ -0,0 +1,134 @@
+package stock_sync
+
+import (
+	"context"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// dailyBarHistoryYears 首次同步時回補的日K年數，涵蓋績效查詢的五年區間
+const dailyBarHistoryYears = 5
+
+type DailyBarSyncUsecase interface {
+	// SyncDailyBars 回補及增量更新有使用者訂閱或觀察的股票日K
+	SyncDailyBars(ctx context.Context) error
+}
+
+type dailyBarSyncUsecase struct {
+	stockSymbolReader port.StockSymbolReader
+	dailyBarRepo      port.DailyBarRepository
+	dailyBarSource    port.DailyBarSource
+	tradeDateReader   port.TradeDateReader
+	logger            logger.Logger
+}
+
+func NewDailyBarSyncUsecase(
+	stockSymbolReader port.StockSymbolReader,
+	dailyBarRepo port.DailyBarRepository,
+	dailyBarSource port.DailyBarSource,
+	tradeDateReader port.TradeDateReader,
+	log logger.Logger,
+) DailyBarSyncUsecase {
+	return &dailyBarSyncUsecase{
+		stockSymbolReader: stockSymbolReader,
+		dailyBarRepo:      dailyBarRepo,
+		dailyBarSource:    dailyBarSource,
+		tradeDateReader:   tradeDateReader,
+		logger:            log,
+	}
+}
+
+func (s *dailyBarSyncUsecase) SyncDailyBars(ctx context.Context) error {
+	s.logger.Info("開始同步日K資料...")
+
+	symbols, err := s.stockSymbolReader.GetTrackedSymbols(ctx)
+	if err != nil {
+		s.logger.Error("取得追蹤股票失敗", logger.Error(err))
+		return err
+	}
+
+	now := time.Now()
+	tradeDates, err := s.tradeDateReader.GetByDateRange(ctx, now.AddDate(0, 0, -30), now)
+	if err != nil {
+		s.logger.Warn("取得交易日失敗，台股將略過已有資料的判斷", logger.Error(err))
+	}
+
+	var updated, skipped, failed int
+	for _, stock := range symbols {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		count, err := s.syncSymbol(ctx, stock, tradeDates, now)
+		if err != nil {
+			failed++
+			s.logger.Warn("同步日K失敗", logger.String("symbol", stock.Symbol), logger.Error(err))
+			continue
+		}
+		if count == 0 {
+			skipped++
+			continue
+		}
+		updated++
+	}
+
+	s.logger.Info("日K資料同步完成",
+		logger.Int("更新", updated),
+		logger.Int("略過", skipped),
+		logger.Int("失敗", failed),
+		logger.Int("總計", len(symbols)))
+	return nil
+}
+
+// syncSymbol 沒有資料時回補近五年日K，已有資料時從最新一筆的隔天開始更新；已是最新時回傳 0
+func (s *dailyBarSyncUsecase) syncSymbol(ctx context.Context, stock *entity.StockSymbol, tradeDates []*entity.TradeDate, now time.Time) (int, error) {
+	latest, err := s.dailyBarRepo.GetLatestDate(ctx, stock.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	startDate := now.AddDate(-dailyBarHistoryYears, 0, 0)
+	if latest != nil {
+		expected, ok := entity.LatestDailyBarDate(stock, tradeDates, now, now)
+		if ok && latest.Format("2006-01-02") >= expected.Format("2006-01-02") {
+			return 0, nil
+		}
+		startDate = time.Date(latest.Year(), latest.Month(), latest.Day()+1, 0, 0, 0, 0, now.Location())
+	}
+	if startDate.After(now) {
+		return 0, nil
+	}
+
+	prices, err := s.dailyBarSource.GetDailyBars(ctx, stock, startDate, now)
+	if err != nil {
+		return 0, err
+	}
+
+	bars := make([]*entity.DailyBar, len(prices))
+	for i, price := range prices {
+		bars[i] = toDailyBar(stock.ID, price)
+	}
+	if err := s.dailyBarRepo.BatchUpsert(ctx, bars); err != nil {
+		return 0, err
+	}
+	return len(bars), nil
+}
+
+func toDailyBar(symbolID uint, price dto.StockPrice) *entity.DailyBar {
+	return &entity.DailyBar{
+		SymbolID:     symbolID,
+		Date:         price.Date,
+		Open:         price.OpenPrice,
+		High:         price.HighPrice,
+		Low:          price.LowPrice,
+		Close:        price.ClosePrice,
+		Volume:       price.Volume,
+		Amount:       price.Amount,
+		Transactions: price.Transactions,
+		Currency:     price.Currency,
+	}
+}
@@ -0,0 +1,157 @@
+package stock_sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+type mockDailyBarRepo struct {
+	latestDates map[uint]*time.Time
+	upserted    []*entity.DailyBar
+}
+
+func (m *mockDailyBarRepo) GetByDateRange(ctx context.Context, symbolID uint, startDate, endDate time.Time) ([]*entity.DailyBar, error) {
+	return nil, nil
+}
+
+func (m *mockDailyBarRepo) GetLatestDate(ctx context.Context, symbolID uint) (*time.Time, error) {
+	return m.latestDates[symbolID], nil
+}
+
+func (m *mockDailyBarRepo) BatchUpsert(ctx context.Context, bars []*entity.DailyBar) error {
+	m.upserted = append(m.upserted, bars...)
+	return nil
+}
+
+type dailyBarRequest struct {
+	symbol    string
+	startDate time.Time
+	endDate   time.Time
+}
+
+type mockDailyBarSource struct {
+	getDailyBarsFunc func(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error)
+	requests         []dailyBarRequest
+}
+
+func (m *mockDailyBarSource) GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	m.requests = append(m.requests, dailyBarRequest{symbol: stock.Symbol, startDate: startDate, endDate: endDate})
+	if m.getDailyBarsFunc != nil {
+		return m.getDailyBarsFunc(ctx, stock, startDate, endDate)
+	}
+	return []dto.StockPrice{{Symbol: stock.Symbol, Date: endDate, ClosePrice: 100, Currency: "TWD"}}, nil
+}
+
+func trackedSymbols(symbols ...*entity.StockSymbol) *mockStockSymbolRepo {
+	return &mockStockSymbolRepo{
+		getTrackedSymbolsFunc: func(ctx context.Context) ([]*entity.StockSymbol, error) {
+			return symbols, nil
+		},
+	}
+}
+
+func TestDailyBarSyncUsecase_SyncDailyBars_Backfill(t *testing.T) {
+	barRepo := &mockDailyBarRepo{}
+	source := &mockDailyBarSource{}
+	usecase := NewDailyBarSyncUsecase(trackedSymbols(&entity.StockSymbol{ID: 1, Symbol: "2330", Market: "TWSE"}), barRepo, source, &mockTradeDateRepository{}, &mockLogger{})
+
+	if err := usecase.SyncDailyBars(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(source.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(source.requests))
+	}
+	wantStart := time.Now().AddDate(-dailyBarHistoryYears, 0, 0).Format("2006-01-02")
+	if got := source.requests[0].startDate.Format("2006-01-02"); got != wantStart {
+		t.Errorf("Expected backfill from %s, got %s", wantStart, got)
+	}
+	if len(barRepo.upserted) != 1 || barRepo.upserted[0].SymbolID != 1 || barRepo.upserted[0].Close != 100 {
+		t.Errorf("Expected bar saved for symbol 1, got %+v", barRepo.upserted)
+	}
+}
+
+func TestDailyBarSyncUsecase_SyncDailyBars_Incremental(t *testing.T) {
+	latest := time.Now().AddDate(0, 0, -10)
+	barRepo := &mockDailyBarRepo{latestDates: map[uint]*time.Time{1: &latest}}
+	source := &mockDailyBarSource{}
+	tradeDateRepo := &mockTradeDateRepository{
+		getByDateRangeFunc: func(ctx context.Context, startDate, endDate time.Time) ([]*entity.TradeDate, error) {
+			return []*entity.TradeDate{{Date: latest}, {Date: time.Now().AddDate(0, 0, -2)}}, nil
+		},
+	}
+	usecase := NewDailyBarSyncUsecase(trackedSymbols(&entity.StockSymbol{ID: 1, Symbol: "2330", Market: "TWSE"}), barRepo, source, tradeDateRepo, &mockLogger{})
+
+	if err := usecase.SyncDailyBars(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(source.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(source.requests))
+	}
+	wantStart := latest.AddDate(0, 0, 1).Format("2006-01-02")
+	if got := source.requests[0].startDate.Format("2006-01-02"); got != wantStart {
+		t.Errorf("Expected update from %s, got %s", wantStart, got)
+	}
+}
+
+func TestDailyBarSyncUsecase_SyncDailyBars_SkipUpToDate(t *testing.T) {
+	today := time.Now()
+	barRepo := &mockDailyBarRepo{latestDates: map[uint]*time.Time{1: &today}}
+	source := &mockDailyBarSource{}
+	usecase := NewDailyBarSyncUsecase(trackedSymbols(&entity.StockSymbol{ID: 1, Symbol: "AAPL", Market: "US"}), barRepo, source, &mockTradeDateRepository{}, &mockLogger{})
+
+	if err := usecase.SyncDailyBars(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(source.requests) != 0 {
+		t.Errorf("Expected no request for up-to-date symbol, got %d", len(source.requests))
+	}
+}
+
+func TestDailyBarSyncUsecase_SyncDailyBars_ContinueOnSourceError(t *testing.T) {
+	barRepo := &mockDailyBarRepo{}
+	source := &mockDailyBarSource{
+		getDailyBarsFunc: func(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+			if stock.Symbol == "2330" {
+				return nil, errors.New("quota exceeded")
+			}
+			return []dto.StockPrice{{Symbol: stock.Symbol, Date: endDate, ClosePrice: 50}}, nil
+		},
+	}
+	symbols := trackedSymbols(
+		&entity.StockSymbol{ID: 1, Symbol: "2330", Market: "TWSE"},
+		&entity.StockSymbol{ID: 2, Symbol: "2317", Market: "TWSE"},
+	)
+	usecase := NewDailyBarSyncUsecase(symbols, barRepo, source, &mockTradeDateRepository{}, &mockLogger{})
+
+	if err := usecase.SyncDailyBars(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(source.requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(source.requests))
+	}
+	if len(barRepo.upserted) != 1 || barRepo.upserted[0].SymbolID != 2 {
+		t.Errorf("Expected only symbol 2 saved, got %+v", barRepo.upserted)
+	}
+}
+
+func TestDailyBarSyncUsecase_SyncDailyBars_TrackedSymbolsError(t *testing.T) {
+	symbolRepo := &mockStockSymbolRepo{
+		getTrackedSymbolsFunc: func(ctx context.Context) ([]*entity.StockSymbol, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	usecase := NewDailyBarSyncUsecase(symbolRepo, &mockDailyBarRepo{}, &mockDailyBarSource{}, &mockTradeDateRepository{}, &mockLogger{})
+
+	if err := usecase.SyncDailyBars(context.Background()); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -11,8 +11,9 @@ import (
 )
 
 type mockStockSymbolRepo struct {
-	batchUpsertFunc    func(ctx context.Context, symbols []*entity.StockSymbol) (int, int, error)
-	getMarketStatsFunc func(ctx context.Context) (map[string]int, error)
+	batchUpsertFunc       func(ctx context.Context, symbols []*entity.StockSymbol) (int, int, error)
+	getMarketStatsFunc    func(ctx context.Context) (map[string]int, error)
+	getTrackedSymbolsFunc func(ctx context.Context) ([]*entity.StockSymbol, error)
 }
 
 func (m *mockStockSymbolRepo) GetByID(ctx context.Context, id uint) (*entity.StockSymbol, error) {
@@ -35,6 +36,21 @@ func (m *mockStockSymbolRepo) GetBySubscriptionAndSymbol(ctx context.Context, su
 	return nil, nil
 }
 
+func (m *mockStockSymbolRepo) GetAll(ctx context.Context) ([]*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolRepo) SearchSymbols(ctx context.Context, keyword string, limit int) ([]*entity.StockSymbol, error) {
+	return nil, nil
+}
+
+func (m *mockStockSymbolRepo) GetTrackedSymbols(ctx context.Context) ([]*entity.StockSymbol, error) {
+	if m.getTrackedSymbolsFunc != nil {
+		return m.getTrackedSymbolsFunc(ctx)
+	}
+	return nil, nil
+}
+
 func (m *mockStockSymbolRepo) GetMarketStats(ctx context.Context) (map[string]int, error) {
 	if m.getMarketStatsFunc != nil {
 		return m.getMarketStatsFunc(ctx)
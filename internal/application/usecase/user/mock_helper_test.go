@@ -0,0 +1,164 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+)
+
+// mockValidationPort 以代號對照表驗證股票，不在表內時回傳查無資料
+type mockValidationPort struct {
+	symbols map[string]*entity.StockSymbol
+}
+
+func (m *mockValidationPort) ValidateSymbol(ctx context.Context, symbol string) (*entity.StockSymbol, error) {
+	if stockSymbol, ok := m.symbols[symbol]; ok {
+		return stockSymbol, nil
+	}
+	return nil, domainerror.ErrNotFound
+}
+
+// mockWatchlistPort 以記憶體保存觀察清單並記錄異動
+type mockWatchlistPort struct {
+	watchlists   []*entity.Watchlist
+	addedItems   []*entity.WatchlistItem
+	deletedItems [][2]uint
+	created      []*entity.Watchlist
+}
+
+func (m *mockWatchlistPort) GetWatchlists(ctx context.Context, userID uint) ([]*entity.Watchlist, error) {
+	return m.watchlists, nil
+}
+
+func (m *mockWatchlistPort) GetWatchlistByName(ctx context.Context, userID uint, name string) (*entity.Watchlist, error) {
+	return findWatchlist(m.watchlists, name), nil
+}
+
+func (m *mockWatchlistPort) CreateWatchlist(ctx context.Context, watchlist *entity.Watchlist) error {
+	watchlist.ID = uint(len(m.watchlists) + 1)
+	m.watchlists = append(m.watchlists, watchlist)
+	m.created = append(m.created, watchlist)
+	return nil
+}
+
+func (m *mockWatchlistPort) RenameWatchlist(ctx context.Context, watchlistID uint, name string) error {
+	return nil
+}
+
+func (m *mockWatchlistPort) DeleteWatchlist(ctx context.Context, watchlistID uint) error {
+	return nil
+}
+
+func (m *mockWatchlistPort) AddWatchlistItem(ctx context.Context, item *entity.WatchlistItem) error {
+	m.addedItems = append(m.addedItems, item)
+	return nil
+}
+
+func (m *mockWatchlistPort) DeleteWatchlistItem(ctx context.Context, watchlistID uint, symbolID uint) error {
+	m.deletedItems = append(m.deletedItems, [2]uint{watchlistID, symbolID})
+	return nil
+}
+
+func (m *mockWatchlistPort) MoveWatchlistItem(ctx context.Context, itemID uint, toWatchlistID uint, sortOrder int) error {
+	return nil
+}
+
+func (m *mockWatchlistPort) UpdateWatchlistItemOrders(ctx context.Context, items []*entity.WatchlistItem) error {
+	return nil
+}
+
+// mockPortfolioTradeRepo 以記憶體保存交易紀錄
+type mockPortfolioTradeRepo struct {
+	trades  []*entity.PortfolioTrade
+	created []*entity.PortfolioTrade
+}
+
+func (m *mockPortfolioTradeRepo) GetByID(ctx context.Context, id uint) (*entity.PortfolioTrade, error) {
+	for _, trade := range m.trades {
+		if trade.ID == id {
+			return trade, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPortfolioTradeRepo) GetByUserID(ctx context.Context, userID uint) ([]*entity.PortfolioTrade, error) {
+	return m.trades, nil
+}
+
+func (m *mockPortfolioTradeRepo) Create(ctx context.Context, trade *entity.PortfolioTrade) error {
+	trade.ID = uint(len(m.trades) + 1)
+	m.trades = append(m.trades, trade)
+	m.created = append(m.created, trade)
+	return nil
+}
+
+func (m *mockPortfolioTradeRepo) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+// mockTradingSettingRepo 固定回傳指定的券商設定，未設定時回傳 nil
+type mockTradingSettingRepo struct {
+	setting *entity.TradingSetting
+}
+
+func (m *mockTradingSettingRepo) GetByUserID(ctx context.Context, userID uint) (*entity.TradingSetting, error) {
+	return m.setting, nil
+}
+
+func (m *mockTradingSettingRepo) Upsert(ctx context.Context, setting *entity.TradingSetting) error {
+	m.setting = setting
+	return nil
+}
+
+// mockMarketDataPort 依股票代號回傳固定收盤價，其餘查詢回傳空結果
+type mockMarketDataPort struct {
+	closePrices map[string]float64
+}
+
+func (m *mockMarketDataPort) GetDailyMarketInfo(ctx context.Context, count int) (*[]dto.DailyMarketInfo, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetStockPerformance(ctx context.Context, symbol string) ([]dto.StockPerformanceData, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetTopVolumeStock(ctx context.Context) ([]*dto.TopVolume, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetStockPrice(ctx context.Context, symbol string, dates ...*time.Time) (*[]dto.StockPrice, error) {
+	closePrice, ok := m.closePrices[symbol]
+	if !ok {
+		return &[]dto.StockPrice{}, nil
+	}
+	return &[]dto.StockPrice{{Symbol: symbol, ClosePrice: closePrice, Date: time.Now()}}, nil
+}
+
+func (m *mockMarketDataPort) GetStockCompanyInfo(ctx context.Context, symbol string) (*dto.StockCompanyInfo, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetStockRevenue(ctx context.Context, symbol string) (*dto.StockRevenue, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetLatestTradeDate(ctx context.Context) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *mockMarketDataPort) GetLatestTradeDateByDateRange(ctx context.Context, startDate time.Time, endDate time.Time) ([]time.Time, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetStockNews(ctx context.Context, symbol string) ([]dto.StockNews, error) {
+	return nil, nil
+}
+
+func (m *mockMarketDataPort) GetStockDividends(ctx context.Context, symbol string, startDate time.Time, endDate time.Time) ([]dto.StockDividend, error) {
+	return nil, nil
+}
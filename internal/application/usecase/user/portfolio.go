@@ -0,0 +1,469 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// quoteLookbackDays 查詢持股報價時往前回溯的天數，涵蓋連假
+const quoteLookbackDays = 14
+
+// dividendLookbackYears 查詢股利資料時，在第一筆交易或近一年之前再往前回溯的年數，涵蓋公告日早於除權息日的情況
+const dividendLookbackYears = 1
+
+type UserPortfolioUsecase interface {
+	AddTrade(ctx context.Context, userID uint, stockSymbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) (string, error)
+	GetPortfolio(ctx context.Context, userID uint) (*dto.Portfolio, error)
+	GetTradeList(ctx context.Context, userID uint) ([]*dto.PortfolioTrade, error)
+	DeleteTrade(ctx context.Context, userID uint, tradeID uint) (string, error)
+	GetTradingSetting(ctx context.Context, userID uint) (*dto.TradingSetting, error)
+	UpdateTradingSetting(ctx context.Context, userID uint, feeDiscount float64, minFee *float64) (string, error)
+	GetDividendIncome(ctx context.Context, userID uint) (*dto.DividendIncome, error)
+}
+
+type userPortfolioUsecase struct {
+	portfolioTradeRepo port.PortfolioTradeRepository
+	tradingSettingRepo port.TradingSettingRepository
+	marketDataPort     port.MarketDataPort
+	validationPort     port.ValidationPort
+	costModel          *entity.TransactionCostModel
+}
+
+var _ UserPortfolioUsecase = (*userPortfolioUsecase)(nil)
+
+func NewUserPortfolioUsecase(
+	portfolioTradeRepo port.PortfolioTradeRepository,
+	tradingSettingRepo port.TradingSettingRepository,
+	marketDataPort port.MarketDataPort,
+	validationPort port.ValidationPort,
+	costModel *entity.TransactionCostModel,
+) UserPortfolioUsecase {
+	return &userPortfolioUsecase{
+		portfolioTradeRepo: portfolioTradeRepo,
+		tradingSettingRepo: tradingSettingRepo,
+		marketDataPort:     marketDataPort,
+		validationPort:     validationPort,
+		costModel:          costModel,
+	}
+}
+
+func (u *userPortfolioUsecase) AddTrade(ctx context.Context, userID uint, stockSymbol string, side valueobject.TradeSide, quantity int64, price float64, tradeDate time.Time) (string, error) {
+	if tradeDate.After(time.Now()) {
+		return "", fmt.Errorf("交易日期不可晚於今天")
+	}
+
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	trade := &entity.PortfolioTrade{
+		UserID:      userID,
+		SymbolID:    stockSymbolEntity.ID,
+		Side:        side,
+		Quantity:    quantity,
+		Price:       price,
+		TradeDate:   tradeDate,
+		StockSymbol: stockSymbolEntity,
+	}
+	if err := trade.Validate(); err != nil {
+		return "", fmt.Errorf("交易資料不正確，請確認後再試")
+	}
+
+	trades, err := u.portfolioTradeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	// 以新增後同一檔股票的完整紀錄（含配股）重播，確認任何時間點的賣出股數都不超過持股
+	holdings, err := u.buildHoldings(ctx, userID, append(filterTradesBySymbol(trades, trade.SymbolID), trade), time.Now())
+	if err != nil {
+		return "", userFacingHoldingError(err)
+	}
+
+	if err := u.portfolioTradeRepo.Create(ctx, trade); err != nil {
+		return "", err
+	}
+
+	var remaining int64
+	for _, holding := range holdings {
+		if holding.SymbolID == stockSymbolEntity.ID {
+			remaining = holding.Quantity
+		}
+	}
+
+	return fmt.Sprintf("已記錄%s：%s(%s) %d 股 @ %.2f（%s），目前持有 %d 股",
+		side.GetName(), stockSymbolEntity.Name, stockSymbolEntity.Symbol, quantity, price, tradeDate.Format("2006-01-02"), remaining), nil
+}
+
+func (u *userPortfolioUsecase) GetPortfolio(ctx context.Context, userID uint) (*dto.Portfolio, error) {
+	trades, err := u.portfolioTradeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	costModel, err := u.getCostModel(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	holdings, err := entity.BuildHoldings(trades, exDividendsUntil(u.getDividends(ctx, trades, now), now), costModel)
+	if err != nil {
+		return nil, userFacingHoldingError(err)
+	}
+
+	portfolio := &dto.Portfolio{Holdings: make([]*dto.PortfolioHolding, 0, len(holdings))}
+	for _, holding := range holdings {
+		portfolio.RealizedPnL += holding.RealizedPnL
+		portfolio.TotalFees += holding.TotalFees
+		portfolio.TotalTax += holding.TotalTax
+		portfolio.CashDividends += holding.CashDividends
+		if !holding.IsOpen() {
+			continue
+		}
+
+		item := &dto.PortfolioHolding{
+			Quantity:    holding.Quantity,
+			AverageCost: holding.AverageCost(),
+			TotalCost:   holding.TotalCost,
+			MarketValue: holding.TotalCost,
+		}
+		if holding.StockSymbol != nil {
+			item.Symbol = holding.StockSymbol.Symbol
+			item.Name = holding.StockSymbol.Name
+		}
+
+		if price := u.getLatestPrice(ctx, item.Symbol); price != nil {
+			item.ClosePrice = price.ClosePrice
+			item.PriceDate = price.Date
+			item.MarketValue = price.ClosePrice * float64(holding.Quantity)
+			item.EstimatedSellCost = holding.EstimateSellCost(price.ClosePrice, costModel).Total()
+			item.HasQuote = true
+			if price.Date.After(portfolio.PriceDate) {
+				portfolio.PriceDate = price.Date
+			}
+		}
+		// 未實現損益扣除以現價全部賣出時的預估手續費與證交稅
+		item.UnrealizedPnL = item.MarketValue - item.EstimatedSellCost - item.TotalCost
+		if item.TotalCost != 0 {
+			item.UnrealizedPnLRate = item.UnrealizedPnL / item.TotalCost * 100
+		}
+
+		portfolio.TotalCost += item.TotalCost
+		portfolio.MarketValue += item.MarketValue
+		portfolio.EstimatedSellCost += item.EstimatedSellCost
+		portfolio.Holdings = append(portfolio.Holdings, item)
+	}
+
+	portfolio.UnrealizedPnL = portfolio.MarketValue - portfolio.EstimatedSellCost - portfolio.TotalCost
+	if portfolio.TotalCost != 0 {
+		portfolio.UnrealizedPnLRate = portfolio.UnrealizedPnL / portfolio.TotalCost * 100
+	}
+	for _, item := range portfolio.Holdings {
+		if portfolio.MarketValue != 0 {
+			item.Weight = item.MarketValue / portfolio.MarketValue * 100
+		}
+	}
+
+	return portfolio, nil
+}
+
+func (u *userPortfolioUsecase) GetTradeList(ctx context.Context, userID uint) ([]*dto.PortfolioTrade, error) {
+	trades, err := u.portfolioTradeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PortfolioTrade, 0, len(trades))
+	for _, trade := range trades {
+		item := &dto.PortfolioTrade{
+			ID:        trade.ID,
+			Side:      trade.Side,
+			Quantity:  trade.Quantity,
+			Price:     trade.Price,
+			TradeDate: trade.TradeDate,
+		}
+		if trade.StockSymbol != nil {
+			item.Symbol = trade.StockSymbol.Symbol
+			item.Name = trade.StockSymbol.Name
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (u *userPortfolioUsecase) DeleteTrade(ctx context.Context, userID uint, tradeID uint) (string, error) {
+	trades, err := u.portfolioTradeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var target *entity.PortfolioTrade
+	remaining := make([]*entity.PortfolioTrade, 0, len(trades))
+	for _, trade := range trades {
+		if trade.ID == tradeID {
+			target = trade
+			continue
+		}
+		remaining = append(remaining, trade)
+	}
+	if target == nil {
+		return fmt.Sprintf("查無此交易編號:%d", tradeID), nil
+	}
+
+	// 刪除買進紀錄可能讓之後的賣出變成超賣
+	if _, err := u.buildHoldings(ctx, userID, filterTradesBySymbol(remaining, target.SymbolID), time.Now()); err != nil {
+		return "", fmt.Errorf("刪除後之後的賣出紀錄將超過持股，請先刪除相關賣出紀錄")
+	}
+
+	if err := u.portfolioTradeRepo.Delete(ctx, tradeID); err != nil {
+		return "", err
+	}
+	return "已刪除交易紀錄", nil
+}
+
+func (u *userPortfolioUsecase) GetTradingSetting(ctx context.Context, userID uint) (*dto.TradingSetting, error) {
+	costModel, err := u.getCostModel(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TradingSetting{
+		FeeRate:         costModel.FeeRate,
+		FeeDiscount:     costModel.FeeDiscount,
+		MinFee:          costModel.MinFee,
+		OddLotMinFee:    costModel.OddLotMinFee,
+		StockTaxRate:    costModel.StockTaxRate,
+		ETFTaxRate:      costModel.ETFTaxRate,
+		DayTradeTaxRate: costModel.DayTradeTaxRate,
+	}, nil
+}
+
+func (u *userPortfolioUsecase) UpdateTradingSetting(ctx context.Context, userID uint, feeDiscount float64, minFee *float64) (string, error) {
+	// 允許以「折」輸入，例如 6 代表六折、2.8 代表二八折
+	if feeDiscount > 1 && feeDiscount <= 10 {
+		feeDiscount = feeDiscount / 10
+	}
+
+	setting := &entity.TradingSetting{
+		UserID:      userID,
+		FeeDiscount: feeDiscount,
+		MinFee:      minFee,
+	}
+	if err := setting.Validate(); err != nil {
+		return "", fmt.Errorf("手續費折扣需介於 0 到 1 之間（例如 0.6 代表六折），最低手續費不可為負數")
+	}
+
+	if err := u.tradingSettingRepo.Upsert(ctx, setting); err != nil {
+		return "", err
+	}
+
+	costModel := u.costModel.WithSetting(setting)
+	return fmt.Sprintf("已更新手續費設定：折扣 %.4g，整股最低手續費 %.0f 元", costModel.FeeDiscount, costModel.MinFee), nil
+}
+
+func (u *userPortfolioUsecase) GetDividendIncome(ctx context.Context, userID uint) (*dto.DividendIncome, error) {
+	trades, err := u.portfolioTradeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	costModel, err := u.getCostModel(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dividends := u.getDividends(ctx, trades, now)
+	holdings, err := entity.BuildHoldings(trades, exDividendsUntil(dividends, now), costModel)
+	if err != nil {
+		return nil, userFacingHoldingError(err)
+	}
+
+	// 近一年每股現金股利，作為預估年度股利的依據
+	trailingStart := now.AddDate(-1, 0, 0)
+	trailingCash := make(map[string]float64)
+	for _, dividend := range dividends {
+		if dividend.ExDividendDate.After(trailingStart) && !dividend.ExDividendDate.After(now) {
+			trailingCash[dividend.Symbol] += dividend.CashDividend
+		}
+	}
+
+	income := &dto.DividendIncome{Year: now.Year(), Holdings: make([]*dto.DividendIncomeHolding, 0, len(holdings))}
+	for _, holding := range holdings {
+		item := &dto.DividendIncomeHolding{Quantity: holding.Quantity}
+		if holding.StockSymbol != nil {
+			item.Symbol = holding.StockSymbol.Symbol
+			item.Name = holding.StockSymbol.Name
+		}
+
+		for _, receipt := range holding.Dividends {
+			if receipt.Dividend.ExDividendDate.Year() != income.Year {
+				continue
+			}
+			item.YearToDateIncome += receipt.CashAmount
+			item.YearToDateStockShares += receipt.StockShares
+		}
+
+		item.TrailingCashDividend = trailingCash[item.Symbol]
+		item.ProjectedAnnualIncome = math.Floor(float64(holding.Quantity) * item.TrailingCashDividend)
+		if holding.IsOpen() && holding.TotalCost > 0 {
+			item.YieldOnCost = item.ProjectedAnnualIncome / holding.TotalCost * 100
+			income.TotalCost += holding.TotalCost
+		}
+
+		// 已出清且今年沒有股利的股票不列出
+		if !holding.IsOpen() && item.YearToDateIncome == 0 && item.YearToDateStockShares == 0 {
+			continue
+		}
+
+		income.YearToDateIncome += item.YearToDateIncome
+		income.ProjectedAnnualIncome += item.ProjectedAnnualIncome
+		income.Holdings = append(income.Holdings, item)
+	}
+	if income.TotalCost > 0 {
+		income.YieldOnCost = income.ProjectedAnnualIncome / income.TotalCost * 100
+	}
+
+	return income, nil
+}
+
+// buildHoldings 以使用者的成本模型及截至 asOf 已除權息的股利重播交易紀錄
+func (u *userPortfolioUsecase) buildHoldings(ctx context.Context, userID uint, trades []*entity.PortfolioTrade, asOf time.Time) ([]*entity.Holding, error) {
+	costModel, err := u.getCostModel(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return entity.BuildHoldings(trades, exDividendsUntil(u.getDividends(ctx, trades, asOf), asOf), costModel)
+}
+
+// getDividends 取得交易紀錄中每檔台股的股利資料，包含已公告但尚未除權息的股利。
+// 查詢失敗的股票略過，以免單一股票影響整體查詢
+func (u *userPortfolioUsecase) getDividends(ctx context.Context, trades []*entity.PortfolioTrade, now time.Time) []*entity.Dividend {
+	earliest := make(map[string]time.Time)
+	for _, trade := range trades {
+		if trade.StockSymbol == nil || !trade.StockSymbol.IsTaiwanStock() {
+			continue
+		}
+		symbol := trade.StockSymbol.Symbol
+		if date, ok := earliest[symbol]; !ok || trade.TradeDate.Before(date) {
+			earliest[symbol] = trade.TradeDate
+		}
+	}
+
+	dividends := make([]*entity.Dividend, 0)
+	for symbol, firstTradeDate := range earliest {
+		start := now.AddDate(-1, 0, 0)
+		if firstTradeDate.Before(start) {
+			start = firstTradeDate
+		}
+		start = start.AddDate(-dividendLookbackYears, 0, 0)
+
+		items, err := u.marketDataPort.GetStockDividends(ctx, symbol, start, now)
+		if err != nil {
+			continue
+		}
+		dividends = append(dividends, toDividendEntities(symbol, items)...)
+	}
+	return dividends
+}
+
+// toDividendEntities 將股利資料轉為除權息事件，現金與股票股利除權息日不同時拆成兩筆
+func toDividendEntities(symbol string, items []dto.StockDividend) []*entity.Dividend {
+	result := make([]*entity.Dividend, 0, len(items))
+	for _, item := range items {
+		cash := &entity.Dividend{
+			Symbol:         symbol,
+			Year:           item.Year,
+			ExDividendDate: item.CashExDividendDate,
+			PaymentDate:    item.CashPaymentDate,
+			CashDividend:   item.CashDividend,
+		}
+		stock := &entity.Dividend{
+			Symbol:         symbol,
+			Year:           item.Year,
+			ExDividendDate: item.StockExDividendDate,
+			StockDividend:  item.StockDividend,
+		}
+
+		// 除權息同一天時合併為一筆
+		if !cash.ExDividendDate.IsZero() && cash.ExDividendDate.Equal(stock.ExDividendDate) {
+			cash.StockDividend = stock.StockDividend
+			result = append(result, cash)
+			continue
+		}
+		for _, dividend := range []*entity.Dividend{cash, stock} {
+			if !dividend.ExDividendDate.IsZero() && !dividend.IsEmpty() {
+				result = append(result, dividend)
+			}
+		}
+	}
+	return result
+}
+
+// exDividendsUntil 篩選除權息日在 asOf 當天或之前的股利
+func exDividendsUntil(dividends []*entity.Dividend, asOf time.Time) []*entity.Dividend {
+	result := make([]*entity.Dividend, 0, len(dividends))
+	for _, dividend := range dividends {
+		if !dividend.ExDividendDate.After(asOf) {
+			result = append(result, dividend)
+		}
+	}
+	return result
+}
+
+// filterTradesBySymbol 篩選同一檔股票的交易紀錄
+func filterTradesBySymbol(trades []*entity.PortfolioTrade, symbolID uint) []*entity.PortfolioTrade {
+	result := make([]*entity.PortfolioTrade, 0, len(trades))
+	for _, trade := range trades {
+		if trade.SymbolID == symbolID {
+			result = append(result, trade)
+		}
+	}
+	return result
+}
+
+// getCostModel 取得套用使用者券商設定後的交易成本模型
+func (u *userPortfolioUsecase) getCostModel(ctx context.Context, userID uint) (*entity.TransactionCostModel, error) {
+	setting, err := u.tradingSettingRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.costModel.WithSetting(setting), nil
+}
+
+// getLatestPrice 取得最近一個交易日的收盤價，查無資料時回傳 nil
+func (u *userPortfolioUsecase) getLatestPrice(ctx context.Context, symbol string) *dto.StockPrice {
+	end := time.Now()
+	start := end.AddDate(0, 0, -quoteLookbackDays)
+	prices, err := u.marketDataPort.GetStockPrice(ctx, symbol, &start, &end)
+	if err != nil || prices == nil || len(*prices) == 0 {
+		return nil
+	}
+
+	latest := &(*prices)[0]
+	for i := range *prices {
+		if (*prices)[i].Date.After(latest.Date) {
+			latest = &(*prices)[i]
+		}
+	}
+	return latest
+}
+
+func userFacingHoldingError(err error) error {
+	var domainErr *domainerror.DomainError
+	if errors.As(err, &domainErr) && domainErr.Code == "INSUFFICIENT_HOLDING" {
+		return domainErr
+	}
+	return fmt.Errorf("交易紀錄計算失敗，請確認後再試")
+}
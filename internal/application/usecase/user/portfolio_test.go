@@ -0,0 +1,115 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+func newTestPortfolioUsecase(trades []*entity.PortfolioTrade, closePrices map[string]float64, symbols ...*entity.StockSymbol) (UserPortfolioUsecase, *mockPortfolioTradeRepo) {
+	tradeRepo := &mockPortfolioTradeRepo{trades: trades}
+	validationPort := &mockValidationPort{symbols: make(map[string]*entity.StockSymbol)}
+	for _, symbol := range symbols {
+		validationPort.symbols[symbol.Symbol] = symbol
+	}
+	usecase := NewUserPortfolioUsecase(
+		tradeRepo,
+		&mockTradingSettingRepo{},
+		&mockMarketDataPort{closePrices: closePrices},
+		validationPort,
+		entity.NewTaiwanTransactionCostModel(),
+	)
+	return usecase, tradeRepo
+}
+
+func TestUserPortfolioUsecase_AddTrade_SellMoreThanHeld(t *testing.T) {
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	day := time.Now().AddDate(0, 0, -10)
+	trades := []*entity.PortfolioTrade{
+		{ID: 1, UserID: 1, SymbolID: 1, Side: valueobject.TradeSideBuy, Quantity: 1000, Price: 900, TradeDate: day, StockSymbol: tsmc},
+		{ID: 2, UserID: 1, SymbolID: 1, Side: valueobject.TradeSideSell, Quantity: 400, Price: 950, TradeDate: day.AddDate(0, 0, 1), StockSymbol: tsmc},
+	}
+
+	tests := []struct {
+		name     string
+		quantity int64
+		wantErr  bool
+	}{
+		{name: "賣出剩餘持股", quantity: 600},
+		{name: "賣出超過剩餘持股", quantity: 601, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase, tradeRepo := newTestPortfolioUsecase(append([]*entity.PortfolioTrade(nil), trades...), nil, tsmc)
+
+			_, err := usecase.AddTrade(context.Background(), 1, "2330", valueobject.TradeSideSell, tt.quantity, 1000, day.AddDate(0, 0, 2))
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("不應該發生錯誤: %v", err)
+				}
+				if len(tradeRepo.created) != 1 {
+					t.Errorf("期望新增 1 筆交易，實際 %d 筆", len(tradeRepo.created))
+				}
+				return
+			}
+
+			var domainErr *domainerror.DomainError
+			if !errors.As(err, &domainErr) || domainErr.Code != "INSUFFICIENT_HOLDING" {
+				t.Fatalf("期望持股不足錯誤，實際 %v", err)
+			}
+			if len(tradeRepo.created) != 0 {
+				t.Errorf("持股不足時不應寫入交易，實際新增 %d 筆", len(tradeRepo.created))
+			}
+		})
+	}
+}
+
+func TestUserPortfolioUsecase_GetPortfolio_AverageCostAcrossBuys(t *testing.T) {
+	us := &entity.StockSymbol{ID: 2, Symbol: "AAPL", Name: "Apple", Market: "US"}
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	day := time.Now().AddDate(0, 0, -30)
+	trades := []*entity.PortfolioTrade{
+		{ID: 1, UserID: 1, SymbolID: 1, Side: valueobject.TradeSideBuy, Quantity: 1000, Price: 100, TradeDate: day, StockSymbol: tsmc},
+		{ID: 2, UserID: 1, SymbolID: 1, Side: valueobject.TradeSideBuy, Quantity: 1000, Price: 110, TradeDate: day.AddDate(0, 0, 5), StockSymbol: tsmc},
+		// 賣出依平均成本沖銷，不影響剩餘持股的平均成本
+		{ID: 3, UserID: 1, SymbolID: 1, Side: valueobject.TradeSideSell, Quantity: 500, Price: 120, TradeDate: day.AddDate(0, 0, 10), StockSymbol: tsmc},
+		{ID: 4, UserID: 1, SymbolID: 2, Side: valueobject.TradeSideBuy, Quantity: 10, Price: 200, TradeDate: day, StockSymbol: us},
+	}
+	usecase, _ := newTestPortfolioUsecase(trades, map[string]float64{"2330": 120})
+
+	portfolio, err := usecase.GetPortfolio(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+	if len(portfolio.Holdings) != 2 {
+		t.Fatalf("持股期望 2 檔，實際 %d 檔", len(portfolio.Holdings))
+	}
+
+	// 兩次買進的手續費分別為 142 與 156 元（100,000 及 110,000 × 0.1425% 無條件捨去）
+	wantAverageCost := (100000 + 142 + 110000 + 156) / 2000.0
+	holding := portfolio.Holdings[0]
+	if holding.Symbol != "2330" || holding.Quantity != 1500 {
+		t.Fatalf("期望 2330 持有 1500 股，實際 %s %d 股", holding.Symbol, holding.Quantity)
+	}
+	if math.Abs(holding.AverageCost-wantAverageCost) > 1e-9 {
+		t.Errorf("平均成本期望 %v，實際 %v", wantAverageCost, holding.AverageCost)
+	}
+	if math.Abs(holding.TotalCost-wantAverageCost*1500) > 1e-6 {
+		t.Errorf("總成本期望 %v，實際 %v", wantAverageCost*1500, holding.TotalCost)
+	}
+	if !holding.HasQuote || holding.MarketValue != 180000 {
+		t.Errorf("市值期望 180000，實際 %v（有報價 %v）", holding.MarketValue, holding.HasQuote)
+	}
+
+	// 查無報價時以成本作為市值
+	if noQuote := portfolio.Holdings[1]; noQuote.HasQuote || noQuote.MarketValue != noQuote.TotalCost {
+		t.Errorf("查無報價時市值期望等於成本，實際 %+v", noQuote)
+	}
+}
@@ -0,0 +1,211 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// UserPreferenceUsecase 使用者推播偏好設定
+type UserPreferenceUsecase interface {
+	GetPreference(ctx context.Context, userID uint) (*dto.UserPreference, error)
+	SetQuietHours(ctx context.Context, userID uint, start, end string) (string, error)
+	DisableQuietHours(ctx context.Context, userID uint) (string, error)
+	SetTimezone(ctx context.Context, userID uint, timezone string) (string, error)
+	SetLanguage(ctx context.Context, userID uint, language string) (string, error)
+	SetDigest(ctx context.Context, userID uint, enabled bool) (string, error)
+	SetMaxDailyPushes(ctx context.Context, userID uint, maxDailyPushes int) (string, error)
+}
+
+type userPreferenceUsecase struct {
+	preferenceRepo       port.UserPreferenceRepository
+	userSubscriptionPort port.UserSubscriptionPort
+	defaultTimezone      string
+}
+
+var _ UserPreferenceUsecase = (*userPreferenceUsecase)(nil)
+
+// NewUserPreferenceUsecase 建立使用者偏好設定；defaultTimezone 為未設定時區時使用的時區
+func NewUserPreferenceUsecase(
+	preferenceRepo port.UserPreferenceRepository,
+	userSubscriptionPort port.UserSubscriptionPort,
+	defaultTimezone string,
+) UserPreferenceUsecase {
+	return &userPreferenceUsecase{
+		preferenceRepo:       preferenceRepo,
+		userSubscriptionPort: userSubscriptionPort,
+		defaultTimezone:      defaultTimezone,
+	}
+}
+
+func (u *userPreferenceUsecase) GetPreference(ctx context.Context, userID uint) (*dto.UserPreference, error) {
+	preference, err := u.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	digestEnabled, err := u.isDigestEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UserPreference{
+		QuietHoursStart: preference.QuietHoursStart,
+		QuietHoursEnd:   preference.QuietHoursEnd,
+		Timezone:        preference.Timezone,
+		Language:        preference.Language,
+		DigestEnabled:   digestEnabled,
+		MaxDailyPushes:  preference.MaxDailyPushes,
+	}, nil
+}
+
+func (u *userPreferenceUsecase) SetQuietHours(ctx context.Context, userID uint, start, end string) (string, error) {
+	normalizedStart, okStart := entity.NormalizeClock(start)
+	normalizedEnd, okEnd := entity.NormalizeClock(end)
+	if !okStart || !okEnd || normalizedStart == normalizedEnd {
+		return "", fmt.Errorf("勿擾時段格式錯誤，請輸入開始與結束時間（HH:MM），例如 22:00 07:00")
+	}
+
+	preference, err := u.load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	preference.QuietHoursStart = normalizedStart
+	preference.QuietHoursEnd = normalizedEnd
+	if err := u.save(ctx, preference); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("已設定勿擾時段：%s～%s（%s），時段內的推播將延後送出", normalizedStart, normalizedEnd, preference.Timezone), nil
+}
+
+func (u *userPreferenceUsecase) DisableQuietHours(ctx context.Context, userID uint) (string, error) {
+	preference, err := u.load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	preference.QuietHoursStart = ""
+	preference.QuietHoursEnd = ""
+	if err := u.save(ctx, preference); err != nil {
+		return "", err
+	}
+	return "已關閉勿擾時段", nil
+}
+
+func (u *userPreferenceUsecase) SetTimezone(ctx context.Context, userID uint, timezone string) (string, error) {
+	if _, err := time.LoadLocation(timezone); timezone == "" || err != nil {
+		return "", fmt.Errorf("無效的時區：%s，請輸入 IANA 時區名稱，例如 Asia/Taipei", timezone)
+	}
+
+	preference, err := u.load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	preference.Timezone = timezone
+	if err := u.save(ctx, preference); err != nil {
+		return "", err
+	}
+	return "已設定時區：" + timezone, nil
+}
+
+// SetLanguage 設定偏好語言，設定後的回覆即以新語言顯示
+func (u *userPreferenceUsecase) SetLanguage(ctx context.Context, userID uint, language string) (string, error) {
+	lang, err := valueobject.NewLanguage(language)
+	if err != nil {
+		return "", fmt.Errorf("不支援的語言：%s，可設定 %s 或 %s", language, valueobject.LanguageTraditionalChinese, valueobject.LanguageEnglish)
+	}
+
+	preference, err := u.load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	preference.Language = lang
+	if err := u.save(ctx, preference); err != nil {
+		return "", err
+	}
+	if lang == valueobject.LanguageEnglish {
+		return "Language set to " + lang.GetName(), nil
+	}
+	return "已設定偏好語言：" + lang.GetName(), nil
+}
+
+// SetDigest 開啟或關閉每日摘要，實際上為訂閱或取消訂閱每日摘要項目
+func (u *userPreferenceUsecase) SetDigest(ctx context.Context, userID uint, enabled bool) (string, error) {
+	digestEnabled, err := u.isDigestEnabled(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case enabled && digestEnabled:
+		return "每日摘要已開啟", nil
+	case !enabled && !digestEnabled:
+		return "每日摘要已關閉", nil
+	case enabled:
+		if err := u.userSubscriptionPort.AddUserSubscriptionItem(ctx, userID, valueobject.SubscriptionTypeDailyDigest); err != nil {
+			return "", err
+		}
+		return "已開啟每日摘要，股票資訊、新聞、大盤及成交量排行將合併為一則推播", nil
+	default:
+		if err := u.userSubscriptionPort.DeleteUserSubscriptionItem(ctx, userID, valueobject.SubscriptionTypeDailyDigest); err != nil {
+			return "", err
+		}
+		return "已關閉每日摘要，恢復各訂閱項目個別推播", nil
+	}
+}
+
+func (u *userPreferenceUsecase) SetMaxDailyPushes(ctx context.Context, userID uint, maxDailyPushes int) (string, error) {
+	if maxDailyPushes < 0 || maxDailyPushes > entity.MaxDailyPushesLimit {
+		return "", fmt.Errorf("每日推播上限需介於 0 到 %d 之間，0 代表不限制", entity.MaxDailyPushesLimit)
+	}
+
+	preference, err := u.load(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	preference.MaxDailyPushes = maxDailyPushes
+	if err := u.save(ctx, preference); err != nil {
+		return "", err
+	}
+	if maxDailyPushes == 0 {
+		return "已取消每日推播上限", nil
+	}
+	return fmt.Sprintf("已設定每日推播上限：%d 則，超過的推播當天不再送出", maxDailyPushes), nil
+}
+
+// load 取得使用者的偏好設定，未設定時回傳預設值
+func (u *userPreferenceUsecase) load(ctx context.Context, userID uint) (*entity.UserPreference, error) {
+	preference, err := u.preferenceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		return entity.NewDefaultUserPreference(userID, u.defaultTimezone), nil
+	}
+	return preference, nil
+}
+
+func (u *userPreferenceUsecase) save(ctx context.Context, preference *entity.UserPreference) error {
+	if err := preference.Validate(); err != nil {
+		return fmt.Errorf("偏好設定不正確，請確認後再試")
+	}
+	return u.preferenceRepo.Upsert(ctx, preference)
+}
+
+// isDigestEnabled 是否已訂閱每日摘要
+func (u *userPreferenceUsecase) isDigestEnabled(ctx context.Context, userID uint) (bool, error) {
+	items, err := u.userSubscriptionPort.GetUserSubscriptionItemList(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range items {
+		if item.Item == valueobject.SubscriptionTypeDailyDigest && item.Status {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,153 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+type UserPriceAlertUsecase interface {
+	AddPriceAlert(ctx context.Context, userID uint, stockSymbol string, operator valueobject.AlertOperator, targetPrice float64) (string, error)
+	AddPercentageAlert(ctx context.Context, userID uint, stockSymbol string, alertType valueobject.AlertType, thresholdPercent float64) (string, error)
+	GetPriceAlertList(ctx context.Context, userID uint) ([]*dto.PriceAlert, error)
+	DeletePriceAlert(ctx context.Context, userID uint, alertID uint) (string, error)
+}
+
+type userPriceAlertUsecase struct {
+	priceAlertRepo port.PriceAlertRepository
+	validationPort port.ValidationPort
+}
+
+var _ UserPriceAlertUsecase = (*userPriceAlertUsecase)(nil)
+
+func NewUserPriceAlertUsecase(
+	priceAlertRepo port.PriceAlertRepository,
+	validationPort port.ValidationPort,
+) UserPriceAlertUsecase {
+	return &userPriceAlertUsecase{
+		priceAlertRepo: priceAlertRepo,
+		validationPort: validationPort,
+	}
+}
+
+func (u *userPriceAlertUsecase) AddPriceAlert(ctx context.Context, userID uint, stockSymbol string, operator valueobject.AlertOperator, targetPrice float64) (string, error) {
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	alerts, err := u.priceAlertRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, alert := range alerts {
+		if alert.SymbolID == stockSymbolEntity.ID && alert.AlertType == valueobject.AlertTypePrice && alert.Operator == operator && alert.TargetPrice == targetPrice {
+			return "已經設定過此提醒", nil
+		}
+	}
+
+	alert := &entity.PriceAlert{
+		UserID:      userID,
+		SymbolID:    stockSymbolEntity.ID,
+		AlertType:   valueobject.AlertTypePrice,
+		Operator:    operator,
+		TargetPrice: targetPrice,
+		Active:      true,
+	}
+	if err := alert.Validate(); err != nil {
+		return "", fmt.Errorf("提醒設定不正確，請確認後再試")
+	}
+
+	if err := u.priceAlertRepo.Create(ctx, alert); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("已設定提醒：%s(%s) 收盤價%s %.2f", stockSymbolEntity.Name, stockSymbolEntity.Symbol, operator.GetName(), targetPrice), nil
+}
+
+func (u *userPriceAlertUsecase) AddPercentageAlert(ctx context.Context, userID uint, stockSymbol string, alertType valueobject.AlertType, thresholdPercent float64) (string, error) {
+	if !alertType.IsPercentage() {
+		return "", fmt.Errorf("提醒類型不正確，請確認後再試")
+	}
+
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	alerts, err := u.priceAlertRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, alert := range alerts {
+		if alert.SymbolID == stockSymbolEntity.ID && alert.AlertType == alertType && alert.ThresholdPercent == thresholdPercent {
+			return "已經設定過此提醒", nil
+		}
+	}
+
+	alert := &entity.PriceAlert{
+		UserID:           userID,
+		SymbolID:         stockSymbolEntity.ID,
+		AlertType:        alertType,
+		ThresholdPercent: thresholdPercent,
+		Active:           true,
+	}
+	if err := alert.Validate(); err != nil {
+		return "", fmt.Errorf("提醒設定不正確，請確認後再試")
+	}
+
+	if err := u.priceAlertRepo.Create(ctx, alert); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("已設定%s：%s(%s) 相對前一交易日收盤價變動超過 %.2f%%", alertType.GetName(), stockSymbolEntity.Name, stockSymbolEntity.Symbol, thresholdPercent), nil
+}
+
+func (u *userPriceAlertUsecase) GetPriceAlertList(ctx context.Context, userID uint) ([]*dto.PriceAlert, error) {
+	alerts, err := u.priceAlertRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PriceAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		item := &dto.PriceAlert{
+			ID:               alert.ID,
+			AlertType:        alert.AlertType,
+			Operator:         alert.Operator,
+			TargetPrice:      alert.TargetPrice,
+			ThresholdPercent: alert.ThresholdPercent,
+			Triggered:        alert.Triggered,
+			TriggeredAt:      alert.TriggeredAt,
+		}
+		if alert.StockSymbol != nil {
+			item.Symbol = alert.StockSymbol.Symbol
+			item.Name = alert.StockSymbol.Name
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (u *userPriceAlertUsecase) DeletePriceAlert(ctx context.Context, userID uint, alertID uint) (string, error) {
+	alert, err := u.priceAlertRepo.GetByID(ctx, alertID)
+	if err != nil {
+		return "", err
+	}
+
+	if alert == nil || alert.UserID != userID {
+		return fmt.Sprintf("查無此提醒編號:%d", alertID), nil
+	}
+
+	if err := u.priceAlertRepo.Delete(ctx, alertID); err != nil {
+		return "", err
+	}
+
+	return "已刪除提醒", nil
+}
@@ -2,12 +2,26 @@ package user
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	"github.com/tian841224/stock-bot/pkg/utils"
 )
 
+// MinScheduleInterval 自訂推播排程兩次觸發之間的最短間隔
+const MinScheduleInterval = time.Hour
+
+// scheduleResetKeywords 將推播排程恢復為預設值的關鍵字
+var scheduleResetKeywords = map[string]bool{"reset": true, "default": true, "預設": true}
+
+// scheduleMorningKeywords 設定為次一交易日早上推播的關鍵字
+var scheduleMorningKeywords = map[string]bool{"morning": true, "盤前": true}
+
 type UserSubscriptionUsecase interface {
 	GetUserSubscriptionItemList(ctx context.Context, userID uint) ([]*dto.UserSubscriptionItem, error)
 	GetUserSubscriptionStockList(ctx context.Context, userID uint) ([]*dto.UserSubscriptionStock, error)
@@ -16,6 +30,7 @@ type UserSubscriptionUsecase interface {
 	AddUserSubscriptionStock(ctx context.Context, userID uint, stockSymbol string) (string, error)
 	DeleteUserSubscriptionItem(ctx context.Context, userID uint, item valueobject.SubscriptionType) (string, error)
 	DeleteUserSubscriptionStock(ctx context.Context, userID uint, stockSymbol string) (string, error)
+	SetUserSubscriptionSchedule(ctx context.Context, userID uint, item valueobject.SubscriptionType, schedule string) (string, error)
 }
 
 type userSubscriptionUsecase struct {
@@ -155,3 +170,69 @@ func (u *userSubscriptionUsecase) DeleteUserSubscriptionStock(ctx context.Contex
 
 	return "已取消訂閱股票", nil
 }
+
+// SetUserSubscriptionSchedule 設定訂閱項目的推播時間，schedule 可為 HH:MM、五欄位 cron 表達式、morning 或 reset
+func (u *userSubscriptionUsecase) SetUserSubscriptionSchedule(ctx context.Context, userID uint, item valueobject.SubscriptionType, schedule string) (string, error) {
+	userSubscriptionItemList, err := u.userSubscriptionPort.GetUserSubscriptionItemList(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	found := false
+	for _, userSubscriptionItem := range userSubscriptionItemList {
+		if userSubscriptionItem.Item == item {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return "未訂閱此項目:" + item.GetName(), nil
+	}
+
+	scheduleCron, err := normalizeScheduleCron(schedule)
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.userSubscriptionPort.UpdateUserSubscriptionSchedule(ctx, userID, item, scheduleCron); err != nil {
+		return "", err
+	}
+
+	if scheduleCron == "" {
+		return "已將「" + item.GetName() + "」恢復為預設推播時間", nil
+	}
+	if scheduleCron == entity.ScheduleNextTradingDayMorning {
+		return "已將「" + item.GetName() + "」設定為次一交易日早上推播", nil
+	}
+	if clock, ok := utils.DailyCronClock(scheduleCron); ok {
+		return "已將「" + item.GetName() + "」推播時間設定為每日 " + clock, nil
+	}
+	return "已將「" + item.GetName() + "」推播排程設定為 " + scheduleCron, nil
+}
+
+// normalizeScheduleCron 將使用者輸入的排程轉換為 cron 表達式，並檢查觸發間隔
+func normalizeScheduleCron(schedule string) (string, error) {
+	schedule = strings.Join(strings.Fields(schedule), " ")
+	if scheduleResetKeywords[strings.ToLower(schedule)] {
+		return "", nil
+	}
+	if scheduleMorningKeywords[strings.ToLower(schedule)] {
+		return entity.ScheduleNextTradingDayMorning, nil
+	}
+
+	scheduleCron := schedule
+	if spec, ok := utils.DailyCronSpec(schedule); ok {
+		scheduleCron = spec
+	}
+
+	parsed, err := utils.ParseCronSpec(scheduleCron)
+	if err != nil || len(strings.Fields(scheduleCron)) != 5 {
+		return "", fmt.Errorf("排程格式錯誤，請輸入 HH:MM（例如 08:30）或五欄位 cron 表達式（例如 0 14 * * 1-5）")
+	}
+
+	if interval := utils.MinCronInterval(parsed, time.Now(), 24); interval > 0 && interval < MinScheduleInterval {
+		return "", fmt.Errorf("推播排程間隔不可少於 %d 分鐘", int(MinScheduleInterval.Minutes()))
+	}
+	return scheduleCron, nil
+}
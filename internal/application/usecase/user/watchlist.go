@@ -0,0 +1,320 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+// reservedWatchlistNames 為 /wl 子指令保留的名稱，不可作為清單名稱
+var reservedWatchlistNames = map[string]bool{
+	"new":    true,
+	"rename": true,
+	"del":    true,
+	"mv":     true,
+	"order":  true,
+}
+
+type UserWatchlistUsecase interface {
+	AddWatchlistStock(ctx context.Context, userID uint, listName string, stockSymbol string) (string, error)
+	DeleteWatchlistStock(ctx context.Context, userID uint, listName string, stockSymbol string) (string, error)
+	GetWatchlists(ctx context.Context, userID uint, listName string) ([]*entity.Watchlist, error)
+	CreateWatchlist(ctx context.Context, userID uint, name string) (string, error)
+	RenameWatchlist(ctx context.Context, userID uint, oldName string, newName string) (string, error)
+	DeleteWatchlist(ctx context.Context, userID uint, name string) (string, error)
+	MoveWatchlistStock(ctx context.Context, userID uint, stockSymbol string, fromName string, toName string) (string, error)
+	ReorderWatchlistStock(ctx context.Context, userID uint, listName string, stockSymbol string, position int) (string, error)
+}
+
+type userWatchlistUsecase struct {
+	watchlistPort  port.WatchlistPort
+	validationPort port.ValidationPort
+}
+
+var _ UserWatchlistUsecase = (*userWatchlistUsecase)(nil)
+
+func NewUserWatchlistUsecase(
+	watchlistPort port.WatchlistPort,
+	validationPort port.ValidationPort,
+) UserWatchlistUsecase {
+	return &userWatchlistUsecase{
+		watchlistPort:  watchlistPort,
+		validationPort: validationPort,
+	}
+}
+
+// AddWatchlistStock 加入股票，未指定清單時加入排序最前面的清單，指定的清單不存在時自動建立
+func (u *userWatchlistUsecase) AddWatchlistStock(ctx context.Context, userID uint, listName string, stockSymbol string) (string, error) {
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	watchlists, err := u.watchlistPort.GetWatchlists(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var watchlist *entity.Watchlist
+	if listName == "" {
+		if len(watchlists) > 0 {
+			watchlist = watchlists[0]
+		} else {
+			listName = entity.DefaultWatchlistName
+		}
+	} else {
+		watchlist = findWatchlist(watchlists, listName)
+	}
+
+	if watchlist == nil {
+		watchlist, err = u.createWatchlist(ctx, userID, listName, watchlists)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if watchlist.HasSymbol(stockSymbolEntity.ID) {
+		return fmt.Sprintf("已在觀察清單「%s」中", watchlist.Name), nil
+	}
+
+	item := &entity.WatchlistItem{
+		WatchlistID: watchlist.ID,
+		SymbolID:    stockSymbolEntity.ID,
+		SortOrder:   watchlist.NextSortOrder(),
+	}
+	if err := u.watchlistPort.AddWatchlistItem(ctx, item); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("已加入觀察清單「%s」：%s(%s)", watchlist.Name, stockSymbolEntity.Name, stockSymbolEntity.Symbol), nil
+}
+
+// DeleteWatchlistStock 移除股票，未指定清單時僅在股票只存在於單一清單時移除
+func (u *userWatchlistUsecase) DeleteWatchlistStock(ctx context.Context, userID uint, listName string, stockSymbol string) (string, error) {
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	watchlists, err := u.watchlistPort.GetWatchlists(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var watchlist *entity.Watchlist
+	if listName != "" {
+		watchlist = findWatchlist(watchlists, listName)
+		if watchlist == nil {
+			return "", fmt.Errorf("查無觀察清單「%s」", listName)
+		}
+		if !watchlist.HasSymbol(stockSymbolEntity.ID) {
+			return fmt.Sprintf("未在觀察清單「%s」中", watchlist.Name), nil
+		}
+	} else {
+		var names []string
+		for _, w := range watchlists {
+			if w.HasSymbol(stockSymbolEntity.ID) {
+				watchlist = w
+				names = append(names, w.Name)
+			}
+		}
+		if len(names) == 0 {
+			return "未在觀察清單中", nil
+		}
+		if len(names) > 1 {
+			return "", fmt.Errorf("%s 同時在多個清單中（%s），請指定清單名稱", stockSymbolEntity.Symbol, strings.Join(names, "、"))
+		}
+	}
+
+	if err := u.watchlistPort.DeleteWatchlistItem(ctx, watchlist.ID, stockSymbolEntity.ID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("已從觀察清單「%s」移除：%s(%s)", watchlist.Name, stockSymbolEntity.Name, stockSymbolEntity.Symbol), nil
+}
+
+// GetWatchlists 取得觀察清單，未指定名稱時回傳所有清單
+func (u *userWatchlistUsecase) GetWatchlists(ctx context.Context, userID uint, listName string) ([]*entity.Watchlist, error) {
+	if listName == "" {
+		return u.watchlistPort.GetWatchlists(ctx, userID)
+	}
+
+	watchlist, err := u.watchlistPort.GetWatchlistByName(ctx, userID, listName)
+	if err != nil {
+		return nil, err
+	}
+	if watchlist == nil {
+		return nil, fmt.Errorf("查無觀察清單「%s」", listName)
+	}
+	return []*entity.Watchlist{watchlist}, nil
+}
+
+func (u *userWatchlistUsecase) CreateWatchlist(ctx context.Context, userID uint, name string) (string, error) {
+	watchlists, err := u.watchlistPort.GetWatchlists(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if findWatchlist(watchlists, name) != nil {
+		return fmt.Sprintf("觀察清單「%s」已存在", name), nil
+	}
+
+	if _, err := u.createWatchlist(ctx, userID, name, watchlists); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("已建立觀察清單「%s」", name), nil
+}
+
+func (u *userWatchlistUsecase) RenameWatchlist(ctx context.Context, userID uint, oldName string, newName string) (string, error) {
+	if err := validateWatchlistName(newName); err != nil {
+		return "", err
+	}
+
+	watchlists, err := u.watchlistPort.GetWatchlists(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	watchlist := findWatchlist(watchlists, oldName)
+	if watchlist == nil {
+		return "", fmt.Errorf("查無觀察清單「%s」", oldName)
+	}
+	if oldName == newName {
+		return "清單名稱未變更", nil
+	}
+	if findWatchlist(watchlists, newName) != nil {
+		return "", fmt.Errorf("觀察清單「%s」已存在", newName)
+	}
+
+	if err := u.watchlistPort.RenameWatchlist(ctx, watchlist.ID, newName); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("已將觀察清單「%s」重新命名為「%s」", oldName, newName), nil
+}
+
+func (u *userWatchlistUsecase) DeleteWatchlist(ctx context.Context, userID uint, name string) (string, error) {
+	watchlist, err := u.watchlistPort.GetWatchlistByName(ctx, userID, name)
+	if err != nil {
+		return "", err
+	}
+	if watchlist == nil {
+		return "", fmt.Errorf("查無觀察清單「%s」", name)
+	}
+
+	if err := u.watchlistPort.DeleteWatchlist(ctx, watchlist.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("已刪除觀察清單「%s」（共 %d 檔股票）", name, len(watchlist.Items)), nil
+}
+
+func (u *userWatchlistUsecase) MoveWatchlistStock(ctx context.Context, userID uint, stockSymbol string, fromName string, toName string) (string, error) {
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	watchlists, err := u.watchlistPort.GetWatchlists(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	from := findWatchlist(watchlists, fromName)
+	if from == nil {
+		return "", fmt.Errorf("查無觀察清單「%s」", fromName)
+	}
+	to := findWatchlist(watchlists, toName)
+	if to == nil {
+		return "", fmt.Errorf("查無觀察清單「%s」", toName)
+	}
+	if from.ID == to.ID {
+		return "來源與目標清單相同", nil
+	}
+
+	item := from.FindItem(stockSymbolEntity.ID)
+	if item == nil {
+		return fmt.Sprintf("未在觀察清單「%s」中", from.Name), nil
+	}
+	if to.HasSymbol(stockSymbolEntity.ID) {
+		return "", fmt.Errorf("%s 已在觀察清單「%s」中", stockSymbolEntity.Symbol, to.Name)
+	}
+
+	if err := u.watchlistPort.MoveWatchlistItem(ctx, item.ID, to.ID, to.NextSortOrder()); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("已將 %s(%s) 從「%s」移至「%s」", stockSymbolEntity.Name, stockSymbolEntity.Symbol, from.Name, to.Name), nil
+}
+
+func (u *userWatchlistUsecase) ReorderWatchlistStock(ctx context.Context, userID uint, listName string, stockSymbol string, position int) (string, error) {
+	if position < 1 {
+		return "", fmt.Errorf("排序位置需大於0")
+	}
+
+	stockSymbolEntity, err := u.validationPort.ValidateSymbol(ctx, stockSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	watchlist, err := u.watchlistPort.GetWatchlistByName(ctx, userID, listName)
+	if err != nil {
+		return "", err
+	}
+	if watchlist == nil {
+		return "", fmt.Errorf("查無觀察清單「%s」", listName)
+	}
+
+	if err := watchlist.MoveItem(stockSymbolEntity.ID, position); err != nil {
+		return fmt.Sprintf("未在觀察清單「%s」中", watchlist.Name), nil
+	}
+
+	if err := u.watchlistPort.UpdateWatchlistItemOrders(ctx, watchlist.Items); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("已將 %s(%s) 移至「%s」第 %d 位", stockSymbolEntity.Name, stockSymbolEntity.Symbol, watchlist.Name, watchlist.FindItem(stockSymbolEntity.ID).SortOrder), nil
+}
+
+// createWatchlist 建立新清單並排在使用者現有清單之後
+func (u *userWatchlistUsecase) createWatchlist(ctx context.Context, userID uint, name string, existing []*entity.Watchlist) (*entity.Watchlist, error) {
+	if err := validateWatchlistName(name); err != nil {
+		return nil, err
+	}
+
+	sortOrder := 1
+	for _, w := range existing {
+		if w.SortOrder >= sortOrder {
+			sortOrder = w.SortOrder + 1
+		}
+	}
+
+	watchlist := &entity.Watchlist{
+		UserID:    userID,
+		Name:      name,
+		SortOrder: sortOrder,
+	}
+	if err := u.watchlistPort.CreateWatchlist(ctx, watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+func validateWatchlistName(name string) error {
+	// 訊息會以 HTML 格式送出，名稱中不允許 HTML 特殊字元
+	if err := entity.ValidateWatchlistName(name); err != nil || strings.ContainsAny(name, "<>&") {
+		return fmt.Errorf("清單名稱需為 1-%d 個字，且不可包含空白或 < > & 符號", entity.MaxWatchlistNameLength)
+	}
+	if reservedWatchlistNames[strings.ToLower(name)] {
+		return fmt.Errorf("「%s」為保留字，請使用其他清單名稱", name)
+	}
+	return nil
+}
+
+func findWatchlist(watchlists []*entity.Watchlist, name string) *entity.Watchlist {
+	for _, watchlist := range watchlists {
+		if watchlist.Name == name {
+			return watchlist
+		}
+	}
+	return nil
+}
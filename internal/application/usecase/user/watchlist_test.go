@@ -0,0 +1,239 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+)
+
+func TestUserWatchlistUsecase_DeleteWatchlistStock(t *testing.T) {
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+	etf := &entity.StockSymbol{ID: 2, Symbol: "0050", Name: "元大台灣50", Market: "TWSE"}
+
+	tests := []struct {
+		name        string
+		listName    string
+		symbol      string
+		watchlists  []*entity.Watchlist
+		want        string
+		wantErr     string
+		wantDeleted [][2]uint
+	}{
+		{
+			name:     "從指定清單移除",
+			listName: "長期",
+			symbol:   "2330",
+			watchlists: []*entity.Watchlist{
+				{ID: 1, Name: "預設"},
+				{ID: 2, Name: "長期", Items: []*entity.WatchlistItem{{WatchlistID: 2, SymbolID: 1}}},
+			},
+			want:        "已從觀察清單「長期」移除：台積電(2330)",
+			wantDeleted: [][2]uint{{2, 1}},
+		},
+		{
+			name:     "股票不在指定清單中",
+			listName: "預設",
+			symbol:   "2330",
+			watchlists: []*entity.Watchlist{
+				{ID: 1, Name: "預設", Items: []*entity.WatchlistItem{{WatchlistID: 1, SymbolID: 2}}},
+				{ID: 2, Name: "長期", Items: []*entity.WatchlistItem{{WatchlistID: 2, SymbolID: 1}}},
+			},
+			want: "未在觀察清單「預設」中",
+		},
+		{
+			name:   "股票不在任何清單中",
+			symbol: "0050",
+			watchlists: []*entity.Watchlist{
+				{ID: 1, Name: "預設", Items: []*entity.WatchlistItem{{WatchlistID: 1, SymbolID: 1}}},
+			},
+			want: "未在觀察清單中",
+		},
+		{
+			name:   "未指定清單時從唯一包含的清單移除",
+			symbol: "0050",
+			watchlists: []*entity.Watchlist{
+				{ID: 1, Name: "預設", Items: []*entity.WatchlistItem{{WatchlistID: 1, SymbolID: 1}}},
+				{ID: 2, Name: "長期", Items: []*entity.WatchlistItem{{WatchlistID: 2, SymbolID: 2}}},
+			},
+			want:        "已從觀察清單「長期」移除：元大台灣50(0050)",
+			wantDeleted: [][2]uint{{2, 2}},
+		},
+		{
+			name:   "未指定清單且同時在多個清單中",
+			symbol: "2330",
+			watchlists: []*entity.Watchlist{
+				{ID: 1, Name: "預設", Items: []*entity.WatchlistItem{{WatchlistID: 1, SymbolID: 1}}},
+				{ID: 2, Name: "長期", Items: []*entity.WatchlistItem{{WatchlistID: 2, SymbolID: 1}}},
+			},
+			wantErr: "2330 同時在多個清單中（預設、長期），請指定清單名稱",
+		},
+		{
+			name:       "指定的清單不存在",
+			listName:   "短線",
+			symbol:     "2330",
+			watchlists: []*entity.Watchlist{{ID: 1, Name: "預設"}},
+			wantErr:    "查無觀察清單「短線」",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchlistPort := &mockWatchlistPort{watchlists: tt.watchlists}
+			validationPort := &mockValidationPort{symbols: map[string]*entity.StockSymbol{"2330": tsmc, "0050": etf}}
+			usecase := NewUserWatchlistUsecase(watchlistPort, validationPort)
+
+			got, err := usecase.DeleteWatchlistStock(context.Background(), 1, tt.listName, tt.symbol)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("錯誤期望 %q，實際 %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("回覆期望 %q，實際 %q", tt.want, got)
+			}
+			if len(watchlistPort.deletedItems) != len(tt.wantDeleted) {
+				t.Fatalf("移除次數期望 %d，實際 %d", len(tt.wantDeleted), len(watchlistPort.deletedItems))
+			}
+			for i, deleted := range watchlistPort.deletedItems {
+				if deleted != tt.wantDeleted[i] {
+					t.Errorf("移除項目期望 %v，實際 %v", tt.wantDeleted[i], deleted)
+				}
+			}
+		})
+	}
+}
+
+func TestUserWatchlistUsecase_AddWatchlistStock(t *testing.T) {
+	tsmc := &entity.StockSymbol{ID: 1, Symbol: "2330", Name: "台積電", Market: "TWSE"}
+
+	tests := []struct {
+		name          string
+		listName      string
+		watchlists    []*entity.Watchlist
+		want          string
+		wantCreated   string
+		wantSortOrder int
+	}{
+		{
+			name:     "未指定清單時加入第一個清單的最後",
+			listName: "",
+			watchlists: []*entity.Watchlist{
+				{ID: 1, Name: "長期", Items: []*entity.WatchlistItem{{WatchlistID: 1, SymbolID: 2, SortOrder: 1}, {WatchlistID: 1, SymbolID: 3, SortOrder: 2}}},
+				{ID: 2, Name: "短線"},
+			},
+			want:          "已加入觀察清單「長期」：台積電(2330)",
+			wantSortOrder: 3,
+		},
+		{
+			name:          "沒有任何清單時建立預設清單",
+			want:          "已加入觀察清單「預設」：台積電(2330)",
+			wantCreated:   entity.DefaultWatchlistName,
+			wantSortOrder: 1,
+		},
+		{
+			name:          "指定的清單不存在時自動建立",
+			listName:      "短線",
+			watchlists:    []*entity.Watchlist{{ID: 1, Name: "預設"}},
+			want:          "已加入觀察清單「短線」：台積電(2330)",
+			wantCreated:   "短線",
+			wantSortOrder: 1,
+		},
+		{
+			name:       "已在清單中",
+			listName:   "預設",
+			watchlists: []*entity.Watchlist{{ID: 1, Name: "預設", Items: []*entity.WatchlistItem{{WatchlistID: 1, SymbolID: 1, SortOrder: 1}}}},
+			want:       "已在觀察清單「預設」中",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchlistPort := &mockWatchlistPort{watchlists: tt.watchlists}
+			validationPort := &mockValidationPort{symbols: map[string]*entity.StockSymbol{"2330": tsmc}}
+			usecase := NewUserWatchlistUsecase(watchlistPort, validationPort)
+
+			got, err := usecase.AddWatchlistStock(context.Background(), 1, tt.listName, "2330")
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("回覆期望 %q，實際 %q", tt.want, got)
+			}
+
+			if tt.wantCreated == "" && len(watchlistPort.created) > 0 {
+				t.Errorf("不應建立清單，實際建立 %q", watchlistPort.created[0].Name)
+			}
+			if tt.wantCreated != "" && (len(watchlistPort.created) != 1 || watchlistPort.created[0].Name != tt.wantCreated) {
+				t.Errorf("期望建立清單 %q，實際 %v", tt.wantCreated, watchlistPort.created)
+			}
+
+			if tt.wantSortOrder == 0 {
+				if len(watchlistPort.addedItems) > 0 {
+					t.Errorf("不應新增股票，實際新增 %d 筆", len(watchlistPort.addedItems))
+				}
+				return
+			}
+			if len(watchlistPort.addedItems) != 1 {
+				t.Fatalf("新增股票期望 1 筆，實際 %d 筆", len(watchlistPort.addedItems))
+			}
+			if item := watchlistPort.addedItems[0]; item.SymbolID != tsmc.ID || item.SortOrder != tt.wantSortOrder {
+				t.Errorf("新增項目期望股票 %d、排序 %d，實際 %+v", tsmc.ID, tt.wantSortOrder, item)
+			}
+		})
+	}
+}
+
+func TestUserWatchlistUsecase_DuplicateNames(t *testing.T) {
+	newUsecase := func() (UserWatchlistUsecase, *mockWatchlistPort) {
+		watchlistPort := &mockWatchlistPort{watchlists: []*entity.Watchlist{
+			{ID: 1, Name: "預設", SortOrder: 1},
+			{ID: 2, Name: "長期", SortOrder: 2},
+		}}
+		return NewUserWatchlistUsecase(watchlistPort, &mockValidationPort{}), watchlistPort
+	}
+
+	t.Run("建立同名清單", func(t *testing.T) {
+		usecase, watchlistPort := newUsecase()
+		got, err := usecase.CreateWatchlist(context.Background(), 1, "長期")
+		if err != nil {
+			t.Fatalf("不應該發生錯誤: %v", err)
+		}
+		if want := "觀察清單「長期」已存在"; got != want {
+			t.Errorf("回覆期望 %q，實際 %q", want, got)
+		}
+		if len(watchlistPort.created) != 0 {
+			t.Errorf("不應建立清單，實際建立 %d 個", len(watchlistPort.created))
+		}
+	})
+
+	t.Run("建立空白名稱的清單", func(t *testing.T) {
+		usecase, watchlistPort := newUsecase()
+		if _, err := usecase.CreateWatchlist(context.Background(), 1, ""); err == nil {
+			t.Error("空白名稱應回傳錯誤")
+		}
+		if len(watchlistPort.created) != 0 {
+			t.Errorf("不應建立清單，實際建立 %d 個", len(watchlistPort.created))
+		}
+	})
+
+	t.Run("重新命名為既有名稱", func(t *testing.T) {
+		usecase, _ := newUsecase()
+		_, err := usecase.RenameWatchlist(context.Background(), 1, "預設", "長期")
+		if err == nil || err.Error() != "觀察清單「長期」已存在" {
+			t.Errorf("期望同名錯誤，實際 %v", err)
+		}
+	})
+
+	t.Run("建立新清單排在最後", func(t *testing.T) {
+		usecase, watchlistPort := newUsecase()
+		if _, err := usecase.CreateWatchlist(context.Background(), 1, "短線"); err != nil {
+			t.Fatalf("不應該發生錯誤: %v", err)
+		}
+		if len(watchlistPort.created) != 1 || watchlistPort.created[0].SortOrder != 3 {
+			t.Errorf("新清單排序期望 3，實際 %v", watchlistPort.created)
+		}
+	})
+}
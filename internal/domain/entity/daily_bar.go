@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// taiwanDailyBarReadyHour 台股當日收盤資料開始提供的時間（時）
+const taiwanDailyBarReadyHour = 14
+
+// DailyBar 本地保存的股票日K資料
+type DailyBar struct {
+	ID           uint
+	SymbolID     uint
+	Date         time.Time
+	Open         float64
+	High         float64
+	Low          float64
+	Close        float64
+	Volume       int64
+	Amount       float64
+	Transactions int64
+	// 計價幣別（TWD、USD）
+	Currency string
+}
+
+// LatestDailyBarDate 回傳查詢到 endDate 為止時，在 now 應已提供日K的最新交易日。
+// 台股依交易日清單判斷，當日資料於收盤後提供；美股交易時段為台灣時間夜間，最多只到前一個平日，不判斷美股假日。
+// tradeDates 需依日期由舊到新排序，找不到交易日時回傳 false
+func LatestDailyBarDate(stock *StockSymbol, tradeDates []*TradeDate, endDate time.Time, now time.Time) (time.Time, bool) {
+	limit := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if stock.IsUSStock() {
+		if yesterday := today.AddDate(0, 0, -1); limit.After(yesterday) {
+			limit = yesterday
+		}
+		for limit.Weekday() == time.Saturday || limit.Weekday() == time.Sunday {
+			limit = limit.AddDate(0, 0, -1)
+		}
+		return limit, true
+	}
+
+	ready := today
+	if now.Hour() < taiwanDailyBarReadyHour {
+		ready = today.AddDate(0, 0, -1)
+	}
+	if limit.After(ready) {
+		limit = ready
+	}
+	for i := len(tradeDates) - 1; i >= 0; i-- {
+		if tradeDates[i].Date.Format("2006-01-02") <= limit.Format("2006-01-02") {
+			return tradeDates[i].Date, true
+		}
+	}
+	return time.Time{}, false
+}
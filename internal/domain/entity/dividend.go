@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// ParValue 台股每股面額，股票股利以此換算配股比例
+const ParValue = 10
+
+// Dividend 單次除權息事件，同一年度的現金與股票股利若除權息日不同會拆成兩筆
+type Dividend struct {
+	Symbol string
+	// Year 股利所屬年度（季度）
+	Year string
+	// ExDividendDate 除權息交易日，當日起買進不參與配發
+	ExDividendDate time.Time
+	// PaymentDate 現金股利發放日，未公告時為零值
+	PaymentDate time.Time
+	// CashDividend 每股現金股利（元）
+	CashDividend float64
+	// StockDividend 每股股票股利（元）
+	StockDividend float64
+}
+
+// StockDividendRatio 每股配股數，例如股票股利 1 元代表每股配 0.1 股
+func (d *Dividend) StockDividendRatio() float64 {
+	return d.StockDividend / ParValue
+}
+
+// IsEmpty 是否沒有任何股利
+func (d *Dividend) IsEmpty() bool {
+	return d.CashDividend <= 0 && d.StockDividend <= 0
+}
+
+// DividendReceipt 持股實際參與的一次配息配股
+type DividendReceipt struct {
+	Dividend *Dividend
+	// Quantity 除權息前一日的持有股數
+	Quantity int64
+	// CashAmount 領取的現金股利，未滿一元捨去
+	CashAmount float64
+	// StockShares 配發的股數，不足一股的部分不計
+	StockShares int64
+}
+
+// SortDividends 依除權息日排序
+func SortDividends(dividends []*Dividend) {
+	sort.SliceStable(dividends, func(i, j int) bool {
+		return dividends[i].ExDividendDate.Before(dividends[j].ExDividendDate)
+	})
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// NotificationEvent 一則推播給使用者的通知事件，內容於建立時即固定
+type NotificationEvent struct {
+	ID     uint
+	UserID uint
+	// FeatureID 觸發通知的訂閱功能，價格提醒等非訂閱通知為 0
+	FeatureID      uint
+	SubscriptionID *uint
+	SymbolID       *uint
+	// Payload 推播內容（JSON）
+	Payload string
+	// DedupeKey 去重鍵，相同去重鍵的通知只會建立一次；為空代表不去重
+	DedupeKey  string
+	OccurredAt time.Time
+	User       *User
+}
+
+// NewNotificationDedupeKey 以使用者、訂閱功能、股票及通知內容所屬的交易日 tradingDate 組成去重鍵，
+// 內容與個股無關的功能不區分股票，同一個交易日只推播一次
+func NewNotificationDedupeKey(userID uint, feature valueobject.SubscriptionType, symbolID uint, tradingDate time.Time) string {
+	if !feature.IsSymbolBased() {
+		symbolID = 0
+	}
+	return fmt.Sprintf("%d:%d:%d:%s", userID, feature, symbolID, tradingDate.Format("2006-01-02"))
+}
+
+// NotificationDelivery 通知事件在某個管道的投遞紀錄
+type NotificationDelivery struct {
+	ID      uint
+	EventID uint
+	Channel valueobject.UserType
+	Status  valueobject.NotificationDeliveryStatus
+	// Response 送出結果（JSON），失敗時包含錯誤訊息
+	Response string
+	// Attempts 已嘗試送出的次數
+	Attempts int
+	// NextRetryAt 下次重試時間，為 nil 代表不再重試；待送出的投遞紀錄遞延時為最早可送出的時間
+	NextRetryAt *time.Time
+	SentAt      *time.Time
+	Event       *NotificationEvent
+}
+
+// IsQueued 是否仍在等待送出
+func (d *NotificationDelivery) IsQueued() bool {
+	return d.Status == valueobject.NotificationDeliveryStatusQueued
+}
+
+// CanRetry 送出失敗後是否仍排定重試
+func (d *NotificationDelivery) CanRetry() bool {
+	return d.Status == valueobject.NotificationDeliveryStatusFailed && d.NextRetryAt != nil
+}
+
+// MarkSent 標記為已送出
+func (d *NotificationDelivery) MarkSent(sentAt time.Time, response string) {
+	d.Status = valueobject.NotificationDeliveryStatusSent
+	d.Attempts++
+	d.SentAt = &sentAt
+	d.NextRetryAt = nil
+	d.Response = response
+}
+
+// MarkFailed 標記為送出失敗，nextRetryAt 為 nil 代表不再重試
+func (d *NotificationDelivery) MarkFailed(response string, nextRetryAt *time.Time) {
+	d.Status = valueobject.NotificationDeliveryStatusFailed
+	d.Attempts++
+	d.NextRetryAt = nextRetryAt
+	d.Response = response
+}
+
+// Defer 遞延到 until 之後再送出（例如使用者的勿擾時段），不計入嘗試次數
+func (d *NotificationDelivery) Defer(until time.Time) {
+	d.NextRetryAt = &until
+}
+
+// MarkSkipped 標記為依使用者偏好設定不送出
+func (d *NotificationDelivery) MarkSkipped(response string) {
+	d.Status = valueobject.NotificationDeliveryStatusSkipped
+	d.NextRetryAt = nil
+	d.Response = response
+}
@@ -0,0 +1,228 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// PortfolioTrade 投資組合的一筆買賣紀錄
+type PortfolioTrade struct {
+	ID       uint
+	UserID   uint
+	SymbolID uint
+	Side     valueobject.TradeSide
+	// Quantity 成交股數
+	Quantity int64
+	// Price 成交單價
+	Price       float64
+	TradeDate   time.Time
+	StockSymbol *StockSymbol
+}
+
+// Validate 驗證交易紀錄的合法性
+func (t *PortfolioTrade) Validate() error {
+	if t.UserID == 0 || t.SymbolID == 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	if !t.Side.IsValid() {
+		return domainerror.ErrInvalidArgument
+	}
+	if t.Quantity <= 0 || t.Price <= 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	if t.TradeDate.IsZero() {
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
+
+// Amount 成交金額
+func (t *PortfolioTrade) Amount() float64 {
+	return float64(t.Quantity) * t.Price
+}
+
+// Holding 單一股票的持股部位，成本採移動平均法計算並包含買進手續費
+type Holding struct {
+	SymbolID    uint
+	StockSymbol *StockSymbol
+	// Quantity 目前持有股數
+	Quantity int64
+	// TotalCost 目前持股的總成本（含買進手續費）
+	TotalCost float64
+	// RealizedPnL 已實現損益（已扣除買賣手續費及證交稅）
+	RealizedPnL float64
+	// TotalFees 累計手續費
+	TotalFees float64
+	// TotalTax 累計證交稅
+	TotalTax float64
+	// CashDividends 累計領取的現金股利
+	CashDividends float64
+	// Dividends 參與過的配息配股紀錄
+	Dividends []*DividendReceipt
+
+	// 當日買進且尚未被當沖賣出的股數，用於判斷現股當沖
+	dayTradeDate     time.Time
+	dayTradeQuantity int64
+}
+
+// AverageCost 平均成本
+func (h *Holding) AverageCost() float64 {
+	if h.Quantity == 0 {
+		return 0
+	}
+	return h.TotalCost / float64(h.Quantity)
+}
+
+// IsOpen 是否仍有持股
+func (h *Holding) IsOpen() bool {
+	return h.Quantity > 0
+}
+
+// Apply 依成本模型套用一筆交易，賣出股數超過持股時回傳錯誤且不變更部位。
+// 同一交易日先買後賣的股數視為現股當沖，適用當沖稅率
+func (h *Holding) Apply(trade *PortfolioTrade, costModel *TransactionCostModel) error {
+	if !sameDay(h.dayTradeDate, trade.TradeDate) {
+		h.dayTradeDate = trade.TradeDate
+		h.dayTradeQuantity = 0
+	}
+
+	switch trade.Side {
+	case valueobject.TradeSideBuy:
+		cost := costModel.BuyCost(trade.Quantity, trade.Price)
+		h.Quantity += trade.Quantity
+		h.TotalCost += trade.Amount() + cost.Total()
+		h.TotalFees += cost.Fee
+		h.dayTradeQuantity += trade.Quantity
+	case valueobject.TradeSideSell:
+		if trade.Quantity > h.Quantity {
+			return domainerror.NewInsufficientHoldingError(h.symbolCode(), h.Quantity, trade.Quantity)
+		}
+		dayTradeQuantity := min(trade.Quantity, h.dayTradeQuantity)
+		cost := costModel.SellCost(h.StockSymbol, trade.Quantity, dayTradeQuantity, trade.Price)
+		costOfSold := h.TotalCost * float64(trade.Quantity) / float64(h.Quantity)
+
+		h.RealizedPnL += trade.Amount() - cost.Total() - costOfSold
+		h.TotalFees += cost.Fee
+		h.TotalTax += cost.Tax
+		h.Quantity -= trade.Quantity
+		h.TotalCost -= costOfSold
+		h.dayTradeQuantity -= dayTradeQuantity
+		if h.Quantity == 0 {
+			h.TotalCost = 0
+		}
+	default:
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
+
+// ApplyDividend 套用一次除權息：現金股利計入股利收入，股票股利增加股數但不增加成本，因此平均成本下降
+func (h *Holding) ApplyDividend(dividend *Dividend) {
+	if h.Quantity <= 0 || dividend.IsEmpty() {
+		return
+	}
+
+	receipt := &DividendReceipt{Dividend: dividend, Quantity: h.Quantity}
+	if dividend.CashDividend > 0 {
+		receipt.CashAmount = floorAmount(float64(h.Quantity) * dividend.CashDividend)
+		h.CashDividends += receipt.CashAmount
+	}
+	if dividend.StockDividend > 0 {
+		receipt.StockShares = int64(floorAmount(float64(h.Quantity) * dividend.StockDividendRatio()))
+		h.Quantity += receipt.StockShares
+	}
+	h.Dividends = append(h.Dividends, receipt)
+}
+
+// EstimateSellCost 估算以指定價格全部賣出目前持股的費用
+func (h *Holding) EstimateSellCost(price float64, costModel *TransactionCostModel) TradeCost {
+	return costModel.SellCost(h.StockSymbol, h.Quantity, 0, price)
+}
+
+func (h *Holding) symbolCode() string {
+	if h.StockSymbol != nil {
+		return h.StockSymbol.Symbol
+	}
+	return ""
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// onOrBefore 以日曆日比較 a 是否在 b 當天或之前
+func onOrBefore(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	if ay != by {
+		return ay < by
+	}
+	if am != bm {
+		return am < bm
+	}
+	return ad <= bd
+}
+
+// SortTrades 依交易日期排序交易紀錄，同日依建立順序
+func SortTrades(trades []*PortfolioTrade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		if !trades[i].TradeDate.Equal(trades[j].TradeDate) {
+			return trades[i].TradeDate.Before(trades[j].TradeDate)
+		}
+		return trades[i].ID < trades[j].ID
+	})
+}
+
+// BuildHoldings 依時間順序重播交易紀錄與已除權息的股利計算每檔股票的部位，回傳順序為第一次交易的先後。
+// 除權息日當天的交易在除權息之後套用，因此當天買進不參與配發、當天賣出仍可參與
+func BuildHoldings(trades []*PortfolioTrade, dividends []*Dividend, costModel *TransactionCostModel) ([]*Holding, error) {
+	sorted := make([]*PortfolioTrade, len(trades))
+	copy(sorted, trades)
+	SortTrades(sorted)
+
+	pending := make(map[string][]*Dividend)
+	for _, dividend := range dividends {
+		pending[dividend.Symbol] = append(pending[dividend.Symbol], dividend)
+	}
+	for _, list := range pending {
+		SortDividends(list)
+	}
+
+	holdings := make([]*Holding, 0)
+	bySymbol := make(map[uint]*Holding)
+	for _, trade := range sorted {
+		holding, ok := bySymbol[trade.SymbolID]
+		if !ok {
+			holding = &Holding{SymbolID: trade.SymbolID, StockSymbol: trade.StockSymbol}
+			bySymbol[trade.SymbolID] = holding
+			holdings = append(holdings, holding)
+		}
+
+		code := holding.symbolCode()
+		list := pending[code]
+		for len(list) > 0 && onOrBefore(list[0].ExDividendDate, trade.TradeDate) {
+			holding.ApplyDividend(list[0])
+			list = list[1:]
+		}
+		pending[code] = list
+
+		if err := holding.Apply(trade, costModel); err != nil {
+			return nil, err
+		}
+	}
+
+	// 最後一筆交易之後的除權息
+	for _, holding := range holdings {
+		code := holding.symbolCode()
+		for _, dividend := range pending[code] {
+			holding.ApplyDividend(dividend)
+		}
+		pending[code] = nil
+	}
+	return holdings, nil
+}
@@ -0,0 +1,195 @@
+package entity
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+func testTrade(id uint, side valueobject.TradeSide, quantity int64, price float64, day int) *PortfolioTrade {
+	return &PortfolioTrade{
+		ID:          id,
+		UserID:      1,
+		SymbolID:    1,
+		Side:        side,
+		Quantity:    quantity,
+		Price:       price,
+		TradeDate:   tradeDay(day),
+		StockSymbol: &StockSymbol{ID: 1, Symbol: "2330", Market: "TWSE"},
+	}
+}
+
+func TestBuildHoldings(t *testing.T) {
+	buy := valueobject.TradeSideBuy
+	sell := valueobject.TradeSideSell
+
+	tests := []struct {
+		name              string
+		trades            []*PortfolioTrade
+		dividends         []*Dividend
+		wantQuantity      int64
+		wantTotalCost     float64
+		wantRealizedPnL   float64
+		wantTotalTax      float64
+		wantCashDividends float64
+		wantReceiptShares []int64
+	}{
+		{
+			name: "移動平均成本",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+				testTrade(2, buy, 1000, 110, 3),
+				testTrade(3, sell, 1000, 120, 4),
+			},
+			wantQuantity: 1000,
+			// 買進成本 100000+142 與 110000+156，賣出一半
+			wantTotalCost: 105149,
+			// 120000 - 手續費 171 - 證交稅 360 - 賣出成本 105149
+			wantRealizedPnL: 14320,
+			wantTotalTax:    360,
+		},
+		{
+			name: "交易依日期排序後重播",
+			trades: []*PortfolioTrade{
+				testTrade(2, sell, 1000, 120, 4),
+				testTrade(1, buy, 1000, 100, 2),
+			},
+			wantQuantity:    0,
+			wantTotalCost:   0,
+			wantRealizedPnL: 120000 - 171 - 360 - 100142,
+			wantTotalTax:    360,
+		},
+		{
+			name: "同日先買後賣適用當沖稅率",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+				testTrade(2, sell, 1000, 101, 2),
+			},
+			wantQuantity:    0,
+			wantRealizedPnL: 101000 - 143 - 151 - 100142,
+			wantTotalTax:    151,
+		},
+		{
+			name: "當日賣出超過當日買進的部分適用一般稅率",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+				testTrade(2, buy, 1000, 100, 3),
+				testTrade(3, sell, 1500, 101, 3),
+			},
+			wantQuantity:  500,
+			wantTotalCost: 50071,
+			// 當沖 1000 股 151 元，一般 500 股 151 元
+			wantRealizedPnL: 151500 - 214 - 302 - 150213,
+			wantTotalTax:    302,
+		},
+		{
+			name: "除權息日當天先配發再套用當天的賣出",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+				testTrade(2, sell, 1000, 98, 10),
+			},
+			dividends: []*Dividend{
+				{Symbol: "2330", ExDividendDate: tradeDay(10), CashDividend: 2.5, StockDividend: 1},
+			},
+			// 配股後 1100 股共用原成本，平均成本下降
+			wantQuantity:      100,
+			wantTotalCost:     100142.0 * 100 / 1100,
+			wantRealizedPnL:   98000 - 139 - 294 - 100142.0*1000/1100,
+			wantTotalTax:      294,
+			wantCashDividends: 2500,
+			wantReceiptShares: []int64{100},
+		},
+		{
+			name: "除權息日當天買進不參與配發",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+				testTrade(2, buy, 1000, 100, 10),
+			},
+			dividends: []*Dividend{
+				{Symbol: "2330", ExDividendDate: tradeDay(10), CashDividend: 2.5},
+			},
+			wantQuantity:      2000,
+			wantTotalCost:     200284,
+			wantCashDividends: 2500,
+			wantReceiptShares: []int64{0},
+		},
+		{
+			name: "最後一筆交易之後的除權息",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+			},
+			dividends: []*Dividend{
+				{Symbol: "2330", ExDividendDate: tradeDay(20), CashDividend: 1},
+				{Symbol: "2330", ExDividendDate: tradeDay(10), StockDividend: 0.5},
+			},
+			wantQuantity:      1050,
+			wantTotalCost:     100142,
+			wantCashDividends: 1050,
+			wantReceiptShares: []int64{50, 0},
+		},
+		{
+			name: "其他股票的股利不影響部位",
+			trades: []*PortfolioTrade{
+				testTrade(1, buy, 1000, 100, 2),
+			},
+			dividends: []*Dividend{
+				{Symbol: "2317", ExDividendDate: tradeDay(10), CashDividend: 5},
+			},
+			wantQuantity:  1000,
+			wantTotalCost: 100142,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holdings, err := BuildHoldings(tt.trades, tt.dividends, NewTaiwanTransactionCostModel())
+			if err != nil {
+				t.Fatalf("不應該發生錯誤: %v", err)
+			}
+			if len(holdings) != 1 {
+				t.Fatalf("部位數量期望 1，實際 %d", len(holdings))
+			}
+
+			h := holdings[0]
+			if h.Quantity != tt.wantQuantity {
+				t.Errorf("股數期望 %d，實際 %d", tt.wantQuantity, h.Quantity)
+			}
+			if math.Abs(h.TotalCost-tt.wantTotalCost) > 1e-6 {
+				t.Errorf("總成本期望 %v，實際 %v", tt.wantTotalCost, h.TotalCost)
+			}
+			if math.Abs(h.RealizedPnL-tt.wantRealizedPnL) > 1e-6 {
+				t.Errorf("已實現損益期望 %v，實際 %v", tt.wantRealizedPnL, h.RealizedPnL)
+			}
+			if h.TotalTax != tt.wantTotalTax {
+				t.Errorf("證交稅期望 %v，實際 %v", tt.wantTotalTax, h.TotalTax)
+			}
+			if h.CashDividends != tt.wantCashDividends {
+				t.Errorf("現金股利期望 %v，實際 %v", tt.wantCashDividends, h.CashDividends)
+			}
+			if len(h.Dividends) != len(tt.wantReceiptShares) {
+				t.Fatalf("配息紀錄期望 %d 筆，實際 %d 筆", len(tt.wantReceiptShares), len(h.Dividends))
+			}
+			for i, receipt := range h.Dividends {
+				if receipt.StockShares != tt.wantReceiptShares[i] {
+					t.Errorf("第 %d 筆配股期望 %d 股，實際 %d 股", i+1, tt.wantReceiptShares[i], receipt.StockShares)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildHoldings_InsufficientHolding(t *testing.T) {
+	trades := []*PortfolioTrade{
+		testTrade(1, valueobject.TradeSideBuy, 1000, 100, 2),
+		testTrade(2, valueobject.TradeSideSell, 1500, 100, 3),
+	}
+
+	_, err := BuildHoldings(trades, nil, NewTaiwanTransactionCostModel())
+	var domainErr *domainerror.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != "INSUFFICIENT_HOLDING" {
+		t.Errorf("期望持股不足錯誤，實際 %v", err)
+	}
+}
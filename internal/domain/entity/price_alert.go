@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"math"
+	"time"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// PriceAlert 價格提醒規則
+type PriceAlert struct {
+	ID        uint
+	UserID    uint
+	SymbolID  uint
+	AlertType valueobject.AlertType
+	Operator  valueobject.AlertOperator
+	// TargetPrice 價格提醒的目標價
+	TargetPrice float64
+	// ThresholdPercent 漲跌幅/跳空提醒的百分比門檻
+	ThresholdPercent float64
+	Active           bool
+	// Triggered 代表目前價格已處於觸發區間，需回到區間外才會再次提醒
+	Triggered bool
+	// TriggeredAt 最近一次觸發提醒的交易日
+	TriggeredAt *time.Time
+	StockSymbol *StockSymbol
+	User        *User
+}
+
+// Validate 驗證價格提醒規則的合法性
+func (a *PriceAlert) Validate() error {
+	if a.UserID == 0 || a.SymbolID == 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	if !a.AlertType.IsValid() {
+		return domainerror.ErrInvalidArgument
+	}
+	if a.AlertType.IsPercentage() {
+		if a.ThresholdPercent <= 0 {
+			return domainerror.ErrInvalidArgument
+		}
+		return nil
+	}
+	if !a.Operator.IsValid() {
+		return domainerror.ErrInvalidArgument
+	}
+	if a.TargetPrice <= 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
+
+// IsMet 檢查價格是否符合提醒條件
+func (a *PriceAlert) IsMet(price float64) bool {
+	switch a.Operator {
+	case valueobject.AlertOperatorAbove:
+		return price > a.TargetPrice
+	case valueobject.AlertOperatorBelow:
+		return price < a.TargetPrice
+	default:
+		return false
+	}
+}
+
+// Evaluate 依 tradeDate 的收盤價更新觸發狀態，僅在由未觸發轉為觸發時回傳 true
+func (a *PriceAlert) Evaluate(price float64, tradeDate time.Time) bool {
+	met := a.IsMet(price)
+	if met && !a.Triggered {
+		a.Triggered = true
+		a.TriggeredAt = &tradeDate
+		return true
+	}
+	if !met && a.Triggered {
+		// 價格回到區間外，重新啟用提醒
+		a.Triggered = false
+	}
+	return false
+}
+
+// EvaluatePercentage 依相對前一交易日的變動百分比更新觸發狀態，同一交易日僅提醒一次
+func (a *PriceAlert) EvaluatePercentage(changePercent float64, tradeDate time.Time) bool {
+	if math.Abs(changePercent) < a.ThresholdPercent {
+		a.Triggered = false
+		return false
+	}
+	if a.TriggeredAt != nil && isSameDate(*a.TriggeredAt, tradeDate) {
+		return false
+	}
+	a.Triggered = true
+	a.TriggeredAt = &tradeDate
+	return true
+}
+
+func (a *PriceAlert) IsActive() bool { return a.Active }
+
+func (a *PriceAlert) Enable() { a.Active = true }
+
+func (a *PriceAlert) Disable() { a.Active = false }
+
+func isSameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
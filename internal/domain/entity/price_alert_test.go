@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+func tradeDay(day int) time.Time {
+	return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPriceAlert_Evaluate(t *testing.T) {
+	type step struct {
+		price         float64
+		date          time.Time
+		wantFired     bool
+		wantTriggered bool
+	}
+
+	tests := []struct {
+		name     string
+		operator valueobject.AlertOperator
+		steps    []step
+	}{
+		{
+			name:     "向上突破時提醒一次",
+			operator: valueobject.AlertOperatorAbove,
+			steps: []step{
+				{price: 990, date: tradeDay(2), wantFired: false, wantTriggered: false},
+				{price: 1010, date: tradeDay(3), wantFired: true, wantTriggered: true},
+				{price: 1020, date: tradeDay(4), wantFired: false, wantTriggered: true},
+			},
+		},
+		{
+			name:     "回到區間外後重新啟用",
+			operator: valueobject.AlertOperatorAbove,
+			steps: []step{
+				{price: 1010, date: tradeDay(2), wantFired: true, wantTriggered: true},
+				{price: 1000, date: tradeDay(3), wantFired: false, wantTriggered: false},
+				{price: 1005, date: tradeDay(4), wantFired: true, wantTriggered: true},
+			},
+		},
+		{
+			name:     "向下跌破時提醒",
+			operator: valueobject.AlertOperatorBelow,
+			steps: []step{
+				{price: 1000, date: tradeDay(2), wantFired: false, wantTriggered: false},
+				{price: 999, date: tradeDay(3), wantFired: true, wantTriggered: true},
+				{price: 1001, date: tradeDay(4), wantFired: false, wantTriggered: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &PriceAlert{AlertType: valueobject.AlertTypePrice, Operator: tt.operator, TargetPrice: 1000}
+			var lastFired time.Time
+
+			for i, s := range tt.steps {
+				fired := alert.Evaluate(s.price, s.date)
+				if fired != s.wantFired {
+					t.Errorf("第 %d 步 fired 期望 %v，實際 %v", i+1, s.wantFired, fired)
+				}
+				if alert.Triggered != s.wantTriggered {
+					t.Errorf("第 %d 步 Triggered 期望 %v，實際 %v", i+1, s.wantTriggered, alert.Triggered)
+				}
+				if fired {
+					lastFired = s.date
+				}
+				if !lastFired.IsZero() && (alert.TriggeredAt == nil || !alert.TriggeredAt.Equal(lastFired)) {
+					t.Errorf("第 %d 步 TriggeredAt 應為最近一次觸發的交易日 %v，實際 %v", i+1, lastFired, alert.TriggeredAt)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceAlert_EvaluatePercentage(t *testing.T) {
+	type step struct {
+		changePercent float64
+		date          time.Time
+		wantFired     bool
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "未達門檻不提醒",
+			steps: []step{
+				{changePercent: 2.9, date: tradeDay(2), wantFired: false},
+				{changePercent: -2.9, date: tradeDay(3), wantFired: false},
+			},
+		},
+		{
+			name: "上漲或下跌達門檻皆提醒",
+			steps: []step{
+				{changePercent: 3, date: tradeDay(2), wantFired: true},
+				{changePercent: -3.5, date: tradeDay(3), wantFired: true},
+			},
+		},
+		{
+			name: "同一交易日僅提醒一次",
+			steps: []step{
+				{changePercent: 3.2, date: tradeDay(2), wantFired: true},
+				{changePercent: 4.1, date: tradeDay(2), wantFired: false},
+				{changePercent: 1, date: tradeDay(2), wantFired: false},
+				{changePercent: 5, date: tradeDay(2), wantFired: false},
+			},
+		},
+		{
+			name: "連續交易日達門檻每日提醒",
+			steps: []step{
+				{changePercent: 3.2, date: tradeDay(2), wantFired: true},
+				{changePercent: 3.4, date: tradeDay(3), wantFired: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &PriceAlert{AlertType: valueobject.AlertTypeMove, ThresholdPercent: 3}
+
+			for i, s := range tt.steps {
+				fired := alert.EvaluatePercentage(s.changePercent, s.date)
+				if fired != s.wantFired {
+					t.Errorf("第 %d 步 fired 期望 %v，實際 %v", i+1, s.wantFired, fired)
+				}
+				if fired && (alert.TriggeredAt == nil || !alert.TriggeredAt.Equal(s.date)) {
+					t.Errorf("第 %d 步 TriggeredAt 期望 %v，實際 %v", i+1, s.date, alert.TriggeredAt)
+				}
+			}
+		})
+	}
+}
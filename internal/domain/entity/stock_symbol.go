@@ -2,6 +2,7 @@ package entity
 
 import (
 	"regexp"
+	"strings"
 
 	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
 )
@@ -59,3 +60,13 @@ func (s *StockSymbol) IsValidSymbol() bool {
 func (s *StockSymbol) IsTaiwanStock() bool {
 	return s.Market == "TWSE" || s.Market == "TPEX"
 }
+
+// IsUSStock 檢查是否為美股
+func (s *StockSymbol) IsUSStock() bool {
+	return s.Market == "US"
+}
+
+// IsETF 檢查是否為台股 ETF（代號以 00 開頭）
+func (s *StockSymbol) IsETF() bool {
+	return s.IsTaiwanStock() && strings.HasPrefix(s.Symbol, "00")
+}
@@ -4,6 +4,9 @@ import (
 	valueobject "github.com/tian841224/stock-bot/internal/domain/valueobject"
 )
 
+// ScheduleNextTradingDayMorning 排程設為次一交易日早上推播，實際時間由通知服務設定
+const ScheduleNextTradingDayMorning = "@morning"
+
 type Subscription struct {
 	ID           uint
 	UserID       uint
@@ -22,3 +25,7 @@ func (s *Subscription) Enable() { s.Active = true }
 func (s *Subscription) Disable() { s.Active = false }
 
 func (s *Subscription) HasSchedule() bool { return s.ScheduleCron != "" }
+
+func (s *Subscription) IsNextTradingDayMorning() bool {
+	return s.ScheduleCron == ScheduleNextTradingDayMorning
+}
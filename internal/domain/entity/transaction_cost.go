@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"math"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+)
+
+// BoardLotShares 台股一張（整股）的股數
+const BoardLotShares = 1000
+
+// TransactionCostModel 台股交易成本模型，所有損益計算皆透過此模型計算手續費與證交稅
+type TransactionCostModel struct {
+	// FeeRate 券商手續費率（法定上限 0.1425%）
+	FeeRate float64
+	// FeeDiscount 券商手續費折扣，1 為不打折，0.6 為六折
+	FeeDiscount float64
+	// MinFee 整股每筆最低手續費
+	MinFee float64
+	// OddLotMinFee 零股每筆最低手續費
+	OddLotMinFee float64
+	// StockTaxRate 股票證券交易稅率
+	StockTaxRate float64
+	// ETFTaxRate ETF 證券交易稅率
+	ETFTaxRate float64
+	// DayTradeTaxRate 現股當沖證券交易稅率。證券交易稅條例第 2 條之 1 將現股當沖減半為 0.15%（實施至 2027 年底），
+	// 並非 ETF 的 0.1%；減徵期滿後應調回 StockTaxRate
+	DayTradeTaxRate float64
+}
+
+// TradeCost 單筆交易的費用
+type TradeCost struct {
+	Fee float64
+	Tax float64
+}
+
+// Total 費用合計
+func (c TradeCost) Total() float64 {
+	return c.Fee + c.Tax
+}
+
+// NewTaiwanTransactionCostModel 建立台股預設交易成本模型
+func NewTaiwanTransactionCostModel() *TransactionCostModel {
+	return &TransactionCostModel{
+		FeeRate:         0.001425,
+		FeeDiscount:     1,
+		MinFee:          20,
+		OddLotMinFee:    1,
+		StockTaxRate:    0.003,
+		ETFTaxRate:      0.001,
+		DayTradeTaxRate: 0.0015,
+	}
+}
+
+// Validate 驗證成本模型參數
+func (m *TransactionCostModel) Validate() error {
+	if m.FeeRate < 0 || m.FeeDiscount <= 0 || m.FeeDiscount > 1 {
+		return domainerror.ErrInvalidArgument
+	}
+	if m.MinFee < 0 || m.OddLotMinFee < 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	if m.StockTaxRate < 0 || m.ETFTaxRate < 0 || m.DayTradeTaxRate < 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
+
+// WithSetting 套用使用者的券商設定，回傳新的模型
+func (m *TransactionCostModel) WithSetting(setting *TradingSetting) *TransactionCostModel {
+	result := *m
+	if setting == nil {
+		return &result
+	}
+	if setting.FeeDiscount > 0 {
+		result.FeeDiscount = setting.FeeDiscount
+	}
+	if setting.MinFee != nil {
+		result.MinFee = *setting.MinFee
+	}
+	return &result
+}
+
+// Fee 計算手續費。整股與零股部分分開計算並各自適用最低手續費，未滿一元無條件捨去
+func (m *TransactionCostModel) Fee(quantity int64, price float64) float64 {
+	if quantity <= 0 || price <= 0 {
+		return 0
+	}
+
+	boardLotQuantity := quantity / BoardLotShares * BoardLotShares
+	oddLotQuantity := quantity - boardLotQuantity
+
+	var fee float64
+	if boardLotQuantity > 0 {
+		fee += math.Max(floorAmount(float64(boardLotQuantity)*price*m.FeeRate*m.FeeDiscount), m.MinFee)
+	}
+	if oddLotQuantity > 0 {
+		fee += math.Max(floorAmount(float64(oddLotQuantity)*price*m.FeeRate*m.FeeDiscount), m.OddLotMinFee)
+	}
+	return fee
+}
+
+// Tax 計算賣出時的證券交易稅，dayTradeQuantity 為其中屬於現股當沖的股數
+func (m *TransactionCostModel) Tax(symbol *StockSymbol, quantity int64, dayTradeQuantity int64, price float64) float64 {
+	if quantity <= 0 || price <= 0 {
+		return 0
+	}
+
+	if symbol != nil && symbol.IsETF() {
+		return floorAmount(float64(quantity) * price * m.ETFTaxRate)
+	}
+
+	if dayTradeQuantity > quantity {
+		dayTradeQuantity = quantity
+	}
+	if dayTradeQuantity < 0 {
+		dayTradeQuantity = 0
+	}
+
+	tax := floorAmount(float64(quantity-dayTradeQuantity) * price * m.StockTaxRate)
+	tax += floorAmount(float64(dayTradeQuantity) * price * m.DayTradeTaxRate)
+	return tax
+}
+
+// floorAmount 金額無條件捨去至整數，並容許浮點數運算誤差（例如 28.999999 視為 29）
+func floorAmount(amount float64) float64 {
+	return math.Floor(amount + 1e-6)
+}
+
+// BuyCost 計算買進費用（僅手續費）
+func (m *TransactionCostModel) BuyCost(quantity int64, price float64) TradeCost {
+	return TradeCost{Fee: m.Fee(quantity, price)}
+}
+
+// SellCost 計算賣出費用（手續費與證交稅）
+func (m *TransactionCostModel) SellCost(symbol *StockSymbol, quantity int64, dayTradeQuantity int64, price float64) TradeCost {
+	return TradeCost{
+		Fee: m.Fee(quantity, price),
+		Tax: m.Tax(symbol, quantity, dayTradeQuantity, price),
+	}
+}
+
+// TradingSetting 使用者的券商交易設定
+type TradingSetting struct {
+	ID     uint
+	UserID uint
+	// FeeDiscount 手續費折扣，1 為不打折
+	FeeDiscount float64
+	// MinFee 整股最低手續費，nil 代表使用預設值
+	MinFee *float64
+}
+
+// Validate 驗證交易設定
+func (s *TradingSetting) Validate() error {
+	if s.UserID == 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	if s.FeeDiscount <= 0 || s.FeeDiscount > 1 {
+		return domainerror.ErrInvalidArgument
+	}
+	if s.MinFee != nil && *s.MinFee < 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package entity
+
+import "testing"
+
+func TestTransactionCostModel_Fee(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount float64
+		quantity int64
+		price    float64
+		want     float64
+	}{
+		{name: "整股依費率計算並捨去", discount: 1, quantity: 1000, price: 100, want: 142},
+		{name: "整股低於最低手續費", discount: 1, quantity: 1000, price: 10, want: 20},
+		{name: "零股依費率計算", discount: 1, quantity: 50, price: 100, want: 7},
+		{name: "零股低於零股最低手續費", discount: 1, quantity: 5, price: 100, want: 1},
+		{name: "整股與零股分開適用最低手續費", discount: 1, quantity: 1050, price: 10, want: 21},
+		{name: "整股與零股分開計算", discount: 1, quantity: 1500, price: 100, want: 213},
+		{name: "手續費折扣", discount: 0.6, quantity: 1000, price: 100, want: 85},
+		{name: "股數為零", discount: 1, quantity: 0, price: 100, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewTaiwanTransactionCostModel()
+			model.FeeDiscount = tt.discount
+
+			if got := model.Fee(tt.quantity, tt.price); got != tt.want {
+				t.Errorf("手續費期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTransactionCostModel_Tax(t *testing.T) {
+	stock := &StockSymbol{Symbol: "2330", Market: "TWSE"}
+	etf := &StockSymbol{Symbol: "0050", Market: "TWSE"}
+
+	tests := []struct {
+		name             string
+		symbol           *StockSymbol
+		quantity         int64
+		dayTradeQuantity int64
+		price            float64
+		want             float64
+	}{
+		{name: "股票", symbol: stock, quantity: 1000, price: 100, want: 300},
+		{name: "ETF", symbol: etf, quantity: 1000, price: 100, want: 100},
+		{name: "ETF 當沖仍適用 ETF 稅率", symbol: etf, quantity: 1000, dayTradeQuantity: 1000, price: 100, want: 100},
+		{name: "股票當沖", symbol: stock, quantity: 1000, dayTradeQuantity: 1000, price: 100, want: 150},
+		{name: "部分當沖分開計算", symbol: stock, quantity: 1000, dayTradeQuantity: 400, price: 100, want: 240},
+		{name: "當沖股數超過賣出股數", symbol: stock, quantity: 1000, dayTradeQuantity: 2000, price: 100, want: 150},
+		{name: "稅額未滿一元捨去", symbol: stock, quantity: 1000, price: 10.05, want: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewTaiwanTransactionCostModel()
+
+			if got := model.Tax(tt.symbol, tt.quantity, tt.dayTradeQuantity, tt.price); got != tt.want {
+				t.Errorf("證交稅期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package entity
+
+import (
+	"time"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// DefaultPreferenceTimezone 使用者未設定時區時使用的時區
+const DefaultPreferenceTimezone = "Asia/Taipei"
+
+// MaxDailyPushesLimit 每日推播上限可設定的最大值
+const MaxDailyPushesLimit = 100
+
+// quietHoursLayout 勿擾時段的時間格式
+const quietHoursLayout = "15:04"
+
+// UserPreference 使用者推播偏好設定
+type UserPreference struct {
+	ID     uint
+	UserID uint
+	// QuietHoursStart 勿擾開始時間（HH:MM），與 QuietHoursEnd 皆為空代表不啟用；可跨越午夜，例如 22:00～07:00
+	QuietHoursStart string
+	// QuietHoursEnd 勿擾結束時間（HH:MM）
+	QuietHoursEnd string
+	// Timezone 解讀勿擾時段及計算每日推播上限所用的 IANA 時區
+	Timezone string
+	// Language 偏好語言
+	Language valueobject.Language
+	// DigestEnabled 是否啟用每日摘要，由每日摘要訂閱（項目 6）決定，不另外儲存
+	DigestEnabled bool
+	// MaxDailyPushes 每日最多推播則數，0 代表不限制
+	MaxDailyPushes int
+}
+
+// NewDefaultUserPreference 建立使用者的預設偏好設定
+func NewDefaultUserPreference(userID uint, timezone string) *UserPreference {
+	if _, err := time.LoadLocation(timezone); timezone == "" || err != nil {
+		timezone = DefaultPreferenceTimezone
+	}
+	return &UserPreference{
+		UserID:   userID,
+		Timezone: timezone,
+		Language: valueobject.LanguageTraditionalChinese,
+	}
+}
+
+// Validate 驗證偏好設定
+func (p *UserPreference) Validate() error {
+	if p.UserID == 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	if (p.QuietHoursStart == "") != (p.QuietHoursEnd == "") {
+		return domainerror.ErrInvalidArgument
+	}
+	if p.HasQuietHours() {
+		if _, ok := parseClock(p.QuietHoursStart); !ok {
+			return domainerror.ErrInvalidArgument
+		}
+		if _, ok := parseClock(p.QuietHoursEnd); !ok {
+			return domainerror.ErrInvalidArgument
+		}
+		if p.QuietHoursStart == p.QuietHoursEnd {
+			return domainerror.ErrInvalidArgument
+		}
+	}
+	if _, err := time.LoadLocation(p.Timezone); p.Timezone == "" || err != nil {
+		return domainerror.ErrInvalidArgument
+	}
+	if !p.Language.IsValid() {
+		return domainerror.ErrInvalidArgument
+	}
+	if p.MaxDailyPushes < 0 || p.MaxDailyPushes > MaxDailyPushesLimit {
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
+
+// HasQuietHours 是否設定勿擾時段
+func (p *UserPreference) HasQuietHours() bool {
+	return p.QuietHoursStart != "" && p.QuietHoursEnd != ""
+}
+
+// HasDailyLimit 是否設定每日推播上限
+func (p *UserPreference) HasDailyLimit() bool {
+	return p.MaxDailyPushes > 0
+}
+
+// Location 取得偏好時區，無法解析時使用預設時區
+func (p *UserPreference) Location() *time.Location {
+	if location, err := time.LoadLocation(p.Timezone); p.Timezone != "" && err == nil {
+		return location
+	}
+	location, err := time.LoadLocation(DefaultPreferenceTimezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// StartOfDay 取得 now 在偏好時區當天的開始時間，用於計算每日推播上限
+func (p *UserPreference) StartOfDay(now time.Time) time.Time {
+	local := now.In(p.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// QuietHoursEndAfter 若 now 位於勿擾時段內，回傳勿擾結束的時間；不在勿擾時段時回傳 false
+func (p *UserPreference) QuietHoursEndAfter(now time.Time) (time.Time, bool) {
+	if !p.HasQuietHours() {
+		return time.Time{}, false
+	}
+	start, okStart := parseClock(p.QuietHoursStart)
+	end, okEnd := parseClock(p.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return time.Time{}, false
+	}
+
+	local := now.In(p.Location())
+	current := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+
+	if start < end {
+		// 同一天內的時段，例如 12:00～14:00
+		if current >= start && current < end {
+			return midnight.Add(end), true
+		}
+		return time.Time{}, false
+	}
+
+	// 跨越午夜的時段，例如 22:00～07:00
+	if current >= start {
+		return midnight.AddDate(0, 0, 1).Add(end), true
+	}
+	if current < end {
+		return midnight.Add(end), true
+	}
+	return time.Time{}, false
+}
+
+// parseClock 將 HH:MM 轉為距離午夜的時間
+func parseClock(clock string) (time.Duration, bool) {
+	t, err := time.Parse(quietHoursLayout, clock)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// NormalizeClock 將 H:MM 或 HH:MM 轉為 HH:MM，格式錯誤時回傳 false
+func NormalizeClock(clock string) (string, bool) {
+	t, err := time.Parse(quietHoursLayout, clock)
+	if err != nil {
+		return "", false
+	}
+	return t.Format(quietHoursLayout), true
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserPreference_QuietHoursEndAfter(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		timezone  string
+		now       time.Time
+		wantQuiet bool
+		wantUntil time.Time
+	}{
+		{
+			name:      "未設定勿擾時段",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 14, 23, 0, 0, 0, taipei),
+			wantQuiet: false,
+		},
+		{
+			name:      "同一天內的時段之中",
+			start:     "12:00",
+			end:       "14:00",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 14, 13, 30, 0, 0, taipei),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 10, 14, 14, 0, 0, 0, taipei),
+		},
+		{
+			name:      "同一天內的時段結束時不在勿擾時段",
+			start:     "12:00",
+			end:       "14:00",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 14, 14, 0, 0, 0, taipei),
+			wantQuiet: false,
+		},
+		{
+			name:      "跨越午夜的時段於午夜前延到隔天",
+			start:     "22:00",
+			end:       "07:00",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 14, 23, 15, 0, 0, taipei),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 10, 15, 7, 0, 0, 0, taipei),
+		},
+		{
+			name:      "跨越午夜的時段於午夜後延到當天",
+			start:     "22:00",
+			end:       "07:00",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 15, 6, 59, 0, 0, taipei),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 10, 15, 7, 0, 0, 0, taipei),
+		},
+		{
+			name:      "跨越午夜的時段開始時即為勿擾",
+			start:     "22:00",
+			end:       "07:00",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 14, 22, 0, 0, 0, taipei),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 10, 15, 7, 0, 0, 0, taipei),
+		},
+		{
+			name:      "跨越午夜的時段之外",
+			start:     "22:00",
+			end:       "07:00",
+			timezone:  "Asia/Taipei",
+			now:       time.Date(2026, 10, 14, 12, 0, 0, 0, taipei),
+			wantQuiet: false,
+		},
+		{
+			name:     "依偏好時區判斷",
+			start:    "22:00",
+			end:      "07:00",
+			timezone: "America/New_York",
+			// 台北 10/15 11:00 為紐約 10/14 23:00
+			now:       time.Date(2026, 10, 15, 11, 0, 0, 0, taipei),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 10, 15, 7, 0, 0, 0, newYork),
+		},
+		{
+			name:     "偏好時區不在勿擾時段",
+			start:    "22:00",
+			end:      "07:00",
+			timezone: "America/New_York",
+			// 台北 10/14 23:00 為紐約 10/14 11:00
+			now:       time.Date(2026, 10, 14, 23, 0, 0, 0, taipei),
+			wantQuiet: false,
+		},
+		{
+			name:      "無法解析的時區使用預設時區",
+			start:     "22:00",
+			end:       "07:00",
+			timezone:  "Mars/Olympus",
+			now:       time.Date(2026, 10, 14, 23, 0, 0, 0, taipei),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 10, 15, 7, 0, 0, 0, taipei),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preference := &UserPreference{UserID: 1, QuietHoursStart: tt.start, QuietHoursEnd: tt.end, Timezone: tt.timezone}
+
+			until, quiet := preference.QuietHoursEndAfter(tt.now)
+			if quiet != tt.wantQuiet {
+				t.Fatalf("勿擾時段期望 %v，實際 %v", tt.wantQuiet, quiet)
+			}
+			if quiet && !until.Equal(tt.wantUntil) {
+				t.Errorf("勿擾結束時間期望 %v，實際 %v", tt.wantUntil, until)
+			}
+		})
+	}
+}
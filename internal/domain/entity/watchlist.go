@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+)
+
+// DefaultWatchlistName 未指定清單名稱時使用的預設觀察清單
+const DefaultWatchlistName = "預設"
+
+// MaxWatchlistNameLength 觀察清單名稱長度上限（字元數）
+const MaxWatchlistNameLength = 20
+
+// Watchlist 使用者的具名觀察清單
+type Watchlist struct {
+	ID     uint
+	UserID uint
+	Name   string
+	// SortOrder 清單在使用者所有清單中的排序，數字越小越前面
+	SortOrder int
+	Items     []*WatchlistItem
+}
+
+// WatchlistItem 觀察清單中的股票
+type WatchlistItem struct {
+	ID          uint
+	WatchlistID uint
+	SymbolID    uint
+	// SortOrder 股票在清單中的排序，從 1 開始
+	SortOrder   int
+	StockSymbol *StockSymbol
+}
+
+// ValidateWatchlistName 驗證觀察清單名稱，名稱不可含空白以便於指令解析
+func ValidateWatchlistName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > MaxWatchlistNameLength {
+		return domainerror.ErrInvalidArgument
+	}
+	if strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return domainerror.ErrInvalidArgument
+	}
+	return nil
+}
+
+// HasSymbol 檢查清單中是否已有指定股票
+func (w *Watchlist) HasSymbol(symbolID uint) bool {
+	return w.FindItem(symbolID) != nil
+}
+
+// FindItem 取得清單中指定股票的項目
+func (w *Watchlist) FindItem(symbolID uint) *WatchlistItem {
+	for _, item := range w.Items {
+		if item.SymbolID == symbolID {
+			return item
+		}
+	}
+	return nil
+}
+
+// NextSortOrder 取得新加入股票的排序值（排在最後）
+func (w *Watchlist) NextSortOrder() int {
+	next := 1
+	for _, item := range w.Items {
+		if item.SortOrder >= next {
+			next = item.SortOrder + 1
+		}
+	}
+	return next
+}
+
+// MoveItem 將股票移動到指定位置（從 1 開始），並重新編排所有項目的排序值
+func (w *Watchlist) MoveItem(symbolID uint, position int) error {
+	index := -1
+	for i, item := range w.Items {
+		if item.SymbolID == symbolID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return domainerror.ErrNotFound
+	}
+	if position < 1 {
+		return domainerror.ErrInvalidArgument
+	}
+	if position > len(w.Items) {
+		position = len(w.Items)
+	}
+
+	item := w.Items[index]
+	items := append(w.Items[:index:index], w.Items[index+1:]...)
+	items = append(items[:position-1], append([]*WatchlistItem{item}, items[position-1:]...)...)
+
+	for i, it := range items {
+		it.SortOrder = i + 1
+	}
+	w.Items = items
+	return nil
+}
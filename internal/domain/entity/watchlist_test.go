@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
+)
+
+func testWatchlist(symbolIDs ...uint) *Watchlist {
+	watchlist := &Watchlist{ID: 1, Name: DefaultWatchlistName}
+	for i, symbolID := range symbolIDs {
+		watchlist.Items = append(watchlist.Items, &WatchlistItem{WatchlistID: 1, SymbolID: symbolID, SortOrder: i + 1})
+	}
+	return watchlist
+}
+
+func TestWatchlist_MoveItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		symbolID uint
+		position int
+		want     []uint
+		wantErr  error
+	}{
+		{name: "移到第一位", symbolID: 3, position: 1, want: []uint{3, 1, 2, 4}},
+		{name: "移到最後一位", symbolID: 1, position: 4, want: []uint{2, 3, 4, 1}},
+		{name: "移到中間", symbolID: 4, position: 2, want: []uint{1, 4, 2, 3}},
+		{name: "位置不變", symbolID: 2, position: 2, want: []uint{1, 2, 3, 4}},
+		{name: "超過清單長度時移到最後", symbolID: 2, position: 10, want: []uint{1, 3, 4, 2}},
+		{name: "位置小於 1", symbolID: 2, position: 0, want: []uint{1, 2, 3, 4}, wantErr: domainerror.ErrInvalidArgument},
+		{name: "股票不在清單中", symbolID: 9, position: 1, want: []uint{1, 2, 3, 4}, wantErr: domainerror.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchlist := testWatchlist(1, 2, 3, 4)
+
+			err := watchlist.MoveItem(tt.symbolID, tt.position)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("錯誤期望 %v，實際 %v", tt.wantErr, err)
+			}
+
+			got := make([]uint, len(watchlist.Items))
+			for i, item := range watchlist.Items {
+				got[i] = item.SymbolID
+				if item.SortOrder != i+1 {
+					t.Errorf("第 %d 個項目排序期望 %d，實際 %d", i+1, i+1, item.SortOrder)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("順序期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWatchlist_NextSortOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		sortOrders []int
+		want       int
+	}{
+		{name: "空清單", want: 1},
+		{name: "連續排序", sortOrders: []int{1, 2, 3}, want: 4},
+		{name: "排序有缺號時排在最大值之後", sortOrders: []int{5, 2}, want: 6},
+		{name: "舊資料排序為 0", sortOrders: []int{0, 0}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchlist := &Watchlist{}
+			for _, sortOrder := range tt.sortOrders {
+				watchlist.Items = append(watchlist.Items, &WatchlistItem{SortOrder: sortOrder})
+			}
+			if got := watchlist.NextSortOrder(); got != tt.want {
+				t.Errorf("期望 %d，實際 %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateWatchlistName(t *testing.T) {
+	tests := []struct {
+		name      string
+		listName  string
+		wantValid bool
+	}{
+		{name: "中文名稱", listName: "半導體", wantValid: true},
+		{name: "名稱長度上限", listName: strings.Repeat("股", MaxWatchlistNameLength), wantValid: true},
+		{name: "空白名稱", listName: ""},
+		{name: "超過長度上限", listName: strings.Repeat("股", MaxWatchlistNameLength+1)},
+		{name: "包含空白", listName: "長期 持有"},
+		{name: "包含全形空白", listName: "長期　持有"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWatchlistName(tt.listName)
+			if (err == nil) != tt.wantValid {
+				t.Errorf("期望有效 %v，實際錯誤 %v", tt.wantValid, err)
+			}
+		})
+	}
+}
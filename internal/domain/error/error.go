@@ -95,6 +95,16 @@ func NewDuplicateSubscriptionError(userID, featureID uint) *DomainError {
 	}
 }
 
+// 持股相關錯誤
+
+// NewInsufficientHoldingError 建立持股不足錯誤
+func NewInsufficientHoldingError(symbol string, holding, quantity int64) *DomainError {
+	return &DomainError{
+		Code:    "INSUFFICIENT_HOLDING",
+		Message: fmt.Sprintf("%s 持股不足，目前持有 %d 股，欲賣出 %d 股", symbol, holding, quantity),
+	}
+}
+
 // IsNotFound 檢查錯誤是否為資源不存在錯誤
 func IsNotFound(err error) bool {
 	var domainErr *DomainError
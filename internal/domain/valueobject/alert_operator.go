@@ -0,0 +1,37 @@
+package valueobject
+
+import "errors"
+
+// AlertOperator 價格提醒比較運算子
+type AlertOperator string
+
+const (
+	AlertOperatorAbove AlertOperator = ">"
+	AlertOperatorBelow AlertOperator = "<"
+)
+
+// NewAlertOperator 建立並驗證價格提醒運算子
+func NewAlertOperator(value string) (AlertOperator, error) {
+	op := AlertOperator(value)
+	if !op.IsValid() {
+		return "", errors.New("invalid alert operator")
+	}
+	return op, nil
+}
+
+// IsValid 驗證運算子是否有效
+func (a AlertOperator) IsValid() bool {
+	return a == AlertOperatorAbove || a == AlertOperatorBelow
+}
+
+// GetName 回傳運算子名稱
+func (a AlertOperator) GetName() string {
+	switch a {
+	case AlertOperatorAbove:
+		return "高於"
+	case AlertOperatorBelow:
+		return "低於"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,49 @@
+package valueobject
+
+// AlertType 提醒類型
+type AlertType int
+
+const (
+	// AlertTypePrice 收盤價突破/跌破目標價
+	AlertTypePrice AlertType = iota + 1
+	// AlertTypeMove 收盤價相對前一交易日收盤價漲跌超過門檻
+	AlertTypeMove
+	// AlertTypeGap 開盤價相對前一交易日收盤價跳空超過門檻
+	AlertTypeGap
+)
+
+// AlertTypeMap mapping table for alert types
+var AlertTypeMap = map[string]AlertType{
+	"move": AlertTypeMove,
+	"gap":  AlertTypeGap,
+}
+
+// ParseAlertType parses alert type from input string
+func ParseAlertType(input string) (AlertType, bool) {
+	alertType, exists := AlertTypeMap[input]
+	return alertType, exists
+}
+
+// IsValid 驗證提醒類型是否有效
+func (a AlertType) IsValid() bool {
+	return a >= AlertTypePrice && a <= AlertTypeGap
+}
+
+// IsPercentage 是否為百分比門檻類型的提醒
+func (a AlertType) IsPercentage() bool {
+	return a == AlertTypeMove || a == AlertTypeGap
+}
+
+// GetName 回傳提醒類型名稱
+func (a AlertType) GetName() string {
+	switch a {
+	case AlertTypePrice:
+		return "價格提醒"
+	case AlertTypeMove:
+		return "漲跌幅提醒"
+	case AlertTypeGap:
+		return "跳空提醒"
+	default:
+		return "Unknown"
+	}
+}
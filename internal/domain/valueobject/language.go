@@ -0,0 +1,41 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+)
+
+// Language 使用者偏好語言
+type Language string
+
+const (
+	LanguageTraditionalChinese Language = "zh-TW"
+	LanguageEnglish            Language = "en"
+)
+
+// NewLanguage 建立並驗證偏好語言，不區分大小寫
+func NewLanguage(value string) (Language, error) {
+	for _, language := range []Language{LanguageTraditionalChinese, LanguageEnglish} {
+		if strings.EqualFold(string(language), value) {
+			return language, nil
+		}
+	}
+	return "", errors.New("invalid language")
+}
+
+// IsValid 驗證偏好語言是否有效
+func (l Language) IsValid() bool {
+	return l == LanguageTraditionalChinese || l == LanguageEnglish
+}
+
+// GetName 回傳偏好語言名稱
+func (l Language) GetName() string {
+	switch l {
+	case LanguageTraditionalChinese:
+		return "繁體中文"
+	case LanguageEnglish:
+		return "English"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,38 @@
+package valueobject
+
+// NotificationDeliveryStatus 通知投遞狀態
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryStatusQueued NotificationDeliveryStatus = "queued"
+	NotificationDeliveryStatusSent   NotificationDeliveryStatus = "sent"
+	NotificationDeliveryStatusFailed NotificationDeliveryStatus = "failed"
+	// NotificationDeliveryStatusSkipped 依使用者偏好設定不送出（例如已達每日推播上限）
+	NotificationDeliveryStatusSkipped NotificationDeliveryStatus = "skipped"
+)
+
+// IsValid 驗證投遞狀態是否有效
+func (s NotificationDeliveryStatus) IsValid() bool {
+	switch s {
+	case NotificationDeliveryStatusQueued, NotificationDeliveryStatusSent, NotificationDeliveryStatusFailed, NotificationDeliveryStatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetName 回傳投遞狀態名稱
+func (s NotificationDeliveryStatus) GetName() string {
+	switch s {
+	case NotificationDeliveryStatusQueued:
+		return "排隊中"
+	case NotificationDeliveryStatusSent:
+		return "已送出"
+	case NotificationDeliveryStatusFailed:
+		return "失敗"
+	case NotificationDeliveryStatusSkipped:
+		return "已略過"
+	default:
+		return "Unknown"
+	}
+}
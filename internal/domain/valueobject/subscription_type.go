@@ -11,6 +11,8 @@ const (
 	SubscriptionTypeStockNews       SubscriptionType = 2
 	SubscriptionTypeDailyMarketInfo SubscriptionType = 3
 	SubscriptionTypeTopVolumeItems  SubscriptionType = 4
+	SubscriptionTypeExDividend      SubscriptionType = 5
+	SubscriptionTypeDailyDigest     SubscriptionType = 6
 )
 
 // NewSubscriptionType 建立並驗證訂閱類型
@@ -29,6 +31,8 @@ var SubscriptionTypeMap = map[string]SubscriptionType{
 	"2": SubscriptionTypeStockNews,
 	"3": SubscriptionTypeDailyMarketInfo,
 	"4": SubscriptionTypeTopVolumeItems,
+	"5": SubscriptionTypeExDividend,
+	"6": SubscriptionTypeDailyDigest,
 }
 
 // GetName returns the name of the subscription type
@@ -44,11 +48,26 @@ func (s SubscriptionType) GetName() string {
 		return "每日大盤資訊"
 	case SubscriptionTypeTopVolumeItems:
 		return "交易量前20名"
+	case SubscriptionTypeExDividend:
+		return "除權息提醒"
+	case SubscriptionTypeDailyDigest:
+		return "每日摘要"
 	default:
 		return "Default"
 	}
 }
 
+// IsSymbolBased 推播內容是否依訂閱的個股而不同；大盤資訊、成交量排行等對所有股票內容相同
+func (s SubscriptionType) IsSymbolBased() bool {
+	return s == SubscriptionTypeStockInfo || s == SubscriptionTypeStockNews || s == SubscriptionTypeExDividend
+}
+
+// IsIncludedInDigest 訂閱每日摘要的使用者不再個別推播的項目，改由每日摘要合併送出
+func (s SubscriptionType) IsIncludedInDigest() bool {
+	return s == SubscriptionTypeStockInfo || s == SubscriptionTypeStockNews ||
+		s == SubscriptionTypeDailyMarketInfo || s == SubscriptionTypeTopVolumeItems
+}
+
 // ParseSubscriptionType parses subscription type from input string
 func ParseSubscriptionType(input string) (SubscriptionType, bool) {
 	item, exists := SubscriptionTypeMap[input]
@@ -57,7 +76,7 @@ func ParseSubscriptionType(input string) (SubscriptionType, bool) {
 
 // IsValid 驗證訂閱類型是否有效
 func (s SubscriptionType) IsValid() bool {
-	return s >= SubscriptionTypeDefault && s <= SubscriptionTypeTopVolumeItems
+	return s >= SubscriptionTypeDefault && s <= SubscriptionTypeDailyDigest
 }
 
 // Equals 比較兩個訂閱類型是否相等
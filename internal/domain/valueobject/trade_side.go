@@ -0,0 +1,37 @@
+package valueobject
+
+import "errors"
+
+// TradeSide 交易方向
+type TradeSide string
+
+const (
+	TradeSideBuy  TradeSide = "buy"
+	TradeSideSell TradeSide = "sell"
+)
+
+// NewTradeSide 建立並驗證交易方向
+func NewTradeSide(value string) (TradeSide, error) {
+	side := TradeSide(value)
+	if !side.IsValid() {
+		return "", errors.New("invalid trade side")
+	}
+	return side, nil
+}
+
+// IsValid 驗證交易方向是否有效
+func (t TradeSide) IsValid() bool {
+	return t == TradeSideBuy || t == TradeSideSell
+}
+
+// GetName 回傳交易方向名稱
+func (t TradeSide) GetName() string {
+	switch t {
+	case TradeSideBuy:
+		return "買進"
+	case TradeSideSell:
+		return "賣出"
+	default:
+		return "Unknown"
+	}
+}
@@ -2,11 +2,14 @@ package formatter
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 	"github.com/tian841224/stock-bot/internal/domain/valueobject"
 	"github.com/tian841224/stock-bot/pkg/formatter"
 	"github.com/tian841224/stock-bot/pkg/utils"
@@ -225,45 +228,51 @@ func (f *formatterAdapter) FormatStockPrice(data *dto.StockPrice, userType value
 		emoji = ""
 	}
 
+	// 美股以美元計價，且沒有成交筆數資料
+	unit := ""
+	transactions := fmt.Sprintf("\n成交筆數：%s 張", strconv.FormatInt(data.Transactions, 10))
+	if data.Currency == "USD" {
+		unit = " USD"
+		transactions = ""
+	}
+
 	if userType == valueobject.UserTypeTelegram {
 		return fmt.Sprintf(`<b>%s</b>
 			<b>─── %s (%s) %s ───</b><code>
-開盤價：%.2f
-收盤價：%.2f
+開盤價：%.2f%s
+收盤價：%.2f%s
 漲跌幅：%.2f (%.2f%%)
-最高價：%.2f
-最低價：%.2f
-交易量：%s
-成交筆數：%s 張
+最高價：%.2f%s
+最低價：%.2f%s
+交易量：%s%s
 		</code>`,
 			displayDate,
 			data.Name, data.Symbol, emoji,
-			data.OpenPrice,
-			data.ClosePrice,
+			data.OpenPrice, unit,
+			data.ClosePrice, unit,
 			data.ChangeAmount, data.ChangeRate,
-			data.HighPrice,
-			data.LowPrice,
+			data.HighPrice, unit,
+			data.LowPrice, unit,
 			formatter.FormatAmountInt(data.Volume),
-			strconv.FormatInt(data.Transactions, 10))
+			transactions)
 	} else {
 		return fmt.Sprintf(`%s
 ─── %s (%s) %s ───
-開盤價：%.2f
-收盤價：%.2f
+開盤價：%.2f%s
+收盤價：%.2f%s
 漲跌幅：%.2f (%.2f%%)
-最高價：%.2f
-最低價：%.2f
-交易量：%s
-成交筆數：%s 張`,
+最高價：%.2f%s
+最低價：%.2f%s
+交易量：%s%s`,
 			displayDate,
 			data.Name, data.Symbol, emoji,
-			data.OpenPrice,
-			data.ClosePrice,
+			data.OpenPrice, unit,
+			data.ClosePrice, unit,
 			data.ChangeAmount, data.ChangeRate,
-			data.HighPrice,
-			data.LowPrice,
+			data.HighPrice, unit,
+			data.LowPrice, unit,
 			formatter.FormatAmountInt(data.Volume),
-			strconv.FormatInt(data.Transactions, 10))
+			transactions)
 	}
 }
 
@@ -324,18 +333,35 @@ func (f *formatterAdapter) FormatLineNewsMessage(news []dto.StockNews, stockName
 	return f.lineFormatter.FormatStockNews(news, stockName, symbol)
 }
 
+// FormatTelegramDailyDigest 格式化 Telegram 每日摘要訊息
+func (f *formatterAdapter) FormatTelegramDailyDigest(digest *dto.DailyDigest) string {
+	return f.telegramFormatter.FormatDailyDigest(digest)
+}
+
+// FormatLineDailyDigest 格式化 LINE 每日摘要訊息
+func (f *formatterAdapter) FormatLineDailyDigest(digest *dto.DailyDigest) *dto.LineDailyDigestMessage {
+	return f.lineFormatter.FormatDailyDigest(digest)
+}
+
 // FormatChartCaption 格式化圖表標題
 func (f *formatterAdapter) FormatChartCaption(stockName, symbol, chartType string) string {
 	return fmt.Sprintf("⚡️%s(%s)-%s", stockName, symbol, chartType)
 }
 
 // FormatSubscribed 格式化訂閱股票和項目
-func (f *formatterAdapter) FormatSubscribed(stocks []*dto.UserSubscriptionStock, items []*dto.UserSubscriptionItem) string {
+func (f *formatterAdapter) FormatSubscribed(stocks []*dto.UserSubscriptionStock, items []*dto.UserSubscriptionItem, userType valueobject.UserType) string {
+	bold := func(text string) string {
+		if userType == valueobject.UserTypeTelegram {
+			return "<b>" + text + "</b>"
+		}
+		return text
+	}
+
 	// 組合訊息
-	messageText := "📋 <b>您目前的訂閱項目</b>\n\n"
+	messageText := "📋 " + bold("您目前的訂閱項目") + "\n\n"
 
 	// 訂閱功能清單
-	messageText += "🔔 <b>已訂閱功能：</b>\n"
+	messageText += "🔔 " + bold("已訂閱功能：") + "\n"
 	if len(items) > 0 {
 		for _, sub := range items {
 			messageText += fmt.Sprintf("• %s\n", sub.Item.GetName())
@@ -345,7 +371,7 @@ func (f *formatterAdapter) FormatSubscribed(stocks []*dto.UserSubscriptionStock,
 	}
 
 	// 訂閱股票清單
-	messageText += "\n📈 <b>已訂閱股票：</b>\n"
+	messageText += "\n📈 " + bold("已訂閱股票：") + "\n"
 	if len(stocks) > 0 {
 		for _, stock := range stocks {
 			if stock.Status {
@@ -358,3 +384,629 @@ func (f *formatterAdapter) FormatSubscribed(stocks []*dto.UserSubscriptionStock,
 
 	return messageText
 }
+
+// FormatPriceAlertList 格式化價格提醒列表
+func (f *formatterAdapter) FormatPriceAlertList(alerts []*dto.PriceAlert, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("⏰ <b>您目前的價格提醒</b>\n\n")
+	} else {
+		message.WriteString("⏰ 您目前的價格提醒\n\n")
+	}
+
+	if len(alerts) == 0 {
+		message.WriteString("• 尚未設定任何提醒\n")
+		return message.String()
+	}
+
+	for _, alert := range alerts {
+		status := "監控中"
+		if alert.Triggered {
+			status = "已觸發"
+		}
+
+		condition := fmt.Sprintf("%s %.2f", alert.Operator.GetName(), alert.TargetPrice)
+		if alert.AlertType.IsPercentage() {
+			condition = fmt.Sprintf("%s ±%.2f%%", alert.AlertType.GetName(), alert.ThresholdPercent)
+		}
+
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("#%d <b>%s (%s)</b> %s <code>%s</code>\n", alert.ID, alert.Name, alert.Symbol, condition, status))
+		} else {
+			message.WriteString(fmt.Sprintf("#%d %s (%s) %s [%s]\n", alert.ID, alert.Name, alert.Symbol, condition, status))
+		}
+	}
+
+	return message.String()
+}
+
+// FormatPriceAlertTriggered 格式化價格提醒觸發訊息，依使用者偏好語言顯示
+func (f *formatterAdapter) FormatPriceAlertTriggered(alert *dto.PriceAlert, price *dto.StockPrice, userType valueobject.UserType, language valueobject.Language) string {
+	if alert.AlertType.IsPercentage() {
+		return f.formatPercentageAlertTriggered(alert, price, userType, language)
+	}
+
+	emoji := "📈"
+	if alert.Operator == valueobject.AlertOperatorBelow {
+		emoji = "📉"
+	}
+
+	title, closeLabel, targetLabel, changeLabel, dateLabel := "價格提醒", "收盤價：", "目標價", "漲跌幅：", "日期："
+	operator := alert.Operator.GetName()
+	if language == valueobject.LanguageEnglish {
+		title, closeLabel, targetLabel, changeLabel, dateLabel = "Price Alert", "Close: ", "target", "Change: ", "Date: "
+		operator = englishAlertOperatorName(alert.Operator)
+	}
+
+	if userType == valueobject.UserTypeTelegram {
+		return fmt.Sprintf(`⏰ <b>%s</b> %s
+<b>%s (%s)</b><code>
+%s%.2f %s %s %.2f
+%s%.2f (%.2f%%)
+%s%s
+</code>`,
+			title, emoji,
+			alert.Name, alert.Symbol,
+			closeLabel, price.ClosePrice, operator, targetLabel, alert.TargetPrice,
+			changeLabel, price.ChangeAmount, price.ChangeRate,
+			dateLabel, price.Date.Format("2006/01/02"))
+	}
+
+	return fmt.Sprintf(`⏰ %s %s
+%s (%s)
+%s%.2f %s %s %.2f
+%s%.2f (%.2f%%)
+%s%s`,
+		title, emoji,
+		alert.Name, alert.Symbol,
+		closeLabel, price.ClosePrice, operator, targetLabel, alert.TargetPrice,
+		changeLabel, price.ChangeAmount, price.ChangeRate,
+		dateLabel, price.Date.Format("2006/01/02"))
+}
+
+// formatPercentageAlertTriggered 格式化漲跌幅/跳空提醒，例如「2330 +7.30% vs 2025-03-14」
+func (f *formatterAdapter) formatPercentageAlertTriggered(alert *dto.PriceAlert, price *dto.StockPrice, userType valueobject.UserType, language valueobject.Language) string {
+	basePrice := price.ClosePrice
+	priceLabel := "收盤價："
+	if alert.AlertType == valueobject.AlertTypeGap {
+		basePrice = price.OpenPrice
+		priceLabel = "開盤價："
+	}
+
+	changePercent := 0.0
+	if price.PrevClosePrice != 0 {
+		changePercent = (basePrice - price.PrevClosePrice) / price.PrevClosePrice * 100
+	}
+
+	emoji := "📈"
+	if changePercent < 0 {
+		emoji = "📉"
+	}
+
+	title, prevCloseLabel, dateLabel := alert.AlertType.GetName(), "前一交易日收盤價：", "日期："
+	if language == valueobject.LanguageEnglish {
+		title, prevCloseLabel, dateLabel = englishAlertTypeName(alert.AlertType), "Previous close: ", "Date: "
+		priceLabel = "Close: "
+		if alert.AlertType == valueobject.AlertTypeGap {
+			priceLabel = "Open: "
+		}
+	}
+
+	summary := fmt.Sprintf("%s %+.2f%% vs %s", alert.Symbol, changePercent, price.PrevTradeDate.Format("2006-01-02"))
+
+	if userType == valueobject.UserTypeTelegram {
+		return fmt.Sprintf(`⏰ <b>%s</b> %s
+<b>%s</b>
+%s<code>
+%s%.2f
+%s%.2f
+%s%s
+</code>`,
+			title, emoji,
+			summary,
+			alert.Name,
+			priceLabel, basePrice,
+			prevCloseLabel, price.PrevClosePrice,
+			dateLabel, price.Date.Format("2006/01/02"))
+	}
+
+	return fmt.Sprintf(`⏰ %s %s
+%s
+%s
+%s%.2f
+%s%.2f
+%s%s`,
+		title, emoji,
+		summary,
+		alert.Name,
+		priceLabel, basePrice,
+		prevCloseLabel, price.PrevClosePrice,
+		dateLabel, price.Date.Format("2006/01/02"))
+}
+
+// englishAlertOperatorName 價格提醒比較條件的英文名稱
+func englishAlertOperatorName(operator valueobject.AlertOperator) string {
+	if operator == valueobject.AlertOperatorBelow {
+		return "below"
+	}
+	return "above"
+}
+
+// englishAlertTypeName 提醒類型的英文名稱
+func englishAlertTypeName(alertType valueobject.AlertType) string {
+	switch alertType {
+	case valueobject.AlertTypeMove:
+		return "Move Alert"
+	case valueobject.AlertTypeGap:
+		return "Gap Alert"
+	default:
+		return "Price Alert"
+	}
+}
+
+// FormatWatchlist 格式化觀察清單報價表，每個清單一張表、每檔股票一列
+func (f *formatterAdapter) FormatWatchlist(watchlists []*dto.Watchlist, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("👀 <b>觀察清單</b>\n")
+	} else {
+		message.WriteString("👀 觀察清單\n")
+	}
+
+	if len(watchlists) == 0 {
+		message.WriteString("\n• 尚未建立任何觀察清單\n")
+		return message.String()
+	}
+
+	var latest time.Time
+	for _, watchlist := range watchlists {
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("\n📁 <b>%s</b>（%d）\n", watchlist.Name, len(watchlist.Quotes)))
+		} else {
+			message.WriteString(fmt.Sprintf("\n📁 %s（%d）\n", watchlist.Name, len(watchlist.Quotes)))
+		}
+
+		if len(watchlist.Quotes) == 0 {
+			message.WriteString("• 清單目前沒有股票\n")
+			continue
+		}
+
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString("<pre>")
+		}
+
+		message.WriteString(fmt.Sprintf("%-6s %9s %8s %8s %8s\n", "代號", "收盤", "漲跌", "幅度", "成交量"))
+		for _, quote := range watchlist.Quotes {
+			if !quote.HasQuote {
+				message.WriteString(fmt.Sprintf("%-6s %9s %8s %8s %8s\n", quote.Symbol, "-", "-", "-", "-"))
+				continue
+			}
+
+			message.WriteString(fmt.Sprintf("%-6s %9.2f %+8.2f %+7.2f%% %8s\n",
+				quote.Symbol,
+				quote.ClosePrice,
+				quote.ChangeAmount,
+				quote.ChangeRate,
+				formatter.FormatAmountInt(quote.Volume)))
+		}
+
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString("</pre>")
+		}
+
+		if date := latestWatchlistDate(watchlist.Quotes); date.After(latest) {
+			latest = date
+		}
+	}
+
+	if !latest.IsZero() {
+		message.WriteString(fmt.Sprintf("\n資料日期：%s", latest.Format("2006/01/02")))
+	}
+
+	return message.String()
+}
+
+// latestWatchlistDate 取得觀察清單中最新的報價日期
+func latestWatchlistDate(quotes []*dto.WatchlistQuote) time.Time {
+	var latest time.Time
+	for _, quote := range quotes {
+		if quote.HasQuote && quote.Date.After(latest) {
+			latest = quote.Date
+		}
+	}
+	return latest
+}
+
+// FormatPortfolio 格式化投資組合持股與損益
+func (f *formatterAdapter) FormatPortfolio(portfolio *dto.Portfolio, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("💼 <b>投資組合</b>\n")
+	} else {
+		message.WriteString("💼 投資組合\n")
+	}
+
+	if len(portfolio.Holdings) == 0 {
+		message.WriteString("\n• 目前沒有持股\n")
+		if portfolio.RealizedPnL != 0 {
+			message.WriteString(fmt.Sprintf("已實現損益：%s\n", formatSignedAmount(portfolio.RealizedPnL)))
+		}
+		return message.String()
+	}
+
+	for _, holding := range portfolio.Holdings {
+		emoji := "📈"
+		if holding.UnrealizedPnL < 0 {
+			emoji = "📉"
+		}
+
+		closePrice := "-"
+		if holding.HasQuote {
+			closePrice = fmt.Sprintf("%.2f", holding.ClosePrice)
+		}
+
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("\n%s<b>%s (%s)</b><code>\n", emoji, holding.Name, holding.Symbol))
+		} else {
+			message.WriteString(fmt.Sprintf("\n%s%s (%s)\n", emoji, holding.Name, holding.Symbol))
+		}
+		message.WriteString(fmt.Sprintf("持股：%s 股\n", utils.FormatNumberWithCommas(holding.Quantity)))
+		message.WriteString(fmt.Sprintf("均價：%.2f　現價：%s\n", holding.AverageCost, closePrice))
+		message.WriteString(fmt.Sprintf("市值：%s\n", utils.FormatFloatWithCommas(holding.MarketValue, 0)))
+		message.WriteString(fmt.Sprintf("損益：%s (%+.2f%%)\n", formatSignedAmount(holding.UnrealizedPnL), holding.UnrealizedPnLRate))
+		message.WriteString(fmt.Sprintf("權重：%.2f%%\n", holding.Weight))
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString("</code>")
+		}
+	}
+
+	summary := fmt.Sprintf("總成本：%s\n總市值：%s\n預估賣出費用：%s\n未實現損益：%s (%+.2f%%)\n已實現損益：%s\n累計現金股利：%s\n累計手續費：%s\n累計證交稅：%s",
+		utils.FormatFloatWithCommas(portfolio.TotalCost, 0),
+		utils.FormatFloatWithCommas(portfolio.MarketValue, 0),
+		utils.FormatFloatWithCommas(portfolio.EstimatedSellCost, 0),
+		formatSignedAmount(portfolio.UnrealizedPnL), portfolio.UnrealizedPnLRate,
+		formatSignedAmount(portfolio.RealizedPnL),
+		utils.FormatFloatWithCommas(portfolio.CashDividends, 0),
+		utils.FormatFloatWithCommas(portfolio.TotalFees, 0),
+		utils.FormatFloatWithCommas(portfolio.TotalTax, 0))
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString(fmt.Sprintf("\n<b>─── 總計 ───</b>\n<code>%s</code>", summary))
+	} else {
+		message.WriteString(fmt.Sprintf("\n─── 總計 ───\n%s", summary))
+	}
+
+	if !portfolio.PriceDate.IsZero() {
+		message.WriteString(fmt.Sprintf("\n\n報價日期：%s", portfolio.PriceDate.Format("2006/01/02")))
+	}
+	message.WriteString("\n損益已扣除手續費及證交稅，可使用 /pf fee 調整券商折扣")
+
+	return message.String()
+}
+
+// FormatTradingSetting 格式化交易成本設定
+func (f *formatterAdapter) FormatTradingSetting(setting *dto.TradingSetting, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("⚙️ <b>交易成本設定</b>\n<code>")
+	} else {
+		message.WriteString("⚙️ 交易成本設定\n")
+	}
+
+	message.WriteString(fmt.Sprintf("手續費率：%.4f%%\n", setting.FeeRate*100))
+	message.WriteString(fmt.Sprintf("手續費折扣：%.4g\n", setting.FeeDiscount))
+	message.WriteString(fmt.Sprintf("整股最低手續費：%.0f 元\n", setting.MinFee))
+	message.WriteString(fmt.Sprintf("零股最低手續費：%.0f 元\n", setting.OddLotMinFee))
+	message.WriteString(fmt.Sprintf("股票證交稅：%.2f%%\n", setting.StockTaxRate*100))
+	message.WriteString(fmt.Sprintf("ETF 證交稅：%.2f%%\n", setting.ETFTaxRate*100))
+	message.WriteString(fmt.Sprintf("當沖證交稅：%.2f%%", setting.DayTradeTaxRate*100))
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("</code>")
+	}
+	message.WriteString("\n\n使用 /pf fee 折扣 [最低手續費] 調整，例如：/pf fee 0.28 1")
+
+	return message.String()
+}
+
+// FormatPortfolioTrades 格式化投資組合交易紀錄
+func (f *formatterAdapter) FormatPortfolioTrades(trades []*dto.PortfolioTrade, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("🧾 <b>交易紀錄</b>\n\n")
+	} else {
+		message.WriteString("🧾 交易紀錄\n\n")
+	}
+
+	if len(trades) == 0 {
+		message.WriteString("• 尚未記錄任何交易\n")
+		return message.String()
+	}
+
+	for _, trade := range trades {
+		line := fmt.Sprintf("#%d %s %s %s(%s) %s 股 @ %.2f",
+			trade.ID,
+			trade.TradeDate.Format("2006-01-02"),
+			trade.Side.GetName(),
+			trade.Name, trade.Symbol,
+			utils.FormatNumberWithCommas(trade.Quantity),
+			trade.Price)
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("<code>%s</code>\n", line))
+		} else {
+			message.WriteString(line + "\n")
+		}
+	}
+
+	return message.String()
+}
+
+// FormatDividendIncome 格式化投資組合股利收入
+func (f *formatterAdapter) FormatDividendIncome(income *dto.DividendIncome, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString(fmt.Sprintf("💰 <b>%d 年股利收入</b>\n", income.Year))
+	} else {
+		message.WriteString(fmt.Sprintf("💰 %d 年股利收入\n", income.Year))
+	}
+
+	if len(income.Holdings) == 0 {
+		message.WriteString("\n• 目前沒有持股\n")
+		return message.String()
+	}
+
+	for _, holding := range income.Holdings {
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("\n<b>%s (%s)</b><code>\n", holding.Name, holding.Symbol))
+		} else {
+			message.WriteString(fmt.Sprintf("\n%s (%s)\n", holding.Name, holding.Symbol))
+		}
+		message.WriteString(fmt.Sprintf("持股：%s 股\n", utils.FormatNumberWithCommas(holding.Quantity)))
+		message.WriteString(fmt.Sprintf("今年已領：%s", utils.FormatFloatWithCommas(holding.YearToDateIncome, 0)))
+		if holding.YearToDateStockShares > 0 {
+			message.WriteString(fmt.Sprintf("（配股 %s 股）", utils.FormatNumberWithCommas(holding.YearToDateStockShares)))
+		}
+		message.WriteString("\n")
+		message.WriteString(fmt.Sprintf("近一年每股配息：%.4g\n", holding.TrailingCashDividend))
+		message.WriteString(fmt.Sprintf("預估年股利：%s (%.2f%%)\n", utils.FormatFloatWithCommas(holding.ProjectedAnnualIncome, 0), holding.YieldOnCost))
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString("</code>")
+		}
+	}
+
+	summary := fmt.Sprintf("今年已領：%s\n預估年股利：%s\n成本殖利率：%.2f%%",
+		utils.FormatFloatWithCommas(income.YearToDateIncome, 0),
+		utils.FormatFloatWithCommas(income.ProjectedAnnualIncome, 0),
+		income.YieldOnCost)
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString(fmt.Sprintf("\n<b>─── 總計 ───</b>\n<code>%s</code>", summary))
+	} else {
+		message.WriteString(fmt.Sprintf("\n─── 總計 ───\n%s", summary))
+	}
+	message.WriteString("\n\n今年已領以除息日認列；預估年股利以目前持股乘上近一年每股現金股利計算")
+
+	return message.String()
+}
+
+// FormatDividendCalendar 格式化個股除權息行事曆
+func (f *formatterAdapter) FormatDividendCalendar(calendar *dto.DividendCalendar, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString(fmt.Sprintf("📅 <b>%s (%s) 除權息行事曆</b>\n", calendar.Name, calendar.Symbol))
+	} else {
+		message.WriteString(fmt.Sprintf("📅 %s (%s) 除權息行事曆\n", calendar.Name, calendar.Symbol))
+	}
+
+	sections := []struct {
+		title     string
+		dividends []dto.StockDividend
+		empty     string
+	}{
+		{"即將除權息", calendar.Upcoming, "目前沒有已公告的除權息"},
+		{"歷史股利", calendar.History, "查無歷史股利資料"},
+	}
+	for _, section := range sections {
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("\n<b>%s</b>\n", section.title))
+		} else {
+			message.WriteString(fmt.Sprintf("\n%s\n", section.title))
+		}
+
+		if len(section.dividends) == 0 {
+			message.WriteString(fmt.Sprintf("• %s\n", section.empty))
+			continue
+		}
+		for _, dividend := range section.dividends {
+			line := formatDividendLine(dividend)
+			if userType == valueobject.UserTypeTelegram {
+				message.WriteString(fmt.Sprintf("<code>%s</code>\n", line))
+			} else {
+				message.WriteString(line + "\n")
+			}
+		}
+	}
+
+	return message.String()
+}
+
+// FormatExDividendReminder 格式化除權息前提醒
+func (f *formatterAdapter) FormatExDividendReminder(reminder *dto.ExDividendReminder, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	title := fmt.Sprintf("%s (%s) 將於 %d 個交易日後除權息", reminder.Name, reminder.Symbol, reminder.TradingDaysLeft)
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString(fmt.Sprintf("🔔 <b>%s</b>\n<code>%s</code>\n", title, formatDividendLine(reminder.Dividend)))
+	} else {
+		message.WriteString(fmt.Sprintf("🔔 %s\n%s\n", title, formatDividendLine(reminder.Dividend)))
+	}
+	message.WriteString(fmt.Sprintf("\n最後買進日：除權息日 %s 的前一個交易日", reminder.ExDividendDate.Format("2006/01/02")))
+
+	return message.String()
+}
+
+// FormatSubscriptionSchedules 格式化訂閱項目的推播排程
+func (f *formatterAdapter) FormatSubscriptionSchedules(items []*dto.UserSubscriptionItem, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("⏱ <b>您目前的推播排程</b>\n\n")
+	} else {
+		message.WriteString("⏱ 您目前的推播排程\n\n")
+	}
+
+	if len(items) == 0 {
+		message.WriteString("• 尚未訂閱任何功能\n")
+		return message.String()
+	}
+
+	for _, item := range items {
+		schedule := "預設時間"
+		if item.ScheduleCron == entity.ScheduleNextTradingDayMorning {
+			schedule = "次一交易日早上"
+		} else if clock, ok := utils.DailyCronClock(item.ScheduleCron); ok {
+			schedule = "每日 " + clock
+		} else if item.ScheduleCron != "" {
+			schedule = item.ScheduleCron
+		}
+
+		if userType == valueobject.UserTypeTelegram {
+			message.WriteString(fmt.Sprintf("• %d. %s：<code>%s</code>\n", int(item.Item), item.Item.GetName(), schedule))
+		} else {
+			message.WriteString(fmt.Sprintf("• %d. %s：%s\n", int(item.Item), item.Item.GetName(), schedule))
+		}
+	}
+	message.WriteString("\n僅在交易日推播，休市日自動略過")
+
+	return message.String()
+}
+
+// FormatUserPreference 格式化使用者推播偏好設定，依使用者偏好語言顯示
+func (f *formatterAdapter) FormatUserPreference(preference *dto.UserPreference, userType valueobject.UserType) string {
+	if preference.Language == valueobject.LanguageEnglish {
+		return f.formatUserPreferenceEnglish(preference, userType)
+	}
+
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("⚙️ <b>推播偏好設定</b>\n<code>")
+	} else {
+		message.WriteString("⚙️ 推播偏好設定\n")
+	}
+
+	quietHours := "未啟用"
+	if preference.QuietHoursStart != "" && preference.QuietHoursEnd != "" {
+		quietHours = preference.QuietHoursStart + "～" + preference.QuietHoursEnd
+	}
+	digest := "關閉"
+	if preference.DigestEnabled {
+		digest = "開啟"
+	}
+	maxDailyPushes := "不限制"
+	if preference.MaxDailyPushes > 0 {
+		maxDailyPushes = fmt.Sprintf("%d 則", preference.MaxDailyPushes)
+	}
+
+	message.WriteString(fmt.Sprintf("勿擾時段：%s\n", quietHours))
+	message.WriteString(fmt.Sprintf("時區：%s\n", preference.Timezone))
+	message.WriteString(fmt.Sprintf("語言：%s\n", valueobject.LanguageTraditionalChinese.GetName()))
+	message.WriteString(fmt.Sprintf("每日摘要：%s\n", digest))
+	message.WriteString(fmt.Sprintf("每日推播上限：%s", maxDailyPushes))
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("</code>")
+	}
+	message.WriteString("\n\n勿擾時段內的推播會延後到時段結束後送出，超過每日上限的推播當天不再送出")
+
+	return message.String()
+}
+
+// formatUserPreferenceEnglish 以英文格式化使用者推播偏好設定
+func (f *formatterAdapter) formatUserPreferenceEnglish(preference *dto.UserPreference, userType valueobject.UserType) string {
+	var message strings.Builder
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("⚙️ <b>Notification Settings</b>\n<code>")
+	} else {
+		message.WriteString("⚙️ Notification Settings\n")
+	}
+
+	quietHours := "off"
+	if preference.QuietHoursStart != "" && preference.QuietHoursEnd != "" {
+		quietHours = preference.QuietHoursStart + "-" + preference.QuietHoursEnd
+	}
+	digest := "off"
+	if preference.DigestEnabled {
+		digest = "on"
+	}
+	maxDailyPushes := "unlimited"
+	if preference.MaxDailyPushes > 0 {
+		maxDailyPushes = fmt.Sprintf("%d", preference.MaxDailyPushes)
+	}
+
+	message.WriteString(fmt.Sprintf("Quiet hours: %s\n", quietHours))
+	message.WriteString(fmt.Sprintf("Time zone: %s\n", preference.Timezone))
+	message.WriteString(fmt.Sprintf("Language: %s\n", valueobject.LanguageEnglish.GetName()))
+	message.WriteString(fmt.Sprintf("Daily digest: %s\n", digest))
+	message.WriteString(fmt.Sprintf("Daily push limit: %s", maxDailyPushes))
+
+	if userType == valueobject.UserTypeTelegram {
+		message.WriteString("</code>")
+	}
+	message.WriteString("\n\nPushes during quiet hours are delayed until they end; pushes over the daily limit are not sent that day")
+
+	return message.String()
+}
+
+// formatDividendLine 格式化單筆股利：年度、現金股利與除息/發放日、股票股利與除權日
+func formatDividendLine(dividend dto.StockDividend) string {
+	formatDate := func(date time.Time) string {
+		if date.IsZero() {
+			return "未定"
+		}
+		return date.Format("2006/01/02")
+	}
+
+	parts := []string{dividend.Year}
+	if dividend.CashDividend > 0 {
+		parts = append(parts, fmt.Sprintf("現金 %.4g 元 除息 %s 發放 %s",
+			dividend.CashDividend, formatDate(dividend.CashExDividendDate), formatDate(dividend.CashPaymentDate)))
+	}
+	if dividend.StockDividend > 0 {
+		parts = append(parts, fmt.Sprintf("股票 %.4g 元 除權 %s",
+			dividend.StockDividend, formatDate(dividend.StockExDividendDate)))
+	}
+	return strings.Join(parts, "｜")
+}
+
+// formatSignedAmount 格式化帶正負號的千分位金額
+func formatSignedAmount(amount float64) string {
+	rounded := math.Round(amount)
+	if rounded < 0 {
+		return "-" + utils.FormatFloatWithCommas(-rounded, 0)
+	}
+	return "+" + utils.FormatFloatWithCommas(rounded, 0)
+}
+
+// digestTopVolumeLimit 每日摘要顯示的成交量排行筆數
+const digestTopVolumeLimit = 10
+
+// upDownEmoji 依漲跌方向回傳對應的 emoji
+func upDownEmoji(sign string) string {
+	switch sign {
+	case "+":
+		return "📈"
+	case "-":
+		return "📉"
+	default:
+		return ""
+	}
+}
@@ -2,15 +2,26 @@ package formatter
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot"
 	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/pkg/formatter"
+	"github.com/tian841224/stock-bot/pkg/utils"
 )
 
 type LineFormatter interface {
 	FormatStockNews(news []dto.StockNews, stockName, symbol string) *dto.LineStockNewsMessage
+	FormatDailyDigest(digest *dto.DailyDigest) *dto.LineDailyDigestMessage
 }
 
+const (
+	// lineCarouselMaxBubbles LINE Flex carousel 最多可放的 bubble 數量
+	lineCarouselMaxBubbles = 12
+	// lineDigestTopVolumeLimit LINE 每日摘要顯示的成交量排行筆數
+	lineDigestTopVolumeLimit = 5
+)
+
 // TelegramFormatter Telegram 訊息格式化器
 type lineFormatter struct {
 }
@@ -163,3 +174,219 @@ func (f *lineFormatter) createNewsFlexMessage(news []dto.StockNews, stockName, s
 
 	return bubble
 }
+
+// FormatDailyDigest 格式化 LINE 每日摘要，第一張為大盤與成交量排行，之後每檔訂閱股票一張；
+// 超過 carousel 上限時，最後一張改為列出其餘股票的收盤資訊（不含新聞）
+func (f *lineFormatter) FormatDailyDigest(digest *dto.DailyDigest) *dto.LineDailyDigestMessage {
+	title := fmt.Sprintf("📰 每日摘要 %s", digest.Date.Format("2006/01/02"))
+
+	stocks := digest.Stocks
+	var overflow []*dto.DailyDigestStock
+	if len(stocks) > lineCarouselMaxBubbles-1 {
+		stocks, overflow = stocks[:lineCarouselMaxBubbles-2], stocks[lineCarouselMaxBubbles-2:]
+	}
+
+	bubbles := []*linebot.BubbleContainer{f.createDigestOverviewBubble(digest, title)}
+	for _, stock := range stocks {
+		bubbles = append(bubbles, f.createDigestStockBubble(stock))
+	}
+	if len(overflow) > 0 {
+		bubbles = append(bubbles, f.createDigestOverflowBubble(overflow))
+	}
+
+	return &dto.LineDailyDigestMessage{
+		Text: title,
+		FlexContainer: &linebot.CarouselContainer{
+			Type:     linebot.FlexContainerTypeCarousel,
+			Contents: bubbles,
+		},
+	}
+}
+
+// createDigestOverviewBubble 建立每日摘要的大盤與成交量排行 bubble
+func (f *lineFormatter) createDigestOverviewBubble(digest *dto.DailyDigest, title string) *linebot.BubbleContainer {
+	contents := make([]linebot.FlexComponent, 0)
+
+	if len(digest.MarketInfo) > 0 {
+		market := digest.MarketInfo[len(digest.MarketInfo)-1]
+		contents = append(contents,
+			f.digestSectionTitle("📊 大盤"),
+			f.digestText(fmt.Sprintf("加權指數：%s (%s)", market.Index, market.Change), "#111111"),
+			f.digestText(fmt.Sprintf("成交金額：%s", formatter.FormatAmountInt(utils.ToInt64(market.Amount))), "#111111"),
+		)
+	}
+
+	if len(digest.TopVolume) > 0 {
+		if len(contents) > 0 {
+			contents = append(contents, &linebot.SeparatorComponent{
+				Type:   linebot.FlexComponentTypeSeparator,
+				Margin: linebot.FlexComponentMarginTypeMd,
+			})
+		}
+		contents = append(contents, f.digestSectionTitle(fmt.Sprintf("🔝 成交量前 %d 名", min(len(digest.TopVolume), lineDigestTopVolumeLimit))))
+		for i, item := range digest.TopVolume {
+			if i >= lineDigestTopVolumeLimit {
+				break
+			}
+			contents = append(contents, f.digestText(
+				fmt.Sprintf("%d. %s (%s) %.2f %s", i+1, item.StockName, item.StockSymbol, item.ClosePrice, item.PercentageChange),
+				upDownColor(item.UpDownSign),
+			))
+		}
+	}
+
+	if len(contents) == 0 {
+		contents = append(contents, f.digestText("暫無大盤資料", "#999999"))
+	}
+
+	return &linebot.BubbleContainer{
+		Type:   linebot.FlexContainerTypeBubble,
+		Header: f.digestHeader(title, "大盤總覽"),
+		Body: &linebot.BoxComponent{
+			Type:     linebot.FlexComponentTypeBox,
+			Layout:   linebot.FlexBoxLayoutTypeVertical,
+			Contents: contents,
+			Spacing:  linebot.FlexComponentSpacingTypeSm,
+		},
+	}
+}
+
+// createDigestStockBubble 建立每日摘要中單一股票的收盤與新聞 bubble
+func (f *lineFormatter) createDigestStockBubble(stock *dto.DailyDigestStock) *linebot.BubbleContainer {
+	contents := make([]linebot.FlexComponent, 0)
+
+	if stock.Price == nil {
+		contents = append(contents, f.digestText("暫無收盤資料", "#999999"))
+	} else {
+		contents = append(contents,
+			&linebot.TextComponent{
+				Type:   linebot.FlexComponentTypeText,
+				Text:   fmt.Sprintf("%.2f", stock.Price.ClosePrice),
+				Size:   linebot.FlexTextSizeTypeXxl,
+				Weight: linebot.FlexTextWeightTypeBold,
+				Color:  upDownColor(stock.Price.UpDownSign),
+			},
+			f.digestText(fmt.Sprintf("漲跌：%+.2f (%+.2f%%)", stock.Price.ChangeAmount, stock.Price.ChangeRate), upDownColor(stock.Price.UpDownSign)),
+		)
+	}
+
+	if len(stock.News) > 0 {
+		contents = append(contents, &linebot.SeparatorComponent{
+			Type:   linebot.FlexComponentTypeSeparator,
+			Margin: linebot.FlexComponentMarginTypeMd,
+		}, f.digestSectionTitle("新聞"))
+		for _, news := range stock.News {
+			title := strings.TrimSpace(news.Title)
+			if title == "" {
+				continue
+			}
+			if len([]rune(title)) > 100 {
+				title = string([]rune(title)[:97]) + "..."
+			}
+			text := &linebot.TextComponent{
+				Type:  linebot.FlexComponentTypeText,
+				Text:  "• " + title,
+				Size:  linebot.FlexTextSizeTypeSm,
+				Wrap:  true,
+				Color: "#111111",
+			}
+			if news.Link != "" {
+				text.Action = &linebot.URIAction{Label: "查看", URI: news.Link}
+			}
+			contents = append(contents, text)
+		}
+	}
+
+	return &linebot.BubbleContainer{
+		Type:   linebot.FlexContainerTypeBubble,
+		Header: f.digestHeader(fmt.Sprintf("%s (%s)", stock.Name, stock.Symbol), "訂閱股票"),
+		Body: &linebot.BoxComponent{
+			Type:     linebot.FlexComponentTypeBox,
+			Layout:   linebot.FlexBoxLayoutTypeVertical,
+			Contents: contents,
+			Spacing:  linebot.FlexComponentSpacingTypeSm,
+		},
+	}
+}
+
+// createDigestOverflowBubble 建立放不下獨立 bubble 的訂閱股票清單，每檔一行收盤與漲跌幅
+func (f *lineFormatter) createDigestOverflowBubble(stocks []*dto.DailyDigestStock) *linebot.BubbleContainer {
+	contents := make([]linebot.FlexComponent, 0, len(stocks))
+	for _, stock := range stocks {
+		if stock.Price == nil {
+			contents = append(contents, f.digestText(fmt.Sprintf("%s (%s) 暫無收盤資料", stock.Name, stock.Symbol), "#999999"))
+			continue
+		}
+		contents = append(contents, f.digestText(
+			fmt.Sprintf("%s (%s) %.2f (%+.2f%%)", stock.Name, stock.Symbol, stock.Price.ClosePrice, stock.Price.ChangeRate),
+			upDownColor(stock.Price.UpDownSign),
+		))
+	}
+
+	return &linebot.BubbleContainer{
+		Type:   linebot.FlexContainerTypeBubble,
+		Header: f.digestHeader(fmt.Sprintf("其他 %d 檔訂閱股票", len(stocks)), "訂閱股票"),
+		Body: &linebot.BoxComponent{
+			Type:     linebot.FlexComponentTypeBox,
+			Layout:   linebot.FlexBoxLayoutTypeVertical,
+			Contents: contents,
+			Spacing:  linebot.FlexComponentSpacingTypeSm,
+		},
+	}
+}
+
+// digestHeader 建立每日摘要 bubble 的標題區塊
+func (f *lineFormatter) digestHeader(title, subtitle string) *linebot.BoxComponent {
+	return &linebot.BoxComponent{
+		Type:   linebot.FlexComponentTypeBox,
+		Layout: linebot.FlexBoxLayoutTypeVertical,
+		Contents: []linebot.FlexComponent{
+			&linebot.TextComponent{
+				Type:   linebot.FlexComponentTypeText,
+				Text:   title,
+				Weight: linebot.FlexTextWeightTypeBold,
+				Size:   linebot.FlexTextSizeTypeLg,
+				Color:  "#1DB446",
+				Wrap:   true,
+			},
+			&linebot.TextComponent{
+				Type:  linebot.FlexComponentTypeText,
+				Text:  subtitle,
+				Size:  linebot.FlexTextSizeTypeSm,
+				Color: "#999999",
+			},
+		},
+		PaddingAll: "15px",
+	}
+}
+
+func (f *lineFormatter) digestSectionTitle(text string) *linebot.TextComponent {
+	return &linebot.TextComponent{
+		Type:   linebot.FlexComponentTypeText,
+		Text:   text,
+		Weight: linebot.FlexTextWeightTypeBold,
+		Size:   linebot.FlexTextSizeTypeMd,
+	}
+}
+
+func (f *lineFormatter) digestText(text, color string) *linebot.TextComponent {
+	return &linebot.TextComponent{
+		Type:  linebot.FlexComponentTypeText,
+		Text:  text,
+		Size:  linebot.FlexTextSizeTypeSm,
+		Wrap:  true,
+		Color: color,
+	}
+}
+
+// upDownColor 依台股慣例，上漲顯示紅色、下跌顯示綠色
+func upDownColor(sign string) string {
+	switch sign {
+	case "+":
+		return "#D32F2F"
+	case "-":
+		return "#1DB446"
+	default:
+		return "#111111"
+	}
+}
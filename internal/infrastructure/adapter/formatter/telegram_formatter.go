@@ -2,14 +2,22 @@ package formatter
 
 import (
 	"fmt"
+	"html"
 	"strings"
+	"unicode/utf8"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/pkg/formatter"
+	"github.com/tian841224/stock-bot/pkg/utils"
 )
 
+// telegramMessageMaxLength Telegram 單則訊息的字數上限
+const telegramMessageMaxLength = 4096
+
 type TelegramFormatter interface {
 	FormatStockNews(news []dto.StockNews, stockName, symbol string) *dto.TgStockNewsMessage
+	FormatDailyDigest(digest *dto.DailyDigest) string
 }
 
 // TelegramFormatter Telegram 訊息格式化器
@@ -47,6 +55,82 @@ func (tf *telegramFormatter) FormatStockNews(news []dto.StockNews, stockName, sy
 	}
 }
 
+// FormatDailyDigest 格式化 Telegram 每日摘要（HTML），新聞標題附上連結
+func (tf *telegramFormatter) FormatDailyDigest(digest *dto.DailyDigest) string {
+	var message strings.Builder
+
+	message.WriteString(fmt.Sprintf("📰 <b>每日摘要 %s</b>\n", digest.Date.Format("2006/01/02")))
+
+	if len(digest.MarketInfo) > 0 {
+		market := digest.MarketInfo[len(digest.MarketInfo)-1]
+		message.WriteString("\n📊 <b>大盤</b>\n<code>")
+		message.WriteString(fmt.Sprintf("加權指數：%s (%s)\n", market.Index, market.Change))
+		message.WriteString(fmt.Sprintf("成交金額：%s\n", formatter.FormatAmountInt(utils.ToInt64(market.Amount))))
+		message.WriteString("</code>")
+	}
+
+	if len(digest.TopVolume) > 0 {
+		message.WriteString(fmt.Sprintf("\n🔝 <b>成交量前 %d 名</b>\n", min(len(digest.TopVolume), digestTopVolumeLimit)))
+		for i, item := range digest.TopVolume {
+			if i >= digestTopVolumeLimit {
+				break
+			}
+			message.WriteString(fmt.Sprintf("%d. %s%s (%s) %.2f %s\n",
+				i+1, upDownEmoji(item.UpDownSign), html.EscapeString(item.StockName), item.StockSymbol, item.ClosePrice, item.PercentageChange))
+		}
+	}
+
+	if len(digest.Stocks) > 0 {
+		message.WriteString("\n💼 <b>訂閱股票</b>\n")
+		// 超過 Telegram 單則訊息長度上限時，先省略新聞，仍放不下的股票只附註省略的檔數
+		length := utf8.RuneCountInString(message.String())
+		for i, stock := range digest.Stocks {
+			line := tf.formatDigestStockLine(stock)
+			news := tf.formatDigestStockNews(stock)
+			remaining := len(digest.Stocks) - i - 1
+
+			reserve := 0
+			if remaining > 0 {
+				reserve = utf8.RuneCountInString(digestOmittedNote(remaining))
+			}
+			if length+utf8.RuneCountInString(line+news)+reserve <= telegramMessageMaxLength {
+				line += news
+			} else if length+utf8.RuneCountInString(line)+reserve > telegramMessageMaxLength {
+				message.WriteString(digestOmittedNote(len(digest.Stocks) - i))
+				return message.String()
+			}
+			message.WriteString(line)
+			length += utf8.RuneCountInString(line)
+		}
+	}
+
+	return message.String()
+}
+
+// formatDigestStockLine 格式化每日摘要中單一股票的收盤資訊
+func (tf *telegramFormatter) formatDigestStockLine(stock *dto.DailyDigestStock) string {
+	if stock.Price == nil {
+		return fmt.Sprintf("<b>%s (%s)</b> 暫無收盤資料\n", html.EscapeString(stock.Name), stock.Symbol)
+	}
+	return fmt.Sprintf("%s<b>%s (%s)</b> %.2f %+.2f (%+.2f%%)\n",
+		upDownEmoji(stock.Price.UpDownSign), html.EscapeString(stock.Name), stock.Symbol,
+		stock.Price.ClosePrice, stock.Price.ChangeAmount, stock.Price.ChangeRate)
+}
+
+// formatDigestStockNews 格式化每日摘要中單一股票的新聞連結
+func (tf *telegramFormatter) formatDigestStockNews(stock *dto.DailyDigestStock) string {
+	var message strings.Builder
+	for _, news := range stock.News {
+		message.WriteString(fmt.Sprintf("  • <a href=\"%s\">%s</a>\n", html.EscapeString(news.Link), html.EscapeString(news.Title)))
+	}
+	return message.String()
+}
+
+// digestOmittedNote 每日摘要因長度上限省略股票時的附註
+func digestOmittedNote(count int) string {
+	return fmt.Sprintf("…另有 %d 檔訂閱股票因訊息長度限制未顯示，可使用 /d 個別查詢\n", count)
+}
+
 // buildStockMessage 建構股票訊息
 func (tf *telegramFormatter) buildStockMessage() string {
 	var message strings.Builder
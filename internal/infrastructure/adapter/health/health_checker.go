@@ -17,6 +17,7 @@ type healthChecker struct {
 	finmindAPI       *finmindtrade.FinmindTradeAPI
 	fugleAPI         *fugle.FugleAPI
 	syncMetadataRepo port.SyncMetadataRepository
+	marketProviders  port.MarketProviderMonitor
 }
 
 var _ port.HealthChecker = (*healthChecker)(nil)
@@ -26,12 +27,14 @@ func NewHealthChecker(
 	finmindAPI *finmindtrade.FinmindTradeAPI,
 	fugleAPI *fugle.FugleAPI,
 	syncMetadataRepo port.SyncMetadataRepository,
+	marketProviders port.MarketProviderMonitor,
 ) port.HealthChecker {
 	return &healthChecker{
 		db:               db,
 		finmindAPI:       finmindAPI,
 		fugleAPI:         fugleAPI,
 		syncMetadataRepo: syncMetadataRepo,
+		marketProviders:  marketProviders,
 	}
 }
 
@@ -219,6 +222,17 @@ func (c *healthChecker) CheckResources(ctx context.Context) port.ResourceHealthS
 	}
 }
 
+// CheckMarketProviders 回傳各資料能力最近由哪個來源提供；未使用市場資料的服務視為正常
+func (c *healthChecker) CheckMarketProviders(ctx context.Context) port.MarketProviderHealthStatus {
+	if c.marketProviders == nil {
+		return port.MarketProviderHealthStatus{
+			Status:       "healthy",
+			Capabilities: []port.MarketCapabilityStatus{},
+		}
+	}
+	return c.marketProviders.GetMarketProviderStatus()
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
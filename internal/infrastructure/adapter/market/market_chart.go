@@ -7,8 +7,6 @@ import (
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
-	fugle "github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle"
-	fugleDto "github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle/dto"
 	"github.com/tian841224/stock-bot/pkg/formatter"
 	"github.com/tian841224/stock-bot/pkg/imageutil"
 )
@@ -16,14 +14,12 @@ import (
 type marketChartGateway struct {
 	marketDataPort port.MarketDataPort
 	validationPort port.ValidationPort
-	fugleAPI       *fugle.FugleAPI
 }
 
-func NewMarketChartGateway(marketDataPort port.MarketDataPort, validationPort port.ValidationPort, fugleAPI *fugle.FugleAPI) *marketChartGateway {
+func NewMarketChartGateway(marketDataPort port.MarketDataPort, validationPort port.ValidationPort) *marketChartGateway {
 	return &marketChartGateway{
 		marketDataPort: marketDataPort,
 		validationPort: validationPort,
-		fugleAPI:       fugleAPI,
 	}
 }
 
@@ -54,35 +50,11 @@ func (g *marketChartGateway) GetHistoricalCandlesChart(ctx context.Context, symb
 	}
 	stockName := stock.Name
 
-	response, err := g.fugleAPI.GetStockHistoricalCandles(fugleDto.FugleCandlesRequestDto{
-		Symbol:    stock.Symbol,
-		From:      time.Now().AddDate(-1, 0, 1).Format("2006-01-02"),
-		Timeframe: "D",
-		Fields:    "open,high,low,close,volume",
-		Sort:      "desc",
-	})
-
+	chartData, err := g.getCandles(ctx, stock.Symbol)
 	if err != nil {
 		return nil, "", err
 	}
 
-	if len(response.Data) == 0 {
-		return nil, "", fmt.Errorf("查無K線資料")
-	}
-
-	// 轉換資料
-	chartData := make([]imageutil.CandlestickData, len(response.Data))
-	for i, d := range response.Data {
-		chartData[i] = imageutil.CandlestickData{
-			Date:   d.Date,
-			Open:   d.Open,
-			High:   d.High,
-			Low:    d.Low,
-			Close:  d.Close,
-			Volume: d.Volume,
-		}
-	}
-
 	// 產生圖表
 	chartBytes, err := imageutil.GenerateCandlestickChart(chartData, stockName, stock.Symbol)
 	if err != nil {
@@ -92,6 +64,33 @@ func (g *marketChartGateway) GetHistoricalCandlesChart(ctx context.Context, symb
 	return chartBytes, stockName, nil
 }
 
+// getCandles 以日K資料組成近一年K線圖資料，依日期由新到舊排序
+func (g *marketChartGateway) getCandles(ctx context.Context, symbol string) ([]imageutil.CandlestickData, error) {
+	endDate := time.Now()
+	startDate := endDate.AddDate(-1, 0, 1)
+	prices, err := g.marketDataPort.GetStockPrice(ctx, symbol, &startDate, &endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if prices == nil || len(*prices) == 0 {
+		return nil, fmt.Errorf("查無K線資料")
+	}
+
+	chartData := make([]imageutil.CandlestickData, len(*prices))
+	for i, d := range *prices {
+		chartData[len(*prices)-1-i] = imageutil.CandlestickData{
+			Date:   d.Date.Format("2006-01-02"),
+			Open:   d.OpenPrice,
+			High:   d.HighPrice,
+			Low:    d.LowPrice,
+			Close:  d.ClosePrice,
+			Volume: float64(d.Volume),
+		}
+	}
+	return chartData, nil
+}
+
 func (g *marketChartGateway) GetPerformanceChart(ctx context.Context, symbol string) (*dto.StockPerformanceChart, error) {
 	stock, err := g.validationPort.ValidateSymbol(ctx, symbol)
 	if err != nil {
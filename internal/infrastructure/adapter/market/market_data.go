@@ -2,6 +2,7 @@ package stock
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,32 +10,42 @@ import (
 
 	"github.com/tian841224/stock-bot/internal/application/dto"
 	"github.com/tian841224/stock-bot/internal/application/port"
-	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/cnyes"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade"
 	finmindtradeDto "github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade/dto"
-	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle"
 	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/twse"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 	"github.com/tian841224/stock-bot/pkg/utils"
 )
 
+// 股價的計價幣別
+const (
+	currencyTWD = "TWD"
+	currencyUSD = "USD"
+)
+
+// localDailyBarStartToleranceDays 本地最早一筆日K晚於查詢起日的容許天數，涵蓋長假休市
+const localDailyBarStartToleranceDays = 14
+
 type marketDataGateway struct {
+	providers       *MarketProviderRegistry
+	dailyBarReader  port.DailyBarReader
 	twseAPI         *twse.TwseAPI
-	cnyesAPI        *cnyes.CnyesAPI
-	fugleAPI        *fugle.FugleAPI
 	finmindAPI      *finmindtrade.FinmindTradeAPI
 	validationPort  port.ValidationPort
 	logger          logger.Logger
 	tradeDateReader port.TradeDateReader
 }
 
-func NewMarketDataGateway(twseAPI *twse.TwseAPI, cnyesAPI *cnyes.CnyesAPI, fugleAPI *fugle.FugleAPI, finmindAPI *finmindtrade.FinmindTradeAPI, validationPort port.ValidationPort, tradeDateReader port.TradeDateReader) *marketDataGateway {
+// NewMarketDataGateway 建立市場資料閘道，日K優先讀取本地資料；日K、報價、月營收及新聞透過資料來源註冊表取得並自動備援
+func NewMarketDataGateway(providers *MarketProviderRegistry, dailyBarReader port.DailyBarReader, twseAPI *twse.TwseAPI, finmindAPI *finmindtrade.FinmindTradeAPI, validationPort port.ValidationPort, tradeDateReader port.TradeDateReader, log logger.Logger) *marketDataGateway {
 	return &marketDataGateway{
+		providers:       providers,
+		dailyBarReader:  dailyBarReader,
 		twseAPI:         twseAPI,
-		cnyesAPI:        cnyesAPI,
-		fugleAPI:        fugleAPI,
 		finmindAPI:      finmindAPI,
 		validationPort:  validationPort,
+		logger:          log,
 		tradeDateReader: tradeDateReader,
 	}
 }
@@ -160,55 +171,75 @@ func (m *marketDataGateway) GetStockPrice(ctx context.Context, symbol string, da
 		return nil, fmt.Errorf("查無此股票代號，請重新確認")
 	}
 
-	// 建立請求參數
-	requestDto := finmindtradeDto.FinmindtradeRequestDto{
-		DataID: symbol,
-	}
-
 	// 如果只有一個日期，則取該日期、若有兩個日期則取區間日期、若沒有日期則取今天
+	startDate, endDate := time.Now(), time.Now()
 	if len(dates) == 1 {
-		requestDto.StartDate = dates[0].Format("2006-01-02")
-		requestDto.EndDate = dates[0].Format("2006-01-02")
+		startDate, endDate = *dates[0], *dates[0]
 	} else if len(dates) == 2 {
-		requestDto.StartDate = dates[0].Format("2006-01-02")
-		requestDto.EndDate = dates[1].Format("2006-01-02")
-	} else {
-		requestDto.StartDate = time.Now().Format("2006-01-02")
-		requestDto.EndDate = time.Now().Format("2006-01-02")
+		startDate, endDate = *dates[0], *dates[1]
 	}
 
-	// 呼叫 FinMind API
-	response, err := m.finmindAPI.GetTaiwanStockPrice(requestDto)
+	result, err := m.getDailyBars(ctx, stock, startDate, endDate)
 	if err != nil {
-		m.logger.Error("呼叫 FinMind API 失敗", logger.Error(err))
+		if errors.Is(err, errNoData) {
+			return nil, fmt.Errorf("查無資料")
+		}
 		return nil, err
 	}
+	return &result, nil
+}
 
-	if len(response.Data) == 0 {
-		return nil, fmt.Errorf("查無資料")
+// getDailyBars 優先使用本地日K，本地資料未涵蓋查詢區間或尚未更新到最新交易日時改向資料來源查詢
+func (m *marketDataGateway) getDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	if prices, ok := m.getLocalDailyBars(ctx, stock, startDate, endDate); ok {
+		return prices, nil
+	}
+	return m.providers.GetDailyBars(ctx, stock, startDate, endDate)
+}
+
+// getLocalDailyBars 讀取本地日K，資料不完整時回傳 false；本地日K由同步服務依序回補及更新，中間不會有缺漏
+func (m *marketDataGateway) getLocalDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, bool) {
+	bars, err := m.dailyBarReader.GetByDateRange(ctx, stock.ID, startDate, endDate)
+	if err != nil {
+		m.logger.Warn("讀取本地日K失敗，改向資料來源查詢", logger.String("symbol", stock.Symbol), logger.Error(err))
+		return nil, false
+	}
+	if len(bars) == 0 {
+		return nil, false
+	}
+	if bars[0].Date.Format("2006-01-02") > startDate.AddDate(0, 0, localDailyBarStartToleranceDays).Format("2006-01-02") {
+		return nil, false
 	}
 
-	result := make([]dto.StockPrice, len(response.Data))
-	for i, data := range response.Data {
-		parsedDate, err := time.Parse("2006-01-02", data.Date)
+	var tradeDates []*entity.TradeDate
+	if stock.IsTaiwanStock() {
+		tradeDates, err = m.tradeDateReader.GetByDateRange(ctx, endDate.AddDate(0, 0, -30), endDate)
 		if err != nil {
-			m.logger.Error("解析日期失敗", logger.String("date", data.Date), logger.Error(err))
-			return nil, fmt.Errorf("解析日期失敗: %w", err)
+			return nil, false
 		}
-		result[i] = dto.StockPrice{
+	}
+	expected, ok := entity.LatestDailyBarDate(stock, tradeDates, endDate, time.Now())
+	if !ok || bars[len(bars)-1].Date.Format("2006-01-02") < expected.Format("2006-01-02") {
+		return nil, false
+	}
+
+	prices := make([]dto.StockPrice, len(bars))
+	for i, bar := range bars {
+		prices[i] = dto.StockPrice{
 			Symbol:       stock.Symbol,
 			Name:         stock.Name,
-			Date:         parsedDate,
-			OpenPrice:    data.Open,
-			ClosePrice:   data.Close,
-			HighPrice:    data.Max,
-			LowPrice:     data.Min,
-			Volume:       int64(data.TradingVolume),
-			Transactions: int64(data.TradingTurnover),
-			Amount:       float64(data.TradingTurnover),
+			Currency:     bar.Currency,
+			Date:         bar.Date,
+			OpenPrice:    bar.Open,
+			ClosePrice:   bar.Close,
+			HighPrice:    bar.High,
+			LowPrice:     bar.Low,
+			Volume:       bar.Volume,
+			Transactions: bar.Transactions,
+			Amount:       bar.Amount,
 		}
 	}
-	return &result, nil
+	return prices, true
 }
 
 // 取得股票近五年價格歷史資料
@@ -223,59 +254,26 @@ func (m *marketDataGateway) GetStockPerformance(ctx context.Context, symbol stri
 		return nil, fmt.Errorf("查無此股票代號，請重新確認")
 	}
 
-	// 建立請求參數
-	requestDto := finmindtradeDto.FinmindtradeRequestDto{
-		DataID:    symbol,
-		StartDate: time.Now().AddDate(-5, 0, 0).Format("2006-01-02"),
-		EndDate:   time.Now().Format("2006-01-02"),
-	}
-
-	// 呼叫 FinMind API
-	priceResponse, err := m.finmindAPI.GetTaiwanStockPrice(requestDto)
-	if err != nil {
-		m.logger.Error("呼叫 FinMind API 失敗", logger.Error(err))
-		return nil, err
-	}
-
-	// 檢查是否有資料
-	if priceResponse.Status != 200 || len(priceResponse.Data) == 0 {
-		return nil, fmt.Errorf("查無股票資料")
-	}
-
-	// 取得分割資料
-	splitRequestDto := finmindtradeDto.FinmindtradeRequestDto{
-		DataID:    symbol,
-		StartDate: "1900-01-01",
-	}
-
-	splitResponse, err := m.finmindAPI.GetTaiwanStockSplitPrice(splitRequestDto)
+	prices, err := m.getDailyBars(ctx, stock, time.Now().AddDate(-5, 0, 0), time.Now())
 	if err != nil {
-		m.logger.Error("取得分割資料失敗", logger.Error(err))
+		if errors.Is(err, errNoData) {
+			return nil, fmt.Errorf("查無股票資料")
+		}
 		return nil, err
 	}
 
 	// 取得基準價格（第一天的收盤價）
-	basePrice := priceResponse.Data[0].Close
+	basePrice := prices[0].ClosePrice
 
-	// 處理股票分割對基準價格的影響
-	if len(splitResponse.Data) > 0 {
-		for _, split := range splitResponse.Data {
-			splitDate, err := time.Parse("2006-01-02", split.Date)
-			if err != nil {
-				continue
-			}
-
-			// 如果分割日期在基準日期之後，需要調整基準價格
-			baseDateParsed, _ := time.Parse("2006-01-02", priceResponse.Data[0].Date)
-			if splitDate.After(baseDateParsed) {
-				splitRatio := split.AfterPrice / split.BeforePrice
-				basePrice = basePrice * splitRatio
-			}
+	// 美股的收盤價已還原分割，台股需以分割資料調整基準價格；分割資料取得失敗時以未調整的價格計算
+	if stock.IsTaiwanStock() {
+		if adjusted, err := m.adjustBasePriceForSplits(symbol, basePrice, prices[0].Date); err == nil {
+			basePrice = adjusted
 		}
 	}
 
 	// 每隔幾天取一個點，避免資料點過多
-	step := len(priceResponse.Data) / 50 // 最多50個點，適合5年資料
+	step := len(prices) / 50 // 最多50個點，適合5年資料
 	if step < 1 {
 		step = 1
 	}
@@ -284,21 +282,17 @@ func (m *marketDataGateway) GetStockPerformance(ctx context.Context, symbol stri
 	}
 
 	// 計算每日相對於基準日的累積漲跌幅
-	for i := 0; i < len(priceResponse.Data); i += step {
-		priceData := priceResponse.Data[i]
-		currentPrice := priceData.Close
+	for i := 0; i < len(prices); i += step {
+		priceData := prices[i]
+		currentPrice := priceData.ClosePrice
 
 		// 計算相對於基準價格的漲跌幅
 		changeAmount := currentPrice - basePrice
 		percentageChange := (changeAmount / basePrice) * 100
 
-		// 格式化日期
-		date, _ := time.Parse("2006-01-02", priceData.Date)
-		dateStr := date.Format("2006/01/02")
-
 		periodData := dto.StockPerformanceData{
-			Period:      priceData.Date,
-			PeriodName:  dateStr,
+			Period:      priceData.Date.Format("2006-01-02"),
+			PeriodName:  priceData.Date.Format("2006/01/02"),
 			Performance: fmt.Sprintf("%.2f%%", percentageChange),
 		}
 
@@ -306,21 +300,18 @@ func (m *marketDataGateway) GetStockPerformance(ctx context.Context, symbol stri
 	}
 
 	// 確保包含最後一天的資料
-	if len(priceResponse.Data) > 0 {
-		lastIndex := len(priceResponse.Data) - 1
+	if len(prices) > 0 {
+		lastIndex := len(prices) - 1
 		if (lastIndex % step) != 0 {
-			priceData := priceResponse.Data[lastIndex]
-			currentPrice := priceData.Close
+			priceData := prices[lastIndex]
+			currentPrice := priceData.ClosePrice
 
 			changeAmount := currentPrice - basePrice
 			percentageChange := (changeAmount / basePrice) * 100
 
-			date, _ := time.Parse("2006-01-02", priceData.Date)
-			dateStr := date.Format("01/02")
-
 			periodData := dto.StockPerformanceData{
-				Period:      priceData.Date,
-				PeriodName:  dateStr,
+				Period:      priceData.Date.Format("2006-01-02"),
+				PeriodName:  priceData.Date.Format("01/02"),
 				Performance: fmt.Sprintf("%.2f%%", percentageChange),
 			}
 
@@ -331,6 +322,36 @@ func (m *marketDataGateway) GetStockPerformance(ctx context.Context, symbol stri
 	return result, nil
 }
 
+// adjustBasePriceForSplits 依台股分割資料調整基準價格，讓分割前後的績效可以比較
+func (m *marketDataGateway) adjustBasePriceForSplits(symbol string, basePrice float64, baseDate time.Time) (float64, error) {
+	// 取得分割資料
+	splitRequestDto := finmindtradeDto.FinmindtradeRequestDto{
+		DataID:    symbol,
+		StartDate: "1900-01-01",
+	}
+
+	splitResponse, err := m.finmindAPI.GetTaiwanStockSplitPrice(splitRequestDto)
+	if err != nil {
+		m.logger.Warn("取得分割資料失敗，以未調整的價格計算績效", logger.String("symbol", symbol), logger.Error(err))
+		return 0, err
+	}
+
+	// 處理股票分割對基準價格的影響
+	for _, split := range splitResponse.Data {
+		splitDate, err := time.Parse("2006-01-02", split.Date)
+		if err != nil {
+			continue
+		}
+
+		// 如果分割日期在基準日期之後，需要調整基準價格
+		if splitDate.After(baseDate) {
+			splitRatio := split.AfterPrice / split.BeforePrice
+			basePrice = basePrice * splitRatio
+		}
+	}
+	return basePrice, nil
+}
+
 func (m *marketDataGateway) GetStockCompanyInfo(ctx context.Context, symbol string) (*dto.StockCompanyInfo, error) {
 	stock, err := m.validationPort.ValidateSymbol(ctx, symbol)
 	if err != nil {
@@ -341,35 +362,14 @@ func (m *marketDataGateway) GetStockCompanyInfo(ctx context.Context, symbol stri
 		return nil, fmt.Errorf("查無此股票代號，請重新確認")
 	}
 
-	response, err := m.cnyesAPI.GetStockQuote(stock.Symbol)
+	stockInfo, err := m.providers.GetQuote(ctx, stock)
 	if err != nil {
+		if errors.Is(err, errNoData) {
+			return nil, fmt.Errorf("查無股票資料: %s", stock.Symbol)
+		}
 		return nil, err
 	}
-
-	// 檢查是否有資料
-	if len(response.Data) == 0 {
-		return nil, fmt.Errorf("查無股票資料: %s", stock.Symbol)
-	}
-
-	// 格式化資料（取第一筆）
-	stockInfo := dto.StockCompanyInfo{
-		Symbol:       response.Data[0].Symbol,
-		Name:         response.Data[0].StockName,
-		Industry:     response.Data[0].Industry,
-		Market:       response.Data[0].Market,
-		PE:           response.Data[0].PE,
-		PB:           response.Data[0].PB,
-		MarketCap:    response.Data[0].MarketCap,
-		BookValue:    response.Data[0].BookValue,
-		EPS:          response.Data[0].EPS,
-		QuarterEPS:   response.Data[0].QuarterEPS,
-		Dividend:     response.Data[0].Dividend,
-		DividendRate: response.Data[0].DividendRate,
-		GrossMargin:  response.Data[0].GrossMargin,
-		OperMargin:   response.Data[0].OperMargin,
-		NetMargin:    response.Data[0].NetMargin,
-	}
-	return &stockInfo, nil
+	return stockInfo, nil
 }
 
 func (m *marketDataGateway) GetStockRevenue(ctx context.Context, symbol string) (*dto.StockRevenue, error) {
@@ -383,21 +383,7 @@ func (m *marketDataGateway) GetStockRevenue(ctx context.Context, symbol string)
 	}
 
 	// 取得近12個月財報
-	response, err := m.cnyesAPI.GetRevenue(stock.Symbol, 12)
-	if err != nil {
-		return nil, err
-	}
-
-	return &dto.StockRevenue{
-		StockSymbol:     stock.Symbol,
-		StockName:       stock.Name,
-		Time:            response.Data.Time,
-		StockPrice:      response.Data.Datasets.C,
-		SaleMonth:       response.Data.Datasets.SaleMonth,
-		SaleAccumulated: response.Data.Datasets.SaleAccumulated,
-		YoY:             response.Data.Datasets.YoY,
-		YoYAccumulated:  response.Data.Datasets.YoYAccumulated,
-	}, nil
+	return m.providers.GetRevenue(ctx, stock, 12)
 }
 
 func (m *marketDataGateway) GetLatestTradeDate(ctx context.Context) (time.Time, error) {
@@ -446,26 +432,55 @@ func (m *marketDataGateway) GetLatestTradeDateByDateRange(ctx context.Context, s
 }
 
 func (m *marketDataGateway) GetStockNews(ctx context.Context, symbol string) ([]dto.StockNews, error) {
+	stock, err := m.validationPort.ValidateSymbol(ctx, symbol)
+	if err != nil {
+		m.logger.Error("驗證股票代號失敗", logger.Error(err))
+		return nil, err
+	}
+	if stock == nil {
+		return nil, fmt.Errorf("查無此股票代號，請重新確認")
+	}
+
+	return m.providers.GetNews(ctx, stock, time.Now().AddDate(0, 0, -30))
+}
 
+// GetStockDividends 取得股票在公告日期區間內的股利發放資料
+func (m *marketDataGateway) GetStockDividends(ctx context.Context, symbol string, startDate time.Time, endDate time.Time) ([]dto.StockDividend, error) {
 	requestDto := finmindtradeDto.FinmindtradeRequestDto{
 		DataID:    symbol,
-		StartDate: time.Now().AddDate(0, 0, -30).Format("2006-01-02"),
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
 	}
 
-	response, err := m.finmindAPI.GetTaiwanStockNews(requestDto)
+	response, err := m.finmindAPI.GetTaiwanStockDividend(requestDto)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("取得股利資料失敗: %w", err)
+	}
+
+	result := make([]dto.StockDividend, 0, len(response.Data))
+	for _, data := range response.Data {
+		result = append(result, dto.StockDividend{
+			Symbol:              data.StockID,
+			Year:                data.Year,
+			CashDividend:        data.CashEarningsDistribution + data.CashStatutorySurplus,
+			StockDividend:       data.StockEarningsDistribution + data.StockStatutorySurplus,
+			CashExDividendDate:  parseDividendDate(data.CashExDividendTradingDate),
+			CashPaymentDate:     parseDividendDate(data.CashDividendPaymentDate),
+			StockExDividendDate: parseDividendDate(data.StockExDividendTradingDate),
+			AnnouncementDate:    parseDividendDate(data.AnnouncementDate),
+		})
 	}
+	return result, nil
+}
 
-	stockNews := make([]dto.StockNews, 0)
-	for _, news := range response.Data {
-		stockNews = append(stockNews, dto.StockNews{
-			Date:        news.Date,
-			StockSymbol: news.StockID,
-			Link:        news.Link,
-			Source:      news.Source,
-			Title:       news.Title,
-		})
+// parseDividendDate 解析股利資料中的日期，空值或格式不符時回傳零值
+func parseDividendDate(value string) time.Time {
+	if len(value) < len("2006-01-02") {
+		return time.Time{}
+	}
+	date, err := time.Parse("2006-01-02", value[:len("2006-01-02")])
+	if err != nil {
+		return time.Time{}
 	}
-	return stockNews, nil
+	return date
 }
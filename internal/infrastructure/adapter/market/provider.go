@@ -0,0 +1,369 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/infrastructure/config"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/cnyes"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/twse"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+// 資料能力名稱，同時用於設定及健康檢查
+const (
+	capabilityDailyBars = "daily_bars"
+	capabilityQuotes    = "quotes"
+	capabilityRevenue   = "revenue"
+	capabilityNews      = "news"
+)
+
+// 資料來源名稱，用於 MARKET_*_PROVIDERS 設定
+const (
+	providerFinMind = "finmind"
+	providerFugle   = "fugle"
+	providerTWSE    = "twse"
+	providerCnyes   = "cnyes"
+)
+
+// 未設定 MARKET_*_PROVIDERS 時各能力的資料來源順序；
+// twse 單次查詢不超過 twseMaxMonths 個月，只作為短區間日K的最後備援，較長區間會直接失敗並回報
+var defaultProviderOrder = map[string][]string{
+	capabilityDailyBars: {providerFinMind, providerFugle, providerTWSE},
+	capabilityQuotes:    {providerCnyes},
+	capabilityRevenue:   {providerCnyes, providerFinMind},
+	capabilityNews:      {providerFinMind},
+}
+
+// recentProviderRequests 每個能力保留的最近請求筆數
+const recentProviderRequests = 20
+
+// errNoData 資料來源沒有回傳資料，視同失敗並改用下一個來源
+var errNoData = errors.New("查無資料")
+
+// marketProvider 所有資料來源共同的介面
+type marketProvider interface {
+	Name() string
+	// Supports 是否提供該股票的指定能力資料，不支援的來源會直接略過
+	Supports(capability string, stock *entity.StockSymbol) bool
+}
+
+// DailyBarProvider 日K資料來源，回傳依日期由舊到新排序的資料
+type DailyBarProvider interface {
+	marketProvider
+	GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error)
+}
+
+// QuoteProvider 個股報價及基本面資料來源
+type QuoteProvider interface {
+	marketProvider
+	GetQuote(ctx context.Context, stock *entity.StockSymbol) (*dto.StockCompanyInfo, error)
+}
+
+// RevenueProvider 月營收資料來源，回傳依月份由舊到新排序的資料
+type RevenueProvider interface {
+	marketProvider
+	GetRevenue(ctx context.Context, stock *entity.StockSymbol, months int) (*dto.StockRevenue, error)
+}
+
+// NewsProvider 個股新聞資料來源
+type NewsProvider interface {
+	marketProvider
+	GetNews(ctx context.Context, stock *entity.StockSymbol, startDate time.Time) ([]dto.StockNews, error)
+}
+
+// MarketProviderRegistry 依資料能力管理排序後的資料來源，前一個來源失敗時自動改用下一個，
+// 並記錄每次請求由哪個來源提供
+type MarketProviderRegistry struct {
+	dailyBars *providerChain[DailyBarProvider]
+	quotes    *providerChain[QuoteProvider]
+	revenue   *providerChain[RevenueProvider]
+	news      *providerChain[NewsProvider]
+}
+
+var (
+	_ port.MarketProviderMonitor = (*MarketProviderRegistry)(nil)
+	_ port.DailyBarSource        = (*MarketProviderRegistry)(nil)
+)
+
+// NewMarketProviderRegistry 依設定的順序建立各能力的資料來源，設定了不支援該能力的來源時回傳錯誤
+func NewMarketProviderRegistry(
+	cfg config.Config,
+	twseAPI *twse.TwseAPI,
+	cnyesAPI *cnyes.CnyesAPI,
+	fugleAPI *fugle.FugleAPI,
+	finmindAPI *finmindtrade.FinmindTradeAPI,
+	log logger.Logger,
+) (*MarketProviderRegistry, error) {
+	finmind := newFinmindProvider(finmindAPI)
+	fugleProvider := newFugleProvider(fugleAPI)
+	twseProvider := newTwseProvider(twseAPI)
+	cnyesProvider := newCnyesProvider(cnyesAPI)
+
+	dailyBars, err := rankProviders(capabilityDailyBars, cfg.MARKET_DAILY_BAR_PROVIDERS, map[string]DailyBarProvider{
+		providerFinMind: finmind,
+		providerFugle:   fugleProvider,
+		providerTWSE:    twseProvider,
+	})
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := rankProviders(capabilityQuotes, cfg.MARKET_QUOTE_PROVIDERS, map[string]QuoteProvider{
+		providerCnyes: cnyesProvider,
+	})
+	if err != nil {
+		return nil, err
+	}
+	revenue, err := rankProviders(capabilityRevenue, cfg.MARKET_REVENUE_PROVIDERS, map[string]RevenueProvider{
+		providerCnyes:   cnyesProvider,
+		providerFinMind: finmind,
+	})
+	if err != nil {
+		return nil, err
+	}
+	news, err := rankProviders(capabilityNews, cfg.MARKET_NEWS_PROVIDERS, map[string]NewsProvider{
+		providerFinMind: finmind,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarketProviderRegistry{
+		dailyBars: newProviderChain(capabilityDailyBars, dailyBars, log),
+		quotes:    newProviderChain(capabilityQuotes, quotes, log),
+		revenue:   newProviderChain(capabilityRevenue, revenue, log),
+		news:      newProviderChain(capabilityNews, news, log),
+	}, nil
+}
+
+// rankProviders 依逗號分隔的設定值排序資料來源，未設定時使用預設順序
+func rankProviders[P marketProvider](capability string, setting string, available map[string]P) ([]P, error) {
+	names := defaultProviderOrder[capability]
+	if strings.TrimSpace(setting) != "" {
+		names = strings.Split(setting, ",")
+	}
+
+	providers := make([]P, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		provider, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("資料來源 %s 不支援 %s", name, capability)
+		}
+		seen[name] = true
+		providers = append(providers, provider)
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("%s 至少需要一個資料來源", capability)
+	}
+	return providers, nil
+}
+
+// GetDailyBars 取得日K資料
+func (r *MarketProviderRegistry) GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	return callWithFailover(ctx, r.dailyBars, stock, func(p DailyBarProvider) ([]dto.StockPrice, error) {
+		return p.GetDailyBars(ctx, stock, startDate, endDate)
+	})
+}
+
+// GetQuote 取得個股報價及基本面資料
+func (r *MarketProviderRegistry) GetQuote(ctx context.Context, stock *entity.StockSymbol) (*dto.StockCompanyInfo, error) {
+	return callWithFailover(ctx, r.quotes, stock, func(p QuoteProvider) (*dto.StockCompanyInfo, error) {
+		return p.GetQuote(ctx, stock)
+	})
+}
+
+// GetRevenue 取得近 months 個月的月營收
+func (r *MarketProviderRegistry) GetRevenue(ctx context.Context, stock *entity.StockSymbol, months int) (*dto.StockRevenue, error) {
+	return callWithFailover(ctx, r.revenue, stock, func(p RevenueProvider) (*dto.StockRevenue, error) {
+		return p.GetRevenue(ctx, stock, months)
+	})
+}
+
+// GetNews 取得個股新聞
+func (r *MarketProviderRegistry) GetNews(ctx context.Context, stock *entity.StockSymbol, startDate time.Time) ([]dto.StockNews, error) {
+	return callWithFailover(ctx, r.news, stock, func(p NewsProvider) ([]dto.StockNews, error) {
+		return p.GetNews(ctx, stock, startDate)
+	})
+}
+
+// GetMarketProviderStatus 回傳各能力的資料來源使用紀錄；最近一次請求需要備援或全部失敗時為 degraded
+func (r *MarketProviderRegistry) GetMarketProviderStatus() port.MarketProviderHealthStatus {
+	capabilities := []port.MarketCapabilityStatus{
+		r.dailyBars.status(),
+		r.quotes.status(),
+		r.revenue.status(),
+		r.news.status(),
+	}
+
+	status := "healthy"
+	for _, capability := range capabilities {
+		if capability.Status != "healthy" {
+			status = "degraded"
+		}
+	}
+	return port.MarketProviderHealthStatus{
+		Status:       status,
+		Capabilities: capabilities,
+	}
+}
+
+// providerChain 單一能力的資料來源及使用紀錄
+type providerChain[P marketProvider] struct {
+	capability string
+	providers  []P
+	logger     logger.Logger
+
+	mu     sync.Mutex
+	stats  map[string]*port.MarketProviderStats
+	recent []port.MarketProviderRequest
+}
+
+func newProviderChain[P marketProvider](capability string, providers []P, log logger.Logger) *providerChain[P] {
+	stats := make(map[string]*port.MarketProviderStats, len(providers))
+	for _, provider := range providers {
+		stats[provider.Name()] = &port.MarketProviderStats{Name: provider.Name()}
+	}
+	return &providerChain[P]{
+		capability: capability,
+		providers:  providers,
+		logger:     log,
+		stats:      stats,
+	}
+}
+
+// callWithFailover 依序呼叫支援該股票的資料來源，回傳第一個成功的結果
+func callWithFailover[P marketProvider, T any](ctx context.Context, chain *providerChain[P], stock *entity.StockSymbol, call func(P) (T, error)) (T, error) {
+	var zero T
+	failed := make([]string, 0)
+	errs := make([]error, 0)
+
+	for _, provider := range chain.providers {
+		if !provider.Supports(chain.capability, stock) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		result, err := call(provider)
+		if err == nil {
+			chain.recordSuccess(provider.Name(), stock.Symbol, failed)
+			return result, nil
+		}
+
+		chain.recordFailure(provider.Name(), err)
+		chain.logger.Warn("市場資料來源失敗，改用下一個來源",
+			logger.String("capability", chain.capability),
+			logger.String("provider", provider.Name()),
+			logger.String("symbol", stock.Symbol),
+			logger.Error(err))
+		failed = append(failed, provider.Name())
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+
+	err := errors.Join(errs...)
+	if err == nil {
+		// 沒有來源支援該市場不影響資料來源的健康狀態，不列入紀錄
+		return zero, fmt.Errorf("%s 市場不支援此查詢", stock.Market)
+	}
+	chain.recordExhausted(stock.Symbol, failed, err)
+	return zero, err
+}
+
+func (c *providerChain[P]) recordSuccess(provider string, symbol string, failed []string) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats[provider]
+	stats.Served++
+	stats.LastServedAt = &now
+	c.appendRecent(port.MarketProviderRequest{
+		Time:            now,
+		Symbol:          symbol,
+		Provider:        provider,
+		FailedProviders: failed,
+	})
+
+	if len(failed) > 0 {
+		c.logger.Info("市場資料由備援來源提供",
+			logger.String("capability", c.capability),
+			logger.String("provider", provider),
+			logger.String("symbol", symbol),
+			logger.String("failed_providers", strings.Join(failed, ",")))
+	}
+}
+
+func (c *providerChain[P]) recordFailure(provider string, err error) {
+	now := time.Now()
+	message := err.Error()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats[provider]
+	stats.Failed++
+	stats.LastFailedAt = &now
+	stats.LastError = &message
+}
+
+func (c *providerChain[P]) recordExhausted(symbol string, failed []string, err error) {
+	message := err.Error()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.appendRecent(port.MarketProviderRequest{
+		Time:            time.Now(),
+		Symbol:          symbol,
+		FailedProviders: failed,
+		Error:           &message,
+	})
+}
+
+// appendRecent 新增請求紀錄，呼叫前需持有鎖
+func (c *providerChain[P]) appendRecent(request port.MarketProviderRequest) {
+	c.recent = append(c.recent, request)
+	if len(c.recent) > recentProviderRequests {
+		c.recent = c.recent[len(c.recent)-recentProviderRequests:]
+	}
+}
+
+func (c *providerChain[P]) status() port.MarketCapabilityStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	providers := make([]port.MarketProviderStats, len(c.providers))
+	for i, provider := range c.providers {
+		providers[i] = *c.stats[provider.Name()]
+	}
+
+	recent := make([]port.MarketProviderRequest, len(c.recent))
+	for i, request := range c.recent {
+		recent[len(c.recent)-1-i] = request
+	}
+
+	status := "healthy"
+	if len(recent) > 0 && (recent[0].Provider == "" || len(recent[0].FailedProviders) > 0) {
+		status = "degraded"
+	}
+	return port.MarketCapabilityStatus{
+		Capability: c.capability,
+		Status:     status,
+		Providers:  providers,
+		Recent:     recent,
+	}
+}
@@ -0,0 +1,83 @@
+package stock
+
+import (
+	"context"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/cnyes"
+)
+
+// cnyesProvider 鉅亨網資料來源，提供台股報價、基本面及月營收
+type cnyesProvider struct {
+	api *cnyes.CnyesAPI
+}
+
+var (
+	_ QuoteProvider   = (*cnyesProvider)(nil)
+	_ RevenueProvider = (*cnyesProvider)(nil)
+)
+
+func newCnyesProvider(api *cnyes.CnyesAPI) *cnyesProvider {
+	return &cnyesProvider{api: api}
+}
+
+func (p *cnyesProvider) Name() string {
+	return providerCnyes
+}
+
+func (p *cnyesProvider) Supports(capability string, stock *entity.StockSymbol) bool {
+	return stock.IsTaiwanStock()
+}
+
+func (p *cnyesProvider) GetQuote(ctx context.Context, stock *entity.StockSymbol) (*dto.StockCompanyInfo, error) {
+	response, err := p.api.GetStockQuote(stock.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// 檢查是否有資料
+	if len(response.Data) == 0 {
+		return nil, errNoData
+	}
+
+	// 格式化資料（取第一筆）
+	return &dto.StockCompanyInfo{
+		Symbol:       response.Data[0].Symbol,
+		Name:         response.Data[0].StockName,
+		Industry:     response.Data[0].Industry,
+		Market:       response.Data[0].Market,
+		PE:           response.Data[0].PE,
+		PB:           response.Data[0].PB,
+		MarketCap:    response.Data[0].MarketCap,
+		BookValue:    response.Data[0].BookValue,
+		EPS:          response.Data[0].EPS,
+		QuarterEPS:   response.Data[0].QuarterEPS,
+		Dividend:     response.Data[0].Dividend,
+		DividendRate: response.Data[0].DividendRate,
+		GrossMargin:  response.Data[0].GrossMargin,
+		OperMargin:   response.Data[0].OperMargin,
+		NetMargin:    response.Data[0].NetMargin,
+	}, nil
+}
+
+func (p *cnyesProvider) GetRevenue(ctx context.Context, stock *entity.StockSymbol, months int) (*dto.StockRevenue, error) {
+	response, err := p.api.GetRevenue(stock.Symbol, months)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data.Time) == 0 {
+		return nil, errNoData
+	}
+
+	return &dto.StockRevenue{
+		StockSymbol:     stock.Symbol,
+		StockName:       stock.Name,
+		Time:            response.Data.Time,
+		StockPrice:      response.Data.Datasets.C,
+		SaleMonth:       response.Data.Datasets.SaleMonth,
+		SaleAccumulated: response.Data.Datasets.SaleAccumulated,
+		YoY:             response.Data.Datasets.YoY,
+		YoYAccumulated:  response.Data.Datasets.YoYAccumulated,
+	}, nil
+}
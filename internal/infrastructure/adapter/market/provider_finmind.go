@@ -0,0 +1,208 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade"
+	finmindtradeDto "github.com/tian841224/stock-bot/internal/infrastructure/external/stock/finmindtrade/dto"
+)
+
+// finmindProvider FinMind 資料來源，提供台股及美股日K、台股月營收及新聞
+type finmindProvider struct {
+	api *finmindtrade.FinmindTradeAPI
+}
+
+var (
+	_ DailyBarProvider = (*finmindProvider)(nil)
+	_ RevenueProvider  = (*finmindProvider)(nil)
+	_ NewsProvider     = (*finmindProvider)(nil)
+)
+
+func newFinmindProvider(api *finmindtrade.FinmindTradeAPI) *finmindProvider {
+	return &finmindProvider{api: api}
+}
+
+func (p *finmindProvider) Name() string {
+	return providerFinMind
+}
+
+// Supports 日K支援台股及美股，月營收及新聞只有台股資料
+func (p *finmindProvider) Supports(capability string, stock *entity.StockSymbol) bool {
+	if capability == capabilityDailyBars {
+		return stock.IsTaiwanStock() || stock.IsUSStock()
+	}
+	return stock.IsTaiwanStock()
+}
+
+func (p *finmindProvider) GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	requestDto := finmindtradeDto.FinmindtradeRequestDto{
+		DataID:    stock.Symbol,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+	}
+	if stock.IsUSStock() {
+		return p.getUSStockPrices(stock, requestDto)
+	}
+	return p.getTaiwanStockPrices(stock, requestDto)
+}
+
+func (p *finmindProvider) getTaiwanStockPrices(stock *entity.StockSymbol, requestDto finmindtradeDto.FinmindtradeRequestDto) ([]dto.StockPrice, error) {
+	response, err := p.api.GetTaiwanStockPrice(requestDto)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, errNoData
+	}
+
+	result := make([]dto.StockPrice, len(response.Data))
+	for i, data := range response.Data {
+		parsedDate, err := time.Parse("2006-01-02", data.Date)
+		if err != nil {
+			return nil, fmt.Errorf("解析日期失敗: %w", err)
+		}
+		result[i] = dto.StockPrice{
+			Symbol:       stock.Symbol,
+			Name:         stock.Name,
+			Currency:     currencyTWD,
+			Date:         parsedDate,
+			OpenPrice:    data.Open,
+			ClosePrice:   data.Close,
+			HighPrice:    data.Max,
+			LowPrice:     data.Min,
+			Volume:       int64(data.TradingVolume),
+			Transactions: int64(data.TradingTurnover),
+			Amount:       float64(data.TradingTurnover),
+		}
+	}
+	return result, nil
+}
+
+// getUSStockPrices 取得美股日收盤資料（美元計價），美股資料沒有成交筆數及成交金額
+func (p *finmindProvider) getUSStockPrices(stock *entity.StockSymbol, requestDto finmindtradeDto.FinmindtradeRequestDto) ([]dto.StockPrice, error) {
+	response, err := p.api.GetUSStockPrice(requestDto)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, errNoData
+	}
+
+	result := make([]dto.StockPrice, len(response.Data))
+	for i, data := range response.Data {
+		parsedDate, err := time.Parse("2006-01-02", data.Date)
+		if err != nil {
+			return nil, fmt.Errorf("解析日期失敗: %w", err)
+		}
+		result[i] = dto.StockPrice{
+			Symbol:     stock.Symbol,
+			Name:       stock.Name,
+			Currency:   currencyUSD,
+			Date:       parsedDate,
+			OpenPrice:  data.Open,
+			ClosePrice: data.Close,
+			HighPrice:  data.High,
+			LowPrice:   data.Low,
+			Volume:     data.Volume,
+		}
+	}
+	return result, nil
+}
+
+// GetRevenue 以 FinMind 月營收計算年增率及累計營收，多取一年資料作為比較基準；
+// 營收單位換算為千元與鉅亨網一致，FinMind 沒有對應的股價資料
+func (p *finmindProvider) GetRevenue(ctx context.Context, stock *entity.StockSymbol, months int) (*dto.StockRevenue, error) {
+	now := time.Now()
+	response, err := p.api.GetTaiwanStockMonthRevenue(finmindtradeDto.FinmindtradeRequestDto{
+		DataID:    stock.Symbol,
+		StartDate: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(-1, -months, 0).Format("2006-01-02"),
+		EndDate:   now.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, errNoData
+	}
+
+	// 依營收年月建立索引，計算同期比較
+	type revenueMonth struct {
+		year, month int
+	}
+	revenues := make(map[revenueMonth]int64, len(response.Data))
+	for _, data := range response.Data {
+		revenues[revenueMonth{int(data.RevenueYear), int(data.RevenueMonth)}] = data.Revenue
+	}
+
+	accumulated := func(year, month int) (int64, bool) {
+		var total int64
+		for m := 1; m <= month; m++ {
+			revenue, ok := revenues[revenueMonth{year, m}]
+			if !ok {
+				return 0, false
+			}
+			total += revenue
+		}
+		return total, true
+	}
+	growth := func(current, previous int64) float64 {
+		if previous == 0 {
+			return 0
+		}
+		return (float64(current) - float64(previous)) / float64(previous) * 100
+	}
+
+	start := len(response.Data) - months
+	if start < 0 {
+		start = 0
+	}
+	result := &dto.StockRevenue{
+		StockSymbol: stock.Symbol,
+		StockName:   stock.Name,
+	}
+	for _, data := range response.Data[start:] {
+		year, month := int(data.RevenueYear), int(data.RevenueMonth)
+		yoy := 0.0
+		if previous, ok := revenues[revenueMonth{year - 1, month}]; ok {
+			yoy = growth(data.Revenue, previous)
+		}
+		saleAccumulated, _ := accumulated(year, month)
+		yoyAccumulated := 0.0
+		if previous, ok := accumulated(year-1, month); ok {
+			yoyAccumulated = growth(saleAccumulated, previous)
+		}
+
+		result.Time = append(result.Time, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local).Unix())
+		result.SaleMonth = append(result.SaleMonth, data.Revenue/1000)
+		result.SaleAccumulated = append(result.SaleAccumulated, saleAccumulated/1000)
+		result.YoY = append(result.YoY, yoy)
+		result.YoYAccumulated = append(result.YoYAccumulated, yoyAccumulated)
+	}
+	return result, nil
+}
+
+func (p *finmindProvider) GetNews(ctx context.Context, stock *entity.StockSymbol, startDate time.Time) ([]dto.StockNews, error) {
+	response, err := p.api.GetTaiwanStockNews(finmindtradeDto.FinmindtradeRequestDto{
+		DataID:    stock.Symbol,
+		StartDate: startDate.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stockNews := make([]dto.StockNews, 0, len(response.Data))
+	for _, news := range response.Data {
+		stockNews = append(stockNews, dto.StockNews{
+			Date:        news.Date,
+			StockSymbol: news.StockID,
+			Link:        news.Link,
+			Source:      news.Source,
+			Title:       news.Title,
+		})
+	}
+	return stockNews, nil
+}
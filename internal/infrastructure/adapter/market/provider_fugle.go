@@ -0,0 +1,81 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle"
+	fugleDto "github.com/tian841224/stock-bot/internal/infrastructure/external/stock/fugle/dto"
+)
+
+// fugleProvider 富果資料來源，提供台股日K
+type fugleProvider struct {
+	api *fugle.FugleAPI
+}
+
+var _ DailyBarProvider = (*fugleProvider)(nil)
+
+func newFugleProvider(api *fugle.FugleAPI) *fugleProvider {
+	return &fugleProvider{api: api}
+}
+
+func (p *fugleProvider) Name() string {
+	return providerFugle
+}
+
+func (p *fugleProvider) Supports(capability string, stock *entity.StockSymbol) bool {
+	return capability == capabilityDailyBars && stock.IsTaiwanStock()
+}
+
+// GetDailyBars 富果歷史K線單次查詢最多一年，超過時分段查詢
+func (p *fugleProvider) GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	result := make([]dto.StockPrice, 0)
+	for from := startDate; !from.After(endDate); {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		to := from.AddDate(1, 0, -1)
+		if to.After(endDate) {
+			to = endDate
+		}
+		response, err := p.api.GetStockHistoricalCandles(fugleDto.FugleCandlesRequestDto{
+			Symbol:    stock.Symbol,
+			From:      from.Format("2006-01-02"),
+			To:        to.Format("2006-01-02"),
+			Timeframe: "D",
+			Fields:    "open,high,low,close,volume",
+			Sort:      "asc",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, data := range response.Data {
+			parsedDate, err := time.Parse("2006-01-02", data.Date)
+			if err != nil {
+				return nil, fmt.Errorf("解析日期失敗: %w", err)
+			}
+			result = append(result, dto.StockPrice{
+				Symbol:     stock.Symbol,
+				Name:       stock.Name,
+				Currency:   currencyTWD,
+				Date:       parsedDate,
+				OpenPrice:  data.Open,
+				ClosePrice: data.Close,
+				HighPrice:  data.High,
+				LowPrice:   data.Low,
+				Volume:     int64(data.Volume),
+			})
+		}
+		from = to.AddDate(0, 0, 1)
+	}
+
+	if len(result) == 0 {
+		return nil, errNoData
+	}
+	return result, nil
+}
@@ -0,0 +1,316 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(msg string, fields ...logger.Field)  {}
+func (m *mockLogger) Error(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Warn(msg string, fields ...logger.Field)  {}
+func (m *mockLogger) Debug(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Panic(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Fatal(msg string, fields ...logger.Field) {}
+func (m *mockLogger) Sync() error                              { return nil }
+
+// fakeDailyBarProvider 以固定結果模擬日K資料來源，markets 為空時支援所有市場
+type fakeDailyBarProvider struct {
+	name    string
+	markets []string
+	err     error
+	calls   int
+}
+
+func (p *fakeDailyBarProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeDailyBarProvider) Supports(capability string, stock *entity.StockSymbol) bool {
+	if capability != capabilityDailyBars {
+		return false
+	}
+	if len(p.markets) == 0 {
+		return true
+	}
+	for _, market := range p.markets {
+		if market == stock.Market {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fakeDailyBarProvider) GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []dto.StockPrice{{Symbol: stock.Symbol, Name: p.name}}, nil
+}
+
+func TestRankProviders(t *testing.T) {
+	available := map[string]DailyBarProvider{
+		providerFinMind: &fakeDailyBarProvider{name: providerFinMind},
+		providerFugle:   &fakeDailyBarProvider{name: providerFugle},
+		providerTWSE:    &fakeDailyBarProvider{name: providerTWSE},
+	}
+
+	tests := []struct {
+		name    string
+		setting string
+		want    []string
+		wantErr bool
+	}{
+		{name: "未設定時使用預設順序", setting: "", want: []string{providerFinMind, providerFugle, providerTWSE}},
+		{name: "依設定排序", setting: "twse,finmind", want: []string{providerTWSE, providerFinMind}},
+		{name: "忽略大小寫、空白及重複", setting: " Fugle , fugle,,TWSE ", want: []string{providerFugle, providerTWSE}},
+		{name: "不支援該能力的來源", setting: "finmind,cnyes", wantErr: true},
+		{name: "沒有任何來源", setting: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers, err := rankProviders(capabilityDailyBars, tt.setting, available)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("錯誤期望 %v，實際 %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, len(providers))
+			for i, provider := range providers {
+				got[i] = provider.Name()
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("資料來源順序期望 %v，實際 %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCallWithFailover(t *testing.T) {
+	twStock := &entity.StockSymbol{Symbol: "2330", Market: "TWSE"}
+	errTimeout := errors.New("timeout")
+
+	tests := []struct {
+		name         string
+		providers    []*fakeDailyBarProvider
+		stock        *entity.StockSymbol
+		wantProvider string
+		wantCalls    []int
+		wantErr      bool
+	}{
+		{
+			name: "第一個來源成功",
+			providers: []*fakeDailyBarProvider{
+				{name: "a"},
+				{name: "b"},
+			},
+			stock:        twStock,
+			wantProvider: "a",
+			wantCalls:    []int{1, 0},
+		},
+		{
+			name: "依序改用下一個來源",
+			providers: []*fakeDailyBarProvider{
+				{name: "a", err: errTimeout},
+				{name: "b", err: errTimeout},
+				{name: "c"},
+			},
+			stock:        twStock,
+			wantProvider: "c",
+			wantCalls:    []int{1, 1, 1},
+		},
+		{
+			name: "查無資料時改用下一個來源",
+			providers: []*fakeDailyBarProvider{
+				{name: "a", err: errNoData},
+				{name: "b"},
+			},
+			stock:        twStock,
+			wantProvider: "b",
+			wantCalls:    []int{1, 1},
+		},
+		{
+			name: "略過不支援該市場的來源",
+			providers: []*fakeDailyBarProvider{
+				{name: "a", markets: []string{"US"}},
+				{name: "b"},
+			},
+			stock:        twStock,
+			wantProvider: "b",
+			wantCalls:    []int{0, 1},
+		},
+		{
+			name: "全部失敗",
+			providers: []*fakeDailyBarProvider{
+				{name: "a", err: errTimeout},
+				{name: "b", err: errNoData},
+			},
+			stock:     twStock,
+			wantCalls: []int{1, 1},
+			wantErr:   true,
+		},
+		{
+			name: "沒有來源支援該市場",
+			providers: []*fakeDailyBarProvider{
+				{name: "a", markets: []string{"US"}},
+			},
+			stock:     twStock,
+			wantCalls: []int{0},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]DailyBarProvider, len(tt.providers))
+			for i, provider := range tt.providers {
+				providers[i] = provider
+			}
+			registry := &MarketProviderRegistry{dailyBars: newProviderChain(capabilityDailyBars, providers, &mockLogger{})}
+
+			bars, err := registry.GetDailyBars(context.Background(), tt.stock, time.Time{}, time.Time{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("錯誤期望 %v，實際 %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && bars[0].Name != tt.wantProvider {
+				t.Errorf("資料來源期望 %s，實際 %s", tt.wantProvider, bars[0].Name)
+			}
+			for i, provider := range tt.providers {
+				if provider.calls != tt.wantCalls[i] {
+					t.Errorf("%s 呼叫次數期望 %d，實際 %d", provider.name, tt.wantCalls[i], provider.calls)
+				}
+			}
+		})
+	}
+}
+
+func TestCallWithFailover_ErrorIncludesEveryProvider(t *testing.T) {
+	providers := []DailyBarProvider{
+		&fakeDailyBarProvider{name: "a", err: errors.New("timeout")},
+		&fakeDailyBarProvider{name: "b", err: errNoData},
+	}
+	registry := &MarketProviderRegistry{dailyBars: newProviderChain(capabilityDailyBars, providers, &mockLogger{})}
+
+	_, err := registry.GetDailyBars(context.Background(), &entity.StockSymbol{Symbol: "2330", Market: "TWSE"}, time.Time{}, time.Time{})
+	if !errors.Is(err, errNoData) {
+		t.Errorf("錯誤應包含 errNoData，實際 %v", err)
+	}
+	if !strings.Contains(err.Error(), "a: timeout") {
+		t.Errorf("錯誤應包含各來源的失敗原因，實際 %v", err)
+	}
+}
+
+func TestProviderChain_Status(t *testing.T) {
+	twStock := &entity.StockSymbol{Symbol: "2330", Market: "TWSE"}
+	usStock := &entity.StockSymbol{Symbol: "AAPL", Market: "US"}
+	primary := &fakeDailyBarProvider{name: "a", markets: []string{"TWSE"}}
+	backup := &fakeDailyBarProvider{name: "b"}
+	chain := newProviderChain(capabilityDailyBars, []DailyBarProvider{primary, backup}, &mockLogger{})
+	registry := &MarketProviderRegistry{dailyBars: chain}
+
+	get := func(stock *entity.StockSymbol) {
+		_, _ = registry.GetDailyBars(context.Background(), stock, time.Time{}, time.Time{})
+	}
+
+	get(twStock)
+	if status := chain.status(); status.Status != "healthy" {
+		t.Errorf("主要來源成功時期望 healthy，實際 %s", status.Status)
+	}
+
+	primary.err = errors.New("timeout")
+	get(twStock)
+	status := chain.status()
+	if status.Status != "degraded" {
+		t.Errorf("使用備援來源時期望 degraded，實際 %s", status.Status)
+	}
+	if recent := status.Recent[0]; recent.Provider != "b" || fmt.Sprint(recent.FailedProviders) != "[a]" {
+		t.Errorf("最近請求期望由 b 提供且 a 失敗，實際 %+v", recent)
+	}
+
+	// 主要來源不支援美股，由備援來源直接提供不算降級
+	get(usStock)
+	if status := chain.status(); status.Status != "healthy" {
+		t.Errorf("不支援的來源被略過時期望 healthy，實際 %s", status.Status)
+	}
+
+	backup.err = errNoData
+	get(twStock)
+	status = chain.status()
+	if status.Status != "degraded" || status.Recent[0].Provider != "" || status.Recent[0].Error == nil {
+		t.Errorf("全部失敗時期望 degraded 且記錄錯誤，實際 %+v", status.Recent[0])
+	}
+
+	stats := map[string][2]int{}
+	for _, provider := range status.Providers {
+		stats[provider.Name] = [2]int{int(provider.Served), int(provider.Failed)}
+	}
+	if stats["a"] != [2]int{1, 2} || stats["b"] != [2]int{2, 1} {
+		t.Errorf("資料來源統計期望 a=[1 2]、b=[2 1]，實際 %v", stats)
+	}
+	if len(status.Recent) != 4 {
+		t.Errorf("最近請求期望 4 筆，實際 %d 筆", len(status.Recent))
+	}
+}
+
+func TestProviderChain_RecentLimit(t *testing.T) {
+	chain := newProviderChain(capabilityDailyBars, []DailyBarProvider{&fakeDailyBarProvider{name: "a"}}, &mockLogger{})
+	registry := &MarketProviderRegistry{dailyBars: chain}
+
+	for i := 0; i < recentProviderRequests+5; i++ {
+		_, _ = registry.GetDailyBars(context.Background(), &entity.StockSymbol{Symbol: fmt.Sprint(i), Market: "TWSE"}, time.Time{}, time.Time{})
+	}
+
+	status := chain.status()
+	if len(status.Recent) != recentProviderRequests {
+		t.Fatalf("最近請求期望 %d 筆，實際 %d 筆", recentProviderRequests, len(status.Recent))
+	}
+	if latest := status.Recent[0].Symbol; latest != fmt.Sprint(recentProviderRequests+4) {
+		t.Errorf("最近請求應由新到舊排列，第一筆期望 %d，實際 %s", recentProviderRequests+4, latest)
+	}
+}
+
+func TestParseTwseStockDay(t *testing.T) {
+	row := []string{"115/10/14", "25,000,000", "25,000,000,000", "1,000.00", "1,010.00", "995.00", "1,005.00", "+5.00", "120,000"}
+
+	price, err := parseTwseStockDay(row)
+	if err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+	if want := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC); !price.Date.Equal(want) || price.Date.Location() != time.UTC {
+		t.Errorf("日期期望 %v（UTC），實際 %v", want, price.Date)
+	}
+	if price.ClosePrice != 1005 || price.Volume != 25000000 || price.Transactions != 120000 {
+		t.Errorf("數值解析錯誤，實際 %+v", price)
+	}
+}
+
+func TestParseTwseStockDay_NoTrade(t *testing.T) {
+	tests := []struct {
+		name string
+		row  []string
+	}{
+		{name: "全日無成交", row: []string{"115/10/14", "0", "0", "--", "--", "--", "--", " 0.00", "0"}},
+		{name: "收盤價為空", row: []string{"115/10/14", "1,000", "1,000,000", "1,000.00", "1,000.00", "1,000.00", "--", "X0.00", "3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTwseStockDay(tt.row); !errors.Is(err, errTwseNoTrade) {
+				t.Errorf("期望 errTwseNoTrade，實際 %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package stock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/infrastructure/external/stock/twse"
+)
+
+// twseMaxMonths 證交所個股日成交資訊一次只能查一個月且有頻率限制，只用於短區間的備援
+const twseMaxMonths = 3
+
+// errTwseNoTrade 當日無成交（價格為 "--"），該筆資料不列入日K
+var errTwseNoTrade = errors.New("當日無成交")
+
+// twseProvider 證交所資料來源，提供上市股票日K
+type twseProvider struct {
+	api *twse.TwseAPI
+}
+
+var _ DailyBarProvider = (*twseProvider)(nil)
+
+func newTwseProvider(api *twse.TwseAPI) *twseProvider {
+	return &twseProvider{api: api}
+}
+
+func (p *twseProvider) Name() string {
+	return providerTWSE
+}
+
+// Supports 證交所只有上市股票資料
+func (p *twseProvider) Supports(capability string, stock *entity.StockSymbol) bool {
+	return capability == capabilityDailyBars && stock.Market == "TWSE"
+}
+
+func (p *twseProvider) GetDailyBars(ctx context.Context, stock *entity.StockSymbol, startDate, endDate time.Time) ([]dto.StockPrice, error) {
+	firstMonth := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+	lastMonth := time.Date(endDate.Year(), endDate.Month(), 1, 0, 0, 0, 0, endDate.Location())
+	if lastMonth.After(firstMonth.AddDate(0, twseMaxMonths-1, 0)) {
+		return nil, fmt.Errorf("查詢區間超過 %d 個月", twseMaxMonths)
+	}
+
+	from, to := startDate.Format("2006-01-02"), endDate.Format("2006-01-02")
+	result := make([]dto.StockPrice, 0)
+	for month := firstMonth; !month.After(lastMonth); month = month.AddDate(0, 1, 0) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		response, err := p.api.GetStockDay(stock.Symbol, month.Format("20060102"))
+		if err != nil {
+			return nil, err
+		}
+		if response.Stat != "OK" {
+			continue
+		}
+
+		for _, row := range response.Data {
+			price, err := parseTwseStockDay(row)
+			if errors.Is(err, errTwseNoTrade) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if day := price.Date.Format("2006-01-02"); day < from || day > to {
+				continue
+			}
+			price.Symbol = stock.Symbol
+			price.Name = stock.Name
+			result = append(result, price)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errNoData
+	}
+	return result, nil
+}
+
+// parseTwseStockDay 解析個股日成交資訊的單筆資料，日期為民國年，數字含千分位；
+// 無成交時價格為 "--"，回傳 errTwseNoTrade，避免存成價格為 0 的日K
+func parseTwseStockDay(row []string) (dto.StockPrice, error) {
+	if len(row) < 9 {
+		return dto.StockPrice{}, fmt.Errorf("日成交資訊欄位不足: %v", row)
+	}
+	for _, value := range row[3:7] {
+		if strings.TrimSpace(value) == "--" {
+			return dto.StockPrice{}, errTwseNoTrade
+		}
+	}
+
+	parts := strings.Split(strings.TrimSpace(row[0]), "/")
+	if len(parts) != 3 {
+		return dto.StockPrice{}, fmt.Errorf("解析日期失敗: %s", row[0])
+	}
+	rocYear, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return dto.StockPrice{}, fmt.Errorf("解析日期失敗: %s", row[0])
+	}
+	// 與其他日K來源相同以 UTC 午夜表示交易日，切換來源時日期比對才會一致
+	date, err := time.Parse("2006/01/02", fmt.Sprintf("%d/%s/%s", rocYear+1911, parts[1], parts[2]))
+	if err != nil {
+		return dto.StockPrice{}, fmt.Errorf("解析日期失敗: %w", err)
+	}
+
+	number := func(value string) float64 {
+		parsed, _ := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+		return parsed
+	}
+	return dto.StockPrice{
+		Currency:     currencyTWD,
+		Date:         date,
+		Volume:       int64(number(row[1])),
+		Amount:       number(row[2]),
+		OpenPrice:    number(row[3]),
+		HighPrice:    number(row[4]),
+		LowPrice:     number(row[5]),
+		ClosePrice:   number(row[6]),
+		Transactions: int64(number(row[8])),
+	}, nil
+}
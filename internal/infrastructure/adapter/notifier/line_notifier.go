@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot"
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	linebotInfra "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/line"
+)
+
+type lineNotifier struct {
+	client *linebotInfra.LineBotClient
+}
+
+var _ port.NotifierPort = (*lineNotifier)(nil)
+
+// NewLineNotifier 建立 LINE 推播管道，透過 Push API 主動推送，不需要 reply token
+func NewLineNotifier(client *linebotInfra.LineBotClient) port.NotifierPort {
+	return &lineNotifier{client: client}
+}
+
+func (n *lineNotifier) Channel() valueobject.UserType {
+	return valueobject.UserTypeLine
+}
+
+// Push 推送訊息給 LINE 使用者，有 Flex 內容時以 Flex Message 送出並以 Text 作為替代文字
+func (n *lineNotifier) Push(ctx context.Context, accountID string, payload dto.NotificationPayload) error {
+	if len(payload.LineFlex) > 0 {
+		contents, err := linebot.UnmarshalFlexMessageJSON(payload.LineFlex)
+		if err != nil {
+			return err
+		}
+		return n.client.PushFlexMessage(accountID, payload.Text, contents)
+	}
+	return n.client.PushMessage(accountID, payload.Text)
+}
+
+func (n *lineNotifier) IsPermanentError(err error) bool {
+	return linebotInfra.IsPermanentError(err)
+}
+
+// IsRecipientUnavailable LINE 的錯誤回應無法可靠辨識使用者是否封鎖官方帳號，一律回傳 false；
+// 封鎖改由 webhook 的 unfollow 事件停用帳號（見 LineMessageProcessor.ProcessUnfollow）
+func (n *lineNotifier) IsRecipientUnavailable(err error) bool {
+	return false
+}
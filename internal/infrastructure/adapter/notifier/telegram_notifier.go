@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/tian841224/stock-bot/internal/application/dto"
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	tgbotInfra "github.com/tian841224/stock-bot/internal/infrastructure/external/bot/telegram"
+)
+
+type telegramNotifier struct {
+	client *tgbotInfra.TgBotClient
+}
+
+var _ port.NotifierPort = (*telegramNotifier)(nil)
+
+// NewTelegramNotifier 建立 Telegram 推播管道
+func NewTelegramNotifier(client *tgbotInfra.TgBotClient) port.NotifierPort {
+	return &telegramNotifier{client: client}
+}
+
+func (n *telegramNotifier) Channel() valueobject.UserType {
+	return valueobject.UserTypeTelegram
+}
+
+// Push 以 chat ID 推送 HTML 訊息，附有按鈕時一併送出
+func (n *telegramNotifier) Push(ctx context.Context, accountID string, payload dto.NotificationPayload) error {
+	chatID, err := strconv.ParseInt(accountID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("無效的 Telegram chat ID %q: %w", accountID, err)
+	}
+	if payload.Keyboard != nil {
+		return n.client.SendMessageWithKeyboard(ctx, chatID, payload.Text, payload.Keyboard)
+	}
+	return n.client.SendMessage(ctx, chatID, payload.Text)
+}
+
+func (n *telegramNotifier) IsPermanentError(err error) bool {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return true
+	}
+	return tgbotInfra.IsPermanentError(err)
+}
+
+func (n *telegramNotifier) IsRecipientUnavailable(err error) bool {
+	return tgbotInfra.IsRecipientUnavailable(err)
+}
@@ -52,8 +52,9 @@ func (p *userSubscriptionGateway) GetUserSubscriptionItemList(ctx context.Contex
 	var items []*dto.UserSubscriptionItem
 	for _, sub := range subscriptions {
 		items = append(items, &dto.UserSubscriptionItem{
-			Item:   sub.Item,
-			Status: sub.Active,
+			Item:         sub.Item,
+			Status:       sub.Active,
+			ScheduleCron: sub.ScheduleCron,
 		})
 	}
 	return items, nil
@@ -201,3 +202,14 @@ func (p *userSubscriptionGateway) DeleteUserSubscriptionItem(ctx context.Context
 	}
 	return p.subscriptionRepoWriter.Delete(ctx, subscription.ID)
 }
+
+func (p *userSubscriptionGateway) UpdateUserSubscriptionSchedule(ctx context.Context, userID uint, item valueobject.SubscriptionType, scheduleCron string) error {
+	subscription, err := p.subscriptionRepo.GetByUserAndFeature(ctx, userID, uint(item))
+	if err != nil {
+		return fmt.Errorf("取得訂閱失敗: %w", err)
+	}
+	if subscription == nil {
+		return fmt.Errorf("尚未訂閱此項目: %s", item.GetName())
+	}
+	return p.subscriptionRepoWriter.UpdateScheduleCron(ctx, subscription.ID, scheduleCron)
+}
@@ -0,0 +1,149 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
+	repository "github.com/tian841224/stock-bot/internal/infrastructure/persistence/postgres"
+)
+
+type watchlistGateway struct {
+	watchlistRepo     repository.WatchlistRepository
+	watchlistItemRepo repository.WatchlistItemRepository
+}
+
+func NewWatchlistGateway(
+	watchlistRepo repository.WatchlistRepository,
+	watchlistItemRepo repository.WatchlistItemRepository,
+) port.WatchlistPort {
+	return &watchlistGateway{
+		watchlistRepo:     watchlistRepo,
+		watchlistItemRepo: watchlistItemRepo,
+	}
+}
+
+var _ port.WatchlistPort = (*watchlistGateway)(nil)
+
+func (g *watchlistGateway) GetWatchlists(ctx context.Context, userID uint) ([]*entity.Watchlist, error) {
+	watchlists, err := g.watchlistRepo.GetListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("取得觀察清單失敗: %w", err)
+	}
+
+	result := make([]*entity.Watchlist, 0, len(watchlists))
+	for _, watchlist := range watchlists {
+		result = append(result, toWatchlistEntity(watchlist))
+	}
+	return result, nil
+}
+
+func (g *watchlistGateway) GetWatchlistByName(ctx context.Context, userID uint, name string) (*entity.Watchlist, error) {
+	watchlist, err := g.watchlistRepo.GetByUserIDAndName(ctx, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("取得觀察清單失敗: %w", err)
+	}
+	if watchlist == nil {
+		return nil, nil
+	}
+	return toWatchlistEntity(watchlist), nil
+}
+
+func (g *watchlistGateway) CreateWatchlist(ctx context.Context, watchlist *entity.Watchlist) error {
+	dbModel := &models.Watchlist{
+		UserID:    watchlist.UserID,
+		Name:      watchlist.Name,
+		SortOrder: watchlist.SortOrder,
+	}
+	if err := g.watchlistRepo.Create(ctx, dbModel); err != nil {
+		return fmt.Errorf("建立觀察清單失敗: %w", err)
+	}
+	watchlist.ID = dbModel.ID
+	return nil
+}
+
+func (g *watchlistGateway) RenameWatchlist(ctx context.Context, watchlistID uint, name string) error {
+	if err := g.watchlistRepo.UpdateName(ctx, watchlistID, name); err != nil {
+		return fmt.Errorf("重新命名觀察清單失敗: %w", err)
+	}
+	return nil
+}
+
+func (g *watchlistGateway) DeleteWatchlist(ctx context.Context, watchlistID uint) error {
+	if err := g.watchlistRepo.DeleteWithItems(ctx, watchlistID); err != nil {
+		return fmt.Errorf("刪除觀察清單失敗: %w", err)
+	}
+	return nil
+}
+
+func (g *watchlistGateway) AddWatchlistItem(ctx context.Context, item *entity.WatchlistItem) error {
+	dbModel := &models.WatchlistItem{
+		WatchlistID: item.WatchlistID,
+		SymbolID:    item.SymbolID,
+		SortOrder:   item.SortOrder,
+	}
+	if err := g.watchlistItemRepo.Create(ctx, dbModel); err != nil {
+		return fmt.Errorf("新增觀察清單項目失敗: %w", err)
+	}
+	item.ID = dbModel.ID
+	return nil
+}
+
+func (g *watchlistGateway) DeleteWatchlistItem(ctx context.Context, watchlistID uint, symbolID uint) error {
+	if err := g.watchlistItemRepo.DeleteByWatchlistAndSymbol(ctx, watchlistID, symbolID); err != nil {
+		return fmt.Errorf("移除觀察清單項目失敗: %w", err)
+	}
+	return nil
+}
+
+func (g *watchlistGateway) MoveWatchlistItem(ctx context.Context, itemID uint, toWatchlistID uint, sortOrder int) error {
+	if err := g.watchlistItemRepo.MoveToWatchlist(ctx, itemID, toWatchlistID, sortOrder); err != nil {
+		return fmt.Errorf("移動觀察清單項目失敗: %w", err)
+	}
+	return nil
+}
+
+func (g *watchlistGateway) UpdateWatchlistItemOrders(ctx context.Context, items []*entity.WatchlistItem) error {
+	dbModels := make([]*models.WatchlistItem, 0, len(items))
+	for _, item := range items {
+		dbModels = append(dbModels, &models.WatchlistItem{
+			Model:     models.Model{ID: item.ID},
+			SortOrder: item.SortOrder,
+		})
+	}
+	if err := g.watchlistItemRepo.UpdateSortOrders(ctx, dbModels); err != nil {
+		return fmt.Errorf("更新觀察清單排序失敗: %w", err)
+	}
+	return nil
+}
+
+func toWatchlistEntity(watchlist *models.Watchlist) *entity.Watchlist {
+	result := &entity.Watchlist{
+		ID:        watchlist.ID,
+		UserID:    watchlist.UserID,
+		Name:      watchlist.Name,
+		SortOrder: watchlist.SortOrder,
+		Items:     make([]*entity.WatchlistItem, 0, len(watchlist.WatchlistItems)),
+	}
+
+	for _, item := range watchlist.WatchlistItems {
+		entityItem := &entity.WatchlistItem{
+			ID:          item.ID,
+			WatchlistID: item.WatchlistID,
+			SymbolID:    item.SymbolID,
+			SortOrder:   item.SortOrder,
+		}
+		if item.Symbol != nil {
+			entityItem.StockSymbol = &entity.StockSymbol{
+				ID:     item.Symbol.ID,
+				Symbol: item.Symbol.Symbol,
+				Name:   item.Symbol.Name,
+				Market: item.Symbol.Market,
+			}
+		}
+		result.Items = append(result.Items, entityItem)
+	}
+	return result
+}
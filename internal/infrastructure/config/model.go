@@ -4,28 +4,38 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	LINE_BOT_WEBHOOK_PATH       string `mapstructure:"LINE_BOT_WEBHOOK_PATH"`
-	TELEGRAM_BOT_SECRET_TOKEN   string `mapstructure:"TELEGRAM_BOT_SECRET_TOKEN"`
-	DB_USER                     string `mapstructure:"DB_USER"`
-	TELEGRAM_ADMIN_CHAT_ID      string `mapstructure:"TELEGRAM_ADMIN_CHAT_ID"`
-	DB_NAME                     string `mapstructure:"DB_NAME"`
-	SCHEDULER_STOCK_SPEC        string `mapstructure:"SCHEDULER_STOCK_SPEC"`
-	CHANNEL_ACCESS_TOKEN        string `mapstructure:"CHANNEL_ACCESS_TOKEN"`
-	CHANNEL_SECRET              string `mapstructure:"CHANNEL_SECRET"`
-	SCHEDULER_TIMEZONE          string `mapstructure:"SCHEDULER_TIMEZONE"`
-	TELEGRAM_BOT_TOKEN          string `mapstructure:"TELEGRAM_BOT_TOKEN"`
-	DB_PASSWORD                 string `mapstructure:"DB_PASSWORD"`
-	TELEGRAM_BOT_WEBHOOK_DOMAIN string `mapstructure:"TELEGRAM_BOT_WEBHOOK_DOMAIN"`
-	TELEGRAM_BOT_WEBHOOK_PATH   string `mapstructure:"TELEGRAM_BOT_WEBHOOK_PATH"`
-	DB_HOST                     string `mapstructure:"DB_HOST"`
-	FINMIND_TOKEN               string `mapstructure:"FINMIND_TOKEN"`
-	FUGLE_API_KEY               string `mapstructure:"FUGLE_API_KEY"`
-	IMGBB_API_KEY               string `mapstructure:"IMGBB_API_KEY"`
-	DB_PORT                     int    `mapstructure:"DB_PORT"`
-	DB_LOG_MODE                 bool   `mapstructure:"DB_LOG"`
+	LINE_BOT_WEBHOOK_PATH       string  `mapstructure:"LINE_BOT_WEBHOOK_PATH"`
+	TELEGRAM_BOT_SECRET_TOKEN   string  `mapstructure:"TELEGRAM_BOT_SECRET_TOKEN"`
+	DB_USER                     string  `mapstructure:"DB_USER"`
+	TELEGRAM_ADMIN_CHAT_ID      string  `mapstructure:"TELEGRAM_ADMIN_CHAT_ID"`
+	DB_NAME                     string  `mapstructure:"DB_NAME"`
+	SCHEDULER_STOCK_SPEC        string  `mapstructure:"SCHEDULER_STOCK_SPEC"`
+	CHANNEL_ACCESS_TOKEN        string  `mapstructure:"CHANNEL_ACCESS_TOKEN"`
+	CHANNEL_SECRET              string  `mapstructure:"CHANNEL_SECRET"`
+	SCHEDULER_TIMEZONE          string  `mapstructure:"SCHEDULER_TIMEZONE"`
+	SCHEDULER_MORNING_TIME      string  `mapstructure:"SCHEDULER_MORNING_TIME"`
+	TELEGRAM_BOT_TOKEN          string  `mapstructure:"TELEGRAM_BOT_TOKEN"`
+	DB_PASSWORD                 string  `mapstructure:"DB_PASSWORD"`
+	TELEGRAM_BOT_WEBHOOK_DOMAIN string  `mapstructure:"TELEGRAM_BOT_WEBHOOK_DOMAIN"`
+	TELEGRAM_BOT_WEBHOOK_PATH   string  `mapstructure:"TELEGRAM_BOT_WEBHOOK_PATH"`
+	DB_HOST                     string  `mapstructure:"DB_HOST"`
+	FINMIND_TOKEN               string  `mapstructure:"FINMIND_TOKEN"`
+	FUGLE_API_KEY               string  `mapstructure:"FUGLE_API_KEY"`
+	IMGBB_API_KEY               string  `mapstructure:"IMGBB_API_KEY"`
+	DB_PORT                     int     `mapstructure:"DB_PORT"`
+	DB_LOG_MODE                 bool    `mapstructure:"DB_LOG"`
+	TRADING_FEE_DISCOUNT        float64 `mapstructure:"TRADING_FEE_DISCOUNT"`
+	TRADING_MIN_FEE             float64 `mapstructure:"TRADING_MIN_FEE"`
+	EX_DIVIDEND_REMINDER_DAYS   int     `mapstructure:"EX_DIVIDEND_REMINDER_DAYS"`
+	NOTIFICATION_MAX_RETRIES    int     `mapstructure:"NOTIFICATION_MAX_RETRIES"`
+	MARKET_DAILY_BAR_PROVIDERS  string  `mapstructure:"MARKET_DAILY_BAR_PROVIDERS"`
+	MARKET_QUOTE_PROVIDERS      string  `mapstructure:"MARKET_QUOTE_PROVIDERS"`
+	MARKET_REVENUE_PROVIDERS    string  `mapstructure:"MARKET_REVENUE_PROVIDERS"`
+	MARKET_NEWS_PROVIDERS       string  `mapstructure:"MARKET_NEWS_PROVIDERS"`
 }
 
 // Validate 驗證配置的必要欄位
@@ -80,5 +90,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("缺少必要的配置項目: %s", strings.Join(missingFields, ", "))
 	}
 
+	// 交易成本設定驗證（選填）
+	if c.TRADING_FEE_DISCOUNT < 0 || c.TRADING_FEE_DISCOUNT > 1 {
+		return fmt.Errorf("TRADING_FEE_DISCOUNT 必須介於 0 到 1 之間")
+	}
+	if c.TRADING_MIN_FEE < 0 {
+		return fmt.Errorf("TRADING_MIN_FEE 不可為負數")
+	}
+
+	// 除權息提醒設定驗證（選填，0 代表使用預設值）
+	if c.EX_DIVIDEND_REMINDER_DAYS < 0 || c.EX_DIVIDEND_REMINDER_DAYS > 20 {
+		return fmt.Errorf("EX_DIVIDEND_REMINDER_DAYS 必須介於 0 到 20 之間（0 代表使用預設值）")
+	}
+
+	// 推播重試設定驗證（選填，0 代表使用預設值）
+	if c.NOTIFICATION_MAX_RETRIES < 0 || c.NOTIFICATION_MAX_RETRIES > 10 {
+		return fmt.Errorf("NOTIFICATION_MAX_RETRIES 必須介於 0 到 10 之間（0 代表使用預設值）")
+	}
+
+	// 推播排程時區驗證（選填），SCHEDULER_STOCK_SPEC 與 SCHEDULER_MORNING_TIME 由排程建立時驗證
+	if c.SCHEDULER_TIMEZONE != "" {
+		if _, err := time.LoadLocation(c.SCHEDULER_TIMEZONE); err != nil {
+			return fmt.Errorf("SCHEDULER_TIMEZONE 無效: %w", err)
+		}
+	}
+
 	return nil
 }
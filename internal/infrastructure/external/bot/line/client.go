@@ -2,6 +2,8 @@ package linebot
 
 import (
 	"bytes"
+	"errors"
+	"net/http"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot"
 	"github.com/tian841224/stock-bot/internal/infrastructure/config"
@@ -49,6 +51,25 @@ func (b *LineBotClient) ReplyMessageWithButtons(replyToken, text string, buttons
 	return err
 }
 
+// ReplyMessageWithQuickReply 回覆帶有快速回覆按鈕的文字訊息，按鈕點擊後會以使用者身分送出 actions 的文字
+func (b *LineBotClient) ReplyMessageWithQuickReply(replyToken, text string, actions []*linebot.MessageAction) error {
+	if len(actions) == 0 {
+		return b.ReplyMessage(replyToken, text)
+	}
+
+	buttons := make([]*linebot.QuickReplyButton, 0, len(actions))
+	for _, action := range actions {
+		buttons = append(buttons, linebot.NewQuickReplyButton("", action))
+	}
+	message := linebot.NewTextMessage(text).WithQuickReplies(linebot.NewQuickReplyItems(buttons...))
+
+	_, err := b.Client.ReplyMessage(replyToken, message).Do()
+	if err != nil {
+		b.logger.Error("發送快速回覆訊息失敗", logger.Error(err))
+	}
+	return err
+}
+
 // ReplyImage 回覆圖片訊息
 func (b *LineBotClient) ReplyImage(replyToken, imageURL string) error {
 	imageMessage := linebot.NewImageMessage(imageURL, imageURL)
@@ -106,3 +127,32 @@ func (b *LineBotClient) ReplyFlexMessage(replyToken string, altText string, cont
 	}
 	return err
 }
+
+// PushMessage 主動推送文字訊息
+func (b *LineBotClient) PushMessage(to, text string) error {
+	_, err := b.Client.PushMessage(to, linebot.NewTextMessage(text)).Do()
+	if err != nil {
+		b.logger.Error("推送訊息失敗", logger.Error(err))
+	}
+	return err
+}
+
+// PushFlexMessage 主動推送 Flex Message
+func (b *LineBotClient) PushFlexMessage(to, altText string, contents linebot.FlexContainer) error {
+	_, err := b.Client.PushMessage(to, linebot.NewFlexMessage(altText, contents)).Do()
+	if err != nil {
+		b.logger.Error("推送 Flex 訊息失敗", logger.Error(err))
+	}
+	return err
+}
+
+// IsPermanentError 判斷送出失敗是否無法透過重試解決（請求內容錯誤、收件者無效等），
+// 429 流量限制及網路錯誤視為暫時性錯誤
+func IsPermanentError(err error) bool {
+	var apiErr *linebot.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code >= http.StatusBadRequest && apiErr.Code < http.StatusInternalServerError &&
+		apiErr.Code != http.StatusTooManyRequests
+}
@@ -2,14 +2,28 @@
 package tgbot
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/tian841224/stock-bot/internal/infrastructure/config"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 )
 
 type TgBotClient struct {
-	Client *tgbotapi.BotAPI
-	logger logger.Logger
+	Client   *tgbotapi.BotAPI
+	throttle *sendThrottler
+	logger   logger.Logger
+}
+
+func newTgBotClient(client *tgbotapi.BotAPI, log logger.Logger) *TgBotClient {
+	return &TgBotClient{
+		Client:   client,
+		throttle: newSendThrottler(globalSendInterval, chatSendInterval),
+		logger:   log,
+	}
 }
 
 // NewBot 初始化 Telegram Bot 並設定 webhook
@@ -30,7 +44,7 @@ func NewBot(cfg config.Config, log logger.Logger) (*TgBotClient, error) {
 		if _, err := client.MakeRequest("setWebhook", params); err != nil {
 			return nil, err
 		}
-		return &TgBotClient{Client: client, logger: log}, nil
+		return newTgBotClient(client, log), nil
 	}
 
 	webhookConfig, err := tgbotapi.NewWebhook(webhookURL)
@@ -40,14 +54,14 @@ func NewBot(cfg config.Config, log logger.Logger) (*TgBotClient, error) {
 	if _, err = client.Request(webhookConfig); err != nil {
 		return nil, err
 	}
-	return &TgBotClient{Client: client, logger: log}, nil
+	return newTgBotClient(client, log), nil
 }
 
 // SendMessage 發送訊息
-func (c *TgBotClient) SendMessage(chatID int64, text string) error {
+func (c *TgBotClient) SendMessage(ctx context.Context, chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = tgbotapi.ModeHTML
-	_, err := c.Client.Send(msg)
+	err := c.send(ctx, chatID, msg)
 	if err != nil {
 		c.logger.Error("發送訊息失敗", logger.Error(err))
 	}
@@ -55,24 +69,46 @@ func (c *TgBotClient) SendMessage(chatID int64, text string) error {
 }
 
 // SendMessageWithKeyboard 發送帶有鍵盤的訊息
-func (c *TgBotClient) SendMessageWithKeyboard(chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+func (c *TgBotClient) SendMessageWithKeyboard(ctx context.Context, chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = tgbotapi.ModeHTML
 	if keyboard != nil {
 		msg.ReplyMarkup = keyboard
 	}
-	_, err := c.Client.Send(msg)
+	err := c.send(ctx, chatID, msg)
 	if err != nil {
 		c.logger.Error("發送帶有鍵盤的訊息失敗", logger.Error(err))
 	}
 	return err
 }
 
+// AnswerCallbackQuery 回應按鈕點擊，text 為空時只結束按鈕的載入狀態
+func (c *TgBotClient) AnswerCallbackQuery(callbackQueryID, text string) error {
+	_, err := c.Client.Request(tgbotapi.NewCallback(callbackQueryID, text))
+	if err != nil {
+		c.logger.Error("回應按鈕點擊失敗", logger.Error(err))
+	}
+	return err
+}
+
+// AnswerInlineQuery 回覆 inline query 結果，cacheTime 為 Telegram 端快取秒數
+func (c *TgBotClient) AnswerInlineQuery(inlineQueryID string, results []interface{}, cacheTime int) error {
+	_, err := c.Client.Request(tgbotapi.InlineConfig{
+		InlineQueryID: inlineQueryID,
+		Results:       results,
+		CacheTime:     cacheTime,
+	})
+	if err != nil {
+		c.logger.Error("回覆 inline query 失敗", logger.Error(err))
+	}
+	return err
+}
+
 // SendMessageHTML 發送 HTML 訊息
-func (c *TgBotClient) SendMessageHTML(chatID int64, text string) error {
+func (c *TgBotClient) SendMessageHTML(ctx context.Context, chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = tgbotapi.ModeHTML
-	_, err := c.Client.Send(msg)
+	err := c.send(ctx, chatID, msg)
 	if err != nil {
 		c.logger.Error("發送 HTML 訊息失敗", logger.Error(err))
 	}
@@ -80,16 +116,56 @@ func (c *TgBotClient) SendMessageHTML(chatID int64, text string) error {
 }
 
 // SendPhoto 發送圖片
-func (c *TgBotClient) SendPhoto(chatID int64, data []byte, caption string) error {
+func (c *TgBotClient) SendPhoto(ctx context.Context, chatID int64, data []byte, caption string) error {
 	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
 		Name:  "chart.png",
 		Bytes: data,
 	})
 	photo.Caption = caption
 	photo.ParseMode = tgbotapi.ModeHTML
-	_, err := c.Client.Send(photo)
+	err := c.send(ctx, chatID, photo)
 	if err != nil {
 		c.logger.Error("發送圖片失敗", logger.Error(err))
 	}
 	return err
 }
+
+// send 經由送出佇列依 Telegram 速率限制（全域每秒約 30 則、同一聊天室每秒 1 則）送出，
+// 收到 429 時依 retry_after 等待後重送；等待期間 ctx 取消時不送出
+func (c *TgBotClient) send(ctx context.Context, chatID int64, chattable tgbotapi.Chattable) error {
+	return c.throttle.send(ctx, chatID, func() error {
+		_, err := c.Client.Send(chattable)
+		if retryAfter, limited := retryAfterOf(err); limited {
+			c.logger.Warn("Telegram 流量限制，等待後重送",
+				logger.Int64("chat_id", chatID),
+				logger.Any("retry_after", retryAfter))
+		}
+		return err
+	})
+}
+
+// IsPermanentError 判斷送出失敗是否無法透過重試解決（請求內容錯誤、使用者封鎖 bot 等），
+// 429 流量限制及網路錯誤視為暫時性錯誤
+func IsPermanentError(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code >= http.StatusBadRequest && apiErr.Code < http.StatusInternalServerError &&
+		apiErr.Code != http.StatusTooManyRequests
+}
+
+// IsRecipientUnavailable 判斷送出失敗是否因使用者封鎖 bot、帳號已停用或聊天室已不存在
+func IsRecipientUnavailable(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusForbidden:
+		return true
+	case http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(apiErr.Message), "chat not found")
+	}
+	return false
+}
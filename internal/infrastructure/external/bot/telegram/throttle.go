@@ -0,0 +1,133 @@
+package tgbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// globalSendInterval 全域送出間隔，Telegram 限制每秒約 30 則
+	globalSendInterval = time.Second / 30
+	// chatSendInterval 同一聊天室的送出間隔，Telegram 限制每秒 1 則
+	chatSendInterval = time.Second
+	// maxRateLimitRetries 收到 429 後於 client 內重送的次數，超過時回傳錯誤交由呼叫端重試
+	maxRateLimitRetries = 3
+	// maxRetryAfter 願意在 client 內等待的 retry_after 上限，超過時直接回傳錯誤
+	maxRetryAfter = time.Minute
+	// chatSlotPruneSize 聊天室送出時間表超過此數量時清除已過期的項目
+	chatSlotPruneSize = 1024
+)
+
+// sendThrottler 依全域與各聊天室的速率限制排定送出時間。
+// 聊天室可送出時才預約全域名額，全域名額即為實際送出時間，不會因併發而超出限制
+type sendThrottler struct {
+	mu             sync.Mutex
+	globalInterval time.Duration
+	chatInterval   time.Duration
+	nextGlobal     time.Time
+	nextChat       map[int64]time.Time
+}
+
+func newSendThrottler(globalInterval, chatInterval time.Duration) *sendThrottler {
+	return &sendThrottler{
+		globalInterval: globalInterval,
+		chatInterval:   chatInterval,
+		nextChat:       make(map[int64]time.Time),
+	}
+}
+
+// wait 等待到 chatID 可送出的時間，ctx 取消時回傳 ctx 的錯誤
+func (t *sendThrottler) wait(ctx context.Context, chatID int64) error {
+	for {
+		delay, reserved := t.reserve(chatID, time.Now())
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if reserved {
+			return nil
+		}
+	}
+}
+
+// reserve 預約 chatID 的送出時間，回傳需要等待的時間及是否已預約。
+// 聊天室仍在間隔內時不預約，只回傳聊天室可送出前需等待的時間，避免佔住其他聊天室的全域名額；
+// 聊天室可送出時預約下一個全域名額，並以該名額的時間作為聊天室的最後送出時間
+func (t *sendThrottler) reserve(chatID int64, now time.Time) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if next, ok := t.nextChat[chatID]; ok && next.After(now) {
+		return next.Sub(now), false
+	}
+
+	slot := now
+	if t.nextGlobal.After(slot) {
+		slot = t.nextGlobal
+	}
+	t.nextGlobal = slot.Add(t.globalInterval)
+	t.nextChat[chatID] = slot.Add(t.chatInterval)
+
+	if len(t.nextChat) > chatSlotPruneSize {
+		t.prune(now)
+	}
+	return slot.Sub(now), true
+}
+
+// pause 收到 429 時暫停所有送出直到 retry_after 結束
+func (t *sendThrottler) pause(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(t.nextGlobal) {
+		t.nextGlobal = until
+	}
+}
+
+// prune 清除已可送出的聊天室，避免時間表無限成長
+func (t *sendThrottler) prune(now time.Time) {
+	for chatID, next := range t.nextChat {
+		if !next.After(now) {
+			delete(t.nextChat, chatID)
+		}
+	}
+}
+
+// send 依速率限制送出，收到 429 時依 retry_after 等待後重送
+func (t *sendThrottler) send(ctx context.Context, chatID int64, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx, chatID); err != nil {
+			return err
+		}
+
+		err := fn()
+		retryAfter, limited := retryAfterOf(err)
+		if !limited || attempt >= maxRateLimitRetries || retryAfter > maxRetryAfter {
+			return err
+		}
+		t.pause(retryAfter)
+	}
+}
+
+// retryAfterOf 判斷是否為 429 流量限制，並回傳 Telegram 要求的等待時間
+func retryAfterOf(err error) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+		return 0, false
+	}
+	retryAfter := time.Duration(apiErr.RetryAfter) * time.Second
+	if retryAfter <= 0 {
+		retryAfter = chatSendInterval
+	}
+	return retryAfter, true
+}
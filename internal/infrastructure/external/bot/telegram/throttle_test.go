@@ -0,0 +1,159 @@
+package tgbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestSendThrottler_Reserve(t *testing.T) {
+	start := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+
+	type reservation struct {
+		chatID       int64
+		at           time.Duration
+		wantDelay    time.Duration
+		wantReserved bool
+	}
+
+	tests := []struct {
+		name         string
+		reservations []reservation
+	}{
+		{
+			name: "不同聊天室依全域間隔排隊",
+			reservations: []reservation{
+				{chatID: 1, wantDelay: 0, wantReserved: true},
+				{chatID: 2, wantDelay: 100 * time.Millisecond, wantReserved: true},
+				{chatID: 3, wantDelay: 200 * time.Millisecond, wantReserved: true},
+			},
+		},
+		{
+			name: "同一聊天室在間隔內不預約",
+			reservations: []reservation{
+				{chatID: 1, wantDelay: 0, wantReserved: true},
+				{chatID: 1, at: 300 * time.Millisecond, wantDelay: 700 * time.Millisecond, wantReserved: false},
+			},
+		},
+		{
+			name: "等待中的聊天室不佔用全域名額",
+			reservations: []reservation{
+				{chatID: 1, wantDelay: 0, wantReserved: true},
+				{chatID: 1, wantDelay: time.Second, wantReserved: false},
+				{chatID: 2, wantDelay: 100 * time.Millisecond, wantReserved: true},
+			},
+		},
+		{
+			name: "聊天室間隔結束後取得當下的全域名額",
+			reservations: []reservation{
+				{chatID: 1, wantDelay: 0, wantReserved: true},
+				{chatID: 1, at: time.Second, wantDelay: 0, wantReserved: true},
+			},
+		},
+		{
+			name: "聊天室最後送出時間以全域名額計算",
+			reservations: []reservation{
+				{chatID: 1, wantDelay: 0, wantReserved: true},
+				{chatID: 2, wantDelay: 100 * time.Millisecond, wantReserved: true},
+				{chatID: 2, at: time.Second, wantDelay: 100 * time.Millisecond, wantReserved: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := newSendThrottler(100*time.Millisecond, time.Second)
+
+			for i, r := range tt.reservations {
+				delay, reserved := throttle.reserve(r.chatID, start.Add(r.at))
+				if delay != r.wantDelay || reserved != r.wantReserved {
+					t.Errorf("第 %d 次預約期望等待 %v、預約 %v，實際等待 %v、預約 %v", i+1, r.wantDelay, r.wantReserved, delay, reserved)
+				}
+			}
+		})
+	}
+}
+
+func TestSendThrottler_Prune(t *testing.T) {
+	start := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	throttle := newSendThrottler(0, time.Second)
+
+	for chatID := int64(0); chatID <= chatSlotPruneSize; chatID++ {
+		throttle.reserve(chatID, start)
+	}
+	throttle.reserve(-1, start.Add(2*time.Second))
+
+	if len(throttle.nextChat) != 1 {
+		t.Errorf("聊天室送出時間表期望剩 1 筆，實際 %d 筆", len(throttle.nextChat))
+	}
+}
+
+func TestSendThrottler_WaitContextCanceled(t *testing.T) {
+	throttle := newSendThrottler(0, time.Hour)
+	if err := throttle.wait(context.Background(), 1); err != nil {
+		t.Fatalf("不應該發生錯誤: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	called := false
+	err := throttle.send(ctx, 1, func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期望 context.DeadlineExceeded，實際 %v", err)
+	}
+	if called {
+		t.Error("ctx 取消後不應送出")
+	}
+}
+
+func TestRetryAfterOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantDelay   time.Duration
+		wantLimited bool
+	}{
+		{name: "沒有錯誤", err: nil},
+		{name: "非 Telegram 錯誤", err: errors.New("connection reset")},
+		{name: "其他 Telegram 錯誤", err: &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request"}},
+		{
+			name:        "流量限制",
+			err:         &tgbotapi.Error{Code: http.StatusTooManyRequests, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5}},
+			wantDelay:   5 * time.Second,
+			wantLimited: true,
+		},
+		{
+			name:        "被包裝的流量限制",
+			err:         fmt.Errorf("send: %w", &tgbotapi.Error{Code: http.StatusTooManyRequests, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 2}}),
+			wantDelay:   2 * time.Second,
+			wantLimited: true,
+		},
+		{
+			name:        "流量限制未提供等待時間",
+			err:         &tgbotapi.Error{Code: http.StatusTooManyRequests},
+			wantDelay:   chatSendInterval,
+			wantLimited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, limited := retryAfterOf(tt.err)
+			if limited != tt.wantLimited {
+				t.Errorf("流量限制期望 %v，實際 %v", tt.wantLimited, limited)
+			}
+			if delay != tt.wantDelay {
+				t.Errorf("等待時間期望 %v，實際 %v", tt.wantDelay, delay)
+			}
+		})
+	}
+}
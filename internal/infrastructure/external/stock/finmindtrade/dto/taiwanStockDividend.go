@@ -2,9 +2,9 @@ package dto
 
 // TaiwanStockDividendResponseDto 股利發放
 type TaiwanStockDividendResponseDto struct {
-	Msg    string                  `json:"msg"`
-	Status int                     `json:"status"`
-	Data   TaiwanStockDividendData `json:"data"`
+	Msg    string                    `json:"msg"`
+	Status int                       `json:"status"`
+	Data   []TaiwanStockDividendData `json:"data"`
 }
 type TaiwanStockDividendData struct {
 	Date                                  string  `json:"date"`
@@ -103,6 +103,38 @@ func (t *TwseAPI) GetDailyMarketInfo() (dto.DailyMarketInfoResponseDto, error) {
 	return response, nil
 }
 
+// GetStockDay 取得個股指定月份的日成交資訊，date 格式為 YYYYMMDD
+func (t *TwseAPI) GetStockDay(symbol string, date string) (dto.StockDayResponseDto, error) {
+	u, err := url.Parse(t.baseURL + "/afterTrading/STOCK_DAY")
+	if err != nil {
+		return dto.StockDayResponseDto{}, err
+	}
+	q := u.Query()
+	q.Set("date", date)
+	q.Set("stockNo", symbol)
+	q.Set("response", "json")
+	u.RawQuery = q.Encode()
+
+	req, err := t.getRequest(u.String())
+	if err != nil {
+		return dto.StockDayResponseDto{}, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return dto.StockDayResponseDto{}, fmt.Errorf("無法連接到外部 API: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return dto.StockDayResponseDto{}, fmt.Errorf("外部 API 回應錯誤，狀態碼: %d", resp.StatusCode)
+	}
+
+	var response dto.StockDayResponseDto
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return dto.StockDayResponseDto{}, fmt.Errorf("無法解析回應 JSON: %v", err)
+	}
+	return response, nil
+}
+
 // 設定Request參數
 func (f *TwseAPI) getRequest(url string) (*http.Request, error) {
 	req, err := http.NewRequest("GET", url, nil)
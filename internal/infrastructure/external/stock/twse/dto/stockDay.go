@@ -0,0 +1,11 @@
+package dto
+
+// StockDayResponseDto 個股日成交資訊（單月），日期為民國年格式（例：113/05/02）
+type StockDayResponseDto struct {
+	Stat   string   `json:"stat"`
+	Date   string   `json:"date"`
+	Title  string   `json:"title"`
+	Fields []string `json:"fields"`
+	// 日期、成交股數、成交金額、開盤價、最高價、最低價、收盤價、漲跌價差、成交筆數
+	Data [][]string `json:"data"`
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// migration 在 AutoMigrate 之後執行的資料遷移，必須可重複執行
+type migration struct {
+	name string
+	run  func(tx *gorm.DB) error
+}
+
+var migrations = []migration{
+	{name: "watchlist_named_lists", run: migrateNamedWatchlists},
+}
+
+// runMigrations 依序執行資料遷移
+func (d *postgresDatabase) runMigrations() error {
+	for _, m := range migrations {
+		if err := d.db.Transaction(m.run); err != nil {
+			return fmt.Errorf("資料遷移 %s 失敗: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// migrateNamedWatchlists 將舊的單一觀察清單轉換為具名清單：
+// 補上清單名稱與排序、讓同一使用者的清單名稱不重複，並依建立順序編排清單內股票的排序。
+// 同一使用者名稱重複的清單保留最早建立（id 最小）的一筆，其餘依建立順序在名稱後加上序號，
+// 例如兩個「預設」清單改為「預設」與「預設2」；加上序號後若又與既有清單同名，會再次加上序號直到沒有重複，
+// 之後才建立 (user_id, name) 唯一索引
+func migrateNamedWatchlists(tx *gorm.DB) error {
+	if err := tx.Exec(`UPDATE watchlists SET name = '預設' WHERE name IS NULL OR name = ''`).Error; err != nil {
+		return err
+	}
+
+	for {
+		result := tx.Exec(`UPDATE watchlists w SET name = w.name || ranked.rn
+			FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, name ORDER BY id) AS rn FROM watchlists) ranked
+			WHERE w.id = ranked.id AND ranked.rn > 1`)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			break
+		}
+	}
+
+	statements := []string{
+		`UPDATE watchlists w SET sort_order = ranked.rn
+			FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY id) AS rn FROM watchlists) ranked
+			WHERE w.id = ranked.id AND w.user_id IN (SELECT user_id FROM watchlists GROUP BY user_id HAVING MAX(sort_order) = 0)`,
+		`UPDATE watchlist_items wi SET sort_order = ranked.rn
+			FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY watchlist_id ORDER BY id) AS rn FROM watchlist_items) ranked
+			WHERE wi.id = ranked.id AND wi.watchlist_id IN (SELECT watchlist_id FROM watchlist_items GROUP BY watchlist_id HAVING MAX(sort_order) = 0)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_watchlist_user_name ON watchlists (user_id, name)`,
+	}
+
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// 股票日K模型
+type DailyBar struct {
+	Model
+	// 股票ID
+	SymbolID uint `gorm:"column:symbol_id;type:bigint;not null;uniqueIndex:idx_daily_bar_symbol_date,priority:1" json:"symbol_id"`
+	// 交易日期
+	Date time.Time `gorm:"column:date;type:date;not null;uniqueIndex:idx_daily_bar_symbol_date,priority:2" json:"date"`
+	// 開盤價
+	Open float64 `gorm:"column:open;type:numeric(12,4);not null" json:"open"`
+	// 最高價
+	High float64 `gorm:"column:high;type:numeric(12,4);not null" json:"high"`
+	// 最低價
+	Low float64 `gorm:"column:low;type:numeric(12,4);not null" json:"low"`
+	// 收盤價
+	Close float64 `gorm:"column:close;type:numeric(12,4);not null" json:"close"`
+	// 成交股數
+	Volume int64 `gorm:"column:volume;type:bigint;not null;default:0" json:"volume"`
+	// 成交金額
+	Amount float64 `gorm:"column:amount;type:numeric(20,2);not null;default:0" json:"amount"`
+	// 成交筆數
+	Transactions int64 `gorm:"column:transactions;type:bigint;not null;default:0" json:"transactions"`
+	// 計價幣別
+	Currency string `gorm:"column:currency;type:varchar(3);not null" json:"currency"`
+	// 關聯資料表
+	StockSymbol *StockSymbol `gorm:"foreignKey:SymbolID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (DailyBar) TableName() string {
+	return "daily_bars"
+}
+
+func init() {
+	RegisterModel(&DailyBar{})
+}
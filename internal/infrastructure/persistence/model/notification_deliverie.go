@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
 
 // 投遞紀錄模型
 type NotificationDelivery struct {
@@ -10,24 +14,19 @@ type NotificationDelivery struct {
 	// 管道ID
 	ChannelID uint `gorm:"column:channel_id;type:bigint;index" json:"channel_id"`
 	// 投遞狀態
-	Status NotificationDeliveryStatus `gorm:"column:status;type:varchar(255)" json:"status"`
+	Status valueobject.NotificationDeliveryStatus `gorm:"column:status;type:varchar(255);index" json:"status"`
 	// 回應
 	Response string `gorm:"column:response;type:jsonb" json:"response"`
+	// 已嘗試次數
+	Attempts int `gorm:"column:attempts;type:integer;not null;default:0" json:"attempts"`
+	// 下次重試時間，為空代表不再重試
+	NextRetryAt *time.Time `gorm:"column:next_retry_at;type:timestamptz;index" json:"next_retry_at"`
 	// 發送時間
-	SentAt time.Time `gorm:"column:sent_at;type:timestamptz" json:"sent_at"`
+	SentAt *time.Time `gorm:"column:sent_at;type:timestamptz" json:"sent_at"`
 	// 關聯資料表
 	Event *NotificationEvent `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
 }
 
-// 投遞狀態
-type NotificationDeliveryStatus string
-
-const (
-	NotificationDeliveryStatusQueued NotificationDeliveryStatus = "queued"
-	NotificationDeliveryStatusSent   NotificationDeliveryStatus = "sent"
-	NotificationDeliveryStatusFailed NotificationDeliveryStatus = "failed"
-)
-
 func (NotificationDelivery) TableName() string {
 	return "notification_deliveries"
 }
@@ -7,14 +7,16 @@ type NotificationEvent struct {
 	Model
 	// 使用者ID
 	UserID uint `gorm:"column:user_id;type:bigint;index" json:"user_id"`
-	// 功能ID
-	FeatureID uint `gorm:"column:feature_id;type:bigint;index" json:"feature_id"`
+	// 功能ID，價格提醒等非訂閱通知為空
+	FeatureID *uint `gorm:"column:feature_id;type:bigint;index" json:"feature_id"`
 	// 訂閱ID
 	SubscriptionID *uint `gorm:"column:subscription_id;type:bigint;index" json:"subscription_id"`
 	// 股票ID
 	SymbolID *uint `gorm:"column:symbol_id;type:bigint;index" json:"symbol_id"`
 	// 事件內容
 	Payload string `gorm:"column:payload;type:jsonb" json:"payload"`
+	// 去重鍵（使用者:功能:股票:交易日），為空代表不去重
+	DedupeKey *string `gorm:"column:dedupe_key;type:varchar(255);uniqueIndex" json:"dedupe_key"`
 	// 發生時間
 	OccurredAt time.Time `gorm:"column:occurred_at;type:timestamptz" json:"occurred_at"`
 	// 關聯資料表
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// 投資組合交易紀錄模型
+type PortfolioTrade struct {
+	Model
+	// 使用者ID
+	UserID uint `gorm:"column:user_id;type:bigint;index" json:"user_id"`
+	// 股票ID
+	SymbolID uint `gorm:"column:symbol_id;type:bigint;index" json:"symbol_id"`
+	// 交易方向 (buy 或 sell)
+	Side valueobject.TradeSide `gorm:"column:side;type:varchar(4);not null" json:"side"`
+	// 成交股數
+	Quantity int64 `gorm:"column:quantity;type:bigint;not null" json:"quantity"`
+	// 成交單價
+	Price float64 `gorm:"column:price;type:numeric(12,4);not null" json:"price"`
+	// 交易日期
+	TradeDate time.Time `gorm:"column:trade_date;type:date;not null" json:"trade_date"`
+	// 關聯資料表
+	StockSymbol *StockSymbol `gorm:"foreignKey:SymbolID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	User        *User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (PortfolioTrade) TableName() string {
+	return "portfolio_trades"
+}
+
+func init() {
+	RegisterModel(&PortfolioTrade{})
+}
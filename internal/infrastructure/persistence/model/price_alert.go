@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+)
+
+// 價格提醒模型
+type PriceAlert struct {
+	Model
+	// 使用者ID
+	UserID uint `gorm:"column:user_id;type:bigint;index" json:"user_id"`
+	// 股票ID
+	SymbolID uint `gorm:"column:symbol_id;type:bigint;index" json:"symbol_id"`
+	// 提醒類型
+	AlertType valueobject.AlertType `gorm:"column:alert_type;type:smallint;not null;default:1" json:"alert_type"`
+	// 比較運算子 (> 或 <)
+	Operator valueobject.AlertOperator `gorm:"column:operator;type:varchar(2)" json:"operator"`
+	// 目標價
+	TargetPrice float64 `gorm:"column:target_price;type:numeric(12,2)" json:"target_price"`
+	// 漲跌幅/跳空百分比門檻
+	ThresholdPercent float64 `gorm:"column:threshold_percent;type:numeric(6,2)" json:"threshold_percent"`
+	// 狀態
+	Status bool `gorm:"column:status;type:boolean;index" json:"status"`
+	// 是否已處於觸發區間
+	Triggered bool `gorm:"column:triggered;type:boolean;default:false" json:"triggered"`
+	// 最近觸發時間
+	TriggeredAt *time.Time `gorm:"column:triggered_at;type:timestamptz" json:"triggered_at"`
+	// 關聯資料表
+	StockSymbol *StockSymbol `gorm:"foreignKey:SymbolID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	User        *User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (PriceAlert) TableName() string {
+	return "price_alerts"
+}
+
+func init() {
+	RegisterModel(&PriceAlert{})
+}
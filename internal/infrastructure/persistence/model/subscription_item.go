@@ -9,6 +9,8 @@ const (
 	SubscriptionItemStockNews       SubscriptionItem = 2
 	SubscriptionItemDailyMarketInfo SubscriptionItem = 3
 	SubscriptionItemTopVolumeItems  SubscriptionItem = 4
+	SubscriptionItemExDividend      SubscriptionItem = 5
+	SubscriptionItemDailyDigest     SubscriptionItem = 6
 )
 
 // SubscriptionItemMap mapping table for subscription items
@@ -18,6 +20,8 @@ var SubscriptionItemMap = map[string]SubscriptionItem{
 	"2": SubscriptionItemStockNews,
 	"3": SubscriptionItemDailyMarketInfo,
 	"4": SubscriptionItemTopVolumeItems,
+	"5": SubscriptionItemExDividend,
+	"6": SubscriptionItemDailyDigest,
 }
 
 // GetName returns the name of the subscription item
@@ -33,6 +37,10 @@ func (s SubscriptionItem) GetName() string {
 		return "每日大盤資訊"
 	case SubscriptionItemTopVolumeItems:
 		return "交易量前20名"
+	case SubscriptionItemExDividend:
+		return "除權息提醒"
+	case SubscriptionItemDailyDigest:
+		return "每日摘要"
 	default:
 		return "Default"
 	}
@@ -0,0 +1,22 @@
+package models
+
+// 使用者券商交易設定模型
+type TradingSetting struct {
+	Model
+	// 使用者ID
+	UserID uint `gorm:"column:user_id;type:bigint;uniqueIndex;not null" json:"user_id"`
+	// 手續費折扣 (1 為不打折)
+	FeeDiscount float64 `gorm:"column:fee_discount;type:numeric(5,4);not null;default:1" json:"fee_discount"`
+	// 整股最低手續費，NULL 代表使用預設值
+	MinFee *float64 `gorm:"column:min_fee;type:numeric(8,2)" json:"min_fee"`
+	// 關聯資料表
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (TradingSetting) TableName() string {
+	return "user_trading_settings"
+}
+
+func init() {
+	RegisterModel(&TradingSetting{})
+}
@@ -0,0 +1,28 @@
+package models
+
+// 使用者推播偏好設定模型
+type UserPreference struct {
+	Model
+	// 使用者ID
+	UserID uint `gorm:"column:user_id;type:bigint;uniqueIndex;not null" json:"user_id"`
+	// 勿擾開始時間 (HH:MM)，NULL 代表不啟用
+	QuietHoursStart *string `gorm:"column:quiet_hours_start;type:varchar(5)" json:"quiet_hours_start"`
+	// 勿擾結束時間 (HH:MM)
+	QuietHoursEnd *string `gorm:"column:quiet_hours_end;type:varchar(5)" json:"quiet_hours_end"`
+	// 時區
+	Timezone string `gorm:"column:timezone;type:varchar(64);not null;default:'Asia/Taipei'" json:"timezone"`
+	// 偏好語言
+	Language string `gorm:"column:language;type:varchar(16);not null;default:'zh-TW'" json:"language"`
+	// 每日推播上限，0 代表不限制
+	MaxDailyPushes int `gorm:"column:max_daily_pushes;type:integer;not null;default:0" json:"max_daily_pushes"`
+	// 關聯資料表
+	User *User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (UserPreference) TableName() string {
+	return "user_preferences"
+}
+
+func init() {
+	RegisterModel(&UserPreference{})
+}
@@ -4,7 +4,11 @@ package models
 type Watchlist struct {
 	Model
 	// 使用者ID
-	UserID uint `gorm:"column:user_id;type:bigint" json:"user_id"`
+	UserID uint `gorm:"column:user_id;type:bigint;index" json:"user_id"`
+	// 清單名稱
+	Name string `gorm:"column:name;type:varchar(50);not null;default:'預設'" json:"name"`
+	// 清單排序
+	SortOrder int `gorm:"column:sort_order;type:int;not null;default:0" json:"sort_order"`
 	// 關聯資料表
 	User           *User            `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
 	WatchlistItems []*WatchlistItem `gorm:"foreignKey:WatchlistID;references:ID" json:"-"`
@@ -7,6 +7,8 @@ type WatchlistItem struct {
 	WatchlistID uint `gorm:"column:watchlist_id;type:bigint;index;uniqueIndex:idx_watchlist_symbol,priority:1" json:"watchlist_id"`
 	// 股票代號
 	SymbolID uint `gorm:"column:symbol_id;type:bigint;index;uniqueIndex:idx_watchlist_symbol,priority:2" json:"symbol_id"`
+	// 清單內排序
+	SortOrder int `gorm:"column:sort_order;type:int;not null;default:0" json:"sort_order"`
 	// 關聯資料表
 	Watchlist *Watchlist   `gorm:"foreignKey:WatchlistID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
 	Symbol    *StockSymbol `gorm:"foreignKey:SymbolID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
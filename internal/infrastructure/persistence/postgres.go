@@ -102,6 +102,10 @@ func (d *postgresDatabase) createOrUpdateTable() error {
 		return fmt.Errorf("資料庫遷移失敗: %w", err)
 	}
 
+	if err := d.runMigrations(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
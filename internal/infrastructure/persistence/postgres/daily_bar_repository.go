@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresDailyBarRepository struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+var _ repo.DailyBarRepository = (*postgresDailyBarRepository)(nil)
+
+func NewDailyBarRepository(db *gorm.DB, log logger.Logger) *postgresDailyBarRepository {
+	return &postgresDailyBarRepository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *postgresDailyBarRepository) toEntity(model *models.DailyBar) *entity.DailyBar {
+	return &entity.DailyBar{
+		ID:           model.ID,
+		SymbolID:     model.SymbolID,
+		Date:         model.Date,
+		Open:         model.Open,
+		High:         model.High,
+		Low:          model.Low,
+		Close:        model.Close,
+		Volume:       model.Volume,
+		Amount:       model.Amount,
+		Transactions: model.Transactions,
+		Currency:     model.Currency,
+	}
+}
+
+func (r *postgresDailyBarRepository) toModel(entity *entity.DailyBar) *models.DailyBar {
+	return &models.DailyBar{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		SymbolID:     entity.SymbolID,
+		Date:         entity.Date,
+		Open:         entity.Open,
+		High:         entity.High,
+		Low:          entity.Low,
+		Close:        entity.Close,
+		Volume:       entity.Volume,
+		Amount:       entity.Amount,
+		Transactions: entity.Transactions,
+		Currency:     entity.Currency,
+	}
+}
+
+// GetByDateRange 取得股票在日期區間內的日K，依日期由舊到新排序
+func (r *postgresDailyBarRepository) GetByDateRange(ctx context.Context, symbolID uint, startDate, endDate time.Time) ([]*entity.DailyBar, error) {
+	var bars []*models.DailyBar
+	err := r.db.WithContext(ctx).
+		Where("symbol_id = ? AND date BETWEEN ? AND ?", symbolID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("date").
+		Find(&bars).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.DailyBar, len(bars))
+	for i, bar := range bars {
+		entities[i] = r.toEntity(bar)
+	}
+	return entities, nil
+}
+
+// GetLatestDate 取得股票最新一筆日K的日期，沒有資料時回傳 nil
+func (r *postgresDailyBarRepository) GetLatestDate(ctx context.Context, symbolID uint) (*time.Time, error) {
+	var bar models.DailyBar
+	err := r.db.WithContext(ctx).Where("symbol_id = ?", symbolID).Order("date DESC").First(&bar).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bar.Date, nil
+}
+
+// BatchUpsert 批次寫入日K，同一股票同一天已有資料時更新價格
+func (r *postgresDailyBarRepository) BatchUpsert(ctx context.Context, bars []*entity.DailyBar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	dbModels := make([]*models.DailyBar, len(bars))
+	for i, bar := range bars {
+		dbModels[i] = r.toModel(bar)
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "volume", "amount", "transactions", "currency", "updated_at"}),
+	}).CreateInBatches(dbModels, 500).Error
+	if err != nil {
+		r.logger.Error("Failed to batch upsert daily bars", logger.Error(err), logger.Int("count", len(bars)))
+		return err
+	}
+	return nil
+}
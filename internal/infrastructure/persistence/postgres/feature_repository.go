@@ -214,6 +214,16 @@ func (r *featureRepository) createDefaultFeatures() error {
 			Code:        "4",
 			Description: models.SubscriptionItemTopVolumeItems.GetName(),
 		},
+		{
+			Name:        "Ex Dividend Reminder",
+			Code:        "5",
+			Description: models.SubscriptionItemExDividend.GetName(),
+		},
+		{
+			Name:        "Daily Digest",
+			Code:        "6",
+			Description: models.SubscriptionItemDailyDigest.GetName(),
+		},
 	}
 
 	for _, feature := range defaultFeatures {
@@ -5,55 +5,99 @@ import (
 	"fmt"
 	"time"
 
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
 
 	"gorm.io/gorm"
 )
 
-type NotificationDeliveryRepository interface {
-	Create(ctx context.Context, delivery *models.NotificationDelivery) error
-	GetByID(ctx context.Context, id uint) (*models.NotificationDelivery, error)
-	GetByEventID(ctx context.Context, eventID uint) ([]*models.NotificationDelivery, error)
-	GetByChannelID(ctx context.Context, channelID uint) ([]*models.NotificationDelivery, error)
-	GetByStatus(ctx context.Context, status models.NotificationDeliveryStatus) ([]*models.NotificationDelivery, error)
-	Update(ctx context.Context, delivery *models.NotificationDelivery) error
-	UpdateStatus(ctx context.Context, id uint, status models.NotificationDeliveryStatus) error
-	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, offset, limit int) ([]*models.NotificationDelivery, error)
-	GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.NotificationDelivery, error)
-	GetFailedDeliveries(ctx context.Context) ([]*models.NotificationDelivery, error)
-	BatchCreate(ctx context.Context, deliveries []*models.NotificationDelivery) error
-}
-
 type notificationDeliveryRepository struct {
 	db     *gorm.DB
 	logger logger.Logger
 }
 
-func NewNotificationDeliveryRepository(db *gorm.DB, log logger.Logger) NotificationDeliveryRepository {
+var _ repo.NotificationDeliveryReader = (*notificationDeliveryRepository)(nil)
+var _ repo.NotificationDeliveryWriter = (*notificationDeliveryRepository)(nil)
+
+func NewNotificationDeliveryRepository(db *gorm.DB, log logger.Logger) *notificationDeliveryRepository {
 	return &notificationDeliveryRepository{
 		db:     db,
 		logger: log,
 	}
 }
 
+func (r *notificationDeliveryRepository) toEntity(model *models.NotificationDelivery) *entity.NotificationDelivery {
+	result := &entity.NotificationDelivery{
+		ID:          model.ID,
+		EventID:     model.EventID,
+		Channel:     valueobject.UserType(model.ChannelID),
+		Status:      model.Status,
+		Response:    model.Response,
+		Attempts:    model.Attempts,
+		NextRetryAt: model.NextRetryAt,
+		SentAt:      model.SentAt,
+	}
+
+	if model.Event != nil {
+		result.Event = toNotificationEventEntity(model.Event)
+	}
+
+	return result
+}
+
+func (r *notificationDeliveryRepository) toEntities(deliveries []*models.NotificationDelivery) []*entity.NotificationDelivery {
+	entities := make([]*entity.NotificationDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		entities = append(entities, r.toEntity(delivery))
+	}
+	return entities
+}
+
+// toNotificationDeliveryModel 轉換投遞紀錄，供通知事件的交易內建立投遞紀錄共用
+func toNotificationDeliveryModel(entity *entity.NotificationDelivery) *models.NotificationDelivery {
+	result := &models.NotificationDelivery{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		EventID:     entity.EventID,
+		ChannelID:   uint(entity.Channel),
+		Status:      entity.Status,
+		Response:    entity.Response,
+		Attempts:    entity.Attempts,
+		NextRetryAt: entity.NextRetryAt,
+		SentAt:      entity.SentAt,
+	}
+	if result.Status == "" {
+		result.Status = valueobject.NotificationDeliveryStatusQueued
+	}
+	// response 欄位為 jsonb，不可寫入空字串
+	if result.Response == "" {
+		result.Response = "{}"
+	}
+	return result
+}
+
 // Create 建立新投遞紀錄
-func (r *notificationDeliveryRepository) Create(ctx context.Context, delivery *models.NotificationDelivery) error {
-	r.logger.Info("Creating notification delivery", logger.Any("event_id", delivery.EventID), logger.Any("channel_id", delivery.ChannelID))
+func (r *notificationDeliveryRepository) Create(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	r.logger.Info("Creating notification delivery", logger.Any("event_id", delivery.EventID), logger.Any("channel", delivery.Channel))
 
-	err := r.db.WithContext(ctx).Create(delivery).Error
+	dbModel := toNotificationDeliveryModel(delivery)
+	err := r.db.WithContext(ctx).Create(dbModel).Error
 	if err != nil {
 		r.logger.Error("Failed to create notification delivery", logger.Error(err), logger.Any("event_id", delivery.EventID))
 		return err
 	}
+	delivery.ID = dbModel.ID
 
 	r.logger.Info("Notification delivery created successfully", logger.Any("id", delivery.ID))
 	return nil
 }
 
 // GetByID 根據 ID 取得投遞紀錄
-func (r *notificationDeliveryRepository) GetByID(ctx context.Context, id uint) (*models.NotificationDelivery, error) {
+func (r *notificationDeliveryRepository) GetByID(ctx context.Context, id uint) (*entity.NotificationDelivery, error) {
 	var delivery models.NotificationDelivery
 	err := r.db.WithContext(ctx).Preload("Event").First(&delivery, id).Error
 	if err != nil {
@@ -64,35 +108,67 @@ func (r *notificationDeliveryRepository) GetByID(ctx context.Context, id uint) (
 		r.logger.Error("Failed to get notification delivery", logger.Error(err), logger.Any("id", id))
 		return nil, err
 	}
-	return &delivery, nil
+	return r.toEntity(&delivery), nil
 }
 
 // GetByEventID 根據事件 ID 取得投遞紀錄
-func (r *notificationDeliveryRepository) GetByEventID(ctx context.Context, eventID uint) ([]*models.NotificationDelivery, error) {
+func (r *notificationDeliveryRepository) GetByEventID(ctx context.Context, eventID uint) ([]*entity.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
 	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&deliveries).Error
-	return deliveries, err
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
 }
 
-// GetByChannelID 根據管道 ID 取得投遞紀錄
-func (r *notificationDeliveryRepository) GetByChannelID(ctx context.Context, channelID uint) ([]*models.NotificationDelivery, error) {
+// GetByChannel 根據推播管道取得投遞紀錄
+func (r *notificationDeliveryRepository) GetByChannel(ctx context.Context, channel valueobject.UserType) ([]*entity.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
-	err := r.db.WithContext(ctx).Where("channel_id = ?", channelID).Find(&deliveries).Error
-	return deliveries, err
+	err := r.db.WithContext(ctx).Where("channel_id = ?", uint(channel)).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
 }
 
 // GetByStatus 根據狀態取得投遞紀錄
-func (r *notificationDeliveryRepository) GetByStatus(ctx context.Context, status models.NotificationDeliveryStatus) ([]*models.NotificationDelivery, error) {
+func (r *notificationDeliveryRepository) GetByStatus(ctx context.Context, status valueobject.NotificationDeliveryStatus) ([]*entity.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
 	err := r.db.WithContext(ctx).Where("status = ?", status).Find(&deliveries).Error
-	return deliveries, err
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
+}
+
+// GetQueuedDeliveries 依建立順序取得待送出的投遞紀錄，略過遞延到 before 之後的紀錄，包含事件與使用者資料
+func (r *notificationDeliveryRepository) GetQueuedDeliveries(ctx context.Context, before time.Time, limit int) ([]*entity.NotificationDelivery, error) {
+	var deliveries []*models.NotificationDelivery
+	err := r.db.WithContext(ctx).
+		Preload("Event").
+		Preload("Event.User").
+		Where("status = ? AND (next_retry_at IS NULL OR next_retry_at <= ?)", valueobject.NotificationDeliveryStatusQueued, before).
+		Order("id").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
 }
 
 // Update 更新投遞紀錄
-func (r *notificationDeliveryRepository) Update(ctx context.Context, delivery *models.NotificationDelivery) error {
+func (r *notificationDeliveryRepository) Update(ctx context.Context, delivery *entity.NotificationDelivery) error {
 	r.logger.Info("Updating notification delivery", logger.Any("id", delivery.ID))
 
-	err := r.db.WithContext(ctx).Save(delivery).Error
+	dbModel := toNotificationDeliveryModel(delivery)
+	err := r.db.WithContext(ctx).Model(&models.NotificationDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":        dbModel.Status,
+		"response":      dbModel.Response,
+		"attempts":      dbModel.Attempts,
+		"next_retry_at": dbModel.NextRetryAt,
+		"sent_at":       dbModel.SentAt,
+	}).Error
 	if err != nil {
 		r.logger.Error("Failed to update notification delivery", logger.Error(err), logger.Any("id", delivery.ID))
 		return err
@@ -103,7 +179,7 @@ func (r *notificationDeliveryRepository) Update(ctx context.Context, delivery *m
 }
 
 // UpdateStatus 更新投遞狀態
-func (r *notificationDeliveryRepository) UpdateStatus(ctx context.Context, id uint, status models.NotificationDeliveryStatus) error {
+func (r *notificationDeliveryRepository) UpdateStatus(ctx context.Context, id uint, status valueobject.NotificationDeliveryStatus) error {
 	r.logger.Info("Updating notification delivery status", logger.Any("id", id), logger.String("status", string(status)))
 
 	err := r.db.WithContext(ctx).Model(&models.NotificationDelivery{}).Where("id = ?", id).Update("status", status).Error
@@ -136,36 +212,75 @@ func (r *notificationDeliveryRepository) Delete(ctx context.Context, id uint) er
 }
 
 // List 取得投遞紀錄列表
-func (r *notificationDeliveryRepository) List(ctx context.Context, offset, limit int) ([]*models.NotificationDelivery, error) {
+func (r *notificationDeliveryRepository) List(ctx context.Context, offset, limit int) ([]*entity.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
 	err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Order("created_at DESC").Find(&deliveries).Error
-	return deliveries, err
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
 }
 
 // GetByDateRange 根據日期範圍取得投遞紀錄
-func (r *notificationDeliveryRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.NotificationDelivery, error) {
+func (r *notificationDeliveryRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
 	err := r.db.WithContext(ctx).Where("sent_at BETWEEN ? AND ?", startDate, endDate).Find(&deliveries).Error
-	return deliveries, err
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
 }
 
-// GetFailedDeliveries 取得失敗的投遞紀錄
-func (r *notificationDeliveryRepository) GetFailedDeliveries(ctx context.Context) ([]*models.NotificationDelivery, error) {
+// GetFailedDeliveries 依重試時間取得已到期、需要重試的失敗投遞紀錄，包含事件與使用者資料
+func (r *notificationDeliveryRepository) GetFailedDeliveries(ctx context.Context, before time.Time, limit int) ([]*entity.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
-	err := r.db.WithContext(ctx).Where("status = ?", models.NotificationDeliveryStatusFailed).Find(&deliveries).Error
-	return deliveries, err
+	err := r.db.WithContext(ctx).
+		Preload("Event").
+		Preload("Event.User").
+		Where("status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", valueobject.NotificationDeliveryStatusFailed, before).
+		Order("next_retry_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(deliveries), nil
+}
+
+// CountSentByUserSince 計算 since 之後已送給使用者的投遞紀錄數量
+func (r *notificationDeliveryRepository) CountSentByUserSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Joins("JOIN notification_events ON notification_events.id = notification_deliveries.event_id").
+		Where("notification_events.user_id = ? AND notification_deliveries.status = ? AND notification_deliveries.sent_at >= ?",
+			userID, valueobject.NotificationDeliveryStatusSent, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
 // BatchCreate 批次建立投遞紀錄
-func (r *notificationDeliveryRepository) BatchCreate(ctx context.Context, deliveries []*models.NotificationDelivery) error {
+func (r *notificationDeliveryRepository) BatchCreate(ctx context.Context, deliveries []*entity.NotificationDelivery) error {
 	r.logger.Info("Batch creating notification deliveries", logger.Int("count", len(deliveries)))
 
-	err := r.db.WithContext(ctx).CreateInBatches(deliveries, 100).Error
+	dbModels := make([]*models.NotificationDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		dbModels = append(dbModels, toNotificationDeliveryModel(delivery))
+	}
+
+	err := r.db.WithContext(ctx).CreateInBatches(dbModels, 100).Error
 	if err != nil {
 		r.logger.Error("Failed to batch create notification deliveries", logger.Error(err), logger.Int("count", len(deliveries)))
 		return err
 	}
 
+	for i, dbModel := range dbModels {
+		deliveries[i].ID = dbModel.ID
+	}
+
 	r.logger.Info("Batch create notification deliveries completed", logger.Int("count", len(deliveries)))
 	return nil
 }
@@ -5,56 +5,153 @@ import (
 	"fmt"
 	"time"
 
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	domainerror "github.com/tian841224/stock-bot/internal/domain/error"
 	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
 	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type NotificationEventRepository interface {
-	Create(ctx context.Context, event *models.NotificationEvent) error
-	GetByID(ctx context.Context, id uint) (*models.NotificationEvent, error)
-	GetByUserID(ctx context.Context, userID uint) ([]*models.NotificationEvent, error)
-	GetByFeatureID(ctx context.Context, featureID uint) ([]*models.NotificationEvent, error)
-	GetBySubscriptionID(ctx context.Context, subscriptionID uint) ([]*models.NotificationEvent, error)
-	GetBySymbolID(ctx context.Context, symbolID uint) ([]*models.NotificationEvent, error)
-	Update(ctx context.Context, event *models.NotificationEvent) error
-	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, offset, limit int) ([]*models.NotificationEvent, error)
-	GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.NotificationEvent, error)
-	GetByUserAndFeature(ctx context.Context, userID, featureID uint) ([]*models.NotificationEvent, error)
-	GetRecentEvents(ctx context.Context, userID uint, limit int) ([]*models.NotificationEvent, error)
-	BatchCreate(ctx context.Context, events []*models.NotificationEvent) error
-}
-
 type notificationEventRepository struct {
 	db     *gorm.DB
 	logger logger.Logger
 }
 
-func NewNotificationEventRepository(db *gorm.DB, log logger.Logger) NotificationEventRepository {
+var _ repo.NotificationEventReader = (*notificationEventRepository)(nil)
+var _ repo.NotificationEventWriter = (*notificationEventRepository)(nil)
+
+func NewNotificationEventRepository(db *gorm.DB, log logger.Logger) *notificationEventRepository {
 	return &notificationEventRepository{
 		db:     db,
 		logger: log,
 	}
 }
 
+func (r *notificationEventRepository) toEntity(model *models.NotificationEvent) *entity.NotificationEvent {
+	return toNotificationEventEntity(model)
+}
+
+// toNotificationEventEntity 轉換通知事件，供投遞紀錄預載事件時共用
+func toNotificationEventEntity(model *models.NotificationEvent) *entity.NotificationEvent {
+	result := &entity.NotificationEvent{
+		ID:             model.ID,
+		UserID:         model.UserID,
+		SubscriptionID: model.SubscriptionID,
+		SymbolID:       model.SymbolID,
+		Payload:        model.Payload,
+		OccurredAt:     model.OccurredAt,
+	}
+	if model.FeatureID != nil {
+		result.FeatureID = *model.FeatureID
+	}
+	if model.DedupeKey != nil {
+		result.DedupeKey = *model.DedupeKey
+	}
+
+	if model.User != nil {
+		result.User = &entity.User{
+			ID:        model.User.ID,
+			AccountID: model.User.AccountID,
+			UserType:  model.User.UserType,
+			Status:    model.User.Status,
+		}
+	}
+
+	return result
+}
+
+func (r *notificationEventRepository) toModel(entity *entity.NotificationEvent) *models.NotificationEvent {
+	result := &models.NotificationEvent{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		UserID:         entity.UserID,
+		SubscriptionID: entity.SubscriptionID,
+		SymbolID:       entity.SymbolID,
+		Payload:        entity.Payload,
+		OccurredAt:     entity.OccurredAt,
+	}
+	if entity.FeatureID != 0 {
+		featureID := entity.FeatureID
+		result.FeatureID = &featureID
+	}
+	if entity.DedupeKey != "" {
+		dedupeKey := entity.DedupeKey
+		result.DedupeKey = &dedupeKey
+	}
+	// payload 欄位為 jsonb，不可寫入空字串
+	if result.Payload == "" {
+		result.Payload = "{}"
+	}
+	return result
+}
+
+func (r *notificationEventRepository) toEntities(events []*models.NotificationEvent) []*entity.NotificationEvent {
+	entities := make([]*entity.NotificationEvent, 0, len(events))
+	for _, event := range events {
+		entities = append(entities, r.toEntity(event))
+	}
+	return entities
+}
+
 // Create 建立新通知事件
-func (r *notificationEventRepository) Create(ctx context.Context, event *models.NotificationEvent) error {
+func (r *notificationEventRepository) Create(ctx context.Context, event *entity.NotificationEvent) error {
 	r.logger.Info("Creating notification event", logger.Any("user_id", event.UserID), logger.Any("feature_id", event.FeatureID))
 
-	err := r.db.WithContext(ctx).Create(event).Error
+	dbModel := r.toModel(event)
+	err := r.db.WithContext(ctx).Create(dbModel).Error
 	if err != nil {
 		r.logger.Error("Failed to create notification event", logger.Error(err), logger.Any("user_id", event.UserID))
 		return err
 	}
+	event.ID = dbModel.ID
 
 	r.logger.Info("Notification event created successfully", logger.Any("id", event.ID))
 	return nil
 }
 
+// CreateWithDelivery 在同一個交易中建立通知事件及其投遞紀錄；
+// 去重鍵已存在時不建立任何資料並回傳 domainerror.ErrAlreadyExists
+func (r *notificationEventRepository) CreateWithDelivery(ctx context.Context, event *entity.NotificationEvent, delivery *entity.NotificationDelivery) error {
+	eventModel := r.toModel(event)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "dedupe_key"}},
+			DoNothing: true,
+		}).Create(eventModel)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domainerror.ErrAlreadyExists
+		}
+
+		delivery.EventID = eventModel.ID
+		deliveryModel := toNotificationDeliveryModel(delivery)
+		if err := tx.Create(deliveryModel).Error; err != nil {
+			return err
+		}
+		delivery.ID = deliveryModel.ID
+		return nil
+	})
+	if err == domainerror.ErrAlreadyExists {
+		r.logger.Info("Notification event already exists", logger.String("dedupe_key", event.DedupeKey))
+		return err
+	}
+	if err != nil {
+		r.logger.Error("Failed to create notification event with delivery", logger.Error(err), logger.Any("user_id", event.UserID))
+		return err
+	}
+
+	event.ID = eventModel.ID
+	return nil
+}
+
 // GetByID 根據 ID 取得通知事件
-func (r *notificationEventRepository) GetByID(ctx context.Context, id uint) (*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetByID(ctx context.Context, id uint) (*entity.NotificationEvent, error) {
 	var event models.NotificationEvent
 	// 優化: 移除過度的 Preload，4個關聯太重，改為按需加載
 	err := r.db.WithContext(ctx).First(&event, id).Error
@@ -66,43 +163,61 @@ func (r *notificationEventRepository) GetByID(ctx context.Context, id uint) (*mo
 		r.logger.Error("Failed to get notification event", logger.Error(err), logger.Any("id", id))
 		return nil, err
 	}
-	return &event, nil
+	return r.toEntity(&event), nil
 }
 
 // GetByUserID 根據使用者 ID 取得通知事件
-func (r *notificationEventRepository) GetByUserID(ctx context.Context, userID uint) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
-	// 優化: 只保留顯示所需的關聯，移除過度加載
-	err := r.db.WithContext(ctx).Preload("Feature").Where("user_id = ?", userID).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("occurred_at DESC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // GetByFeatureID 根據功能 ID 取得通知事件
-func (r *notificationEventRepository) GetByFeatureID(ctx context.Context, featureID uint) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetByFeatureID(ctx context.Context, featureID uint) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
-	err := r.db.WithContext(ctx).Preload("User").Preload("Symbol").Where("feature_id = ?", featureID).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	err := r.db.WithContext(ctx).Preload("User").Where("feature_id = ?", featureID).Order("occurred_at DESC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // GetBySubscriptionID 根據訂閱 ID 取得通知事件
-func (r *notificationEventRepository) GetBySubscriptionID(ctx context.Context, subscriptionID uint) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetBySubscriptionID(ctx context.Context, subscriptionID uint) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
-	err := r.db.WithContext(ctx).Preload("User").Preload("Feature").Preload("Symbol").Where("subscription_id = ?", subscriptionID).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	err := r.db.WithContext(ctx).Preload("User").Where("subscription_id = ?", subscriptionID).Order("occurred_at DESC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // GetBySymbolID 根據股票 ID 取得通知事件
-func (r *notificationEventRepository) GetBySymbolID(ctx context.Context, symbolID uint) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetBySymbolID(ctx context.Context, symbolID uint) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
-	err := r.db.WithContext(ctx).Preload("User").Preload("Feature").Where("symbol_id = ?", symbolID).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	err := r.db.WithContext(ctx).Preload("User").Where("symbol_id = ?", symbolID).Order("occurred_at DESC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // Update 更新通知事件
-func (r *notificationEventRepository) Update(ctx context.Context, event *models.NotificationEvent) error {
+func (r *notificationEventRepository) Update(ctx context.Context, event *entity.NotificationEvent) error {
 	r.logger.Info("Updating notification event", logger.Any("id", event.ID))
 
-	err := r.db.WithContext(ctx).Save(event).Error
+	dbModel := r.toModel(event)
+	err := r.db.WithContext(ctx).Model(&models.NotificationEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"feature_id":      dbModel.FeatureID,
+		"subscription_id": dbModel.SubscriptionID,
+		"symbol_id":       dbModel.SymbolID,
+		"payload":         dbModel.Payload,
+		"occurred_at":     dbModel.OccurredAt,
+	}).Error
 	if err != nil {
 		r.logger.Error("Failed to update notification event", logger.Error(err), logger.Any("id", event.ID))
 		return err
@@ -132,44 +247,65 @@ func (r *notificationEventRepository) Delete(ctx context.Context, id uint) error
 }
 
 // List 取得通知事件列表
-func (r *notificationEventRepository) List(ctx context.Context, offset, limit int) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) List(ctx context.Context, offset, limit int) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
 	// 優化: 列表查詢移除所有 Preload，大幅減少資料傳輸
 	err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // GetByDateRange 根據日期範圍取得通知事件
-func (r *notificationEventRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
 	err := r.db.WithContext(ctx).Where("occurred_at BETWEEN ? AND ?", startDate, endDate).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // GetByUserAndFeature 根據使用者和功能取得通知事件
-func (r *notificationEventRepository) GetByUserAndFeature(ctx context.Context, userID, featureID uint) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetByUserAndFeature(ctx context.Context, userID, featureID uint) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
-	err := r.db.WithContext(ctx).Preload("Symbol").Where("user_id = ? AND feature_id = ?", userID, featureID).Order("occurred_at DESC").Find(&events).Error
-	return events, err
+	err := r.db.WithContext(ctx).Where("user_id = ? AND feature_id = ?", userID, featureID).Order("occurred_at DESC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // GetRecentEvents 取得使用者最近的通知事件
-func (r *notificationEventRepository) GetRecentEvents(ctx context.Context, userID uint, limit int) ([]*models.NotificationEvent, error) {
+func (r *notificationEventRepository) GetRecentEvents(ctx context.Context, userID uint, limit int) ([]*entity.NotificationEvent, error) {
 	var events []*models.NotificationEvent
-	err := r.db.WithContext(ctx).Preload("Feature").Preload("Symbol").Where("user_id = ?", userID).Order("occurred_at DESC").Limit(limit).Find(&events).Error
-	return events, err
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("occurred_at DESC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(events), nil
 }
 
 // BatchCreate 批次建立通知事件
-func (r *notificationEventRepository) BatchCreate(ctx context.Context, events []*models.NotificationEvent) error {
+func (r *notificationEventRepository) BatchCreate(ctx context.Context, events []*entity.NotificationEvent) error {
 	r.logger.Info("Batch creating notification events", logger.Int("count", len(events)))
 
-	err := r.db.WithContext(ctx).CreateInBatches(events, 100).Error
+	dbModels := make([]*models.NotificationEvent, 0, len(events))
+	for _, event := range events {
+		dbModels = append(dbModels, r.toModel(event))
+	}
+
+	err := r.db.WithContext(ctx).CreateInBatches(dbModels, 100).Error
 	if err != nil {
 		r.logger.Error("Failed to batch create notification events", logger.Error(err), logger.Int("count", len(events)))
 		return err
 	}
 
+	for i, dbModel := range dbModels {
+		events[i].ID = dbModel.ID
+	}
+
 	r.logger.Info("Batch create notification events completed", logger.Int("count", len(events)))
 	return nil
 }
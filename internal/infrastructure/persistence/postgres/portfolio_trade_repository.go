@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+type portfolioTradeRepository struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+var _ repo.PortfolioTradeReader = (*portfolioTradeRepository)(nil)
+var _ repo.PortfolioTradeWriter = (*portfolioTradeRepository)(nil)
+
+func NewPortfolioTradeRepository(db *gorm.DB, log logger.Logger) *portfolioTradeRepository {
+	return &portfolioTradeRepository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *portfolioTradeRepository) toEntity(model *models.PortfolioTrade) *entity.PortfolioTrade {
+	result := &entity.PortfolioTrade{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		SymbolID:  model.SymbolID,
+		Side:      model.Side,
+		Quantity:  model.Quantity,
+		Price:     model.Price,
+		TradeDate: model.TradeDate,
+	}
+
+	if model.StockSymbol != nil {
+		result.StockSymbol = &entity.StockSymbol{
+			ID:     model.StockSymbol.ID,
+			Symbol: model.StockSymbol.Symbol,
+			Market: model.StockSymbol.Market,
+			Name:   model.StockSymbol.Name,
+		}
+	}
+
+	return result
+}
+
+func (r *portfolioTradeRepository) toModel(entity *entity.PortfolioTrade) *models.PortfolioTrade {
+	return &models.PortfolioTrade{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		UserID:    entity.UserID,
+		SymbolID:  entity.SymbolID,
+		Side:      entity.Side,
+		Quantity:  entity.Quantity,
+		Price:     entity.Price,
+		TradeDate: entity.TradeDate,
+	}
+}
+
+// GetByID 根據 ID 取得交易紀錄
+func (r *portfolioTradeRepository) GetByID(ctx context.Context, id uint) (*entity.PortfolioTrade, error) {
+	var trade models.PortfolioTrade
+	err := r.db.WithContext(ctx).Preload("StockSymbol").First(&trade, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&trade), nil
+}
+
+// GetByUserID 依交易日期取得使用者的所有交易紀錄
+func (r *portfolioTradeRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.PortfolioTrade, error) {
+	var trades []*models.PortfolioTrade
+	err := r.db.WithContext(ctx).
+		Preload("StockSymbol").
+		Where("user_id = ?", userID).
+		Order("trade_date, id").
+		Find(&trades).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.PortfolioTrade, 0, len(trades))
+	for _, trade := range trades {
+		entities = append(entities, r.toEntity(trade))
+	}
+	return entities, nil
+}
+
+// Create 建立交易紀錄
+func (r *portfolioTradeRepository) Create(ctx context.Context, trade *entity.PortfolioTrade) error {
+	r.logger.Info("Creating portfolio trade", logger.Any("user_id", trade.UserID), logger.Any("symbol_id", trade.SymbolID), logger.String("side", string(trade.Side)))
+
+	dbModel := r.toModel(trade)
+	err := r.db.WithContext(ctx).Create(dbModel).Error
+	if err != nil {
+		r.logger.Error("Failed to create portfolio trade", logger.Error(err), logger.Any("user_id", trade.UserID), logger.Any("symbol_id", trade.SymbolID))
+		return err
+	}
+	trade.ID = dbModel.ID
+
+	r.logger.Info("Portfolio trade created successfully", logger.Any("id", trade.ID))
+	return nil
+}
+
+// Delete 刪除交易紀錄
+func (r *portfolioTradeRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.Info("Deleting portfolio trade", logger.Any("id", id))
+
+	result := r.db.WithContext(ctx).Delete(&models.PortfolioTrade{}, id)
+	if result.Error != nil {
+		r.logger.Error("Failed to delete portfolio trade", logger.Error(result.Error), logger.Any("id", id))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Portfolio trade not found for deletion", logger.Any("id", id))
+		return fmt.Errorf("portfolio trade not found with id: %d", id)
+	}
+
+	r.logger.Info("Portfolio trade deleted successfully", logger.Any("id", id))
+	return nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+type priceAlertRepository struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+var _ repo.PriceAlertReader = (*priceAlertRepository)(nil)
+var _ repo.PriceAlertWriter = (*priceAlertRepository)(nil)
+
+func NewPriceAlertRepository(db *gorm.DB, log logger.Logger) *priceAlertRepository {
+	return &priceAlertRepository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *priceAlertRepository) toEntity(model *models.PriceAlert) *entity.PriceAlert {
+	result := &entity.PriceAlert{
+		ID:               model.ID,
+		UserID:           model.UserID,
+		SymbolID:         model.SymbolID,
+		AlertType:        model.AlertType,
+		Operator:         model.Operator,
+		TargetPrice:      model.TargetPrice,
+		ThresholdPercent: model.ThresholdPercent,
+		Active:           model.Status,
+		Triggered:        model.Triggered,
+		TriggeredAt:      model.TriggeredAt,
+	}
+
+	if model.StockSymbol != nil {
+		result.StockSymbol = &entity.StockSymbol{
+			ID:     model.StockSymbol.ID,
+			Symbol: model.StockSymbol.Symbol,
+			Market: model.StockSymbol.Market,
+			Name:   model.StockSymbol.Name,
+		}
+	}
+
+	if model.User != nil {
+		result.User = &entity.User{
+			ID:        model.User.ID,
+			AccountID: model.User.AccountID,
+			UserType:  model.User.UserType,
+			Status:    model.User.Status,
+		}
+	}
+
+	return result
+}
+
+func (r *priceAlertRepository) toModel(entity *entity.PriceAlert) *models.PriceAlert {
+	return &models.PriceAlert{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		UserID:           entity.UserID,
+		SymbolID:         entity.SymbolID,
+		AlertType:        entity.AlertType,
+		Operator:         entity.Operator,
+		TargetPrice:      entity.TargetPrice,
+		ThresholdPercent: entity.ThresholdPercent,
+		Status:           entity.Active,
+		Triggered:        entity.Triggered,
+		TriggeredAt:      entity.TriggeredAt,
+	}
+}
+
+// GetByID 根據 ID 取得價格提醒
+func (r *priceAlertRepository) GetByID(ctx context.Context, id uint) (*entity.PriceAlert, error) {
+	var alert models.PriceAlert
+	err := r.db.WithContext(ctx).Preload("StockSymbol").First(&alert, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&alert), nil
+}
+
+// GetByUserID 取得使用者的價格提醒列表
+func (r *priceAlertRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.PriceAlert, error) {
+	var alerts []*models.PriceAlert
+	err := r.db.WithContext(ctx).
+		Preload("StockSymbol").
+		Where("user_id = ? AND status = ?", userID, true).
+		Order("id").
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.PriceAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		entities = append(entities, r.toEntity(alert))
+	}
+	return entities, nil
+}
+
+// GetActiveAlerts 取得所有啟用中的價格提醒 (包含股票及使用者資料)
+func (r *priceAlertRepository) GetActiveAlerts(ctx context.Context) ([]*entity.PriceAlert, error) {
+	var alerts []*models.PriceAlert
+	err := r.db.WithContext(ctx).
+		Preload("StockSymbol").
+		Preload("User").
+		Where("status = ?", true).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.PriceAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		entities = append(entities, r.toEntity(alert))
+	}
+	return entities, nil
+}
+
+// Create 建立價格提醒
+func (r *priceAlertRepository) Create(ctx context.Context, alert *entity.PriceAlert) error {
+	r.logger.Info("Creating price alert", logger.Any("user_id", alert.UserID), logger.Any("symbol_id", alert.SymbolID))
+
+	dbModel := r.toModel(alert)
+	err := r.db.WithContext(ctx).Create(dbModel).Error
+	if err != nil {
+		r.logger.Error("Failed to create price alert", logger.Error(err), logger.Any("user_id", alert.UserID), logger.Any("symbol_id", alert.SymbolID))
+		return err
+	}
+	alert.ID = dbModel.ID
+
+	r.logger.Info("Price alert created successfully", logger.Any("id", alert.ID))
+	return nil
+}
+
+// UpdateTriggerState 更新價格提醒的觸發狀態
+func (r *priceAlertRepository) UpdateTriggerState(ctx context.Context, id uint, triggered bool, triggeredAt *time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.PriceAlert{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"triggered":    triggered,
+			"triggered_at": triggeredAt,
+		})
+
+	if result.Error != nil {
+		r.logger.Error("Failed to update price alert trigger state", logger.Error(result.Error), logger.Any("id", id))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("price alert not found with id: %d", id)
+	}
+	return nil
+}
+
+// Delete 刪除價格提醒
+func (r *priceAlertRepository) Delete(ctx context.Context, id uint) error {
+	r.logger.Info("Deleting price alert", logger.Any("id", id))
+
+	result := r.db.WithContext(ctx).Delete(&models.PriceAlert{}, id)
+	if result.Error != nil {
+		r.logger.Error("Failed to delete price alert", logger.Error(result.Error), logger.Any("id", id))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Price alert not found for deletion", logger.Any("id", id))
+		return fmt.Errorf("price alert not found with id: %d", id)
+	}
+
+	r.logger.Info("Price alert deleted successfully", logger.Any("id", id))
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	repo "github.com/tian841224/stock-bot/internal/application/port"
 	"github.com/tian841224/stock-bot/internal/domain/entity"
@@ -213,6 +214,21 @@ func (r *postgresStockSymbolsRepository) List(ctx context.Context, offset, limit
 	return entities, nil
 }
 
+// GetAll 取得所有股票代號
+func (r *postgresStockSymbolsRepository) GetAll(ctx context.Context) ([]*entity.StockSymbol, error) {
+	var symbols []*models.StockSymbol
+	err := r.db.WithContext(ctx).Order("symbol").Find(&symbols).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.StockSymbol, len(symbols))
+	for i, symbol := range symbols {
+		entities[i] = r.toEntity(symbol)
+	}
+	return entities, nil
+}
+
 // BatchCreate 批次建立股票代號
 func (r *postgresStockSymbolsRepository) BatchCreate(ctx context.Context, symbols []*entity.StockSymbol) error {
 	r.logger.Info("Batch creating stock symbols", logger.Int("count", len(symbols)))
@@ -298,3 +314,52 @@ func (r *postgresStockSymbolsRepository) GetMarketStats(ctx context.Context) (ma
 
 	return stats, nil
 }
+
+// SearchSymbols 以股票代號開頭或名稱包含關鍵字搜尋股票，代號完全相符者排在最前，其次為代號較短者
+func (r *postgresStockSymbolsRepository) SearchSymbols(ctx context.Context, keyword string, limit int) ([]*entity.StockSymbol, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []*entity.StockSymbol{}, nil
+	}
+
+	escaped := likeEscaper.Replace(keyword)
+	var symbols []*models.StockSymbol
+	err := r.db.WithContext(ctx).
+		Where("symbol ILIKE ? OR name ILIKE ?", escaped+"%", "%"+escaped+"%").
+		Order(clause.Expr{SQL: "UPPER(symbol) = UPPER(?) DESC", Vars: []interface{}{keyword}}).
+		Order("LENGTH(symbol), symbol").
+		Limit(limit).
+		Find(&symbols).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.StockSymbol, len(symbols))
+	for i, symbol := range symbols {
+		entities[i] = r.toEntity(symbol)
+	}
+	return entities, nil
+}
+
+// likeEscaper 跳脫 LIKE 的萬用字元，避免使用者輸入的 % _ 被當成萬用字元
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetTrackedSymbols 取得有使用者訂閱或加入觀察清單的股票
+func (r *postgresStockSymbolsRepository) GetTrackedSymbols(ctx context.Context) ([]*entity.StockSymbol, error) {
+	var symbols []*models.StockSymbol
+	err := r.db.WithContext(ctx).
+		Where("id IN (?) OR id IN (?)",
+			r.db.Model(&models.SubscriptionSymbol{}).Select("symbol_id"),
+			r.db.Model(&models.WatchlistItem{}).Select("symbol_id")).
+		Order("symbol").
+		Find(&symbols).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.StockSymbol, len(symbols))
+	for i, symbol := range symbols {
+		entities[i] = r.toEntity(symbol)
+	}
+	return entities, nil
+}
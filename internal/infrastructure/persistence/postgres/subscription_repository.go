@@ -276,6 +276,28 @@ func (r *subscriptionRepository) UpdateStatus(ctx context.Context, id uint, stat
 	return nil
 }
 
+// UpdateScheduleCron 更新訂閱的推播排程，空字串代表使用預設排程
+func (r *subscriptionRepository) UpdateScheduleCron(ctx context.Context, id uint, scheduleCron string) error {
+	r.logger.Info("Updating subscription schedule", logger.Any("id", id), logger.String("schedule_cron", scheduleCron))
+
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("id = ?", id).
+		Update("schedule_cron", scheduleCron)
+
+	if result.Error != nil {
+		r.logger.Error("Failed to update subscription schedule", logger.Error(result.Error), logger.Any("id", id))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Subscription not found for schedule update", logger.Any("id", id))
+		return fmt.Errorf("subscription not found with id: %d", id)
+	}
+
+	r.logger.Info("Subscription schedule updated successfully", logger.Any("id", id), logger.String("schedule_cron", scheduleCron))
+	return nil
+}
+
 // Delete 刪除訂閱
 func (r *subscriptionRepository) Delete(ctx context.Context, id uint) error {
 	r.logger.Info("Deleting subscription", logger.Any("id", id))
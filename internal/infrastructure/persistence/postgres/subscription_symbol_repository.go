@@ -327,10 +327,12 @@ func (r *subscriptionSymbolRepository) GetByFeature(ctx context.Context, feature
 		// Subscription 資訊
 		if sub, ok := userSubscriptionMap[s.UserID]; ok {
 			entityItem.Subscription = &entity.Subscription{
-				ID:        sub.ID,
-				UserID:    sub.UserID,
-				FeatureID: sub.FeatureID,
-				Active:    sub.Status,
+				ID:           sub.ID,
+				UserID:       sub.UserID,
+				FeatureID:    sub.FeatureID,
+				Item:         valueobject.SubscriptionType(sub.FeatureID),
+				Active:       sub.Status,
+				ScheduleCron: sub.ScheduleCron,
 			}
 		}
 
@@ -73,7 +73,7 @@ func (r *postgresTradeDateRepository) GetByDate(ctx context.Context, date time.T
 // GetByDateRange 根據日期範圍取得交易日資料
 func (r *postgresTradeDateRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*entity.TradeDate, error) {
 	var tradeDates []*models.TradeDate
-	err := r.db.WithContext(ctx).Where("date BETWEEN ? AND ?", startDate, endDate).Find(&tradeDates).Error
+	err := r.db.WithContext(ctx).Where("date BETWEEN ? AND ?", startDate, endDate).Order("date").Find(&tradeDates).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
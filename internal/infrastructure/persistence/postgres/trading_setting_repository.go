@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tradingSettingRepository struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+var _ repo.TradingSettingReader = (*tradingSettingRepository)(nil)
+var _ repo.TradingSettingWriter = (*tradingSettingRepository)(nil)
+
+func NewTradingSettingRepository(db *gorm.DB, log logger.Logger) *tradingSettingRepository {
+	return &tradingSettingRepository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *tradingSettingRepository) toEntity(model *models.TradingSetting) *entity.TradingSetting {
+	return &entity.TradingSetting{
+		ID:          model.ID,
+		UserID:      model.UserID,
+		FeeDiscount: model.FeeDiscount,
+		MinFee:      model.MinFee,
+	}
+}
+
+func (r *tradingSettingRepository) toModel(entity *entity.TradingSetting) *models.TradingSetting {
+	return &models.TradingSetting{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		UserID:      entity.UserID,
+		FeeDiscount: entity.FeeDiscount,
+		MinFee:      entity.MinFee,
+	}
+}
+
+// GetByUserID 取得使用者的交易設定，未設定時回傳 nil
+func (r *tradingSettingRepository) GetByUserID(ctx context.Context, userID uint) (*entity.TradingSetting, error) {
+	var setting models.TradingSetting
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&setting), nil
+}
+
+// Upsert 建立或更新使用者的交易設定
+func (r *tradingSettingRepository) Upsert(ctx context.Context, setting *entity.TradingSetting) error {
+	r.logger.Info("Upserting trading setting", logger.Any("user_id", setting.UserID))
+
+	dbModel := r.toModel(setting)
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fee_discount", "min_fee", "updated_at"}),
+	}).Create(dbModel).Error
+	if err != nil {
+		r.logger.Error("Failed to upsert trading setting", logger.Error(err), logger.Any("user_id", setting.UserID))
+		return err
+	}
+	setting.ID = dbModel.ID
+
+	r.logger.Info("Trading setting upserted successfully", logger.Any("user_id", setting.UserID))
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	repo "github.com/tian841224/stock-bot/internal/application/port"
+	"github.com/tian841224/stock-bot/internal/domain/entity"
+	"github.com/tian841224/stock-bot/internal/domain/valueobject"
+	logger "github.com/tian841224/stock-bot/internal/infrastructure/logging"
+	models "github.com/tian841224/stock-bot/internal/infrastructure/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userPreferenceRepository struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+var _ repo.UserPreferenceReader = (*userPreferenceRepository)(nil)
+var _ repo.UserPreferenceWriter = (*userPreferenceRepository)(nil)
+
+func NewUserPreferenceRepository(db *gorm.DB, log logger.Logger) *userPreferenceRepository {
+	return &userPreferenceRepository{
+		db:     db,
+		logger: log,
+	}
+}
+
+func (r *userPreferenceRepository) toEntity(model *models.UserPreference) *entity.UserPreference {
+	result := &entity.UserPreference{
+		ID:             model.ID,
+		UserID:         model.UserID,
+		Timezone:       model.Timezone,
+		Language:       valueobject.Language(model.Language),
+		MaxDailyPushes: model.MaxDailyPushes,
+	}
+	if model.QuietHoursStart != nil && model.QuietHoursEnd != nil {
+		result.QuietHoursStart = *model.QuietHoursStart
+		result.QuietHoursEnd = *model.QuietHoursEnd
+	}
+	return result
+}
+
+func (r *userPreferenceRepository) toModel(entity *entity.UserPreference) *models.UserPreference {
+	result := &models.UserPreference{
+		Model: models.Model{
+			ID: entity.ID,
+		},
+		UserID:         entity.UserID,
+		Timezone:       entity.Timezone,
+		Language:       string(entity.Language),
+		MaxDailyPushes: entity.MaxDailyPushes,
+	}
+	if entity.HasQuietHours() {
+		start := entity.QuietHoursStart
+		end := entity.QuietHoursEnd
+		result.QuietHoursStart = &start
+		result.QuietHoursEnd = &end
+	}
+	return result
+}
+
+// GetByUserID 取得使用者的偏好設定，未設定時回傳 nil
+func (r *userPreferenceRepository) GetByUserID(ctx context.Context, userID uint) (*entity.UserPreference, error) {
+	var preference models.UserPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&preference), nil
+}
+
+// GetByUserIDs 批次取得多位使用者的偏好設定，未設定的使用者不包含在結果中
+func (r *userPreferenceRepository) GetByUserIDs(ctx context.Context, userIDs []uint) ([]*entity.UserPreference, error) {
+	if len(userIDs) == 0 {
+		return []*entity.UserPreference{}, nil
+	}
+
+	var preferences []*models.UserPreference
+	err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&preferences).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*entity.UserPreference, 0, len(preferences))
+	for _, preference := range preferences {
+		entities = append(entities, r.toEntity(preference))
+	}
+	return entities, nil
+}
+
+// Upsert 建立或更新使用者的偏好設定
+func (r *userPreferenceRepository) Upsert(ctx context.Context, preference *entity.UserPreference) error {
+	r.logger.Info("Upserting user preference", logger.Any("user_id", preference.UserID))
+
+	dbModel := r.toModel(preference)
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quiet_hours_start", "quiet_hours_end", "timezone", "language", "max_daily_pushes", "updated_at"}),
+	}).Create(dbModel).Error
+	if err != nil {
+		r.logger.Error("Failed to upsert user preference", logger.Error(err), logger.Any("user_id", preference.UserID))
+		return err
+	}
+	preference.ID = dbModel.ID
+
+	r.logger.Info("User preference upserted successfully", logger.Any("user_id", preference.UserID))
+	return nil
+}
@@ -93,10 +93,31 @@ func (r *postgresUserRepository) GetOrCreate(ctx context.Context, accountID stri
 		if err != nil {
 			return nil, err
 		}
+		return user, nil
+	}
+
+	// 使用者重新與 bot 互動時，恢復先前因封鎖 bot 而停用的帳號
+	if !user.IsActive() {
+		if err := r.UpdateStatus(ctx, user.ID, true); err != nil {
+			return nil, err
+		}
+		user.Enable()
 	}
 	return user, nil
 }
 
+// Deactivate 停用使用者，使用者不存在或已停用時不做任何事
+func (r *postgresUserRepository) Deactivate(ctx context.Context, accountID string, userType valueobject.UserType) error {
+	user, err := r.GetByAccountID(ctx, accountID, userType)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive() {
+		return nil
+	}
+	return r.UpdateStatus(ctx, user.ID, false)
+}
+
 // List 取得使用者列表
 func (r *postgresUserRepository) List(ctx context.Context, offset, limit int) ([]*entity.User, error) {
 	var users []*models.User
@@ -123,6 +144,7 @@ func (r *postgresUserRepository) Create(ctx context.Context, user *entity.User)
 		r.logger.Error("Failed to create user", logger.Error(err), logger.String("account_id", user.AccountID))
 		return err
 	}
+	user.ID = dbModel.ID
 
 	r.logger.Info("User created successfully", logger.String("account_id", user.AccountID))
 	return nil
@@ -152,6 +174,20 @@ func (r *postgresUserRepository) Update(ctx context.Context, user *entity.User)
 	return nil
 }
 
+// UpdateStatus 更新使用者啟用狀態
+func (r *postgresUserRepository) UpdateStatus(ctx context.Context, id uint, status bool) error {
+	r.logger.Info("Updating user status", logger.Any("id", id), logger.Bool("status", status))
+
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("status", status).Error
+	if err != nil {
+		r.logger.Error("Failed to update user status", logger.Error(err), logger.Any("id", id))
+		return err
+	}
+
+	r.logger.Info("User status updated successfully", logger.Any("id", id))
+	return nil
+}
+
 // Delete 刪除使用者
 func (r *postgresUserRepository) Delete(ctx context.Context, id uint) error {
 	r.logger.Info("Deleting user", logger.Any("id", id))
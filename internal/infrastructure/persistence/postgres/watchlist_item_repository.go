@@ -24,6 +24,8 @@ type WatchlistItemRepository interface {
 	BatchCreate(ctx context.Context, items []*models.WatchlistItem) error
 	GetSymbolsByWatchlistID(ctx context.Context, watchlistID uint) ([]*models.StockSymbol, error)
 	IsSymbolInWatchlist(ctx context.Context, watchlistID, symbolID uint) (bool, error)
+	MoveToWatchlist(ctx context.Context, id, watchlistID uint, sortOrder int) error
+	UpdateSortOrders(ctx context.Context, items []*models.WatchlistItem) error
 }
 
 type watchlistItemRepository struct {
@@ -70,7 +72,7 @@ func (r *watchlistItemRepository) GetByID(ctx context.Context, id uint) (*models
 // GetByWatchlistID 根據觀察清單 ID 取得項目
 func (r *watchlistItemRepository) GetByWatchlistID(ctx context.Context, watchlistID uint) ([]*models.WatchlistItem, error) {
 	var items []*models.WatchlistItem
-	err := r.db.WithContext(ctx).Preload("Symbol").Where("watchlist_id = ?", watchlistID).Find(&items).Error
+	err := r.db.WithContext(ctx).Preload("Symbol").Where("watchlist_id = ?", watchlistID).Order("sort_order, id").Find(&items).Error
 	return items, err
 }
 
@@ -185,8 +187,8 @@ func (r *watchlistItemRepository) BatchCreate(ctx context.Context, items []*mode
 // GetSymbolsByWatchlistID 根據觀察清單 ID 取得所有股票
 func (r *watchlistItemRepository) GetSymbolsByWatchlistID(ctx context.Context, watchlistID uint) ([]*models.StockSymbol, error) {
 	var symbols []*models.StockSymbol
-	err := r.db.WithContext(ctx).Table("symbols").
-		Joins("JOIN watchlist_items ON symbols.id = watchlist_items.symbol_id").
+	err := r.db.WithContext(ctx).Table("stock_symbols").
+		Joins("JOIN watchlist_items ON stock_symbols.id = watchlist_items.symbol_id").
 		Where("watchlist_items.watchlist_id = ?", watchlistID).
 		Find(&symbols).Error
 	return symbols, err
@@ -203,3 +205,45 @@ func (r *watchlistItemRepository) IsSymbolInWatchlist(ctx context.Context, watch
 	}
 	return count > 0, nil
 }
+
+// MoveToWatchlist 將項目移動到另一個觀察清單
+func (r *watchlistItemRepository) MoveToWatchlist(ctx context.Context, id, watchlistID uint, sortOrder int) error {
+	r.logger.Info("Moving watchlist item", logger.Any("id", id), logger.Any("watchlist_id", watchlistID))
+
+	result := r.db.WithContext(ctx).Model(&models.WatchlistItem{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"watchlist_id": watchlistID,
+			"sort_order":   sortOrder,
+		})
+	if result.Error != nil {
+		r.logger.Error("Failed to move watchlist item", logger.Error(result.Error), logger.Any("id", id))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("watchlist item not found with id: %d", id)
+	}
+
+	r.logger.Info("Watchlist item moved successfully", logger.Any("id", id), logger.Any("watchlist_id", watchlistID))
+	return nil
+}
+
+// UpdateSortOrders 批次更新項目排序
+func (r *watchlistItemRepository) UpdateSortOrders(ctx context.Context, items []*models.WatchlistItem) error {
+	r.logger.Info("Updating watchlist item sort orders", logger.Int("count", len(items)))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Model(&models.WatchlistItem{}).Where("id = ?", item.ID).Update("sort_order", item.SortOrder).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to update watchlist item sort orders", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -15,8 +15,12 @@ type WatchlistRepository interface {
 	GetByID(ctx context.Context, id uint) (*models.Watchlist, error)
 	GetByUserID(ctx context.Context, userID uint) (*models.Watchlist, error)
 	GetByUserIDWithItems(ctx context.Context, userID uint) (*models.Watchlist, error)
+	GetByUserIDAndName(ctx context.Context, userID uint, name string) (*models.Watchlist, error)
+	GetListByUserID(ctx context.Context, userID uint) ([]*models.Watchlist, error)
+	UpdateName(ctx context.Context, id uint, name string) error
 	Update(ctx context.Context, watchlist *models.Watchlist) error
 	Delete(ctx context.Context, id uint) error
+	DeleteWithItems(ctx context.Context, id uint) error
 	DeleteByUserID(ctx context.Context, userID uint) error
 	List(ctx context.Context, offset, limit int) ([]*models.Watchlist, error)
 	GetAllWithItems(ctx context.Context) ([]*models.Watchlist, error)
@@ -63,10 +67,10 @@ func (r *watchlistRepository) GetByID(ctx context.Context, id uint) (*models.Wat
 	return &watchlist, nil
 }
 
-// GetByUserID 根據使用者 ID 取得觀察清單
+// GetByUserID 根據使用者 ID 取得排序最前面的觀察清單
 func (r *watchlistRepository) GetByUserID(ctx context.Context, userID uint) (*models.Watchlist, error) {
 	var watchlist models.Watchlist
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&watchlist).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("sort_order, id").First(&watchlist).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -79,7 +83,8 @@ func (r *watchlistRepository) GetByUserID(ctx context.Context, userID uint) (*mo
 // GetByUserIDWithItems 根據使用者 ID 取得觀察清單（包含項目）
 func (r *watchlistRepository) GetByUserIDWithItems(ctx context.Context, userID uint) (*models.Watchlist, error) {
 	var watchlist models.Watchlist
-	err := r.db.WithContext(ctx).Preload("WatchlistItems").Preload("WatchlistItems.Symbol").Where("user_id = ?", userID).First(&watchlist).Error
+	err := r.db.WithContext(ctx).Preload("WatchlistItems", orderWatchlistItems).Preload("WatchlistItems.Symbol").
+		Where("user_id = ?", userID).Order("sort_order, id").First(&watchlist).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -89,6 +94,46 @@ func (r *watchlistRepository) GetByUserIDWithItems(ctx context.Context, userID u
 	return &watchlist, nil
 }
 
+// GetByUserIDAndName 根據使用者 ID 與清單名稱取得觀察清單（包含項目）
+func (r *watchlistRepository) GetByUserIDAndName(ctx context.Context, userID uint, name string) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	err := r.db.WithContext(ctx).Preload("WatchlistItems", orderWatchlistItems).Preload("WatchlistItems.Symbol").
+		Where("user_id = ? AND name = ?", userID, name).First(&watchlist).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+// GetListByUserID 根據使用者 ID 依排序取得所有觀察清單（包含項目）
+func (r *watchlistRepository) GetListByUserID(ctx context.Context, userID uint) ([]*models.Watchlist, error) {
+	var watchlists []*models.Watchlist
+	err := r.db.WithContext(ctx).Preload("WatchlistItems", orderWatchlistItems).Preload("WatchlistItems.Symbol").
+		Where("user_id = ?", userID).Order("sort_order, id").Find(&watchlists).Error
+	return watchlists, err
+}
+
+// UpdateName 更新觀察清單名稱
+func (r *watchlistRepository) UpdateName(ctx context.Context, id uint, name string) error {
+	r.logger.Info("Renaming watchlist", logger.Any("id", id), logger.String("name", name))
+
+	result := r.db.WithContext(ctx).Model(&models.Watchlist{}).Where("id = ?", id).Update("name", name)
+	if result.Error != nil {
+		r.logger.Error("Failed to rename watchlist", logger.Error(result.Error), logger.Any("id", id))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("watchlist not found with id: %d", id)
+	}
+
+	r.logger.Info("Watchlist renamed successfully", logger.Any("id", id))
+	return nil
+}
+
 // Update 更新觀察清單
 func (r *watchlistRepository) Update(ctx context.Context, watchlist *models.Watchlist) error {
 	r.logger.Info("Updating watchlist", logger.Any("id", watchlist.ID))
@@ -122,6 +167,32 @@ func (r *watchlistRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+// DeleteWithItems 在同一交易中刪除觀察清單及其所有項目
+func (r *watchlistRepository) DeleteWithItems(ctx context.Context, id uint) error {
+	r.logger.Info("Deleting watchlist with items", logger.Any("id", id))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("watchlist_id = ?", id).Delete(&models.WatchlistItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Watchlist{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("watchlist not found with id: %d", id)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to delete watchlist with items", logger.Error(err), logger.Any("id", id))
+		return err
+	}
+
+	r.logger.Info("Watchlist deleted successfully", logger.Any("id", id))
+	return nil
+}
+
 // DeleteByUserID 根據使用者 ID 刪除觀察清單
 func (r *watchlistRepository) DeleteByUserID(ctx context.Context, userID uint) error {
 	r.logger.Info("Deleting watchlist by user ID", logger.Any("user_id", userID))
@@ -154,3 +225,8 @@ func (r *watchlistRepository) GetAllWithItems(ctx context.Context) ([]*models.Wa
 	err := r.db.WithContext(ctx).Preload("User").Preload("WatchlistItems").Preload("WatchlistItems.Symbol").Find(&watchlists).Error
 	return watchlists, err
 }
+
+// orderWatchlistItems 依使用者自訂順序載入觀察清單項目
+func orderWatchlistItems(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, id")
+}
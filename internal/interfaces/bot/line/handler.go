@@ -52,13 +52,22 @@ func (h *LineBotHandler) Webhook(c *gin.Context) {
 
 		ctx := context.Background()
 		for _, event := range evts {
-			if event.Type == linebot.EventTypeMessage {
+			switch event.Type {
+			case linebot.EventTypeMessage:
 				switch message := event.Message.(type) {
 				case *linebot.TextMessage:
 					if err := h.messageProcessor.ProcessTextMessage(ctx, event, message); err != nil {
 						h.logger.Error("處理 LINE 文字訊息失敗", logger.Error(err))
 					}
 				}
+			case linebot.EventTypeFollow:
+				if err := h.messageProcessor.ProcessFollow(ctx, event); err != nil {
+					h.logger.Error("處理 LINE 加入好友事件失敗", logger.Error(err))
+				}
+			case linebot.EventTypeUnfollow:
+				if err := h.messageProcessor.ProcessUnfollow(ctx, event); err != nil {
+					h.logger.Error("處理 LINE 封鎖事件失敗", logger.Error(err))
+				}
 			}
 		}
 	}(events)
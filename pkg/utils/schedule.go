@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ParseCronSpec 解析標準五欄位 cron 表達式（分 時 日 月 週）
+func ParseCronSpec(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(strings.TrimSpace(spec))
+	if err != nil {
+		return nil, fmt.Errorf("排程格式錯誤: %w", err)
+	}
+	return schedule, nil
+}
+
+// IsCronDueBetween 判斷排程是否在 after 所在分鐘之後、until 所在分鐘（含）之前觸發，時區以 until 為準
+func IsCronDueBetween(schedule cron.Schedule, after, until time.Time) bool {
+	from := truncateMinute(after.In(until.Location()))
+	next := schedule.Next(from)
+	return !next.IsZero() && !next.After(truncateMinute(until))
+}
+
+func truncateMinute(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+}
+
+// MinCronInterval 從 from 起取樣 samples 次觸發時間，回傳相鄰兩次觸發的最短間隔
+func MinCronInterval(schedule cron.Schedule, from time.Time, samples int) time.Duration {
+	var interval time.Duration
+	previous := schedule.Next(from)
+	for i := 0; i < samples; i++ {
+		next := schedule.Next(previous)
+		if next.IsZero() {
+			break
+		}
+		if gap := next.Sub(previous); interval == 0 || gap < interval {
+			interval = gap
+		}
+		previous = next
+	}
+	return interval
+}
+
+// DailyCronSpec 將 HH:MM 轉換為每日固定時間執行的 cron 表達式
+func DailyCronSpec(clock string) (string, bool) {
+	parts := strings.Split(clock, ":")
+	if len(parts) != 2 {
+		return "", false
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return "", false
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return "", false
+	}
+	return fmt.Sprintf("%d %d * * *", minute, hour), true
+}
+
+// DailyCronClock 若 cron 表達式為每日固定時間，回傳 HH:MM
+func DailyCronClock(spec string) (string, bool) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 || fields[2] != "*" || fields[3] != "*" || fields[4] != "*" {
+		return "", false
+	}
+	minute, err := strconv.Atoi(fields[0])
+	if err != nil || minute < 0 || minute > 59 {
+		return "", false
+	}
+	hour, err := strconv.Atoi(fields[1])
+	if err != nil || hour < 0 || hour > 23 {
+		return "", false
+	}
+	return fmt.Sprintf("%02d:%02d", hour, minute), true
+}